@@ -5,6 +5,8 @@ type (
 		PostgreSQL PostgreSQLConfig
 		App        Gin
 		JWT        JWT
+		Storage    Storage
+		Encryption Encryption
 	}
 
 	PostgreSQLConfig struct {
@@ -19,10 +21,36 @@ type (
 	Gin struct {
 		Host string
 		Port string
+		// Mode is development, staging or production. Requests and
+		// responses are checked against the OpenAPI document in every mode
+		// but production; leave it empty to turn the checks off.
+		Mode string
 	}
 
 	JWT struct {
 		Secret string
 		Expire int
 	}
+
+	Storage struct {
+		Driver        string
+		LocalPath     string
+		MaxUploadSize int64
+		S3            S3
+	}
+
+	S3 struct {
+		Endpoint       string
+		Region         string
+		Bucket         string
+		AccessKey      string
+		SecretKey      string
+		ForcePathStyle bool
+	}
+
+	Encryption struct {
+		Keys          string
+		ActiveVersion uint32
+		IndexKey      string
+	}
 )
@@ -1,14 +1,29 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	_hospitalRepo "github.com/Teemo4621/Hospital-Api/modules/hospitals/repositories"
+	_hospitalUseCase "github.com/Teemo4621/Hospital-Api/modules/hospitals/usecases"
+	_patientRepo "github.com/Teemo4621/Hospital-Api/modules/patients/repositories"
+	_patientUseCase "github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
 	"github.com/Teemo4621/Hospital-Api/modules/servers"
+	_staffRepo "github.com/Teemo4621/Hospital-Api/modules/staffs/repositories"
+	"github.com/Teemo4621/Hospital-Api/pkgs/ciphers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
 	"github.com/Teemo4621/Hospital-Api/pkgs/databases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/storages"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -19,6 +34,7 @@ func main() {
 	cfg := new(configs.Config)
 	cfg.App.Host = os.Getenv("GIN_HOST")
 	cfg.App.Port = os.Getenv("GIN_PORT")
+	cfg.App.Mode = os.Getenv("APP_MODE")
 
 	cfg.PostgreSQL.Host = os.Getenv("POSTGRES_HOST")
 	cfg.PostgreSQL.User = os.Getenv("POSTGRES_USER")
@@ -34,6 +50,39 @@ func main() {
 	}
 	cfg.JWT.Expire = expire
 
+	cfg.Storage.Driver = os.Getenv("STORAGE_DRIVER")
+	cfg.Storage.LocalPath = os.Getenv("STORAGE_LOCAL_PATH")
+	if maxUpload := os.Getenv("STORAGE_MAX_UPLOAD_MB"); maxUpload != "" {
+		maxUploadMB, err := strconv.ParseInt(maxUpload, 10, 64)
+		if err != nil {
+			panic(err)
+		}
+		cfg.Storage.MaxUploadSize = maxUploadMB << 20
+	}
+	cfg.Storage.S3.Endpoint = os.Getenv("S3_ENDPOINT")
+	cfg.Storage.S3.Region = os.Getenv("S3_REGION")
+	cfg.Storage.S3.Bucket = os.Getenv("S3_BUCKET")
+	cfg.Storage.S3.AccessKey = os.Getenv("S3_ACCESS_KEY")
+	cfg.Storage.S3.SecretKey = os.Getenv("S3_SECRET_KEY")
+	cfg.Storage.S3.ForcePathStyle = os.Getenv("S3_FORCE_PATH_STYLE") == "true"
+
+	cfg.Encryption.Keys = os.Getenv("FIELD_ENCRYPTION_KEYS")
+	cfg.Encryption.IndexKey = os.Getenv("BLIND_INDEX_KEY")
+	if cfg.Encryption.Keys == "" || cfg.Encryption.IndexKey == "" {
+		panic("FIELD_ENCRYPTION_KEYS and BLIND_INDEX_KEY must be set, see .env.development")
+	}
+	activeKey, err := strconv.ParseUint(os.Getenv("FIELD_ENCRYPTION_ACTIVE_KEY"), 10, 32)
+	if err != nil {
+		panic("FIELD_ENCRYPTION_ACTIVE_KEY must be a key version number")
+	}
+	cfg.Encryption.ActiveVersion = uint32(activeKey)
+
+	keyring, err := ciphers.NewKeyring(cfg.Encryption)
+	if err != nil {
+		panic(err)
+	}
+	ciphers.SetDefault(keyring)
+
 	db, err := databases.NewPostgresConnection(*cfg)
 	if err != nil {
 		panic(err)
@@ -43,8 +92,159 @@ func main() {
 		panic(err)
 	}
 
+	// `reencrypt` seals legacy plaintext identifiers and moves rows written
+	// under older key versions to the active one, then exits.
+	if len(os.Args) > 1 && os.Args[1] == "reencrypt" {
+		count, err := _patientRepo.ReencryptPatients(db, keyring, 500)
+		if err != nil {
+			panic(err)
+		}
+		log.Printf("re-encrypted %d patient rows with key version %d", count, keyring.ActiveVersion())
+		return
+	}
+
+	// `set-role <username> <role>` grants a staff member a role, then exits.
+	if len(os.Args) > 1 && os.Args[1] == "set-role" {
+		if len(os.Args) != 4 {
+			panic("usage: set-role <username> <role>")
+		}
+		if err := _staffRepo.SetStaffRole(db, os.Args[2], consts.StaffRole(os.Args[3])); err != nil {
+			panic(err)
+		}
+		log.Printf("staff %s is now %s", os.Args[2], os.Args[3])
+		return
+	}
+
+	// `import-patients` runs a bulk patient import in the foreground, then
+	// exits. Pass -resume to continue a failed job instead of a new file.
+	if len(os.Args) > 1 && os.Args[1] == "import-patients" {
+		if err := importPatients(cfg, db, os.Args[2:]); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	// `import-hospital-codes <file.csv>` loads the Ministry of Public Health
+	// hospital code list, then exits.
+	if len(os.Args) > 1 && os.Args[1] == "import-hospital-codes" {
+		if len(os.Args) != 3 {
+			panic("usage: import-hospital-codes <file.csv>")
+		}
+		if err := importHospitalCodes(db, os.Args[2]); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	gin.SetMode(gin.ReleaseMode)
 
 	server := servers.NewServer(cfg, db)
 	server.Start()
 }
+
+func importHospitalCodes(db *gorm.DB, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	codeUseCase := _hospitalUseCase.NewHospitalCodeUseCase(_hospitalRepo.NewHospitalCodeRepository(db), _hospitalRepo.NewHospitalRepository(db))
+	result, err := codeUseCase.Import(f)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range result.Issues {
+		log.Printf("line %d skipped: %s", issue.Row, issue.Message)
+	}
+	log.Printf("hospital codes: %d imported, %d skipped", result.Imported, result.Skipped)
+	return nil
+}
+
+func importPatients(cfg *configs.Config, db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("import-patients", flag.ExitOnError)
+	hospitalID := fs.Uint("hospital", 0, "hospital id the patients belong to")
+	file := fs.String("file", "", "csv or xlsx file to import")
+	mapping := fs.String("mapping", "", `JSON object of patient field to column name, e.g. {"national_id":"CID"}`)
+	dryRun := fs.Bool("dry-run", false, "validate and report without inserting")
+	batchSize := fs.Int("batch", _patientUseCase.DefaultImportBatchSize, "rows per transaction")
+	resume := fs.Uint("resume", 0, "resume the import job with this id")
+	report := fs.String("report", "", "write the error report CSV to this path")
+	fs.Parse(args)
+
+	if *hospitalID == 0 {
+		return errors.New("-hospital is required")
+	}
+
+	storage, err := storages.NewStorage(cfg.Storage)
+	if err != nil {
+		return err
+	}
+	importUseCase := _patientUseCase.NewPatientImportUseCase(_patientRepo.NewPatientImportRepository(db), storage)
+
+	var job *entities.PatientImportJob
+	if *resume != 0 {
+		job, err = importUseCase.Resume(*resume, *hospitalID)
+		if err != nil {
+			return err
+		}
+	} else {
+		if *file == "" {
+			return errors.New("-file or -resume is required")
+		}
+
+		var columns map[string]string
+		if *mapping != "" {
+			if err := json.Unmarshal([]byte(*mapping), &columns); err != nil {
+				return errors.New("-mapping must be a JSON object of patient field to column name")
+			}
+		}
+
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		job, err = importUseCase.Create(&entities.PatientImportRequest{
+			HospitalID: uint(*hospitalID),
+			FileName:   filepath.Base(*file),
+			Mapping:    columns,
+			DryRun:     *dryRun,
+			BatchSize:  *batchSize,
+			Body:       f,
+			Size:       info.Size(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("patient import %d started", job.ID)
+	job, runErr := importUseCase.Run(job, func(j *entities.PatientImportJob) {
+		log.Printf("patient import %d: %d/%d rows (%.1f%%)", j.ID, j.ProcessedRows, j.TotalRows, j.Progress)
+	})
+	if job != nil {
+		log.Printf("patient import %d %s: %d inserted, %d duplicate, %d invalid", job.ID, job.Status, job.InsertedRows, job.DuplicateRows, job.InvalidRows)
+	}
+
+	if *report != "" && job != nil {
+		out, err := os.Create(*report)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		if err := importUseCase.WriteReport(job.ID, job.HospitalID, out); err != nil {
+			return err
+		}
+		log.Printf("error report written to %s", *report)
+	}
+
+	return runErr
+}
@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AppointmentCon struct {
+	Cfg                configs.Config
+	AppointmentUsecase entities.AppointmentUseCase
+	AuthMiddleware     middlewares.AuthMiddleware
+}
+
+func NewAppointmentController(c *gin.RouterGroup, cfg configs.Config, appointmentUsecase entities.AppointmentUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &AppointmentCon{
+		Cfg:                cfg,
+		AppointmentUsecase: appointmentUsecase,
+		AuthMiddleware:     authMiddleware,
+	}
+
+	c.POST("/", controller.AuthMiddleware.JwtAuthentication(), controller.Book)
+	c.GET("/day", controller.AuthMiddleware.JwtAuthentication(), controller.FindHospitalDay)
+	c.GET("/:id", controller.AuthMiddleware.JwtAuthentication(), controller.FindById)
+	c.POST("/:id/cancel", controller.AuthMiddleware.JwtAuthentication(), controller.Cancel)
+	c.POST("/:id/reschedule", controller.AuthMiddleware.JwtAuthentication(), controller.Reschedule)
+
+	staffGroup := c.Group("/staff/:staffId")
+	staffGroup.GET("/day", controller.AuthMiddleware.JwtAuthentication(), controller.FindStaffDay)
+	staffGroup.GET("/availability", controller.AuthMiddleware.JwtAuthentication(), controller.FindAvailability)
+	staffGroup.POST("/availability", controller.AuthMiddleware.JwtAuthentication(), controller.CreateAvailability)
+	staffGroup.DELETE("/availability/:availabilityId", controller.AuthMiddleware.JwtAuthentication(), controller.DeleteAvailability)
+	staffGroup.POST("/exceptions", controller.AuthMiddleware.JwtAuthentication(), controller.CreateException)
+	staffGroup.DELETE("/exceptions/:exceptionId", controller.AuthMiddleware.JwtAuthentication(), controller.DeleteException)
+}
+
+func (a *AppointmentCon) Book(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	var req entities.AppointmentBookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	appointment, err := a.AppointmentUsecase.Book(&req, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, appointment.Version)
+	utils.OkResponse(c, appointment)
+}
+
+func (a *AppointmentCon) FindById(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	appointment, err := a.AppointmentUsecase.FindById(uint(id), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, appointment.Version)
+	utils.OkResponse(c, appointment)
+}
+
+func (a *AppointmentCon) Cancel(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.AppointmentCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	appointment, err := a.AppointmentUsecase.Cancel(uint(id), &req, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, appointment.Version)
+	utils.OkResponse(c, appointment)
+}
+
+func (a *AppointmentCon) Reschedule(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.AppointmentRescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	appointment, err := a.AppointmentUsecase.Reschedule(uint(id), &req, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, appointment.Version)
+	utils.OkResponse(c, appointment)
+}
+
+func (a *AppointmentCon) FindHospitalDay(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	hospitalDay, err := a.AppointmentUsecase.FindHospitalDay(c.Query("date"), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, hospitalDay)
+}
+
+func (a *AppointmentCon) FindStaffDay(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	staffId, err := strconv.Atoi(c.Param("staffId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "staffId is not a number")
+		return
+	}
+
+	staffDay, err := a.AppointmentUsecase.FindStaffDay(uint(staffId), c.Query("date"), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, staffDay)
+}
+
+func (a *AppointmentCon) FindAvailability(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	staffId, err := strconv.Atoi(c.Param("staffId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "staffId is not a number")
+		return
+	}
+
+	availability, err := a.AppointmentUsecase.FindAvailability(uint(staffId), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, availability)
+}
+
+func (a *AppointmentCon) CreateAvailability(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	staffId, err := strconv.Atoi(c.Param("staffId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "staffId is not a number")
+		return
+	}
+
+	var req entities.StaffAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	availability, err := a.AppointmentUsecase.CreateAvailability(&req, uint(staffId), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, availability)
+}
+
+func (a *AppointmentCon) DeleteAvailability(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	staffId, err := strconv.Atoi(c.Param("staffId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "staffId is not a number")
+		return
+	}
+
+	availabilityId, err := strconv.Atoi(c.Param("availabilityId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "availabilityId is not a number")
+		return
+	}
+
+	if err := a.AppointmentUsecase.DeleteAvailability(uint(staffId), uint(availabilityId), userData.(*entities.JwtClaim).HospitalID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, nil)
+}
+
+func (a *AppointmentCon) CreateException(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	staffId, err := strconv.Atoi(c.Param("staffId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "staffId is not a number")
+		return
+	}
+
+	var req entities.StaffAvailabilityExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	exception, err := a.AppointmentUsecase.CreateException(&req, uint(staffId), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, exception)
+}
+
+func (a *AppointmentCon) DeleteException(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	staffId, err := strconv.Atoi(c.Param("staffId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "staffId is not a number")
+		return
+	}
+
+	exceptionId, err := strconv.Atoi(c.Param("exceptionId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "exceptionId is not a number")
+		return
+	}
+
+	if err := a.AppointmentUsecase.DeleteException(uint(staffId), uint(exceptionId), userData.(*entities.JwtClaim).HospitalID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, nil)
+}
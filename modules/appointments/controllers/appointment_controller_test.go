@@ -0,0 +1,159 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/appointments/controllers"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupRouter(mockUseCase *mocks.MockAppointmentUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/appointments")
+	controllers.NewAppointmentController(group, *cfg, mockUseCase, *authMiddleware)
+	return r, cfg
+}
+
+func addAccessToken(req *http.Request, cfg *configs.Config) {
+	token, _ := utils.GenerateAccessToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1})
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+}
+
+// ----------- Tests ----------- //
+
+func TestBookAppointmentController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAppointmentUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Book", mock.AnythingOfType("*entities.AppointmentBookRequest"), uint(1), uint(9)).Return(&entities.Appointment{ID: 1, Version: 1}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/appointments/", bytes.NewBufferString(`{"patient_id":1,"staff_id":2,"start_at":"2030-01-07T09:00:00+07:00","end_at":"2030-01-07T09:30:00+07:00"}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"1"`, resp.Header().Get("ETag"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Slot Taken", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAppointmentUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Book", mock.Anything, uint(1), uint(9)).Return((*entities.Appointment)(nil), entities.ErrAppointmentConflict)
+
+		req, _ := http.NewRequest(http.MethodPost, "/appointments/", bytes.NewBufferString(`{"patient_id":1,"staff_id":2,"start_at":"2030-01-07T09:00:00+07:00","end_at":"2030-01-07T09:30:00+07:00"}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("Missing Required Fields", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAppointmentUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPost, "/appointments/", bytes.NewBufferString(`{"patient_id":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Book", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCancelAppointmentController(t *testing.T) {
+	t.Run("Not Found", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAppointmentUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Cancel", uint(5), &entities.AppointmentCancelRequest{Reason: "sick"}, uint(1), uint(9)).Return((*entities.Appointment)(nil), entities.NotFound("appointment"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/appointments/5/cancel", bytes.NewBufferString(`{"reason":"sick"}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Transition", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAppointmentUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Cancel", uint(5), mock.Anything, uint(1), uint(9)).Return((*entities.Appointment)(nil), entities.ErrInvalidTransition)
+
+		req, _ := http.NewRequest(http.MethodPost, "/appointments/5/cancel", bytes.NewBufferString(`{"reason":"sick"}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+}
+
+func TestFindStaffDayController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAppointmentUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("FindStaffDay", uint(2), "2030-01-07", uint(1)).Return(&entities.AppointmentStaffDay{StaffID: 2, Date: "2030-01-07"}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/appointments/staff/2/day?date=2030-01-07", nil)
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Date", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAppointmentUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("FindStaffDay", uint(2), "07-01-2030", uint(1)).Return((*entities.AppointmentStaffDay)(nil), entities.Invalid("date must be in YYYY-MM-DD format"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/appointments/staff/2/day?date=07-01-2030", nil)
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AppointmentRepo struct {
+	Db *gorm.DB
+}
+
+func NewAppointmentRepository(db *gorm.DB) entities.AppointmentRepository {
+	return &AppointmentRepo{Db: db}
+}
+
+func (r *AppointmentRepo) Book(appointment *entities.Appointment) (*entities.Appointment, error) {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := lockParticipants(tx, appointment); err != nil {
+			return err
+		}
+		if err := checkOverlap(tx, appointment, 0); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(appointment).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+func (r *AppointmentRepo) Reschedule(previous *entities.Appointment, next *entities.Appointment) (*entities.Appointment, error) {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := lockParticipants(tx, next); err != nil {
+			return err
+		}
+		if err := updateAppointment(tx, previous); err != nil {
+			return err
+		}
+		if err := checkOverlap(tx, next, previous.ID); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(next).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
+
+func (r *AppointmentRepo) Update(appointment *entities.Appointment) (*entities.Appointment, error) {
+	if err := updateAppointment(r.Db, appointment); err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+func (r *AppointmentRepo) FindById(id uint) (*entities.Appointment, error) {
+	var appointment entities.Appointment
+	if err := r.Db.First(&appointment, id).Error; err != nil {
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+func (r *AppointmentRepo) FindByDay(filter entities.AppointmentDayFilter) ([]entities.Appointment, error) {
+	var appointments []entities.Appointment
+
+	query := r.Db.Where("hospital_id = ?", filter.HospitalID).
+		Where("start_at < ? AND end_at > ?", filter.To, filter.From)
+
+	if filter.StaffID != 0 {
+		query = query.Where("staff_id = ?", filter.StaffID)
+	}
+
+	if err := query.Order("start_at, id").Find(&appointments).Error; err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+func (r *AppointmentRepo) CreateAvailability(availability *entities.StaffAvailability) (*entities.StaffAvailability, error) {
+	if err := r.Db.Create(&availability).Error; err != nil {
+		return nil, err
+	}
+
+	return availability, nil
+}
+
+func (r *AppointmentRepo) DeleteAvailability(id uint) error {
+	return r.Db.Delete(&entities.StaffAvailability{}, id).Error
+}
+
+func (r *AppointmentRepo) FindAvailabilityById(id uint) (*entities.StaffAvailability, error) {
+	var availability entities.StaffAvailability
+	if err := r.Db.First(&availability, id).Error; err != nil {
+		return nil, err
+	}
+	return &availability, nil
+}
+
+func (r *AppointmentRepo) FindAvailabilities(staffId uint) ([]entities.StaffAvailability, error) {
+	var availabilities []entities.StaffAvailability
+	if err := r.Db.Where("staff_id = ?", staffId).Order("weekday, start_time").Find(&availabilities).Error; err != nil {
+		return nil, err
+	}
+	return availabilities, nil
+}
+
+func (r *AppointmentRepo) CreateException(exception *entities.StaffAvailabilityException) (*entities.StaffAvailabilityException, error) {
+	if err := r.Db.Create(&exception).Error; err != nil {
+		return nil, err
+	}
+
+	return exception, nil
+}
+
+func (r *AppointmentRepo) DeleteException(id uint) error {
+	return r.Db.Delete(&entities.StaffAvailabilityException{}, id).Error
+}
+
+func (r *AppointmentRepo) FindExceptionById(id uint) (*entities.StaffAvailabilityException, error) {
+	var exception entities.StaffAvailabilityException
+	if err := r.Db.First(&exception, id).Error; err != nil {
+		return nil, err
+	}
+	return &exception, nil
+}
+
+func (r *AppointmentRepo) FindExceptions(staffId uint, fromDate string, toDate string) ([]entities.StaffAvailabilityException, error) {
+	var exceptions []entities.StaffAvailabilityException
+
+	query := r.Db.Where("staff_id = ?", staffId)
+	if fromDate != "" {
+		query = query.Where("date >= ?", fromDate)
+	}
+	if toDate != "" {
+		query = query.Where("date <= ?", toDate)
+	}
+
+	if err := query.Order("date, start_time").Find(&exceptions).Error; err != nil {
+		return nil, err
+	}
+	return exceptions, nil
+}
+
+// lockParticipants takes row locks on the staff member and the patient so
+// that concurrent bookings for either of them are checked one at a time.
+// Staff is always locked before patient to keep the lock order stable.
+func lockParticipants(tx *gorm.DB, appointment *entities.Appointment) error {
+	var staff entities.Staff
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&staff, appointment.StaffID).Error; err != nil {
+		return err
+	}
+
+	var patient entities.Patient
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&patient, appointment.PatientID).Error
+}
+
+func checkOverlap(tx *gorm.DB, appointment *entities.Appointment, excludeId uint) error {
+	var count int64
+	query := tx.Model(&entities.Appointment{}).
+		Where("status = ?", consts.AppointmentStatusBooked).
+		Where("staff_id = ? OR patient_id = ?", appointment.StaffID, appointment.PatientID).
+		Where("start_at < ? AND end_at > ?", appointment.EndAt, appointment.StartAt)
+	if excludeId != 0 {
+		query = query.Where("id <> ?", excludeId)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return entities.ErrAppointmentConflict
+	}
+	return nil
+}
+
+func updateAppointment(db *gorm.DB, appointment *entities.Appointment) error {
+	version := appointment.Version
+	appointment.Version = version + 1
+
+	result := db.Model(appointment).
+		Where("version = ?", version).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(appointment)
+	if result.Error != nil {
+		appointment.Version = version
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		appointment.Version = version
+		return entities.ErrVersionConflict
+	}
+
+	return nil
+}
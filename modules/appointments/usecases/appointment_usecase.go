@@ -0,0 +1,495 @@
+package usecases
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	clockLayout = "15:04"
+)
+
+type AppointmentUseCase struct {
+	repo            entities.AppointmentRepository
+	patientRepo     entities.PatientRepository
+	staffRepo       entities.StaffRepository
+	settingsUsecase entities.HospitalSettingsUseCase
+}
+
+func NewAppointmentUseCase(repo entities.AppointmentRepository, patientRepo entities.PatientRepository, staffRepo entities.StaffRepository, settingsUsecase entities.HospitalSettingsUseCase) entities.AppointmentUseCase {
+	return &AppointmentUseCase{repo: repo, patientRepo: patientRepo, staffRepo: staffRepo, settingsUsecase: settingsUsecase}
+}
+
+func (u *AppointmentUseCase) Book(req *entities.AppointmentBookRequest, staffHospitalId uint, bookedById uint) (*entities.Appointment, error) {
+	loc, err := u.location(staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSlot(req.StartAt, req.EndAt, loc); err != nil {
+		return nil, err
+	}
+
+	patient, err := u.patientRepo.FindById(req.PatientID)
+	if err != nil || patient == nil || patient.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("patient")
+	}
+
+	if err := u.checkStaff(req.StaffID, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkAvailable(req.StaffID, loc, req.StartAt, req.EndAt); err != nil {
+		return nil, err
+	}
+
+	appointment := &entities.Appointment{
+		HospitalID: staffHospitalId,
+		PatientID:  req.PatientID,
+		StaffID:    req.StaffID,
+		StartAt:    req.StartAt,
+		EndAt:      req.EndAt,
+		Status:     consts.AppointmentStatusBooked,
+		Reason:     req.Reason,
+		BookedByID: bookedById,
+	}
+
+	return u.repo.Book(appointment)
+}
+
+func (u *AppointmentUseCase) Cancel(id uint, req *entities.AppointmentCancelRequest, staffHospitalId uint, staffId uint) (*entities.Appointment, error) {
+	appointment, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if appointment.Status != consts.AppointmentStatusBooked {
+		return nil, entities.ErrInvalidTransition
+	}
+
+	now := time.Now()
+	appointment.Status = consts.AppointmentStatusCancelled
+	appointment.CancelReason = req.Reason
+	appointment.CancelledByID = &staffId
+	appointment.CancelledAt = &now
+
+	return u.repo.Update(appointment)
+}
+
+func (u *AppointmentUseCase) Reschedule(id uint, req *entities.AppointmentRescheduleRequest, staffHospitalId uint, staffId uint) (*entities.Appointment, error) {
+	loc, err := u.location(staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSlot(req.StartAt, req.EndAt, loc); err != nil {
+		return nil, err
+	}
+
+	previous, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous.Status != consts.AppointmentStatusBooked {
+		return nil, entities.ErrInvalidTransition
+	}
+
+	if err := u.checkAvailable(previous.StaffID, loc, req.StartAt, req.EndAt); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	previous.Status = consts.AppointmentStatusRescheduled
+	previous.CancelReason = req.Reason
+	previous.CancelledByID = &staffId
+	previous.CancelledAt = &now
+
+	next := &entities.Appointment{
+		HospitalID:        previous.HospitalID,
+		PatientID:         previous.PatientID,
+		StaffID:           previous.StaffID,
+		StartAt:           req.StartAt,
+		EndAt:             req.EndAt,
+		Status:            consts.AppointmentStatusBooked,
+		Reason:            previous.Reason,
+		RescheduledFromID: &previous.ID,
+		BookedByID:        staffId,
+	}
+
+	return u.repo.Reschedule(previous, next)
+}
+
+func (u *AppointmentUseCase) FindById(id uint, staffHospitalId uint) (*entities.Appointment, error) {
+	appointment, err := u.repo.FindById(id)
+	if err != nil || appointment == nil || appointment.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("appointment")
+	}
+
+	return appointment, nil
+}
+
+// FindHospitalDay lists the appointments of the hospital on date, a
+// "YYYY-MM-DD" calendar date in the hospital's time zone (today when empty).
+func (u *AppointmentUseCase) FindHospitalDay(date string, staffHospitalId uint) (*entities.AppointmentHospitalDay, error) {
+	day, err := u.day(date, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	appointments, err := u.repo.FindByDay(entities.AppointmentDayFilter{
+		HospitalID: staffHospitalId,
+		From:       day,
+		To:         day.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if appointments == nil {
+		appointments = []entities.Appointment{}
+	}
+
+	return &entities.AppointmentHospitalDay{
+		Date:         day.Format(dateLayout),
+		Appointments: appointments,
+	}, nil
+}
+
+// FindStaffDay reports the windows and appointments of a staff member on
+// date, counted in the hospital's time zone like FindHospitalDay.
+func (u *AppointmentUseCase) FindStaffDay(staffId uint, date string, staffHospitalId uint) (*entities.AppointmentStaffDay, error) {
+	if err := u.checkStaff(staffId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	day, err := u.day(date, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := u.availableRanges(staffId, day)
+	if err != nil {
+		return nil, err
+	}
+
+	appointments, err := u.repo.FindByDay(entities.AppointmentDayFilter{
+		HospitalID: staffHospitalId,
+		StaffID:    staffId,
+		From:       day,
+		To:         day.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if available == nil {
+		available = []entities.TimeRange{}
+	}
+	if appointments == nil {
+		appointments = []entities.Appointment{}
+	}
+
+	return &entities.AppointmentStaffDay{
+		StaffID:      staffId,
+		Date:         day.Format(dateLayout),
+		Available:    available,
+		Appointments: appointments,
+	}, nil
+}
+
+func (u *AppointmentUseCase) CreateAvailability(req *entities.StaffAvailabilityRequest, staffId uint, staffHospitalId uint) (*entities.StaffAvailability, error) {
+	if err := u.checkStaff(staffId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	if *req.Weekday < int(time.Sunday) || *req.Weekday > int(time.Saturday) {
+		return nil, entities.Invalid("weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+
+	if err := validateClock(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+
+	return u.repo.CreateAvailability(&entities.StaffAvailability{
+		StaffID:    staffId,
+		HospitalID: staffHospitalId,
+		Weekday:    *req.Weekday,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+	})
+}
+
+func (u *AppointmentUseCase) DeleteAvailability(staffId uint, availabilityId uint, staffHospitalId uint) error {
+	if err := u.checkStaff(staffId, staffHospitalId); err != nil {
+		return err
+	}
+
+	availability, err := u.repo.FindAvailabilityById(availabilityId)
+	if err != nil || availability == nil || availability.StaffID != staffId {
+		return entities.NotFound("availability")
+	}
+
+	return u.repo.DeleteAvailability(availabilityId)
+}
+
+func (u *AppointmentUseCase) CreateException(req *entities.StaffAvailabilityExceptionRequest, staffId uint, staffHospitalId uint) (*entities.StaffAvailabilityException, error) {
+	if err := u.checkStaff(staffId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	if _, err := time.Parse(dateLayout, req.Date); err != nil {
+		return nil, entities.Invalid("date must be in YYYY-MM-DD format")
+	}
+
+	if req.StartTime != "" || req.EndTime != "" || req.Available {
+		if err := validateClock(req.StartTime, req.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.repo.CreateException(&entities.StaffAvailabilityException{
+		StaffID:    staffId,
+		HospitalID: staffHospitalId,
+		Date:       req.Date,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Available:  req.Available,
+		Reason:     req.Reason,
+	})
+}
+
+func (u *AppointmentUseCase) DeleteException(staffId uint, exceptionId uint, staffHospitalId uint) error {
+	if err := u.checkStaff(staffId, staffHospitalId); err != nil {
+		return err
+	}
+
+	exception, err := u.repo.FindExceptionById(exceptionId)
+	if err != nil || exception == nil || exception.StaffID != staffId {
+		return entities.NotFound("availability exception")
+	}
+
+	return u.repo.DeleteException(exceptionId)
+}
+
+func (u *AppointmentUseCase) FindAvailability(staffId uint, staffHospitalId uint) (*entities.StaffAvailabilityResponse, error) {
+	if err := u.checkStaff(staffId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	weekly, err := u.repo.FindAvailabilities(staffId)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := u.repo.FindExceptions(staffId, time.Now().Format(dateLayout), "")
+	if err != nil {
+		return nil, err
+	}
+
+	if weekly == nil {
+		weekly = []entities.StaffAvailability{}
+	}
+	if exceptions == nil {
+		exceptions = []entities.StaffAvailabilityException{}
+	}
+
+	return &entities.StaffAvailabilityResponse{Weekly: weekly, Exceptions: exceptions}, nil
+}
+
+func (u *AppointmentUseCase) checkStaff(staffId uint, staffHospitalId uint) error {
+	staff, err := u.staffRepo.FindById(staffId)
+	if err != nil || staff == nil || staff.HospitalID != staffHospitalId {
+		return entities.NotFound("staff")
+	}
+	return nil
+}
+
+// location is the time zone of the hospital. The "HH:MM" availability
+// templates are wall clock times there, whatever offset a request uses.
+func (u *AppointmentUseCase) location(hospitalId uint) (*time.Location, error) {
+	settings, err := u.settingsUsecase.Get(hospitalId)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// day is midnight of date in the hospital's time zone, or of today there when
+// date is empty. The day a list covers does not depend on the zone of the
+// server or of the client.
+func (u *AppointmentUseCase) day(date string, hospitalId uint) (time.Time, error) {
+	loc, err := u.location(hospitalId)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if date == "" {
+		return startOfDay(time.Now().In(loc)), nil
+	}
+
+	day, err := time.ParseInLocation(dateLayout, date, loc)
+	if err != nil {
+		return time.Time{}, entities.Invalid("date must be in YYYY-MM-DD format")
+	}
+	return day, nil
+}
+
+func (u *AppointmentUseCase) checkAvailable(staffId uint, loc *time.Location, startAt time.Time, endAt time.Time) error {
+	day := startOfDay(startAt.In(loc))
+
+	available, err := u.availableRanges(staffId, day)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range available {
+		if !startAt.Before(r.StartAt) && !endAt.After(r.EndAt) {
+			return nil
+		}
+	}
+
+	return entities.Conflict("staff_unavailable", "staff is not available at the requested time")
+}
+
+func (u *AppointmentUseCase) availableRanges(staffId uint, day time.Time) ([]entities.TimeRange, error) {
+	templates, err := u.repo.FindAvailabilities(staffId)
+	if err != nil {
+		return nil, err
+	}
+
+	date := day.Format(dateLayout)
+	exceptions, err := u.repo.FindExceptions(staffId, date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAvailability(day, templates, exceptions), nil
+}
+
+// buildAvailability resolves the weekly templates and the exceptions of one
+// day into the sorted, non-overlapping windows the staff member can be booked.
+func buildAvailability(day time.Time, templates []entities.StaffAvailability, exceptions []entities.StaffAvailabilityException) []entities.TimeRange {
+	var ranges []entities.TimeRange
+
+	for _, t := range templates {
+		if t.Weekday == int(day.Weekday()) {
+			ranges = append(ranges, clockRange(day, t.StartTime, t.EndTime))
+		}
+	}
+	for _, e := range exceptions {
+		if e.Available {
+			ranges = append(ranges, clockRange(day, e.StartTime, e.EndTime))
+		}
+	}
+
+	ranges = mergeRanges(ranges)
+
+	for _, e := range exceptions {
+		if e.Available {
+			continue
+		}
+		if e.StartTime == "" {
+			return nil
+		}
+		ranges = subtractRange(ranges, clockRange(day, e.StartTime, e.EndTime))
+	}
+
+	return ranges
+}
+
+func mergeRanges(ranges []entities.TimeRange) []entities.TimeRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].StartAt.Before(ranges[j].StartAt)
+	})
+
+	merged := []entities.TimeRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if !r.StartAt.After(last.EndAt) {
+			if r.EndAt.After(last.EndAt) {
+				last.EndAt = r.EndAt
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+func subtractRange(ranges []entities.TimeRange, blocked entities.TimeRange) []entities.TimeRange {
+	var result []entities.TimeRange
+	for _, r := range ranges {
+		if !blocked.StartAt.Before(r.EndAt) || !blocked.EndAt.After(r.StartAt) {
+			result = append(result, r)
+			continue
+		}
+		if r.StartAt.Before(blocked.StartAt) {
+			result = append(result, entities.TimeRange{StartAt: r.StartAt, EndAt: blocked.StartAt})
+		}
+		if blocked.EndAt.Before(r.EndAt) {
+			result = append(result, entities.TimeRange{StartAt: blocked.EndAt, EndAt: r.EndAt})
+		}
+	}
+	return result
+}
+
+func clockRange(day time.Time, start string, end string) entities.TimeRange {
+	return entities.TimeRange{StartAt: atClock(day, start), EndAt: atClock(day, end)}
+}
+
+func atClock(day time.Time, clock string) time.Time {
+	t, _ := time.Parse(clockLayout, clock)
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location())
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func validateSlot(startAt time.Time, endAt time.Time, loc *time.Location) error {
+	if !endAt.After(startAt) {
+		return entities.Invalid("end_at must be after start_at")
+	}
+
+	if startAt.Before(time.Now()) {
+		return entities.Invalid("start_at must be in the future")
+	}
+
+	if endAt.After(startOfDay(startAt.In(loc)).AddDate(0, 0, 1)) {
+		return entities.Invalid("an appointment must start and end on the same day")
+	}
+
+	return nil
+}
+
+func validateClock(start string, end string) error {
+	startTime, err := time.Parse(clockLayout, start)
+	if err != nil {
+		return entities.Invalid("start_time must be in HH:MM format")
+	}
+
+	endTime, err := time.Parse(clockLayout, end)
+	if err != nil {
+		return entities.Invalid("end_time must be in HH:MM format")
+	}
+
+	if !endTime.After(startTime) {
+		return entities.Invalid("end_time must be after start_time")
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+package usecases_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/appointments/usecases"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// bangkok is the time zone of the default hospital settings.
+var bangkok, _ = time.LoadLocation("Asia/Bangkok")
+
+func newUseCase() (entities.AppointmentUseCase, *mocks.MockAppointmentRepository, *mocks.MockPatientRepository, *mocks.MockStaffRepository) {
+	mockRepo := mocks.NewMockAppointmentRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockStaffRepo := mocks.NewMockStaffRepository()
+	mockSettings := mocks.NewMockHospitalSettingsUseCase()
+	mockSettings.On("Get", mock.Anything).Return(entities.DefaultHospitalSettings(1), nil)
+	return usecases.NewAppointmentUseCase(mockRepo, mockPatientRepo, mockStaffRepo, mockSettings), mockRepo, mockPatientRepo, mockStaffRepo
+}
+
+// nextWeek returns midnight in the hospital's time zone a week from today so
+// booked slots are always in the future.
+func nextWeek() time.Time {
+	now := time.Now().In(bangkok).AddDate(0, 0, 7)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, bangkok)
+}
+
+func at(day time.Time, hour int, minute int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func weeklyTemplate(day time.Time) []entities.StaffAvailability {
+	return []entities.StaffAvailability{
+		{ID: 1, StaffID: 2, Weekday: int(day.Weekday()), StartTime: "09:00", EndTime: "12:00"},
+		{ID: 2, StaffID: 2, Weekday: int(day.Weekday()), StartTime: "13:00", EndTime: "16:00"},
+	}
+}
+
+// ---------- TEST CASES ---------- //
+
+func TestBookAppointment(t *testing.T) {
+	day := nextWeek()
+	date := day.Format("2006-01-02")
+
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+		mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{}, nil)
+		mockRepo.On("Book", mock.Anything).Return(&entities.Appointment{ID: 1}, nil)
+
+		_, err := usecase.Book(&entities.AppointmentBookRequest{PatientID: 1, StaffID: 2, StartAt: at(day, 9, 30), EndAt: at(day, 10, 0)}, 1, 9)
+		assert.NoError(t, err)
+
+		booked := mockRepo.Calls[2].Arguments.Get(0).(*entities.Appointment)
+		assert.Equal(t, consts.AppointmentStatusBooked, booked.Status)
+		assert.Equal(t, uint(9), booked.BookedByID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Outside Availability", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+		mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{}, nil)
+
+		_, err := usecase.Book(&entities.AppointmentBookRequest{PatientID: 1, StaffID: 2, StartAt: at(day, 11, 30), EndAt: at(day, 13, 30)}, 1, 9)
+		assert.EqualError(t, err, "staff is not available at the requested time")
+		mockRepo.AssertNotCalled(t, "Book", mock.Anything)
+	})
+
+	t.Run("Reads Availability In The Hospital Time Zone", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			startAt time.Time
+			ok      bool
+		}{
+			{"09:30 Bangkok Sent As Bangkok Time", at(day, 9, 30), true},
+			{"09:30 Bangkok Sent As UTC", at(day, 9, 30).UTC(), true},
+			{"09:30 UTC Is After Hours In Bangkok", time.Date(day.Year(), day.Month(), day.Day(), 9, 30, 0, 0, time.UTC), false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+				mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+				mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+				mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+				mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{}, nil)
+				mockRepo.On("Book", mock.Anything).Return(&entities.Appointment{ID: 1}, nil)
+
+				_, err := usecase.Book(&entities.AppointmentBookRequest{PatientID: 1, StaffID: 2, StartAt: tt.startAt, EndAt: tt.startAt.Add(30 * time.Minute)}, 1, 9)
+				if tt.ok {
+					assert.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, "staff is not available at the requested time")
+				}
+			})
+		}
+	})
+
+	t.Run("Blocked By Partial Exception", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+		mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{
+			{StaffID: 2, Date: date, StartTime: "10:00", EndTime: "11:00"},
+		}, nil)
+
+		_, err := usecase.Book(&entities.AppointmentBookRequest{PatientID: 1, StaffID: 2, StartAt: at(day, 10, 30), EndAt: at(day, 11, 0)}, 1, 9)
+		assert.EqualError(t, err, "staff is not available at the requested time")
+	})
+
+	t.Run("Extra Window From Exception", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+		mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{
+			{StaffID: 2, Date: date, StartTime: "12:00", EndTime: "13:00", Available: true},
+		}, nil)
+		mockRepo.On("Book", mock.Anything).Return(&entities.Appointment{ID: 1}, nil)
+
+		_, err := usecase.Book(&entities.AppointmentBookRequest{PatientID: 1, StaffID: 2, StartAt: at(day, 11, 30), EndAt: at(day, 13, 30)}, 1, 9)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Overlapping Appointment", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+		mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{}, nil)
+		mockRepo.On("Book", mock.Anything).Return((*entities.Appointment)(nil), entities.ErrAppointmentConflict)
+
+		_, err := usecase.Book(&entities.AppointmentBookRequest{PatientID: 1, StaffID: 2, StartAt: at(day, 9, 0), EndAt: at(day, 9, 30)}, 1, 9)
+		assert.ErrorIs(t, err, entities.ErrAppointmentConflict)
+	})
+
+	t.Run("End Before Start", func(t *testing.T) {
+		usecase, _, mockPatientRepo, _ := newUseCase()
+
+		_, err := usecase.Book(&entities.AppointmentBookRequest{PatientID: 1, StaffID: 2, StartAt: at(day, 10, 0), EndAt: at(day, 9, 0)}, 1, 9)
+		assert.EqualError(t, err, "end_at must be after start_at")
+		mockPatientRepo.AssertNotCalled(t, "FindById", mock.Anything)
+	})
+
+	t.Run("Patient Of Another Hospital", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 2}, nil)
+
+		_, err := usecase.Book(&entities.AppointmentBookRequest{PatientID: 1, StaffID: 2, StartAt: at(day, 9, 0), EndAt: at(day, 9, 30)}, 1, 9)
+		assert.EqualError(t, err, "patient not found")
+		mockRepo.AssertNotCalled(t, "Book", mock.Anything)
+	})
+}
+
+func TestCancelAppointment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Appointment{ID: 1, HospitalID: 1, Status: consts.AppointmentStatusBooked}, nil)
+		mockRepo.On("Update", mock.Anything).Return(&entities.Appointment{ID: 1}, nil)
+
+		_, err := usecase.Cancel(1, &entities.AppointmentCancelRequest{Reason: "patient request"}, 1, 9)
+		assert.NoError(t, err)
+
+		updated := mockRepo.Calls[1].Arguments.Get(0).(*entities.Appointment)
+		assert.Equal(t, consts.AppointmentStatusCancelled, updated.Status)
+		assert.Equal(t, uint(9), *updated.CancelledByID)
+		assert.NotNil(t, updated.CancelledAt)
+	})
+
+	t.Run("Already Cancelled", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Appointment{ID: 1, HospitalID: 1, Status: consts.AppointmentStatusCancelled}, nil)
+
+		_, err := usecase.Cancel(1, &entities.AppointmentCancelRequest{Reason: "again"}, 1, 9)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Another Hospital", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Appointment{ID: 1, HospitalID: 2, Status: consts.AppointmentStatusBooked}, nil)
+
+		_, err := usecase.Cancel(1, &entities.AppointmentCancelRequest{Reason: "x"}, 1, 9)
+		assert.EqualError(t, err, "appointment not found")
+	})
+}
+
+func TestRescheduleAppointment(t *testing.T) {
+	day := nextWeek()
+	date := day.Format("2006-01-02")
+
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Appointment{ID: 1, HospitalID: 1, PatientID: 1, StaffID: 2, Status: consts.AppointmentStatusBooked, Reason: "follow up"}, nil)
+		mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+		mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{}, nil)
+		mockRepo.On("Reschedule", mock.Anything, mock.Anything).Return(&entities.Appointment{ID: 2}, nil)
+
+		_, err := usecase.Reschedule(1, &entities.AppointmentRescheduleRequest{StartAt: at(day, 14, 0), EndAt: at(day, 14, 30), Reason: "doctor busy"}, 1, 9)
+		assert.NoError(t, err)
+
+		previous := mockRepo.Calls[3].Arguments.Get(0).(*entities.Appointment)
+		next := mockRepo.Calls[3].Arguments.Get(1).(*entities.Appointment)
+		assert.Equal(t, consts.AppointmentStatusRescheduled, previous.Status)
+		assert.Equal(t, consts.AppointmentStatusBooked, next.Status)
+		assert.Equal(t, uint(1), *next.RescheduledFromID)
+		assert.Equal(t, "follow up", next.Reason)
+	})
+
+	t.Run("Not Booked", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Appointment{ID: 1, HospitalID: 1, Status: consts.AppointmentStatusCompleted}, nil)
+
+		_, err := usecase.Reschedule(1, &entities.AppointmentRescheduleRequest{StartAt: at(day, 14, 0), EndAt: at(day, 14, 30), Reason: "x"}, 1, 9)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+	})
+}
+
+func TestFindStaffDay(t *testing.T) {
+	day := nextWeek()
+	date := day.Format("2006-01-02")
+
+	t.Run("Whole Day Blocked", func(t *testing.T) {
+		usecase, mockRepo, _, mockStaffRepo := newUseCase()
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+		mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{
+			{StaffID: 2, Date: date, Reason: "leave"},
+		}, nil)
+		mockRepo.On("FindByDay", mock.Anything).Return([]entities.Appointment{}, nil)
+
+		result, err := usecase.FindStaffDay(2, date, 1)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Available)
+		assert.Equal(t, date, result.Date)
+	})
+
+	t.Run("Split Windows", func(t *testing.T) {
+		usecase, mockRepo, _, mockStaffRepo := newUseCase()
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("FindAvailabilities", uint(2)).Return(weeklyTemplate(day), nil)
+		mockRepo.On("FindExceptions", uint(2), date, date).Return([]entities.StaffAvailabilityException{
+			{StaffID: 2, Date: date, StartTime: "10:00", EndTime: "11:00"},
+		}, nil)
+		mockRepo.On("FindByDay", mock.Anything).Return([]entities.Appointment{}, nil)
+
+		result, err := usecase.FindStaffDay(2, date, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []entities.TimeRange{
+			{StartAt: at(day, 9, 0), EndAt: at(day, 10, 0)},
+			{StartAt: at(day, 11, 0), EndAt: at(day, 12, 0)},
+			{StartAt: at(day, 13, 0), EndAt: at(day, 16, 0)},
+		}, result.Available)
+	})
+}
+
+func TestFindHospitalDay(t *testing.T) {
+	t.Run("Day In Hospital Time Zone", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		from := time.Date(2030, 1, 7, 0, 0, 0, 0, bangkok)
+		mockRepo.On("FindByDay", entities.AppointmentDayFilter{HospitalID: 1, From: from, To: from.AddDate(0, 0, 1)}).Return([]entities.Appointment(nil), nil)
+
+		result, err := usecase.FindHospitalDay("2030-01-07", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "2030-01-07", result.Date)
+		assert.NotNil(t, result.Appointments)
+	})
+
+	t.Run("Today When Empty", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindByDay", mock.Anything).Return([]entities.Appointment{}, nil)
+
+		result, err := usecase.FindHospitalDay("", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Now().In(bangkok).Format("2006-01-02"), result.Date)
+	})
+
+	t.Run("Invalid Date", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+
+		_, err := usecase.FindHospitalDay("07-01-2030", 1)
+		assert.ErrorIs(t, err, entities.ErrValidation)
+		mockRepo.AssertNotCalled(t, "FindByDay", mock.Anything)
+	})
+}
+
+func TestCreateAvailability(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, _, mockStaffRepo := newUseCase()
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("CreateAvailability", mock.Anything).Return(&entities.StaffAvailability{ID: 1}, nil)
+
+		weekday := 1
+		_, err := usecase.CreateAvailability(&entities.StaffAvailabilityRequest{Weekday: &weekday, StartTime: "08:00", EndTime: "12:00"}, 2, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid Weekday", func(t *testing.T) {
+		usecase, mockRepo, _, mockStaffRepo := newUseCase()
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+
+		weekday := 7
+		_, err := usecase.CreateAvailability(&entities.StaffAvailabilityRequest{Weekday: &weekday, StartTime: "08:00", EndTime: "12:00"}, 2, 1)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "CreateAvailability", mock.Anything)
+	})
+
+	t.Run("End Before Start", func(t *testing.T) {
+		usecase, _, _, mockStaffRepo := newUseCase()
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+
+		weekday := 1
+		_, err := usecase.CreateAvailability(&entities.StaffAvailabilityRequest{Weekday: &weekday, StartTime: "12:00", EndTime: "08:00"}, 2, 1)
+		assert.EqualError(t, err, "end_time must be after start_time")
+	})
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogCon struct {
+	Cfg             configs.Config
+	AuditLogUsecase entities.AuditLogUseCase
+	AuthMiddleware  middlewares.AuthMiddleware
+}
+
+func NewAuditLogController(c *gin.RouterGroup, cfg configs.Config, auditLogUsecase entities.AuditLogUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &AuditLogCon{
+		Cfg:             cfg,
+		AuditLogUsecase: auditLogUsecase,
+		AuthMiddleware:  authMiddleware,
+	}
+
+	c.GET("", controller.AuthMiddleware.JwtAuthentication(), controller.AuthMiddleware.RequireScope(consts.ScopeAuditRead), controller.FindAll)
+}
+
+func (a *AuditLogCon) FindAll(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	var filter entities.AuditLogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+	filter.HospitalID = userData.(*entities.JwtClaim).HospitalID
+
+	page, limit, err := query.ParsePage(c.Request.URL.Query(), 20, 0)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	logs, total, err := a.AuditLogUsecase.FindAll(filter, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(logs) == 0 {
+		logs = []entities.AuditLog{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"audit_logs": logs,
+		"meta": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"page_total": total,
+		},
+	})
+}
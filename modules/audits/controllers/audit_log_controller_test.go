@@ -0,0 +1,84 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/audits/controllers"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupRouter(mockUseCase *mocks.MockAuditLogUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/audits")
+	controllers.NewAuditLogController(group, *cfg, mockUseCase, *authMiddleware)
+	return r, cfg
+}
+
+func addAccessToken(req *http.Request, cfg *configs.Config, role consts.StaffRole) {
+	token, _ := utils.GenerateAccessToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1, Role: string(role), Scopes: role.Scopes()})
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+}
+
+// ----------- Tests ----------- //
+
+func TestFindAllAuditLogController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		filter := entities.AuditLogFilter{HospitalID: 1, Action: consts.AuditActionRevealPII, ResourceID: 4}
+		mockUseCase.On("FindAll", filter, 1, 20).Return([]entities.AuditLog{{ID: 1}}, 1, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/audits?action=pii.reveal&resource_id=4", nil)
+		addAccessToken(req, cfg, consts.StaffRoleAuditor)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Missing Scope", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/audits", nil)
+		addAccessToken(req, cfg, consts.StaffRoleClerk)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockUseCase.AssertNotCalled(t, "FindAll")
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockUseCase := mocks.NewMockAuditLogUseCase()
+		r, _ := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/audits", nil)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepo struct {
+	Db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) entities.AuditLogRepository {
+	return &AuditLogRepo{Db: db}
+}
+
+func (r *AuditLogRepo) Create(logs []entities.AuditLog) error {
+	return r.Db.CreateInBatches(logs, 100).Error
+}
+
+func (r *AuditLogRepo) FindAll(filter entities.AuditLogFilter, page int, limit int) ([]entities.AuditLog, int, error) {
+	var logs []entities.AuditLog
+	var totalCount int64
+
+	query := r.Db.Model(&entities.AuditLog{}).Where("hospital_id = ?", filter.HospitalID)
+
+	if filter.StaffID != 0 {
+		query = query.Where("staff_id = ?", filter.StaffID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != 0 {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, int(totalCount), nil
+}
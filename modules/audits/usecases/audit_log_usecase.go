@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"errors"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+)
+
+type AuditLogUseCase struct {
+	repo entities.AuditLogRepository
+}
+
+func NewAuditLogUseCase(repo entities.AuditLogRepository) entities.AuditLogUseCase {
+	return &AuditLogUseCase{repo: repo}
+}
+
+func (u *AuditLogUseCase) Record(logs ...entities.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	for _, log := range logs {
+		if log.HospitalID == 0 || log.StaffID == 0 || log.Action == "" {
+			return errors.New("audit log requires hospital, staff and action")
+		}
+	}
+
+	return u.repo.Create(logs)
+}
+
+func (u *AuditLogUseCase) FindAll(filter entities.AuditLogFilter, page int, limit int) ([]entities.AuditLog, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return u.repo.FindAll(filter, page, limit)
+}
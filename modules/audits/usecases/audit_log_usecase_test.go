@@ -0,0 +1,61 @@
+package usecases_test
+
+import (
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/audits/usecases"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+// ---------- TEST CASES ---------- //
+
+func TestRecordAuditLog(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewMockAuditLogRepository()
+		usecase := usecases.NewAuditLogUseCase(mockRepo)
+		log := entities.AuditLog{HospitalID: 1, StaffID: 2, Action: consts.AuditActionRevealPII, ResourceType: consts.AuditResourcePatient, ResourceID: 3}
+
+		mockRepo.On("Create", []entities.AuditLog{log}).Return(nil)
+
+		err := usecase.Record(log)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Nothing to record", func(t *testing.T) {
+		mockRepo := mocks.NewMockAuditLogRepository()
+		usecase := usecases.NewAuditLogUseCase(mockRepo)
+
+		err := usecase.Record()
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Missing staff", func(t *testing.T) {
+		mockRepo := mocks.NewMockAuditLogRepository()
+		usecase := usecases.NewAuditLogUseCase(mockRepo)
+
+		err := usecase.Record(entities.AuditLog{HospitalID: 1, Action: consts.AuditActionRevealPII})
+		assert.EqualError(t, err, "audit log requires hospital, staff and action")
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestFindAllAuditLogs(t *testing.T) {
+	t.Run("Clamps paging", func(t *testing.T) {
+		mockRepo := mocks.NewMockAuditLogRepository()
+		usecase := usecases.NewAuditLogUseCase(mockRepo)
+		filter := entities.AuditLogFilter{HospitalID: 1}
+
+		mockRepo.On("FindAll", filter, 1, 20).Return([]entities.AuditLog{{ID: 1}}, 1, nil)
+
+		logs, total, err := usecase.FindAll(filter, 0, 500)
+		assert.NoError(t, err)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, 1, total)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+type BedCon struct {
+	Cfg            configs.Config
+	BedUsecase     entities.BedUseCase
+	AuthMiddleware middlewares.AuthMiddleware
+}
+
+func NewBedController(c *gin.RouterGroup, cfg configs.Config, bedUsecase entities.BedUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &BedCon{
+		Cfg:            cfg,
+		BedUsecase:     bedUsecase,
+		AuthMiddleware: authMiddleware,
+	}
+
+	c.GET("/occupancy", controller.AuthMiddleware.JwtAuthentication(), controller.FindOccupancy)
+	c.GET("/wards", controller.AuthMiddleware.JwtAuthentication(), controller.FindWards)
+	c.POST("/wards", controller.AuthMiddleware.JwtAuthentication(), controller.CreateWard)
+	c.GET("/wards/:wardId", controller.AuthMiddleware.JwtAuthentication(), controller.FindWardById)
+	c.POST("/wards/:wardId/beds", controller.AuthMiddleware.JwtAuthentication(), controller.CreateBed)
+	c.PUT("/:id/status", controller.AuthMiddleware.JwtAuthentication(), controller.UpdateBedStatus)
+	c.POST("/:id/assign", controller.AuthMiddleware.JwtAuthentication(), controller.Assign)
+	c.GET("/encounters/:encounterId", controller.AuthMiddleware.JwtAuthentication(), controller.FindAssignments)
+	c.POST("/encounters/:encounterId/release", controller.AuthMiddleware.JwtAuthentication(), controller.Release)
+}
+
+func (a *BedCon) FindOccupancy(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	occupancy, err := a.BedUsecase.FindOccupancy(userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(occupancy) == 0 {
+		occupancy = []entities.WardOccupancy{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"wards": occupancy,
+	})
+}
+
+func (a *BedCon) FindWards(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	wards, err := a.BedUsecase.FindWards(userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(wards) == 0 {
+		wards = []entities.Ward{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"wards": wards,
+	})
+}
+
+func (a *BedCon) CreateWard(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	var req entities.WardCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	ward, err := a.BedUsecase.CreateWard(&req, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, ward)
+}
+
+func (a *BedCon) FindWardById(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	wardId, err := strconv.Atoi(c.Param("wardId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "wardId is not a number")
+		return
+	}
+
+	ward, err := a.BedUsecase.FindWardById(uint(wardId), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, ward)
+}
+
+func (a *BedCon) CreateBed(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	wardId, err := strconv.Atoi(c.Param("wardId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "wardId is not a number")
+		return
+	}
+
+	var req entities.BedCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	bed, err := a.BedUsecase.CreateBed(uint(wardId), &req, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, bed)
+}
+
+func (a *BedCon) UpdateBedStatus(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.BedStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	bed, err := a.BedUsecase.UpdateBedStatus(uint(id), &req, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, bed)
+}
+
+func (a *BedCon) Assign(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.BedAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	assignment, err := a.BedUsecase.Assign(uint(id), &req, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, assignment)
+}
+
+func (a *BedCon) FindAssignments(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	encounterId, err := strconv.Atoi(c.Param("encounterId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "encounterId is not a number")
+		return
+	}
+
+	assignments, err := a.BedUsecase.FindAssignments(uint(encounterId), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(assignments) == 0 {
+		assignments = []entities.BedAssignment{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"assignments": assignments,
+	})
+}
+
+func (a *BedCon) Release(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	encounterId, err := strconv.Atoi(c.Param("encounterId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "encounterId is not a number")
+		return
+	}
+
+	var req entities.BedReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	assignment, err := a.BedUsecase.Release(uint(encounterId), &req, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, assignment)
+}
@@ -0,0 +1,127 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/beds/controllers"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupRouter(mockUseCase *mocks.MockBedUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/beds")
+	controllers.NewBedController(group, *cfg, mockUseCase, *authMiddleware)
+	return r, cfg
+}
+
+func addAccessToken(req *http.Request, cfg *configs.Config) {
+	token, _ := utils.GenerateAccessToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1})
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+}
+
+// ----------- Tests ----------- //
+
+func TestAssignBedController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockBedUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Assign", uint(3), &entities.BedAssignRequest{EncounterID: 5}, uint(1), uint(9)).Return(&entities.BedAssignment{ID: 1}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/beds/3/assign", bytes.NewBufferString(`{"encounter_id":5}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Bed Taken", func(t *testing.T) {
+		mockUseCase := mocks.NewMockBedUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Assign", uint(3), mock.Anything, uint(1), uint(9)).Return((*entities.BedAssignment)(nil), entities.ErrBedNotAvailable)
+
+		req, _ := http.NewRequest(http.MethodPost, "/beds/3/assign", bytes.NewBufferString(`{"encounter_id":5}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+
+	t.Run("Encounter Not Found", func(t *testing.T) {
+		mockUseCase := mocks.NewMockBedUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Assign", uint(3), mock.Anything, uint(1), uint(9)).Return((*entities.BedAssignment)(nil), entities.NotFound("encounter"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/beds/3/assign", bytes.NewBufferString(`{"encounter_id":5}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestFindOccupancyController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockBedUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("FindOccupancy", uint(1)).Return([]entities.WardOccupancy{{WardID: 1, WardName: "5A", Total: 4, Occupied: 3, OccupancyRate: 75}}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/beds/occupancy", nil)
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		wards := body["data"].(map[string]interface{})["wards"].([]interface{})
+		assert.Equal(t, float64(75), wards[0].(map[string]interface{})["occupancy_rate"])
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockUseCase := mocks.NewMockBedUseCase()
+		r, _ := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/beds/occupancy", nil)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		mockUseCase.AssertNotCalled(t, "FindOccupancy", mock.Anything)
+	})
+}
@@ -0,0 +1,240 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BedRepo struct {
+	Db *gorm.DB
+}
+
+func NewBedRepository(db *gorm.DB) entities.BedRepository {
+	return &BedRepo{Db: db}
+}
+
+func (r *BedRepo) CreateWard(ward *entities.Ward) (*entities.Ward, error) {
+	if err := r.Db.Omit(clause.Associations).Create(ward).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, entities.Conflict("ward_name_taken", "ward name already exists")
+		}
+		return nil, err
+	}
+
+	return ward, nil
+}
+
+func (r *BedRepo) FindWardById(id uint) (*entities.Ward, error) {
+	var ward entities.Ward
+	if err := r.Db.Preload("Beds", func(db *gorm.DB) *gorm.DB {
+		return db.Order("code")
+	}).First(&ward, id).Error; err != nil {
+		return nil, err
+	}
+	return &ward, nil
+}
+
+func (r *BedRepo) FindWards(hospitalId uint) ([]entities.Ward, error) {
+	var wards []entities.Ward
+	if err := r.Db.Where("hospital_id = ?", hospitalId).Order("name").Find(&wards).Error; err != nil {
+		return nil, err
+	}
+	return wards, nil
+}
+
+func (r *BedRepo) CreateBed(bed *entities.Bed) (*entities.Bed, error) {
+	if err := r.Db.Create(bed).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, entities.Conflict("bed_code_taken", "bed code already exists in this ward")
+		}
+		return nil, err
+	}
+
+	return bed, nil
+}
+
+func (r *BedRepo) FindBedById(id uint) (*entities.Bed, error) {
+	var bed entities.Bed
+	if err := r.Db.First(&bed, id).Error; err != nil {
+		return nil, err
+	}
+	return &bed, nil
+}
+
+func (r *BedRepo) UpdateBedStatus(id uint, from consts.BedStatus, to consts.BedStatus, note string) error {
+	result := r.Db.Model(&entities.Bed{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{"status": to, "note": note})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return entities.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// Assign moves the encounter into the bed. Claiming the bed is a conditional
+// update on its status, so of two concurrent admissions only one can flip it
+// from available to occupied; the loser gets ErrBedNotAvailable. When the
+// encounter already holds a bed, that stay is closed and its bed sent for
+// cleaning in the same transaction, which records the transfer. The
+// encounter row is locked and its status checked again here, so a discharge
+// that commits after the usecase looked cannot leave a closed encounter in a
+// bed.
+func (r *BedRepo) Assign(assignment *entities.BedAssignment) (*entities.BedAssignment, error) {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		var encounter entities.Encounter
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			First(&encounter, assignment.EncounterID).Error; err != nil {
+			return err
+		}
+		if encounter.Status != consts.EncounterStatusAdmitted {
+			return entities.ErrEncounterNotAdmitted
+		}
+
+		var current entities.BedAssignment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("encounter_id = ? AND released_at IS NULL", assignment.EncounterID).
+			Limit(1).
+			Find(&current).Error; err != nil {
+			return err
+		}
+
+		if current.ID != 0 && current.BedID == assignment.BedID {
+			return entities.Conflict("bed_already_assigned", "encounter is already assigned to this bed")
+		}
+
+		result := tx.Model(&entities.Bed{}).
+			Where("id = ? AND status = ?", assignment.BedID, consts.BedStatusAvailable).
+			Update("status", consts.BedStatusOccupied)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrBedNotAvailable
+		}
+
+		if current.ID != 0 {
+			if err := releaseAssignment(tx, &current, assignment.AssignedAt, "transferred"); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Create(assignment).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return entities.ErrBedNotAvailable
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+func (r *BedRepo) Release(encounterId uint, reason string) (*entities.BedAssignment, error) {
+	var released *entities.BedAssignment
+
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		released, err = ReleaseOpen(tx, encounterId, time.Now(), reason)
+		if err != nil {
+			return err
+		}
+		if released == nil {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
+
+// ReleaseOpen closes the open bed assignment of the encounter and sends its
+// bed for cleaning. It returns nil when the encounter holds no bed. It runs on
+// the given transaction so that the encounter repository can free the bed in
+// the same transaction that discharges or cancels the encounter.
+func ReleaseOpen(tx *gorm.DB, encounterId uint, at time.Time, reason string) (*entities.BedAssignment, error) {
+	var current entities.BedAssignment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("encounter_id = ? AND released_at IS NULL", encounterId).
+		Limit(1).
+		Find(&current).Error; err != nil {
+		return nil, err
+	}
+
+	if current.ID == 0 {
+		return nil, nil
+	}
+
+	if err := releaseAssignment(tx, &current, at, reason); err != nil {
+		return nil, err
+	}
+	return &current, nil
+}
+
+func (r *BedRepo) FindAssignments(encounterId uint) ([]entities.BedAssignment, error) {
+	var assignments []entities.BedAssignment
+	if err := r.Db.Preload("Bed").
+		Where("encounter_id = ?", encounterId).
+		Order("assigned_at, id").
+		Find(&assignments).Error; err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+func (r *BedRepo) FindOccupancy(hospitalId uint) ([]entities.WardOccupancy, error) {
+	var occupancy []entities.WardOccupancy
+
+	err := r.Db.Model(&entities.Ward{}).
+		Select(`wards.id AS ward_id,
+			wards.name AS ward_name,
+			COUNT(beds.id) AS total,
+			COUNT(beds.id) FILTER (WHERE beds.status = ?) AS available,
+			COUNT(beds.id) FILTER (WHERE beds.status = ?) AS occupied,
+			COUNT(beds.id) FILTER (WHERE beds.status = ?) AS cleaning,
+			COUNT(beds.id) FILTER (WHERE beds.status = ?) AS blocked`,
+			consts.BedStatusAvailable, consts.BedStatusOccupied, consts.BedStatusCleaning, consts.BedStatusBlocked).
+		Joins("LEFT JOIN beds ON beds.ward_id = wards.id").
+		Where("wards.hospital_id = ?", hospitalId).
+		Group("wards.id, wards.name").
+		Order("wards.name").
+		Scan(&occupancy).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return occupancy, nil
+}
+
+func releaseAssignment(tx *gorm.DB, assignment *entities.BedAssignment, at time.Time, reason string) error {
+	assignment.ReleasedAt = &at
+	assignment.ReleaseReason = reason
+
+	if err := tx.Model(assignment).Updates(map[string]interface{}{
+		"released_at":    at,
+		"release_reason": reason,
+	}).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&entities.Bed{}).
+		Where("id = ?", assignment.BedID).
+		Update("status", consts.BedStatusCleaning).Error
+}
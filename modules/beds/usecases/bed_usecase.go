@@ -0,0 +1,192 @@
+package usecases
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"gorm.io/gorm"
+)
+
+type BedUseCase struct {
+	repo          entities.BedRepository
+	encounterRepo entities.EncounterRepository
+}
+
+func NewBedUseCase(repo entities.BedRepository, encounterRepo entities.EncounterRepository) entities.BedUseCase {
+	return &BedUseCase{repo: repo, encounterRepo: encounterRepo}
+}
+
+func (u *BedUseCase) CreateWard(req *entities.WardCreateRequest, staffHospitalId uint) (*entities.Ward, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, entities.Invalid("name is required")
+	}
+
+	return u.repo.CreateWard(&entities.Ward{
+		HospitalID: staffHospitalId,
+		Name:       name,
+		Department: req.Department,
+	})
+}
+
+func (u *BedUseCase) FindWardById(id uint, staffHospitalId uint) (*entities.Ward, error) {
+	ward, err := u.repo.FindWardById(id)
+	if err != nil || ward == nil || ward.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("ward")
+	}
+
+	return ward, nil
+}
+
+func (u *BedUseCase) FindWards(staffHospitalId uint) ([]entities.Ward, error) {
+	return u.repo.FindWards(staffHospitalId)
+}
+
+func (u *BedUseCase) CreateBed(wardId uint, req *entities.BedCreateRequest, staffHospitalId uint) (*entities.Bed, error) {
+	ward, err := u.FindWardById(wardId, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	code := strings.TrimSpace(req.Code)
+	if code == "" {
+		return nil, entities.Invalid("code is required")
+	}
+
+	return u.repo.CreateBed(&entities.Bed{
+		WardID:     ward.ID,
+		HospitalID: staffHospitalId,
+		Code:       code,
+		Status:     consts.BedStatusAvailable,
+	})
+}
+
+func (u *BedUseCase) UpdateBedStatus(id uint, req *entities.BedStatusRequest, staffHospitalId uint) (*entities.Bed, error) {
+	if !req.Status.IsValid() {
+		return nil, entities.Invalid("invalid bed status")
+	}
+
+	if req.Status == consts.BedStatusOccupied {
+		return nil, entities.Invalid("beds become occupied only through an assignment")
+	}
+
+	bed, err := u.findBed(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if bed.Status == consts.BedStatusOccupied {
+		return nil, entities.Conflict("bed_occupied", "release the patient before changing an occupied bed")
+	}
+
+	if err := u.repo.UpdateBedStatus(bed.ID, bed.Status, req.Status, req.Note); err != nil {
+		return nil, err
+	}
+
+	bed.Status = req.Status
+	bed.Note = req.Note
+	return bed, nil
+}
+
+func (u *BedUseCase) Assign(bedId uint, req *entities.BedAssignRequest, staffHospitalId uint, staffId uint) (*entities.BedAssignment, error) {
+	bed, err := u.findBed(bedId, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	encounter, err := u.encounterRepo.FindById(req.EncounterID)
+	if err != nil || encounter == nil || encounter.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("encounter")
+	}
+
+	if encounter.Status != consts.EncounterStatusAdmitted {
+		return nil, entities.ErrEncounterNotAdmitted
+	}
+
+	if bed.Status != consts.BedStatusAvailable {
+		return nil, entities.ErrBedNotAvailable
+	}
+
+	assignment, err := u.repo.Assign(&entities.BedAssignment{
+		BedID:        bed.ID,
+		EncounterID:  encounter.ID,
+		PatientID:    encounter.PatientID,
+		HospitalID:   staffHospitalId,
+		AssignedByID: staffId,
+		AssignedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bed.Status = consts.BedStatusOccupied
+	assignment.Bed = bed
+	return assignment, nil
+}
+
+func (u *BedUseCase) Release(encounterId uint, req *entities.BedReleaseRequest, staffHospitalId uint) (*entities.BedAssignment, error) {
+	if _, err := u.findEncounter(encounterId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "released"
+	}
+
+	assignment, err := u.repo.Release(encounterId, reason)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.Conflict("bed_not_assigned", "encounter has no bed assigned")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+func (u *BedUseCase) FindAssignments(encounterId uint, staffHospitalId uint) ([]entities.BedAssignment, error) {
+	if _, err := u.findEncounter(encounterId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindAssignments(encounterId)
+}
+
+func (u *BedUseCase) FindOccupancy(staffHospitalId uint) ([]entities.WardOccupancy, error) {
+	occupancy, err := u.repo.FindOccupancy(staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocked beds are out of service, so the rate is measured against the
+	// beds that could actually take a patient.
+	for i := range occupancy {
+		usable := occupancy[i].Total - occupancy[i].Blocked
+		if usable > 0 {
+			occupancy[i].OccupancyRate = math.Round(float64(occupancy[i].Occupied)/float64(usable)*10000) / 100
+		}
+	}
+
+	return occupancy, nil
+}
+
+func (u *BedUseCase) findBed(id uint, staffHospitalId uint) (*entities.Bed, error) {
+	bed, err := u.repo.FindBedById(id)
+	if err != nil || bed == nil || bed.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("bed")
+	}
+	return bed, nil
+}
+
+func (u *BedUseCase) findEncounter(id uint, staffHospitalId uint) (*entities.Encounter, error) {
+	encounter, err := u.encounterRepo.FindById(id)
+	if err != nil || encounter == nil || encounter.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("encounter")
+	}
+	return encounter, nil
+}
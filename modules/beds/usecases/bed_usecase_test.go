@@ -0,0 +1,153 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/beds/usecases"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newUseCase() (entities.BedUseCase, *mocks.MockBedRepository, *mocks.MockEncounterRepository) {
+	mockRepo := mocks.NewMockBedRepository()
+	mockEncounterRepo := mocks.NewMockEncounterRepository()
+	return usecases.NewBedUseCase(mockRepo, mockEncounterRepo), mockRepo, mockEncounterRepo
+}
+
+// ---------- TEST CASES ---------- //
+
+func TestAssignBed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, mockEncounterRepo := newUseCase()
+		mockRepo.On("FindBedById", uint(3)).Return(&entities.Bed{ID: 3, HospitalID: 1, Status: consts.BedStatusAvailable}, nil)
+		mockEncounterRepo.On("FindById", uint(5)).Return(&entities.Encounter{ID: 5, HospitalID: 1, PatientID: 7, Status: consts.EncounterStatusAdmitted}, nil)
+		mockRepo.On("Assign", mock.Anything).Return(&entities.BedAssignment{ID: 1, BedID: 3, EncounterID: 5}, nil)
+
+		result, err := usecase.Assign(3, &entities.BedAssignRequest{EncounterID: 5}, 1, 9)
+		assert.NoError(t, err)
+		assert.Equal(t, consts.BedStatusOccupied, result.Bed.Status)
+
+		assigned := mockRepo.Calls[1].Arguments.Get(0).(*entities.BedAssignment)
+		assert.Equal(t, uint(7), assigned.PatientID)
+		assert.Equal(t, uint(9), assigned.AssignedByID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Encounter Not Admitted", func(t *testing.T) {
+		usecase, mockRepo, mockEncounterRepo := newUseCase()
+		mockRepo.On("FindBedById", uint(3)).Return(&entities.Bed{ID: 3, HospitalID: 1, Status: consts.BedStatusAvailable}, nil)
+		mockEncounterRepo.On("FindById", uint(5)).Return(&entities.Encounter{ID: 5, HospitalID: 1, Status: consts.EncounterStatusInProgress}, nil)
+
+		_, err := usecase.Assign(3, &entities.BedAssignRequest{EncounterID: 5}, 1, 9)
+		assert.EqualError(t, err, "only admitted encounters can be assigned a bed")
+		mockRepo.AssertNotCalled(t, "Assign", mock.Anything)
+	})
+
+	t.Run("Bed Occupied", func(t *testing.T) {
+		usecase, mockRepo, mockEncounterRepo := newUseCase()
+		mockRepo.On("FindBedById", uint(3)).Return(&entities.Bed{ID: 3, HospitalID: 1, Status: consts.BedStatusOccupied}, nil)
+		mockEncounterRepo.On("FindById", uint(5)).Return(&entities.Encounter{ID: 5, HospitalID: 1, Status: consts.EncounterStatusAdmitted}, nil)
+
+		_, err := usecase.Assign(3, &entities.BedAssignRequest{EncounterID: 5}, 1, 9)
+		assert.ErrorIs(t, err, entities.ErrBedNotAvailable)
+		mockRepo.AssertNotCalled(t, "Assign", mock.Anything)
+	})
+
+	t.Run("Lost Race", func(t *testing.T) {
+		usecase, mockRepo, mockEncounterRepo := newUseCase()
+		mockRepo.On("FindBedById", uint(3)).Return(&entities.Bed{ID: 3, HospitalID: 1, Status: consts.BedStatusAvailable}, nil)
+		mockEncounterRepo.On("FindById", uint(5)).Return(&entities.Encounter{ID: 5, HospitalID: 1, Status: consts.EncounterStatusAdmitted}, nil)
+		mockRepo.On("Assign", mock.Anything).Return((*entities.BedAssignment)(nil), entities.ErrBedNotAvailable)
+
+		_, err := usecase.Assign(3, &entities.BedAssignRequest{EncounterID: 5}, 1, 9)
+		assert.ErrorIs(t, err, entities.ErrBedNotAvailable)
+	})
+
+	t.Run("Discharged Meanwhile", func(t *testing.T) {
+		usecase, mockRepo, mockEncounterRepo := newUseCase()
+		mockRepo.On("FindBedById", uint(3)).Return(&entities.Bed{ID: 3, HospitalID: 1, Status: consts.BedStatusAvailable}, nil)
+		mockEncounterRepo.On("FindById", uint(5)).Return(&entities.Encounter{ID: 5, HospitalID: 1, Status: consts.EncounterStatusAdmitted}, nil)
+		mockRepo.On("Assign", mock.Anything).Return((*entities.BedAssignment)(nil), entities.ErrEncounterNotAdmitted)
+
+		_, err := usecase.Assign(3, &entities.BedAssignRequest{EncounterID: 5}, 1, 9)
+		assert.ErrorIs(t, err, entities.ErrEncounterNotAdmitted)
+	})
+
+	t.Run("Bed Of Another Hospital", func(t *testing.T) {
+		usecase, mockRepo, _ := newUseCase()
+		mockRepo.On("FindBedById", uint(3)).Return(&entities.Bed{ID: 3, HospitalID: 2, Status: consts.BedStatusAvailable}, nil)
+
+		_, err := usecase.Assign(3, &entities.BedAssignRequest{EncounterID: 5}, 1, 9)
+		assert.EqualError(t, err, "bed not found")
+	})
+}
+
+func TestUpdateBedStatus(t *testing.T) {
+	t.Run("Cleaning To Available", func(t *testing.T) {
+		usecase, mockRepo, _ := newUseCase()
+		mockRepo.On("FindBedById", uint(3)).Return(&entities.Bed{ID: 3, HospitalID: 1, Status: consts.BedStatusCleaning}, nil)
+		mockRepo.On("UpdateBedStatus", uint(3), consts.BedStatusCleaning, consts.BedStatusAvailable, "").Return(nil)
+
+		result, err := usecase.UpdateBedStatus(3, &entities.BedStatusRequest{Status: consts.BedStatusAvailable}, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, consts.BedStatusAvailable, result.Status)
+	})
+
+	t.Run("Occupied Bed", func(t *testing.T) {
+		usecase, mockRepo, _ := newUseCase()
+		mockRepo.On("FindBedById", uint(3)).Return(&entities.Bed{ID: 3, HospitalID: 1, Status: consts.BedStatusOccupied}, nil)
+
+		_, err := usecase.UpdateBedStatus(3, &entities.BedStatusRequest{Status: consts.BedStatusBlocked}, 1)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "UpdateBedStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cannot Set Occupied", func(t *testing.T) {
+		usecase, mockRepo, _ := newUseCase()
+
+		_, err := usecase.UpdateBedStatus(3, &entities.BedStatusRequest{Status: consts.BedStatusOccupied}, 1)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "FindBedById", mock.Anything)
+	})
+}
+
+func TestReleaseBed(t *testing.T) {
+	t.Run("No Bed Assigned", func(t *testing.T) {
+		usecase, mockRepo, mockEncounterRepo := newUseCase()
+		mockEncounterRepo.On("FindById", uint(5)).Return(&entities.Encounter{ID: 5, HospitalID: 1}, nil)
+		mockRepo.On("Release", uint(5), "released").Return((*entities.BedAssignment)(nil), gorm.ErrRecordNotFound)
+
+		_, err := usecase.Release(5, &entities.BedReleaseRequest{}, 1)
+		assert.EqualError(t, err, "encounter has no bed assigned")
+	})
+
+	t.Run("Repository Failure", func(t *testing.T) {
+		usecase, mockRepo, mockEncounterRepo := newUseCase()
+		mockEncounterRepo.On("FindById", uint(5)).Return(&entities.Encounter{ID: 5, HospitalID: 1}, nil)
+		dbErr := errors.New("connection reset")
+		mockRepo.On("Release", uint(5), "released").Return((*entities.BedAssignment)(nil), dbErr)
+
+		_, err := usecase.Release(5, &entities.BedReleaseRequest{}, 1)
+		assert.Equal(t, dbErr, err)
+	})
+}
+
+func TestFindOccupancy(t *testing.T) {
+	t.Run("Rate Excludes Blocked Beds", func(t *testing.T) {
+		usecase, mockRepo, _ := newUseCase()
+		mockRepo.On("FindOccupancy", uint(1)).Return([]entities.WardOccupancy{
+			{WardID: 1, WardName: "5A", Total: 10, Occupied: 6, Available: 2, Blocked: 2},
+			{WardID: 2, WardName: "ICU", Total: 0},
+		}, nil)
+
+		result, err := usecase.FindOccupancy(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 75.0, result[0].OccupancyRate)
+		assert.Equal(t, 0.0, result[1].OccupancyRate)
+	})
+}
@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+type DepartmentCon struct {
+	Cfg               configs.Config
+	DepartmentUsecase entities.DepartmentUseCase
+	AuthMiddleware    middlewares.AuthMiddleware
+}
+
+// NewDepartmentController registers the routes on a group mounted at
+// /hospitals/:id/departments. Staff see the departments of their own
+// hospital; system administrators see every hospital's.
+func NewDepartmentController(c *gin.RouterGroup, cfg configs.Config, departmentUsecase entities.DepartmentUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &DepartmentCon{
+		Cfg:               cfg,
+		DepartmentUsecase: departmentUsecase,
+		AuthMiddleware:    authMiddleware,
+	}
+
+	c.Use(controller.AuthMiddleware.JwtAuthentication(), controller.AuthMiddleware.RequireHospital("id"))
+	manage := controller.AuthMiddleware.RequireScope(consts.ScopeDepartmentManage)
+
+	c.GET("/", controller.FindTree)
+	c.POST("/", manage, controller.Create)
+	c.GET("/:departmentId", controller.FindById)
+	c.PUT("/:departmentId", manage, controller.Update)
+	c.DELETE("/:departmentId", manage, controller.Delete)
+	c.GET("/:departmentId/staff", controller.FindStaff)
+	c.PUT("/:departmentId/staff/:staffId", manage, controller.AssignStaff)
+	c.DELETE("/:departmentId/staff/:staffId", manage, controller.UnassignStaff)
+}
+
+func (a *DepartmentCon) FindTree(c *gin.Context) {
+	hospitalId, ok := hospitalParam(c)
+	if !ok {
+		return
+	}
+
+	departments, err := a.DepartmentUsecase.FindTree(hospitalId)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(departments) == 0 {
+		departments = []entities.Department{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"departments": departments,
+	})
+}
+
+func (a *DepartmentCon) Create(c *gin.Context) {
+	hospitalId, ok := hospitalParam(c)
+	if !ok {
+		return
+	}
+
+	var req entities.DepartmentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	department, err := a.DepartmentUsecase.Create(hospitalId, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, department.Version)
+	utils.OkResponse(c, department)
+}
+
+func (a *DepartmentCon) FindById(c *gin.Context) {
+	hospitalId, ok := hospitalParam(c)
+	if !ok {
+		return
+	}
+
+	departmentId, err := strconv.Atoi(c.Param("departmentId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "departmentId is not a number")
+		return
+	}
+
+	department, err := a.DepartmentUsecase.FindById(hospitalId, uint(departmentId))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, department.Version)
+	utils.OkResponse(c, department)
+}
+
+// Update renames or moves a department. It needs the version from the
+// department's ETag in If-Match.
+func (a *DepartmentCon) Update(c *gin.Context) {
+	hospitalId, ok := hospitalParam(c)
+	if !ok {
+		return
+	}
+
+	departmentId, err := strconv.Atoi(c.Param("departmentId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "departmentId is not a number")
+		return
+	}
+
+	var req entities.DepartmentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	version, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	department, err := a.DepartmentUsecase.Update(hospitalId, uint(departmentId), version, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, department.Version)
+	utils.OkResponse(c, department)
+}
+
+func (a *DepartmentCon) Delete(c *gin.Context) {
+	hospitalId, ok := hospitalParam(c)
+	if !ok {
+		return
+	}
+
+	departmentId, err := strconv.Atoi(c.Param("departmentId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "departmentId is not a number")
+		return
+	}
+
+	if err := a.DepartmentUsecase.Delete(hospitalId, uint(departmentId)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, nil)
+}
+
+func (a *DepartmentCon) FindStaff(c *gin.Context) {
+	hospitalId, ok := hospitalParam(c)
+	if !ok {
+		return
+	}
+
+	departmentId, err := strconv.Atoi(c.Param("departmentId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "departmentId is not a number")
+		return
+	}
+
+	staffs, err := a.DepartmentUsecase.FindStaff(hospitalId, uint(departmentId))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	staffResponse := []entities.StaffResponse{}
+	for _, staff := range staffs {
+		staffResponse = append(staffResponse, entities.StaffResponse{
+			ID:           staff.ID,
+			FirstNameTH:  staff.FirstNameTH,
+			MiddleNameTH: staff.MiddleNameTH,
+			LastNameTH:   staff.LastNameTH,
+			FirstNameEN:  staff.FirstNameEN,
+			MiddleNameEN: staff.MiddleNameEN,
+			LastNameEN:   staff.LastNameEN,
+			Gender:       staff.Gender,
+			Role:         staff.Role,
+			Version:      staff.Version,
+			Hospital:     &staff.Hospital,
+		})
+	}
+
+	utils.OkResponse(c, gin.H{
+		"staffs": staffResponse,
+	})
+}
+
+func (a *DepartmentCon) AssignStaff(c *gin.Context) {
+	hospitalId, ok := hospitalParam(c)
+	if !ok {
+		return
+	}
+
+	departmentId, staffId, ok := staffParams(c)
+	if !ok {
+		return
+	}
+
+	if err := a.DepartmentUsecase.AssignStaff(hospitalId, departmentId, staffId); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, nil)
+}
+
+func (a *DepartmentCon) UnassignStaff(c *gin.Context) {
+	hospitalId, ok := hospitalParam(c)
+	if !ok {
+		return
+	}
+
+	departmentId, staffId, ok := staffParams(c)
+	if !ok {
+		return
+	}
+
+	if err := a.DepartmentUsecase.UnassignStaff(hospitalId, departmentId, staffId); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, nil)
+}
+
+// hospitalParam reads the hospital id from the path. RequireHospital has
+// already checked that the caller may see it.
+func hospitalParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func staffParams(c *gin.Context) (uint, uint, bool) {
+	departmentId, err := strconv.Atoi(c.Param("departmentId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "departmentId is not a number")
+		return 0, 0, false
+	}
+
+	staffId, err := strconv.Atoi(c.Param("staffId"))
+	if err != nil {
+		utils.BadRequestResponse(c, "staffId is not a number")
+		return 0, 0, false
+	}
+
+	return uint(departmentId), uint(staffId), true
+}
@@ -0,0 +1,200 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/departments/controllers"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func testConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	return cfg
+}
+
+func setupRouter(usecase entities.DepartmentUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := testConfig()
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/hospitals/:id/departments")
+	controllers.NewDepartmentController(group, *cfg, usecase, *authMiddleware)
+	return r
+}
+
+func withRole(req *http.Request, role consts.StaffRole) *http.Request {
+	token, _ := utils.GenerateAccessToken(testConfig(), &entities.Jwtpassport{Id: 1, HospitalID: 1, Role: string(role), Scopes: role.Scopes()})
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	return req
+}
+
+// ----------- Tests ----------- //
+
+func TestFindDepartmentTreeHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+		mockUsecase.On("FindTree", uint(1)).Return([]entities.Department{{ID: 1, Name: "Main Building"}}, nil)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/departments/", nil)
+		withRole(req, consts.StaffRoleNurse)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Other Hospital", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/2/departments/", nil)
+		withRole(req, consts.StaffRoleNurse)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		mockUsecase.AssertNotCalled(t, "FindTree", mock.Anything)
+	})
+
+	t.Run("SysAdmin Sees Other Hospital", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+		mockUsecase.On("FindTree", uint(2)).Return([]entities.Department{}, nil)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/2/departments/", nil)
+		withRole(req, consts.StaffRoleSysAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestCreateDepartmentHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+		mockUsecase.On("Create", uint(1), &entities.DepartmentCreateRequest{Kind: consts.DepartmentKindBuilding, Code: "B1", Name: "Main Building"}).
+			Return(&entities.Department{ID: 1, Version: 1}, nil)
+
+		r := setupRouter(mockUsecase)
+		body := `{"kind":"building","code":"B1","name":"Main Building"}`
+		req, _ := http.NewRequest(http.MethodPost, "/hospitals/1/departments/", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"1"`, resp.Header().Get("ETag"))
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Nurse Is Forbidden", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+
+		r := setupRouter(mockUsecase)
+		body := `{"kind":"building","code":"B1","name":"Main Building"}`
+		req, _ := http.NewRequest(http.MethodPost, "/hospitals/1/departments/", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleNurse)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockUsecase.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateDepartmentHandler(t *testing.T) {
+	t.Run("Missing If-Match", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1/departments/2", bytes.NewBufferString(`{"code":"ER","name":"Emergency"}`))
+		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+	})
+
+	t.Run("Stale Version", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+		mockUsecase.On("Update", uint(1), uint(2), uint(3), mock.AnythingOfType("*entities.DepartmentUpdateRequest")).
+			Return((*entities.Department)(nil), entities.ErrVersionConflict)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1/departments/2", bytes.NewBufferString(`{"code":"ER","name":"Emergency"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"3"`)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestDepartmentStaffHandler(t *testing.T) {
+	t.Run("Assign", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+		mockUsecase.On("AssignStaff", uint(1), uint(2), uint(7)).Return(nil)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1/departments/2/staff/7", http.NoBody)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Assign Unknown Staff", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+		mockUsecase.On("AssignStaff", uint(1), uint(2), uint(7)).Return(entities.NotFound("staff"))
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1/departments/2/staff/7", http.NoBody)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("List Hides Passwords", func(t *testing.T) {
+		mockUsecase := mocks.NewMockDepartmentUseCase()
+		mockUsecase.On("FindStaff", uint(1), uint(2)).Return([]entities.Staff{{ID: 7, Username: "nurse", Password: "hash"}}, nil)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/departments/2/staff", nil)
+		withRole(req, consts.StaffRoleNurse)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.NotContains(t, resp.Body.String(), "hash")
+	})
+}
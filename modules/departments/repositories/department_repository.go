@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DepartmentRepo struct {
+	Db *gorm.DB
+}
+
+func NewDepartmentRepository(db *gorm.DB) entities.DepartmentRepository {
+	return &DepartmentRepo{Db: db}
+}
+
+// Create inserts the department and then sets its path, which needs the
+// new id, from the parent's path.
+func (r *DepartmentRepo) Create(department *entities.Department) (*entities.Department, error) {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		parentPath := "/"
+		if department.ParentID != nil {
+			var parent entities.Department
+			if err := tx.Select("path").First(&parent, *department.ParentID).Error; err != nil {
+				return err
+			}
+			parentPath = parent.Path
+		}
+
+		department.Path = parentPath
+		if err := tx.Omit(clause.Associations).Create(department).Error; err != nil {
+			return err
+		}
+
+		department.Path = fmt.Sprintf("%s%d/", parentPath, department.ID)
+		return tx.Model(department).UpdateColumn("path", department.Path).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, entities.Conflict("department_code_taken", "department code already exists")
+		}
+		return nil, err
+	}
+
+	return department, nil
+}
+
+// Update saves the department under optimistic locking. When its path has
+// changed from oldPath the paths of the whole subtree are rewritten too.
+func (r *DepartmentRepo) Update(department *entities.Department, oldPath string) (*entities.Department, error) {
+	version := department.Version
+	department.Version = version + 1
+
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(department).
+			Where("version = ?", version).
+			Select("*").
+			Omit("id", "created_at", clause.Associations).
+			Updates(department)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrVersionConflict
+		}
+
+		if department.Path == oldPath {
+			return nil
+		}
+
+		return tx.Model(&entities.Department{}).
+			Where("path LIKE ? AND id <> ?", oldPath+"%", department.ID).
+			UpdateColumn("path", gorm.Expr("? || SUBSTRING(path FROM ?)", department.Path, len(oldPath)+1)).Error
+	})
+	if err != nil {
+		department.Version = version
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, entities.Conflict("department_code_taken", "department code already exists")
+		}
+		return nil, err
+	}
+
+	return department, nil
+}
+
+func (r *DepartmentRepo) Delete(id uint) error {
+	return r.Db.Delete(&entities.Department{}, id).Error
+}
+
+func (r *DepartmentRepo) FindById(id uint) (*entities.Department, error) {
+	var department entities.Department
+	if err := r.Db.First(&department, id).Error; err != nil {
+		return nil, err
+	}
+	return &department, nil
+}
+
+func (r *DepartmentRepo) FindByHospital(hospitalId uint) ([]entities.Department, error) {
+	var departments []entities.Department
+	if err := r.Db.Where("hospital_id = ?", hospitalId).Order("name, id").Find(&departments).Error; err != nil {
+		return nil, err
+	}
+	return departments, nil
+}
+
+func (r *DepartmentRepo) CountChildren(id uint) (int64, error) {
+	var count int64
+	if err := r.Db.Model(&entities.Department{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// AssignStaff is idempotent: assigning a staff member twice is not an error.
+func (r *DepartmentRepo) AssignStaff(assignment *entities.StaffDepartment) error {
+	return r.Db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(assignment).Error
+}
+
+func (r *DepartmentRepo) UnassignStaff(departmentId uint, staffId uint) error {
+	return r.Db.Where("department_id = ? AND staff_id = ?", departmentId, staffId).Delete(&entities.StaffDepartment{}).Error
+}
+
+func (r *DepartmentRepo) FindStaff(departmentId uint) ([]entities.Staff, error) {
+	var staffs []entities.Staff
+	err := r.Db.Preload("Hospital").
+		Where("id IN (?)", r.Db.Model(&entities.StaffDepartment{}).Select("staff_id").Where("department_id = ?", departmentId)).
+		Order("id").
+		Find(&staffs).Error
+	if err != nil {
+		return nil, err
+	}
+	return staffs, nil
+}
@@ -0,0 +1,182 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+)
+
+type DepartmentUseCase struct {
+	repo      entities.DepartmentRepository
+	staffRepo entities.StaffRepository
+}
+
+func NewDepartmentUseCase(repo entities.DepartmentRepository, staffRepo entities.StaffRepository) entities.DepartmentUseCase {
+	return &DepartmentUseCase{repo: repo, staffRepo: staffRepo}
+}
+
+func (u *DepartmentUseCase) Create(hospitalId uint, req *entities.DepartmentCreateRequest) (*entities.Department, error) {
+	if !req.Kind.IsValid() {
+		return nil, entities.Invalid("kind must be building, department or unit")
+	}
+
+	code, name := strings.TrimSpace(req.Code), strings.TrimSpace(req.Name)
+	if code == "" || name == "" {
+		return nil, entities.Invalid("code and name are required")
+	}
+
+	department := &entities.Department{
+		HospitalID: hospitalId,
+		Kind:       req.Kind,
+		Code:       code,
+		Name:       name,
+	}
+
+	if req.ParentID != nil {
+		parent, err := u.FindById(hospitalId, *req.ParentID)
+		if err != nil {
+			return nil, entities.NotFound("parent department")
+		}
+		if err := checkParent(parent, department); err != nil {
+			return nil, err
+		}
+		department.ParentID = &parent.ID
+	}
+
+	return u.repo.Create(department)
+}
+
+// Update renames the department and moves it under req.ParentID. A
+// department cannot be moved below itself or one of its descendants.
+func (u *DepartmentUseCase) Update(hospitalId uint, id uint, version uint, req *entities.DepartmentUpdateRequest) (*entities.Department, error) {
+	department, err := u.FindById(hospitalId, id)
+	if err != nil {
+		return nil, err
+	}
+	if department.Version != version {
+		return nil, entities.ErrVersionConflict
+	}
+
+	code, name := strings.TrimSpace(req.Code), strings.TrimSpace(req.Name)
+	if code == "" || name == "" {
+		return nil, entities.Invalid("code and name are required")
+	}
+
+	oldPath := department.Path
+	department.Code = code
+	department.Name = name
+	department.ParentID = nil
+	department.Path = fmt.Sprintf("/%d/", department.ID)
+
+	if req.ParentID != nil {
+		parent, err := u.FindById(hospitalId, *req.ParentID)
+		if err != nil {
+			return nil, entities.NotFound("parent department")
+		}
+		if strings.HasPrefix(parent.Path, oldPath) {
+			return nil, entities.Invalid("a department cannot be moved under itself")
+		}
+		if err := checkParent(parent, department); err != nil {
+			return nil, err
+		}
+		department.ParentID = &parent.ID
+		department.Path = fmt.Sprintf("%s%d/", parent.Path, department.ID)
+	}
+
+	return u.repo.Update(department, oldPath)
+}
+
+// Delete removes a department without children. Its staff assignments go
+// with it.
+func (u *DepartmentUseCase) Delete(hospitalId uint, id uint) error {
+	if _, err := u.FindById(hospitalId, id); err != nil {
+		return err
+	}
+
+	children, err := u.repo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return entities.Conflict("department_has_children", "department still has child departments")
+	}
+
+	return u.repo.Delete(id)
+}
+
+func (u *DepartmentUseCase) FindById(hospitalId uint, id uint) (*entities.Department, error) {
+	department, err := u.repo.FindById(id)
+	if err != nil || department == nil || department.HospitalID != hospitalId {
+		return nil, entities.NotFound("department")
+	}
+
+	return department, nil
+}
+
+// FindTree returns the hospital's top level departments with their
+// descendants nested in Children.
+func (u *DepartmentUseCase) FindTree(hospitalId uint) ([]entities.Department, error) {
+	departments, err := u.repo.FindByHospital(hospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]entities.Department)
+	var roots []entities.Department
+	for _, department := range departments {
+		if department.ParentID == nil {
+			roots = append(roots, department)
+		} else {
+			children[*department.ParentID] = append(children[*department.ParentID], department)
+		}
+	}
+
+	var attach func(nodes []entities.Department) []entities.Department
+	attach = func(nodes []entities.Department) []entities.Department {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	return attach(roots), nil
+}
+
+func (u *DepartmentUseCase) AssignStaff(hospitalId uint, id uint, staffId uint) error {
+	department, err := u.FindById(hospitalId, id)
+	if err != nil {
+		return err
+	}
+
+	staff, err := u.staffRepo.FindById(staffId)
+	if err != nil || staff == nil || staff.HospitalID != hospitalId {
+		return entities.NotFound("staff")
+	}
+
+	return u.repo.AssignStaff(&entities.StaffDepartment{StaffID: staff.ID, DepartmentID: department.ID})
+}
+
+func (u *DepartmentUseCase) UnassignStaff(hospitalId uint, id uint, staffId uint) error {
+	if _, err := u.FindById(hospitalId, id); err != nil {
+		return err
+	}
+
+	return u.repo.UnassignStaff(id, staffId)
+}
+
+func (u *DepartmentUseCase) FindStaff(hospitalId uint, id uint) ([]entities.Staff, error) {
+	if _, err := u.FindById(hospitalId, id); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindStaff(id)
+}
+
+// checkParent enforces the building → department → unit order.
+func checkParent(parent *entities.Department, child *entities.Department) error {
+	if parent.Kind.Level() >= child.Kind.Level() {
+		return entities.Invalid(fmt.Sprintf("a %s cannot be placed under a %s", child.Kind, parent.Kind))
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/departments/usecases"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func TestCreateDepartment(t *testing.T) {
+	t.Run("Success Under Building", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		building := &entities.Department{ID: 1, HospitalID: 1, Kind: consts.DepartmentKindBuilding, Path: "/1/"}
+		mockRepo.On("FindById", uint(1)).Return(building, nil)
+		mockRepo.On("Create", mock.MatchedBy(func(d *entities.Department) bool {
+			return d.HospitalID == 1 && *d.ParentID == 1 && d.Code == "ER" && d.Name == "Emergency"
+		})).Return(&entities.Department{ID: 2}, nil)
+
+		_, err := usecase.Create(1, &entities.DepartmentCreateRequest{
+			ParentID: uintPtr(1), Kind: consts.DepartmentKindDepartment, Code: " ER ", Name: "Emergency",
+		})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Building Under Unit", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		unit := &entities.Department{ID: 3, HospitalID: 1, Kind: consts.DepartmentKindUnit, Path: "/1/2/3/"}
+		mockRepo.On("FindById", uint(3)).Return(unit, nil)
+
+		_, err := usecase.Create(1, &entities.DepartmentCreateRequest{
+			ParentID: uintPtr(3), Kind: consts.DepartmentKindBuilding, Code: "B2", Name: "Building 2",
+		})
+		assert.EqualError(t, err, "a building cannot be placed under a unit")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Parent In Another Hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Department{ID: 1, HospitalID: 2, Kind: consts.DepartmentKindBuilding}, nil)
+
+		_, err := usecase.Create(1, &entities.DepartmentCreateRequest{
+			ParentID: uintPtr(1), Kind: consts.DepartmentKindDepartment, Code: "ER", Name: "Emergency",
+		})
+		assert.EqualError(t, err, "parent department not found")
+	})
+
+	t.Run("Invalid Kind", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		_, err := usecase.Create(1, &entities.DepartmentCreateRequest{Kind: "floor", Code: "F1", Name: "Floor 1"})
+		assert.EqualError(t, err, "kind must be building, department or unit")
+	})
+}
+
+func TestUpdateDepartment(t *testing.T) {
+	t.Run("Move Rewrites Path", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		department := &entities.Department{ID: 2, HospitalID: 1, ParentID: uintPtr(1), Kind: consts.DepartmentKindDepartment, Path: "/1/2/", Version: 3}
+		mockRepo.On("FindById", uint(2)).Return(department, nil)
+		mockRepo.On("FindById", uint(5)).Return(&entities.Department{ID: 5, HospitalID: 1, Kind: consts.DepartmentKindBuilding, Path: "/5/"}, nil)
+		mockRepo.On("Update", mock.MatchedBy(func(d *entities.Department) bool {
+			return d.Path == "/5/2/" && *d.ParentID == 5
+		}), "/1/2/").Return(department, nil)
+
+		_, err := usecase.Update(1, 2, 3, &entities.DepartmentUpdateRequest{ParentID: uintPtr(5), Code: "ER", Name: "Emergency"})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Move Under Own Descendant", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Department{ID: 1, HospitalID: 1, Kind: consts.DepartmentKindBuilding, Path: "/1/", Version: 1}, nil)
+		mockRepo.On("FindById", uint(2)).Return(&entities.Department{ID: 2, HospitalID: 1, Kind: consts.DepartmentKindDepartment, Path: "/1/2/"}, nil)
+
+		_, err := usecase.Update(1, 1, 1, &entities.DepartmentUpdateRequest{ParentID: uintPtr(2), Code: "B1", Name: "Building 1"})
+		assert.EqualError(t, err, "a department cannot be moved under itself")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Version Conflict", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		mockRepo.On("FindById", uint(2)).Return(&entities.Department{ID: 2, HospitalID: 1, Version: 4}, nil)
+
+		_, err := usecase.Update(1, 2, 3, &entities.DepartmentUpdateRequest{Code: "ER", Name: "Emergency"})
+		assert.ErrorIs(t, err, entities.ErrVersionConflict)
+	})
+}
+
+func TestDeleteDepartment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		mockRepo.On("FindById", uint(3)).Return(&entities.Department{ID: 3, HospitalID: 1}, nil)
+		mockRepo.On("CountChildren", uint(3)).Return(int64(0), nil)
+		mockRepo.On("Delete", uint(3)).Return(nil)
+
+		assert.NoError(t, usecase.Delete(1, 3))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Has Children", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Department{ID: 1, HospitalID: 1}, nil)
+		mockRepo.On("CountChildren", uint(1)).Return(int64(2), nil)
+
+		assert.EqualError(t, usecase.Delete(1, 1), "department still has child departments")
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestFindDepartmentTree(t *testing.T) {
+	mockRepo := mocks.NewMockDepartmentRepository()
+	usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+	mockRepo.On("FindByHospital", uint(1)).Return([]entities.Department{
+		{ID: 1, HospitalID: 1, Kind: consts.DepartmentKindBuilding},
+		{ID: 2, HospitalID: 1, ParentID: uintPtr(1), Kind: consts.DepartmentKindDepartment},
+		{ID: 3, HospitalID: 1, ParentID: uintPtr(2), Kind: consts.DepartmentKindUnit},
+		{ID: 4, HospitalID: 1, Kind: consts.DepartmentKindDepartment},
+	}, nil)
+
+	tree, err := usecase.FindTree(1)
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, uint(2), tree[0].Children[0].ID)
+	assert.Equal(t, uint(3), tree[0].Children[0].Children[0].ID)
+	assert.Empty(t, tree[1].Children)
+}
+
+func TestAssignStaffToDepartment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		mockStaffRepo := mocks.NewMockStaffRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mockStaffRepo)
+
+		mockRepo.On("FindById", uint(2)).Return(&entities.Department{ID: 2, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(7)).Return(&entities.Staff{ID: 7, HospitalID: 1}, nil)
+		mockRepo.On("AssignStaff", &entities.StaffDepartment{StaffID: 7, DepartmentID: 2}).Return(nil)
+
+		assert.NoError(t, usecase.AssignStaff(1, 2, 7))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Staff Of Another Hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		mockStaffRepo := mocks.NewMockStaffRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mockStaffRepo)
+
+		mockRepo.On("FindById", uint(2)).Return(&entities.Department{ID: 2, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(7)).Return(&entities.Staff{ID: 7, HospitalID: 2}, nil)
+
+		assert.EqualError(t, usecase.AssignStaff(1, 2, 7), "staff not found")
+		mockRepo.AssertNotCalled(t, "AssignStaff", mock.Anything)
+	})
+
+	t.Run("Department Not Found", func(t *testing.T) {
+		mockRepo := mocks.NewMockDepartmentRepository()
+		usecase := usecases.NewDepartmentUseCase(mockRepo, mocks.NewMockStaffRepository())
+
+		mockRepo.On("FindById", uint(2)).Return((*entities.Department)(nil), errors.New("record not found"))
+
+		assert.EqualError(t, usecase.AssignStaff(1, 2, 7), "department not found")
+	})
+}
@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type EncounterCon struct {
+	Cfg              configs.Config
+	EncounterUsecase entities.EncounterUseCase
+	AuthMiddleware   middlewares.AuthMiddleware
+}
+
+func NewEncounterController(c *gin.RouterGroup, cfg configs.Config, encounterUsecase entities.EncounterUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &EncounterCon{
+		Cfg:              cfg,
+		EncounterUsecase: encounterUsecase,
+		AuthMiddleware:   authMiddleware,
+	}
+
+	c.GET("/", controller.AuthMiddleware.JwtAuthentication(), controller.FindAll)
+	c.GET("/:id", controller.AuthMiddleware.JwtAuthentication(), controller.FindById)
+	c.POST("/", controller.AuthMiddleware.JwtAuthentication(), controller.Create)
+	c.POST("/:id/admit", controller.AuthMiddleware.JwtAuthentication(), controller.Admit)
+	c.POST("/:id/discharge", controller.AuthMiddleware.JwtAuthentication(), controller.Discharge)
+	c.POST("/:id/transfer", controller.AuthMiddleware.JwtAuthentication(), controller.Transfer)
+	c.POST("/:id/cancel", controller.AuthMiddleware.JwtAuthentication(), controller.Cancel)
+}
+
+func (a *EncounterCon) FindAll(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	page, limit, err := query.ParsePage(c.Request.URL.Query(), 10, 0)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	filter := entities.EncounterFilter{
+		HospitalID: userData.(*entities.JwtClaim).HospitalID,
+		Type:       consts.EncounterType(c.Query("type")),
+		Status:     consts.EncounterStatus(c.Query("status")),
+	}
+
+	if patientID := c.Query("patient_id"); patientID != "" {
+		patientIDInt, err := strconv.Atoi(patientID)
+		if err != nil {
+			utils.BadRequestResponse(c, "patient_id must be an integer")
+			return
+		}
+		filter.PatientID = uint(patientIDInt)
+	}
+
+	if departmentID := c.Query("department_id"); departmentID != "" {
+		departmentIDInt, err := strconv.Atoi(departmentID)
+		if err != nil {
+			utils.BadRequestResponse(c, "department_id must be an integer")
+			return
+		}
+		filter.DepartmentID = uint(departmentIDInt)
+	}
+
+	encounters, totalPage, err := a.EncounterUsecase.FindAll(filter, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(encounters) == 0 {
+		encounters = []entities.Encounter{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"encounters": encounters,
+		"meta": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"page_total": totalPage,
+		},
+	})
+}
+
+func (a *EncounterCon) FindById(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	encounter, err := a.EncounterUsecase.FindById(uint(id), userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, encounter.Version)
+	utils.OkResponse(c, encounter)
+}
+
+func (a *EncounterCon) Create(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	var req entities.EncounterCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	encounter, err := a.EncounterUsecase.Create(&req, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, encounter.Version)
+	utils.OkResponse(c, encounter)
+}
+
+func (a *EncounterCon) Admit(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.EncounterAdmitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	encounter, err := a.EncounterUsecase.Admit(uint(id), &req, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, encounter.Version)
+	utils.OkResponse(c, encounter)
+}
+
+func (a *EncounterCon) Discharge(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.EncounterDischargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	encounter, err := a.EncounterUsecase.Discharge(uint(id), &req, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, encounter.Version)
+	utils.OkResponse(c, encounter)
+}
+
+func (a *EncounterCon) Transfer(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.EncounterTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	encounter, err := a.EncounterUsecase.Transfer(uint(id), &req, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, encounter.Version)
+	utils.OkResponse(c, encounter)
+}
+
+func (a *EncounterCon) Cancel(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.EncounterCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	encounter, err := a.EncounterUsecase.Cancel(uint(id), &req, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, encounter.Version)
+	utils.OkResponse(c, encounter)
+}
@@ -0,0 +1,168 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/encounters/controllers"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupRouter(mockUseCase *mocks.MockEncounterUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/encounters")
+	controllers.NewEncounterController(group, *cfg, mockUseCase, *authMiddleware)
+	return r, cfg
+}
+
+func addAccessToken(req *http.Request, cfg *configs.Config) {
+	token, _ := utils.GenerateAccessToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1})
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+}
+
+// ----------- Tests ----------- //
+
+func TestCreateEncounterController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockEncounterUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Create", &entities.EncounterCreateRequest{
+			PatientID:        1,
+			AttendingStaffID: 2,
+			Type:             consts.EncounterTypeEmergency,
+			Department:       "ER",
+		}, uint(1)).Return(&entities.Encounter{ID: 1, Version: 1}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/encounters/", bytes.NewBufferString(`{"patient_id":1,"attending_staff_id":2,"type":"emergency","department":"ER"}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockUseCase := mocks.NewMockEncounterUseCase()
+		r, _ := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPost, "/encounters/", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestDischargeEncounterController(t *testing.T) {
+	t.Run("Illegal Transition", func(t *testing.T) {
+		mockUseCase := mocks.NewMockEncounterUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Discharge", uint(1), mock.Anything, uint(1)).Return((*entities.Encounter)(nil), entities.ErrInvalidTransition)
+
+		req, _ := http.NewRequest(http.MethodPost, "/encounters/1/discharge", bytes.NewBufferString(`{"disposition":"home"}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockUseCase := mocks.NewMockEncounterUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Discharge", uint(1), mock.Anything, uint(1)).Return((*entities.Encounter)(nil), entities.NotFound("encounter"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/encounters/1/discharge", bytes.NewBufferString(`{"disposition":"home"}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Missing Disposition", func(t *testing.T) {
+		mockUseCase := mocks.NewMockEncounterUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPost, "/encounters/1/discharge", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Discharge")
+	})
+}
+
+func TestTransferEncounterController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockEncounterUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		mockUseCase.On("Transfer", uint(1), &entities.EncounterTransferRequest{ToWard: "ICU"}, uint(1), uint(9)).Return(&entities.Encounter{ID: 1, Ward: "ICU", Version: 3}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/encounters/1/transfer", bytes.NewBufferString(`{"to_ward":"ICU"}`))
+		req.Header.Set("Content-Type", "application/json")
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"3"`, resp.Header().Get("ETag"))
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestFindAllEncounterController(t *testing.T) {
+	t.Run("Filter By Patient", func(t *testing.T) {
+		mockUseCase := mocks.NewMockEncounterUseCase()
+		r, cfg := setupRouter(mockUseCase)
+
+		filter := entities.EncounterFilter{HospitalID: 1, PatientID: 5, Status: consts.EncounterStatusAdmitted}
+		mockUseCase.On("FindAll", filter, 1, 10).Return([]entities.Encounter{}, 0, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/encounters/?patient_id=5&status=admitted", nil)
+		addAccessToken(req, cfg)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
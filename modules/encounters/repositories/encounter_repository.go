@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"time"
+
+	beds "github.com/Teemo4621/Hospital-Api/modules/beds/repositories"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EncounterRepo struct {
+	Db *gorm.DB
+}
+
+func NewEncounterRepository(db *gorm.DB) entities.EncounterRepository {
+	return &EncounterRepo{Db: db}
+}
+
+func (r *EncounterRepo) Create(encounter *entities.Encounter) (*entities.Encounter, error) {
+	if err := r.Db.Omit(clause.Associations).Create(&encounter).Error; err != nil {
+		return nil, err
+	}
+
+	return encounter, nil
+}
+
+func (r *EncounterRepo) Update(encounter *entities.Encounter) (*entities.Encounter, error) {
+	if err := updateEncounter(r.Db, encounter); err != nil {
+		return nil, err
+	}
+
+	return encounter, nil
+}
+
+func (r *EncounterRepo) Transfer(encounter *entities.Encounter, transfer *entities.EncounterTransfer) (*entities.Encounter, error) {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := updateEncounter(tx, encounter); err != nil {
+			return err
+		}
+		return tx.Create(transfer).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encounter.Transfers = append(encounter.Transfers, *transfer)
+	return encounter, nil
+}
+
+// Close saves a discharged or cancelled encounter and, in the same
+// transaction, releases the bed it still holds so the bed goes to cleaning.
+func (r *EncounterRepo) Close(encounter *entities.Encounter, releaseReason string) (*entities.Encounter, error) {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if err := updateEncounter(tx, encounter); err != nil {
+			return err
+		}
+		_, err := beds.ReleaseOpen(tx, encounter.ID, time.Now(), releaseReason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return encounter, nil
+}
+
+func (r *EncounterRepo) FindById(id uint) (*entities.Encounter, error) {
+	var encounter entities.Encounter
+	if err := r.Db.Preload("Transfers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("transferred_at")
+	}).First(&encounter, id).Error; err != nil {
+		return nil, err
+	}
+	return &encounter, nil
+}
+
+func (r *EncounterRepo) FindAll(filter entities.EncounterFilter, page int, limit int) ([]entities.Encounter, int64, error) {
+	var encounters []entities.Encounter
+	var totalCount int64
+
+	query := r.Db.Model(&entities.Encounter{}).Where("hospital_id = ?", filter.HospitalID)
+
+	if filter.PatientID != 0 {
+		query = query.Where("patient_id = ?", filter.PatientID)
+	}
+	if filter.DepartmentID != 0 {
+		subtree := r.Db.Model(&entities.Department{}).Select("path || '%'").Where("id = ? AND hospital_id = ?", filter.DepartmentID, filter.HospitalID)
+		names := r.Db.Model(&entities.Department{}).Select("name").Where("hospital_id = ? AND path LIKE (?)", filter.HospitalID, subtree)
+		query = query.Where("department IN (?)", names)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("started_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&encounters).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return encounters, totalCount, nil
+}
+
+// updateEncounter saves the encounter only if nobody else changed it since it
+// was read, so two concurrent transitions cannot both succeed.
+func updateEncounter(db *gorm.DB, encounter *entities.Encounter) error {
+	version := encounter.Version
+	encounter.Version = version + 1
+
+	result := db.Model(encounter).
+		Where("version = ?", version).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(encounter)
+	if result.Error != nil {
+		encounter.Version = version
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		encounter.Version = version
+		return entities.ErrVersionConflict
+	}
+
+	return nil
+}
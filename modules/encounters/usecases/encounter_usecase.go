@@ -0,0 +1,199 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type EncounterUseCase struct {
+	repo        entities.EncounterRepository
+	patientRepo entities.PatientRepository
+	staffRepo   entities.StaffRepository
+}
+
+func NewEncounterUseCase(repo entities.EncounterRepository, patientRepo entities.PatientRepository, staffRepo entities.StaffRepository) entities.EncounterUseCase {
+	return &EncounterUseCase{repo: repo, patientRepo: patientRepo, staffRepo: staffRepo}
+}
+
+func (u *EncounterUseCase) Create(req *entities.EncounterCreateRequest, staffHospitalId uint) (*entities.Encounter, error) {
+	if !req.Type.IsValid() {
+		return nil, entities.Invalid("type must be outpatient, inpatient or emergency")
+	}
+
+	patient, err := u.patientRepo.FindById(req.PatientID)
+	if err != nil || patient == nil || patient.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("patient")
+	}
+
+	if err := u.checkStaff(req.AttendingStaffID, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	encounter := &entities.Encounter{
+		PatientID:        req.PatientID,
+		HospitalID:       staffHospitalId,
+		AttendingStaffID: req.AttendingStaffID,
+		Type:             req.Type,
+		Status:           consts.EncounterStatusInProgress,
+		Department:       req.Department,
+		Ward:             req.Ward,
+		ChiefComplaint:   req.ChiefComplaint,
+		StartedAt:        now,
+	}
+
+	if req.Type == consts.EncounterTypeInpatient {
+		if req.Ward == "" {
+			return nil, entities.Invalid("ward is required for an inpatient admission")
+		}
+		encounter.Status = consts.EncounterStatusAdmitted
+		encounter.AdmittedAt = &now
+	}
+
+	return u.repo.Create(encounter)
+}
+
+func (u *EncounterUseCase) Admit(id uint, req *entities.EncounterAdmitRequest, staffHospitalId uint) (*entities.Encounter, error) {
+	encounter, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !encounter.Status.CanTransitionTo(consts.EncounterStatusAdmitted) {
+		return nil, entities.ErrInvalidTransition
+	}
+
+	if req.AttendingStaffID != 0 {
+		if err := u.checkStaff(req.AttendingStaffID, staffHospitalId); err != nil {
+			return nil, err
+		}
+		encounter.AttendingStaffID = req.AttendingStaffID
+	}
+
+	now := time.Now()
+	encounter.Type = consts.EncounterTypeInpatient
+	encounter.Status = consts.EncounterStatusAdmitted
+	encounter.Ward = req.Ward
+	if req.Department != "" {
+		encounter.Department = req.Department
+	}
+	encounter.AdmittedAt = &now
+
+	return u.repo.Update(encounter)
+}
+
+func (u *EncounterUseCase) Discharge(id uint, req *entities.EncounterDischargeRequest, staffHospitalId uint) (*entities.Encounter, error) {
+	if !req.Disposition.IsValid() {
+		return nil, entities.Invalid("disposition must be home, referred, against_advice, deceased or other")
+	}
+
+	encounter, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !encounter.Status.CanTransitionTo(consts.EncounterStatusDischarged) {
+		return nil, entities.ErrInvalidTransition
+	}
+
+	now := time.Now()
+	encounter.Status = consts.EncounterStatusDischarged
+	encounter.Disposition = req.Disposition
+	encounter.DischargeNote = req.DischargeNote
+	encounter.DischargedAt = &now
+
+	return u.repo.Close(encounter, "discharged")
+}
+
+func (u *EncounterUseCase) Transfer(id uint, req *entities.EncounterTransferRequest, staffHospitalId uint, staffId uint) (*entities.Encounter, error) {
+	if req.ToDepartment == "" && req.ToWard == "" {
+		return nil, entities.Invalid("to_department or to_ward is required")
+	}
+
+	encounter, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !encounter.Status.IsActive() {
+		return nil, entities.ErrInvalidTransition
+	}
+
+	if req.ToWard != "" && encounter.Status != consts.EncounterStatusAdmitted {
+		return nil, entities.Conflict("encounter_not_admitted", "only admitted patients can be transferred between wards")
+	}
+
+	transfer := &entities.EncounterTransfer{
+		EncounterID:     encounter.ID,
+		FromDepartment:  encounter.Department,
+		FromWard:        encounter.Ward,
+		ToDepartment:    encounter.Department,
+		ToWard:          encounter.Ward,
+		Reason:          req.Reason,
+		TransferredByID: staffId,
+		TransferredAt:   time.Now(),
+	}
+	if req.ToDepartment != "" {
+		transfer.ToDepartment = req.ToDepartment
+	}
+	if req.ToWard != "" {
+		transfer.ToWard = req.ToWard
+	}
+
+	encounter.Department = transfer.ToDepartment
+	encounter.Ward = transfer.ToWard
+
+	return u.repo.Transfer(encounter, transfer)
+}
+
+func (u *EncounterUseCase) Cancel(id uint, req *entities.EncounterCancelRequest, staffHospitalId uint) (*entities.Encounter, error) {
+	encounter, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !encounter.Status.CanTransitionTo(consts.EncounterStatusCancelled) {
+		return nil, entities.ErrInvalidTransition
+	}
+
+	now := time.Now()
+	encounter.Status = consts.EncounterStatusCancelled
+	encounter.CancelReason = req.Reason
+	encounter.CancelledAt = &now
+
+	return u.repo.Close(encounter, "cancelled")
+}
+
+func (u *EncounterUseCase) FindById(id uint, staffHospitalId uint) (*entities.Encounter, error) {
+	encounter, err := u.repo.FindById(id)
+	if err != nil || encounter == nil {
+		return nil, entities.NotFound("encounter")
+	}
+
+	if encounter.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("encounter")
+	}
+
+	return encounter, nil
+}
+
+func (u *EncounterUseCase) FindAll(filter entities.EncounterFilter, page int, limit int) ([]entities.Encounter, int, error) {
+	encounters, totalCount, err := u.repo.FindAll(filter, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	totalPage := int((totalCount + int64(limit) - 1) / int64(limit))
+
+	return encounters, totalPage, nil
+}
+
+func (u *EncounterUseCase) checkStaff(staffId uint, staffHospitalId uint) error {
+	staff, err := u.staffRepo.FindById(staffId)
+	if err != nil || staff == nil || staff.HospitalID != staffHospitalId {
+		return entities.NotFound("attending staff")
+	}
+	return nil
+}
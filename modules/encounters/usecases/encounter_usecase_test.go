@@ -0,0 +1,202 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/encounters/usecases"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newUseCase() (entities.EncounterUseCase, *mocks.MockEncounterRepository, *mocks.MockPatientRepository, *mocks.MockStaffRepository) {
+	mockRepo := mocks.NewMockEncounterRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockStaffRepo := mocks.NewMockStaffRepository()
+	return usecases.NewEncounterUseCase(mockRepo, mockPatientRepo, mockStaffRepo), mockRepo, mockPatientRepo, mockStaffRepo
+}
+
+// ---------- TEST CASES ---------- //
+
+func TestCreateEncounter(t *testing.T) {
+	t.Run("Outpatient", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("Create", mock.Anything).Return(&entities.Encounter{ID: 1, Status: consts.EncounterStatusInProgress}, nil)
+
+		_, err := usecase.Create(&entities.EncounterCreateRequest{PatientID: 1, AttendingStaffID: 2, Type: consts.EncounterTypeOutpatient, Department: "OPD"}, 1)
+		assert.NoError(t, err)
+
+		created := mockRepo.Calls[0].Arguments.Get(0).(*entities.Encounter)
+		assert.Equal(t, consts.EncounterStatusInProgress, created.Status)
+		assert.Nil(t, created.AdmittedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Inpatient Is Admitted", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 1}, nil)
+		mockRepo.On("Create", mock.Anything).Return(&entities.Encounter{ID: 1}, nil)
+
+		_, err := usecase.Create(&entities.EncounterCreateRequest{PatientID: 1, AttendingStaffID: 2, Type: consts.EncounterTypeInpatient, Department: "MED", Ward: "5A"}, 1)
+		assert.NoError(t, err)
+
+		created := mockRepo.Calls[0].Arguments.Get(0).(*entities.Encounter)
+		assert.Equal(t, consts.EncounterStatusAdmitted, created.Status)
+		assert.NotNil(t, created.AdmittedAt)
+	})
+
+	t.Run("Patient Of Another Hospital", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 2}, nil)
+
+		_, err := usecase.Create(&entities.EncounterCreateRequest{PatientID: 1, AttendingStaffID: 2, Type: consts.EncounterTypeEmergency, Department: "ER"}, 1)
+		assert.EqualError(t, err, "patient not found")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Attending Staff Of Another Hospital", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockStaffRepo := newUseCase()
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockStaffRepo.On("FindById", uint(2)).Return(&entities.Staff{ID: 2, HospitalID: 3}, nil)
+
+		_, err := usecase.Create(&entities.EncounterCreateRequest{PatientID: 1, AttendingStaffID: 2, Type: consts.EncounterTypeEmergency, Department: "ER"}, 1)
+		assert.EqualError(t, err, "attending staff not found")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestEncounterTransitions(t *testing.T) {
+	t.Run("Admit Emergency", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		encounter := &entities.Encounter{ID: 1, HospitalID: 1, Type: consts.EncounterTypeEmergency, Status: consts.EncounterStatusInProgress}
+		mockRepo.On("FindById", uint(1)).Return(encounter, nil)
+		mockRepo.On("Update", encounter).Return(encounter, nil)
+
+		result, err := usecase.Admit(1, &entities.EncounterAdmitRequest{Ward: "ICU"}, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, consts.EncounterStatusAdmitted, result.Status)
+		assert.Equal(t, consts.EncounterTypeInpatient, result.Type)
+		assert.Equal(t, "ICU", result.Ward)
+	})
+
+	t.Run("Admit Discharged Is Rejected", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Encounter{ID: 1, HospitalID: 1, Status: consts.EncounterStatusDischarged}, nil)
+
+		_, err := usecase.Admit(1, &entities.EncounterAdmitRequest{Ward: "ICU"}, 1)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Discharge Admitted", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		encounter := &entities.Encounter{ID: 1, HospitalID: 1, Status: consts.EncounterStatusAdmitted}
+		mockRepo.On("FindById", uint(1)).Return(encounter, nil)
+		mockRepo.On("Close", encounter, "discharged").Return(encounter, nil)
+
+		result, err := usecase.Discharge(1, &entities.EncounterDischargeRequest{Disposition: consts.DispositionHome}, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, consts.EncounterStatusDischarged, result.Status)
+		assert.NotNil(t, result.DischargedAt)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Cancel Releases The Bed", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		encounter := &entities.Encounter{ID: 1, HospitalID: 1, Status: consts.EncounterStatusInProgress}
+		mockRepo.On("FindById", uint(1)).Return(encounter, nil)
+		mockRepo.On("Close", encounter, "cancelled").Return(encounter, nil)
+
+		result, err := usecase.Cancel(1, &entities.EncounterCancelRequest{Reason: "duplicate"}, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, consts.EncounterStatusCancelled, result.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cancel Admitted Is Rejected", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Encounter{ID: 1, HospitalID: 1, Status: consts.EncounterStatusAdmitted}, nil)
+
+		_, err := usecase.Cancel(1, &entities.EncounterCancelRequest{Reason: "duplicate"}, 1)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+	})
+
+	t.Run("Invalid Disposition", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+
+		_, err := usecase.Discharge(1, &entities.EncounterDischargeRequest{Disposition: "vanished"}, 1)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "FindById", mock.Anything)
+	})
+
+	t.Run("Encounter Of Another Hospital", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Encounter{ID: 1, HospitalID: 2, Status: consts.EncounterStatusAdmitted}, nil)
+
+		_, err := usecase.Discharge(1, &entities.EncounterDischargeRequest{Disposition: consts.DispositionHome}, 1)
+		assert.EqualError(t, err, "encounter not found")
+	})
+}
+
+func TestTransferEncounter(t *testing.T) {
+	t.Run("Ward Transfer", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		encounter := &entities.Encounter{ID: 1, HospitalID: 1, Status: consts.EncounterStatusAdmitted, Department: "MED", Ward: "5A"}
+		mockRepo.On("FindById", uint(1)).Return(encounter, nil)
+		mockRepo.On("Transfer", encounter, mock.MatchedBy(func(tr *entities.EncounterTransfer) bool {
+			return tr.FromWard == "5A" && tr.ToWard == "ICU" && tr.ToDepartment == "MED" && tr.TransferredByID == 9
+		})).Return(encounter, nil)
+
+		result, err := usecase.Transfer(1, &entities.EncounterTransferRequest{ToWard: "ICU"}, 1, 9)
+		assert.NoError(t, err)
+		assert.Equal(t, "ICU", result.Ward)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Ward Transfer Of Outpatient Is Rejected", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Encounter{ID: 1, HospitalID: 1, Status: consts.EncounterStatusInProgress}, nil)
+
+		_, err := usecase.Transfer(1, &entities.EncounterTransferRequest{ToWard: "ICU"}, 1, 9)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Transfer Of Discharged Is Rejected", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		mockRepo.On("FindById", uint(1)).Return(&entities.Encounter{ID: 1, HospitalID: 1, Status: consts.EncounterStatusDischarged}, nil)
+
+		_, err := usecase.Transfer(1, &entities.EncounterTransferRequest{ToDepartment: "SUR"}, 1, 9)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+	})
+}
+
+func TestFindAllEncounters(t *testing.T) {
+	t.Run("Failed", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		filter := entities.EncounterFilter{HospitalID: 1}
+		mockRepo.On("FindAll", filter, 1, 10).Return([]entities.Encounter{}, int64(0), errors.New("db error"))
+
+		result, total, err := usecase.FindAll(filter, 1, 10)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Equal(t, 0, total)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newUseCase()
+		filter := entities.EncounterFilter{HospitalID: 1}
+		mockRepo.On("FindAll", filter, 1, 10).Return([]entities.Encounter{{ID: 1}}, int64(11), nil)
+
+		result, total, err := usecase.FindAll(filter, 1, 10)
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, 2, total)
+	})
+}
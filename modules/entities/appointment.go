@@ -0,0 +1,150 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	Appointment struct {
+		ID                uint                     `gorm:"primaryKey autoIncrement" json:"id"`
+		HospitalID        uint                     `gorm:"not null;index" json:"hospital_id"`
+		Hospital          Hospital                 `gorm:"foreignKey:HospitalID" json:"-"`
+		PatientID         uint                     `gorm:"not null;index" json:"patient_id"`
+		Patient           Patient                  `gorm:"foreignKey:PatientID" json:"-"`
+		StaffID           uint                     `gorm:"not null;index" json:"staff_id"`
+		Staff             Staff                    `gorm:"foreignKey:StaffID" json:"-"`
+		StartAt           time.Time                `gorm:"not null;index" json:"start_at"`
+		EndAt             time.Time                `gorm:"not null" json:"end_at"`
+		Status            consts.AppointmentStatus `gorm:"type:varchar(16);not null;index" json:"status"`
+		Reason            string                   `json:"reason,omitempty"`
+		CancelReason      string                   `json:"cancel_reason,omitempty"`
+		RescheduledFromID *uint                    `json:"rescheduled_from_id,omitempty"`
+		BookedByID        uint                     `gorm:"not null" json:"booked_by_id"`
+		CancelledByID     *uint                    `json:"cancelled_by_id,omitempty"`
+		CancelledAt       *time.Time               `json:"cancelled_at,omitempty"`
+		Version           uint                     `gorm:"not null;default:1" json:"version"`
+		CreatedAt         time.Time                `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt         time.Time                `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	// StaffAvailability is a recurring weekly window in which a staff member
+	// accepts appointments. Times are "HH:MM" on the wall clock.
+	StaffAvailability struct {
+		ID         uint      `gorm:"primaryKey autoIncrement" json:"id"`
+		StaffID    uint      `gorm:"not null;index" json:"staff_id"`
+		HospitalID uint      `gorm:"not null" json:"hospital_id"`
+		Weekday    int       `gorm:"not null" json:"weekday"`
+		StartTime  string    `gorm:"type:varchar(5);not null" json:"start_time"`
+		EndTime    string    `gorm:"type:varchar(5);not null" json:"end_time"`
+		CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	// StaffAvailabilityException overrides the weekly template on one date.
+	// An unavailable exception without times blocks the whole day; an
+	// available one adds an extra window.
+	StaffAvailabilityException struct {
+		ID         uint      `gorm:"primaryKey autoIncrement" json:"id"`
+		StaffID    uint      `gorm:"not null;index:idx_exception_staff_date" json:"staff_id"`
+		HospitalID uint      `gorm:"not null" json:"hospital_id"`
+		Date       string    `gorm:"type:varchar(10);not null;index:idx_exception_staff_date" json:"date"`
+		StartTime  string    `gorm:"type:varchar(5)" json:"start_time,omitempty"`
+		EndTime    string    `gorm:"type:varchar(5)" json:"end_time,omitempty"`
+		Available  bool      `gorm:"not null;default:false" json:"available"`
+		Reason     string    `json:"reason,omitempty"`
+		CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	}
+
+	TimeRange struct {
+		StartAt time.Time `json:"start_at"`
+		EndAt   time.Time `json:"end_at"`
+	}
+
+	AppointmentDayFilter struct {
+		HospitalID uint
+		StaffID    uint
+		From       time.Time
+		To         time.Time
+	}
+
+	AppointmentRepository interface {
+		Book(appointment *Appointment) (*Appointment, error)
+		Reschedule(previous *Appointment, next *Appointment) (*Appointment, error)
+		Update(appointment *Appointment) (*Appointment, error)
+		FindById(id uint) (*Appointment, error)
+		FindByDay(filter AppointmentDayFilter) ([]Appointment, error)
+		CreateAvailability(availability *StaffAvailability) (*StaffAvailability, error)
+		DeleteAvailability(id uint) error
+		FindAvailabilityById(id uint) (*StaffAvailability, error)
+		FindAvailabilities(staffId uint) ([]StaffAvailability, error)
+		CreateException(exception *StaffAvailabilityException) (*StaffAvailabilityException, error)
+		DeleteException(id uint) error
+		FindExceptionById(id uint) (*StaffAvailabilityException, error)
+		FindExceptions(staffId uint, fromDate string, toDate string) ([]StaffAvailabilityException, error)
+	}
+
+	AppointmentUseCase interface {
+		Book(req *AppointmentBookRequest, staffHospitalId uint, bookedById uint) (*Appointment, error)
+		Cancel(id uint, req *AppointmentCancelRequest, staffHospitalId uint, staffId uint) (*Appointment, error)
+		Reschedule(id uint, req *AppointmentRescheduleRequest, staffHospitalId uint, staffId uint) (*Appointment, error)
+		FindById(id uint, staffHospitalId uint) (*Appointment, error)
+		FindHospitalDay(date string, staffHospitalId uint) (*AppointmentHospitalDay, error)
+		FindStaffDay(staffId uint, date string, staffHospitalId uint) (*AppointmentStaffDay, error)
+		CreateAvailability(req *StaffAvailabilityRequest, staffId uint, staffHospitalId uint) (*StaffAvailability, error)
+		DeleteAvailability(staffId uint, availabilityId uint, staffHospitalId uint) error
+		CreateException(req *StaffAvailabilityExceptionRequest, staffId uint, staffHospitalId uint) (*StaffAvailabilityException, error)
+		DeleteException(staffId uint, exceptionId uint, staffHospitalId uint) error
+		FindAvailability(staffId uint, staffHospitalId uint) (*StaffAvailabilityResponse, error)
+	}
+
+	AppointmentBookRequest struct {
+		PatientID uint      `json:"patient_id" binding:"required"`
+		StaffID   uint      `json:"staff_id" binding:"required"`
+		StartAt   time.Time `json:"start_at" binding:"required"`
+		EndAt     time.Time `json:"end_at" binding:"required"`
+		Reason    string    `json:"reason"`
+	}
+
+	AppointmentCancelRequest struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	AppointmentRescheduleRequest struct {
+		StartAt time.Time `json:"start_at" binding:"required"`
+		EndAt   time.Time `json:"end_at" binding:"required"`
+		Reason  string    `json:"reason" binding:"required"`
+	}
+
+	StaffAvailabilityRequest struct {
+		Weekday   *int   `json:"weekday" binding:"required"`
+		StartTime string `json:"start_time" binding:"required"`
+		EndTime   string `json:"end_time" binding:"required"`
+	}
+
+	StaffAvailabilityExceptionRequest struct {
+		Date      string `json:"date" binding:"required"`
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+		Available bool   `json:"available"`
+		Reason    string `json:"reason"`
+	}
+
+	StaffAvailabilityResponse struct {
+		Weekly     []StaffAvailability          `json:"weekly"`
+		Exceptions []StaffAvailabilityException `json:"exceptions"`
+	}
+
+	AppointmentHospitalDay struct {
+		Date         string        `json:"date"`
+		Appointments []Appointment `json:"appointments"`
+	}
+
+	AppointmentStaffDay struct {
+		StaffID      uint          `json:"staff_id"`
+		Date         string        `json:"date"`
+		Available    []TimeRange   `json:"available"`
+		Appointments []Appointment `json:"appointments"`
+	}
+)
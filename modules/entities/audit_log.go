@@ -0,0 +1,36 @@
+package entities
+
+import "time"
+
+type (
+	AuditLog struct {
+		ID           uint      `gorm:"primaryKey autoIncrement" json:"id"`
+		HospitalID   uint      `gorm:"not null;index" json:"hospital_id"`
+		StaffID      uint      `gorm:"not null;index" json:"staff_id"`
+		Action       string    `gorm:"type:varchar(64);not null;index" json:"action"`
+		ResourceType string    `gorm:"type:varchar(32);not null;index:idx_audit_logs_resource" json:"resource_type"`
+		ResourceID   uint      `gorm:"not null;index:idx_audit_logs_resource" json:"resource_id"`
+		Detail       string    `json:"detail,omitempty"`
+		IPAddress    string    `gorm:"type:varchar(64)" json:"ip_address,omitempty"`
+		UserAgent    string    `json:"user_agent,omitempty"`
+		CreatedAt    time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	}
+
+	AuditLogRepository interface {
+		Create(logs []AuditLog) error
+		FindAll(filter AuditLogFilter, page int, limit int) ([]AuditLog, int, error)
+	}
+
+	AuditLogUseCase interface {
+		Record(logs ...AuditLog) error
+		FindAll(filter AuditLogFilter, page int, limit int) ([]AuditLog, int, error)
+	}
+
+	AuditLogFilter struct {
+		HospitalID   uint
+		StaffID      uint   `form:"staff_id"`
+		Action       string `form:"action"`
+		ResourceType string `form:"resource_type"`
+		ResourceID   uint   `form:"resource_id"`
+	}
+)
@@ -0,0 +1,106 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	Ward struct {
+		ID         uint      `gorm:"primaryKey autoIncrement" json:"id"`
+		HospitalID uint      `gorm:"not null;uniqueIndex:idx_ward_hospital_name" json:"hospital_id"`
+		Hospital   Hospital  `gorm:"foreignKey:HospitalID" json:"-"`
+		Name       string    `gorm:"not null;uniqueIndex:idx_ward_hospital_name" json:"name"`
+		Department string    `json:"department,omitempty"`
+		Beds       []Bed     `gorm:"foreignKey:WardID" json:"beds,omitempty"`
+		CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	Bed struct {
+		ID         uint             `gorm:"primaryKey autoIncrement" json:"id"`
+		WardID     uint             `gorm:"not null;uniqueIndex:idx_bed_ward_code" json:"ward_id"`
+		HospitalID uint             `gorm:"not null;index" json:"hospital_id"`
+		Code       string           `gorm:"not null;uniqueIndex:idx_bed_ward_code" json:"code"`
+		Status     consts.BedStatus `gorm:"type:varchar(16);not null;default:available;index" json:"status"`
+		Note       string           `json:"note,omitempty"`
+		CreatedAt  time.Time        `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt  time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	// BedAssignment records one stay of an encounter in a bed. The open
+	// assignment (ReleasedAt nil) is the current bed; closed ones form the
+	// transfer history. The partial unique indexes guarantee at most one open
+	// assignment per bed and per encounter even under concurrent admissions.
+	BedAssignment struct {
+		ID            uint       `gorm:"primaryKey autoIncrement" json:"id"`
+		BedID         uint       `gorm:"not null;uniqueIndex:idx_bed_assignment_open_bed,where:released_at IS NULL" json:"bed_id"`
+		Bed           *Bed       `gorm:"foreignKey:BedID" json:"bed,omitempty"`
+		EncounterID   uint       `gorm:"not null;index;uniqueIndex:idx_bed_assignment_open_encounter,where:released_at IS NULL" json:"encounter_id"`
+		PatientID     uint       `gorm:"not null;index" json:"patient_id"`
+		HospitalID    uint       `gorm:"not null" json:"hospital_id"`
+		AssignedByID  uint       `gorm:"not null" json:"assigned_by_id"`
+		AssignedAt    time.Time  `gorm:"not null" json:"assigned_at"`
+		ReleasedAt    *time.Time `json:"released_at,omitempty"`
+		ReleaseReason string     `json:"release_reason,omitempty"`
+	}
+
+	WardOccupancy struct {
+		WardID        uint    `json:"ward_id"`
+		WardName      string  `json:"ward_name"`
+		Total         int64   `json:"total"`
+		Available     int64   `json:"available"`
+		Occupied      int64   `json:"occupied"`
+		Cleaning      int64   `json:"cleaning"`
+		Blocked       int64   `json:"blocked"`
+		OccupancyRate float64 `json:"occupancy_rate"`
+	}
+
+	BedRepository interface {
+		CreateWard(ward *Ward) (*Ward, error)
+		FindWardById(id uint) (*Ward, error)
+		FindWards(hospitalId uint) ([]Ward, error)
+		CreateBed(bed *Bed) (*Bed, error)
+		FindBedById(id uint) (*Bed, error)
+		UpdateBedStatus(id uint, from consts.BedStatus, to consts.BedStatus, note string) error
+		Assign(assignment *BedAssignment) (*BedAssignment, error)
+		Release(encounterId uint, reason string) (*BedAssignment, error)
+		FindAssignments(encounterId uint) ([]BedAssignment, error)
+		FindOccupancy(hospitalId uint) ([]WardOccupancy, error)
+	}
+
+	BedUseCase interface {
+		CreateWard(req *WardCreateRequest, staffHospitalId uint) (*Ward, error)
+		FindWardById(id uint, staffHospitalId uint) (*Ward, error)
+		FindWards(staffHospitalId uint) ([]Ward, error)
+		CreateBed(wardId uint, req *BedCreateRequest, staffHospitalId uint) (*Bed, error)
+		UpdateBedStatus(id uint, req *BedStatusRequest, staffHospitalId uint) (*Bed, error)
+		Assign(bedId uint, req *BedAssignRequest, staffHospitalId uint, staffId uint) (*BedAssignment, error)
+		Release(encounterId uint, req *BedReleaseRequest, staffHospitalId uint) (*BedAssignment, error)
+		FindAssignments(encounterId uint, staffHospitalId uint) ([]BedAssignment, error)
+		FindOccupancy(staffHospitalId uint) ([]WardOccupancy, error)
+	}
+
+	WardCreateRequest struct {
+		Name       string `json:"name" binding:"required"`
+		Department string `json:"department"`
+	}
+
+	BedCreateRequest struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	BedStatusRequest struct {
+		Status consts.BedStatus `json:"status" binding:"required"`
+		Note   string           `json:"note"`
+	}
+
+	BedAssignRequest struct {
+		EncounterID uint `json:"encounter_id" binding:"required"`
+	}
+
+	BedReleaseRequest struct {
+		Reason string `json:"reason"`
+	}
+)
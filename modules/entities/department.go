@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	// Department is one node of a hospital's building → department → unit
+	// tree. Path holds the ids from the root down to the node itself, like
+	// "/3/12/", so a whole subtree is a single prefix match.
+	Department struct {
+		ID         uint                  `gorm:"primaryKey autoIncrement" json:"id"`
+		HospitalID uint                  `gorm:"not null;uniqueIndex:idx_department_hospital_code" json:"hospital_id"`
+		Hospital   Hospital              `gorm:"foreignKey:HospitalID" json:"-"`
+		ParentID   *uint                 `gorm:"index" json:"parent_id"`
+		Parent     *Department           `gorm:"foreignKey:ParentID" json:"-"`
+		Kind       consts.DepartmentKind `gorm:"type:varchar(16);not null" json:"kind"`
+		Code       string                `gorm:"type:varchar(32);not null;uniqueIndex:idx_department_hospital_code" json:"code"`
+		Name       string                `gorm:"not null" json:"name"`
+		Path       string                `gorm:"not null;index" json:"path"`
+		Version    uint                  `gorm:"not null;default:1" json:"version"`
+		CreatedAt  time.Time             `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt  time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+
+		// Children is filled in by FindTree only.
+		Children []Department `gorm:"-" json:"children,omitempty"`
+	}
+
+	// StaffDepartment assigns a staff member to a department. A staff member
+	// may work in several departments.
+	StaffDepartment struct {
+		StaffID      uint       `gorm:"primaryKey" json:"staff_id"`
+		Staff        Staff      `gorm:"foreignKey:StaffID;constraint:OnDelete:CASCADE" json:"-"`
+		DepartmentID uint       `gorm:"primaryKey;index" json:"department_id"`
+		Department   Department `gorm:"foreignKey:DepartmentID;constraint:OnDelete:CASCADE" json:"-"`
+		CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	}
+
+	DepartmentRepository interface {
+		Create(department *Department) (*Department, error)
+		Update(department *Department, oldPath string) (*Department, error)
+		Delete(id uint) error
+		FindById(id uint) (*Department, error)
+		FindByHospital(hospitalId uint) ([]Department, error)
+		CountChildren(id uint) (int64, error)
+		AssignStaff(assignment *StaffDepartment) error
+		UnassignStaff(departmentId uint, staffId uint) error
+		FindStaff(departmentId uint) ([]Staff, error)
+	}
+
+	DepartmentUseCase interface {
+		Create(hospitalId uint, req *DepartmentCreateRequest) (*Department, error)
+		Update(hospitalId uint, id uint, version uint, req *DepartmentUpdateRequest) (*Department, error)
+		Delete(hospitalId uint, id uint) error
+		FindById(hospitalId uint, id uint) (*Department, error)
+		FindTree(hospitalId uint) ([]Department, error)
+		AssignStaff(hospitalId uint, id uint, staffId uint) error
+		UnassignStaff(hospitalId uint, id uint, staffId uint) error
+		FindStaff(hospitalId uint, id uint) ([]Staff, error)
+	}
+
+	DepartmentCreateRequest struct {
+		ParentID *uint                 `json:"parent_id"`
+		Kind     consts.DepartmentKind `json:"kind" binding:"required"`
+		Code     string                `json:"code" binding:"required"`
+		Name     string                `json:"name" binding:"required"`
+	}
+
+	// DepartmentUpdateRequest renames a department or moves it, with its
+	// subtree, under another parent. A nil ParentID moves it to the top.
+	DepartmentUpdateRequest struct {
+		ParentID *uint  `json:"parent_id"`
+		Code     string `json:"code" binding:"required"`
+		Name     string `json:"name" binding:"required"`
+	}
+)
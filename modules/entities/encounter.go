@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	Encounter struct {
+		ID               uint                        `gorm:"primaryKey autoIncrement" json:"id"`
+		PatientID        uint                        `gorm:"not null;index" json:"patient_id"`
+		Patient          Patient                     `gorm:"foreignKey:PatientID" json:"-"`
+		HospitalID       uint                        `gorm:"not null;index" json:"hospital_id"`
+		Hospital         Hospital                    `gorm:"foreignKey:HospitalID" json:"-"`
+		AttendingStaffID uint                        `gorm:"not null" json:"attending_staff_id"`
+		AttendingStaff   Staff                       `gorm:"foreignKey:AttendingStaffID" json:"-"`
+		Type             consts.EncounterType        `gorm:"type:varchar(16);not null" json:"type"`
+		Status           consts.EncounterStatus      `gorm:"type:varchar(16);not null;index" json:"status"`
+		Department       string                      `json:"department"`
+		Ward             string                      `json:"ward,omitempty"`
+		ChiefComplaint   string                      `json:"chief_complaint,omitempty"`
+		Disposition      consts.DischargeDisposition `gorm:"type:varchar(16)" json:"disposition,omitempty"`
+		DischargeNote    string                      `json:"discharge_note,omitempty"`
+		CancelReason     string                      `json:"cancel_reason,omitempty"`
+		StartedAt        time.Time                   `gorm:"not null" json:"started_at"`
+		AdmittedAt       *time.Time                  `json:"admitted_at,omitempty"`
+		DischargedAt     *time.Time                  `json:"discharged_at,omitempty"`
+		CancelledAt      *time.Time                  `json:"cancelled_at,omitempty"`
+		Version          uint                        `gorm:"not null;default:1" json:"version"`
+		CreatedAt        time.Time                   `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt        time.Time                   `gorm:"autoUpdateTime" json:"updated_at"`
+
+		// Relations
+		Transfers []EncounterTransfer `gorm:"foreignKey:EncounterID;constraint:OnDelete:CASCADE" json:"transfers,omitempty"`
+	}
+
+	EncounterTransfer struct {
+		ID              uint      `gorm:"primaryKey autoIncrement" json:"id"`
+		EncounterID     uint      `gorm:"not null;index" json:"encounter_id"`
+		FromDepartment  string    `json:"from_department"`
+		FromWard        string    `json:"from_ward"`
+		ToDepartment    string    `json:"to_department"`
+		ToWard          string    `json:"to_ward"`
+		Reason          string    `json:"reason,omitempty"`
+		TransferredByID uint      `gorm:"not null" json:"transferred_by_id"`
+		TransferredAt   time.Time `gorm:"not null" json:"transferred_at"`
+	}
+
+	// EncounterFilter narrows encounter listings. Encounters record their
+	// department by name, so DepartmentID matches the names of the
+	// department and its descendants.
+	EncounterFilter struct {
+		HospitalID   uint
+		PatientID    uint
+		DepartmentID uint
+		Type         consts.EncounterType
+		Status       consts.EncounterStatus
+	}
+
+	EncounterRepository interface {
+		Create(encounter *Encounter) (*Encounter, error)
+		Update(encounter *Encounter) (*Encounter, error)
+		Transfer(encounter *Encounter, transfer *EncounterTransfer) (*Encounter, error)
+		Close(encounter *Encounter, releaseReason string) (*Encounter, error)
+		FindById(id uint) (*Encounter, error)
+		FindAll(filter EncounterFilter, page int, limit int) ([]Encounter, int64, error)
+	}
+
+	EncounterUseCase interface {
+		Create(req *EncounterCreateRequest, staffHospitalId uint) (*Encounter, error)
+		Admit(id uint, req *EncounterAdmitRequest, staffHospitalId uint) (*Encounter, error)
+		Discharge(id uint, req *EncounterDischargeRequest, staffHospitalId uint) (*Encounter, error)
+		Transfer(id uint, req *EncounterTransferRequest, staffHospitalId uint, staffId uint) (*Encounter, error)
+		Cancel(id uint, req *EncounterCancelRequest, staffHospitalId uint) (*Encounter, error)
+		FindById(id uint, staffHospitalId uint) (*Encounter, error)
+		FindAll(filter EncounterFilter, page int, limit int) ([]Encounter, int, error)
+	}
+
+	EncounterCreateRequest struct {
+		PatientID        uint                 `json:"patient_id" binding:"required"`
+		AttendingStaffID uint                 `json:"attending_staff_id" binding:"required"`
+		Type             consts.EncounterType `json:"type" binding:"required"`
+		Department       string               `json:"department" binding:"required"`
+		Ward             string               `json:"ward"`
+		ChiefComplaint   string               `json:"chief_complaint"`
+	}
+
+	EncounterAdmitRequest struct {
+		Ward             string `json:"ward" binding:"required"`
+		Department       string `json:"department"`
+		AttendingStaffID uint   `json:"attending_staff_id"`
+	}
+
+	EncounterDischargeRequest struct {
+		Disposition   consts.DischargeDisposition `json:"disposition" binding:"required"`
+		DischargeNote string                      `json:"discharge_note"`
+	}
+
+	EncounterTransferRequest struct {
+		ToDepartment string `json:"to_department"`
+		ToWard       string `json:"to_ward"`
+		Reason       string `json:"reason"`
+	}
+
+	EncounterCancelRequest struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+)
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+)
+
+// The kinds of domain error. Use errors.Is with one of them to learn how an
+// error should be reported, whatever its message.
+var (
+	ErrNotFound   = errors.New("resource not found")
+	ErrConflict   = errors.New("request conflicts with the current state")
+	ErrValidation = errors.New("request is not valid")
+	ErrForbidden  = errors.New("request is not allowed")
+)
+
+var (
+	ErrVersionConflict      = errors.New("resource has been modified by another request")
+	ErrInvalidTransition    = Conflict("invalid_transition", "status transition is not allowed")
+	ErrAppointmentConflict  = Conflict("appointment_conflict", "the time slot overlaps another appointment")
+	ErrBedNotAvailable      = Conflict("bed_not_available", "bed is not available")
+	ErrEncounterNotAdmitted = Conflict("encounter_not_admitted", "only admitted encounters can be assigned a bed")
+	ErrTransferPending      = Conflict("transfer_pending", "patient already has a pending transfer")
+	ErrHospitalInUse        = Conflict("hospital_in_use", "hospital still has active staff or patients")
+	ErrHospitalHNCollision  = Conflict("patient_hn_taken", "patient_hn is already used at the target hospital")
+)
+
+// DomainError is an error of one of the kinds above. Its message is safe to
+// return to the caller as is, and Code identifies it for clients that must
+// not depend on the wording.
+type DomainError struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func (e *DomainError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// NotFound reports that the named resource, such as "patient" or "parent
+// department", does not exist or is not visible to the caller.
+func NotFound(resource string) error {
+	return &DomainError{
+		Kind:    ErrNotFound,
+		Code:    strings.ReplaceAll(resource, " ", "_") + "_not_found",
+		Message: resource + " not found",
+	}
+}
+
+// Conflict reports a request that is valid but cannot be applied to the
+// current state, such as a duplicate name.
+func Conflict(code string, message string) error {
+	return &DomainError{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// Invalid reports a request that is not valid as a whole. Use FieldError
+// when the problem is in a single field.
+func Invalid(message string) error {
+	return &DomainError{Kind: ErrValidation, Code: "invalid_request", Message: message}
+}
+
+// Forbidden reports a request the caller is not allowed to make on the
+// resource, although they may see it.
+func Forbidden(code string, message string) error {
+	return &DomainError{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+// FieldError reports an invalid value for a single request field. Its
+// message is safe to return to the caller as is.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+func (e *FieldError) Is(target error) bool {
+	return target == ErrValidation
+}
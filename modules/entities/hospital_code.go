@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"io"
+	"time"
+)
+
+type (
+	// HospitalCodeEntry is one unit of the Ministry of Public Health
+	// hospital code list (hcode), kept as reference data. It is replaced by
+	// each import and is independent of the hospitals registered here.
+	HospitalCodeEntry struct {
+		Code        string    `gorm:"primaryKey;type:varchar(5)" json:"code"`
+		Name        string    `gorm:"not null" json:"name"`
+		Type        string    `json:"type,omitempty"`
+		Affiliation string    `json:"affiliation,omitempty"`
+		Subdistrict string    `json:"subdistrict,omitempty"`
+		District    string    `json:"district,omitempty"`
+		Province    string    `gorm:"index" json:"province,omitempty"`
+		PostalCode  string    `gorm:"type:varchar(10)" json:"postal_code,omitempty"`
+		ImportedAt  time.Time `gorm:"not null" json:"imported_at"`
+	}
+
+	HospitalCodeRepository interface {
+		Upsert(entries []HospitalCodeEntry) error
+		FindByCode(code string) (*HospitalCodeEntry, error)
+	}
+
+	HospitalCodeUseCase interface {
+		Import(r io.Reader) (*HospitalCodeImportResult, error)
+		Lookup(code string) (*HospitalCodeLookup, error)
+	}
+
+	// HospitalCodeImportResult summarises an import. Issues lists the rows
+	// that were skipped; Row is the line number, with the header on line 1.
+	HospitalCodeImportResult struct {
+		Imported int                       `json:"imported"`
+		Skipped  int                       `json:"skipped"`
+		Issues   []HospitalCodeImportIssue `json:"issues"`
+	}
+
+	HospitalCodeImportIssue struct {
+		Row     int    `json:"row"`
+		Message string `json:"message"`
+	}
+
+	// HospitalCodeLookup answers a lookup by code. Either part may be nil:
+	// a code can be in the ministry list without being registered here, and
+	// a hospital can carry a code missing from an outdated list.
+	HospitalCodeLookup struct {
+		Code     string             `json:"code"`
+		Registry *HospitalCodeEntry `json:"registry"`
+		Hospital *Hospital          `json:"hospital"`
+	}
+)
+
+func (HospitalCodeEntry) TableName() string {
+	return "hospital_codes"
+}
@@ -0,0 +1,144 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	// HospitalSettings holds the per hospital configuration. A hospital
+	// without a stored row uses DefaultHospitalSettings, reported with
+	// Version 0.
+	HospitalSettings struct {
+		HospitalID            uint                    `gorm:"primaryKey;autoIncrement:false" json:"hospital_id"`
+		Timezone              string                  `gorm:"not null" json:"timezone"`
+		DefaultLocale         consts.Locale           `gorm:"type:varchar(8);not null" json:"default_locale"`
+		HNFormat              string                  `gorm:"column:hn_format" json:"hn_format"`
+		PasswordPolicy        PasswordPolicy          `gorm:"embedded;embeddedPrefix:password_" json:"password_policy"`
+		MFARequired           bool                    `gorm:"column:mfa_required;not null" json:"mfa_required"`
+		SessionTimeoutMinutes int                     `gorm:"not null" json:"session_timeout_minutes"`
+		RetentionDays         int                     `gorm:"not null" json:"retention_days"`
+		EnabledModules        []consts.HospitalModule `gorm:"serializer:json" json:"enabled_modules"`
+		Version               uint                    `gorm:"not null;default:1" json:"version"`
+		UpdatedByID           uint                    `json:"updated_by_id,omitempty"`
+		UpdatedAt             time.Time               `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	PasswordPolicy struct {
+		MinLength      int  `gorm:"not null" json:"min_length"`
+		RequireUpper   bool `gorm:"not null" json:"require_upper"`
+		RequireDigit   bool `gorm:"not null" json:"require_digit"`
+		RequireSymbol  bool `gorm:"not null" json:"require_symbol"`
+		MaxAgeDays     int  `gorm:"not null" json:"max_age_days"`
+		HistoryToCheck int  `gorm:"not null" json:"history_to_check"`
+	}
+
+	// HospitalSettingsChange records one field changed by a settings update.
+	HospitalSettingsChange struct {
+		ID          uint      `gorm:"primaryKey autoIncrement" json:"id"`
+		HospitalID  uint      `gorm:"not null;index:idx_settings_change_hospital" json:"hospital_id"`
+		Version     uint      `gorm:"not null" json:"version"`
+		Field       string    `gorm:"not null" json:"field"`
+		OldValue    string    `json:"old_value"`
+		NewValue    string    `json:"new_value"`
+		ChangedByID uint      `gorm:"not null" json:"changed_by_id"`
+		ChangedAt   time.Time `gorm:"not null;index:idx_settings_change_hospital" json:"changed_at"`
+	}
+
+	HospitalSettingsRepository interface {
+		FindByHospital(hospitalId uint) (*HospitalSettings, error)
+		Save(settings *HospitalSettings, changes []HospitalSettingsChange) (*HospitalSettings, error)
+		FindHistory(hospitalId uint, page int, limit int) ([]HospitalSettingsChange, int64, error)
+	}
+
+	HospitalSettingsUseCase interface {
+		Get(hospitalId uint) (*HospitalSettings, error)
+		Update(hospitalId uint, version uint, req *HospitalSettingsRequest, staffId uint) (*HospitalSettings, error)
+		FindHistory(hospitalId uint, page int, limit int) ([]HospitalSettingsChange, int, error)
+	}
+
+	// HospitalSettingsRequest replaces every setting at once.
+	HospitalSettingsRequest struct {
+		Timezone              string                  `json:"timezone" binding:"required"`
+		DefaultLocale         consts.Locale           `json:"default_locale" binding:"required"`
+		HNFormat              string                  `json:"hn_format"`
+		PasswordPolicy        PasswordPolicy          `json:"password_policy"`
+		MFARequired           bool                    `json:"mfa_required"`
+		SessionTimeoutMinutes int                     `json:"session_timeout_minutes" binding:"required"`
+		RetentionDays         int                     `json:"retention_days"`
+		EnabledModules        []consts.HospitalModule `json:"enabled_modules"`
+	}
+)
+
+// Limits for the numeric settings.
+const (
+	MinSessionTimeoutMinutes = 5
+	MaxSessionTimeoutMinutes = 24 * 60
+	MinPasswordLength        = 8
+	MaxPasswordLength        = 128
+	// MinRetentionDays is ten years, the shortest period Thai medical
+	// records may be kept for. Zero keeps records forever.
+	MinRetentionDays = 3650
+)
+
+// DefaultHospitalSettings returns the settings a hospital starts with.
+func DefaultHospitalSettings(hospitalId uint) *HospitalSettings {
+	return &HospitalSettings{
+		HospitalID:    hospitalId,
+		Timezone:      "Asia/Bangkok",
+		DefaultLocale: consts.LocaleTH,
+		PasswordPolicy: PasswordPolicy{
+			MinLength:    MinPasswordLength,
+			RequireDigit: true,
+		},
+		SessionTimeoutMinutes: 60,
+		EnabledModules:        append([]consts.HospitalModule(nil), consts.HospitalModules...),
+	}
+}
+
+func (s *HospitalSettings) Validate() error {
+	if _, err := time.LoadLocation(s.Timezone); s.Timezone == "" || err != nil {
+		return &FieldError{Field: "timezone", Message: "timezone must be an IANA time zone such as Asia/Bangkok"}
+	}
+
+	if !s.DefaultLocale.IsValid() {
+		return &FieldError{Field: "default_locale", Message: "default_locale must be th or en"}
+	}
+
+	if s.HNFormat != "" {
+		if _, err := regexp.Compile(s.HNFormat); err != nil {
+			return &FieldError{Field: "hn_format", Message: "hn_format must be a valid regular expression"}
+		}
+	}
+
+	if p := s.PasswordPolicy; p.MinLength < MinPasswordLength || p.MinLength > MaxPasswordLength {
+		return &FieldError{Field: "password_policy.min_length", Message: fmt.Sprintf("password_policy.min_length must be between %d and %d", MinPasswordLength, MaxPasswordLength)}
+	}
+	if s.PasswordPolicy.MaxAgeDays < 0 || s.PasswordPolicy.HistoryToCheck < 0 {
+		return &FieldError{Field: "password_policy", Message: "password_policy.max_age_days and history_to_check must not be negative"}
+	}
+
+	if s.SessionTimeoutMinutes < MinSessionTimeoutMinutes || s.SessionTimeoutMinutes > MaxSessionTimeoutMinutes {
+		return &FieldError{Field: "session_timeout_minutes", Message: fmt.Sprintf("session_timeout_minutes must be between %d and %d", MinSessionTimeoutMinutes, MaxSessionTimeoutMinutes)}
+	}
+
+	if s.RetentionDays != 0 && s.RetentionDays < MinRetentionDays {
+		return &FieldError{Field: "retention_days", Message: fmt.Sprintf("retention_days must be 0 or at least %d", MinRetentionDays)}
+	}
+
+	seen := make(map[consts.HospitalModule]bool)
+	for _, module := range s.EnabledModules {
+		if !module.IsValid() {
+			return &FieldError{Field: "enabled_modules", Message: fmt.Sprintf("unknown module %q", module)}
+		}
+		if seen[module] {
+			return &FieldError{Field: "enabled_modules", Message: fmt.Sprintf("module %q is listed twice", module)}
+		}
+		seen[module] = true
+	}
+
+	return nil
+}
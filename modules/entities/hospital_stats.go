@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	// HospitalRegistrationStat is a row of the hospital_registration_stats
+	// materialized view: the patients registered on one day, counted in the
+	// hospital's own time zone.
+	HospitalRegistrationStat struct {
+		HospitalID    uint       `json:"hospital_id"`
+		Day           civil.Date `json:"day"`
+		Registrations int64      `json:"registrations"`
+	}
+
+	// HospitalDemographicStat is a row of the hospital_patient_demographics
+	// materialized view. RefreshedAt is when the view was last refreshed.
+	HospitalDemographicStat struct {
+		HospitalID  uint           `json:"hospital_id"`
+		Gender      consts.Gender  `json:"gender"`
+		AgeBand     consts.AgeBand `json:"age_band"`
+		Patients    int64          `json:"patients"`
+		RefreshedAt time.Time      `json:"refreshed_at"`
+	}
+
+	HospitalStatsRepository interface {
+		Refresh() error
+		FindDemographics(hospitalId uint) ([]HospitalDemographicStat, error)
+		CountRegistrations(hospitalId uint, from civil.Date, to civil.Date) (int64, error)
+		CountRegistrationsBefore(hospitalId uint, day civil.Date) (int64, error)
+		FindRegistrationTrend(hospitalId uint, from civil.Date, to civil.Date, interval consts.StatsInterval) ([]HospitalStatsPoint, error)
+		CountActiveStaff(hospitalId uint) (int64, error)
+	}
+
+	HospitalStatsUseCase interface {
+		Find(hospitalId uint, req *HospitalStatsRequest) (*HospitalStats, error)
+		Refresh() error
+		StartRefresh(interval time.Duration)
+	}
+
+	// HospitalStatsRequest selects the range of the trend. Zero values mean
+	// the last 30 days, by day.
+	HospitalStatsRequest struct {
+		From     civil.Date
+		To       civil.Date
+		Interval consts.StatsInterval
+	}
+
+	HospitalStats struct {
+		HospitalID    uint                     `json:"hospital_id"`
+		TotalPatients int64                    `json:"total_patients"`
+		ActiveStaff   int64                    `json:"active_staff"`
+		NewPatients   HospitalNewPatients      `json:"new_patients"`
+		Genders       map[consts.Gender]int64  `json:"genders"`
+		AgeBands      map[consts.AgeBand]int64 `json:"age_bands"`
+		From          civil.Date               `json:"from"`
+		To            civil.Date               `json:"to"`
+		Interval      consts.StatsInterval     `json:"interval"`
+		Trend         []HospitalStatsPoint     `json:"trend"`
+		RefreshedAt   *time.Time               `json:"refreshed_at"`
+	}
+
+	// HospitalNewPatients counts registrations over the day, the 7 days and
+	// the 30 days up to and including today.
+	HospitalNewPatients struct {
+		Day   int64 `json:"day"`
+		Week  int64 `json:"week"`
+		Month int64 `json:"month"`
+	}
+
+	// HospitalStatsPoint is one period of the trend. TotalPatients is the
+	// running total at the end of the period.
+	HospitalStatsPoint struct {
+		Period        civil.Date `json:"period"`
+		Registrations int64      `json:"registrations"`
+		TotalPatients int64      `json:"total_patients"`
+	}
+)
+
+// MaxStatsRangeDays bounds the range of a trend.
+const MaxStatsRangeDays = 731
+
+func (HospitalRegistrationStat) TableName() string {
+	return "hospital_registration_stats"
+}
+
+func (HospitalDemographicStat) TableName() string {
+	return "hospital_patient_demographics"
+}
@@ -1,41 +1,236 @@
 package entities
 
-import "time"
+import (
+	"regexp"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/geo"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"gorm.io/gorm"
+)
+
+var postalCodePattern = regexp.MustCompile(`^[0-9]{5}$`)
+
+// HospitalCodePattern matches a Ministry of Public Health hospital code.
+var HospitalCodePattern = regexp.MustCompile(`^[0-9]{5}$`)
+
+// HospitalQuery is what hospital listings accept. ?name= and ?province=
+// are kept as shorthands for filter[hospital_name][like] and
+// filter[province][eq].
+var HospitalQuery = &query.Resource{
+	Filters: map[string]query.Field{
+		"hospital_name": {Column: "hospital_name", Ops: []query.Op{query.OpEq, query.OpNe, query.OpLike, query.OpIn}},
+		"code":          {Column: "code", Ops: []query.Op{query.OpEq, query.OpIn}},
+		"subdistrict":   {Column: "subdistrict", Ops: []query.Op{query.OpEq, query.OpLike, query.OpIn}},
+		"district":      {Column: "district", Ops: []query.Op{query.OpEq, query.OpLike, query.OpIn}},
+		"province":      {Column: "province", Ops: []query.Op{query.OpEq, query.OpNe, query.OpLike, query.OpIn}},
+		"postal_code":   {Column: "postal_code", Ops: []query.Op{query.OpEq, query.OpIn}},
+		"created_at":    {Column: "created_at", Kind: query.Time, Ops: []query.Op{query.OpGt, query.OpGte, query.OpLt, query.OpLte}},
+		"updated_at":    {Column: "updated_at", Kind: query.Time, Ops: []query.Op{query.OpGt, query.OpGte, query.OpLt, query.OpLte}},
+	},
+	Aliases: map[string]query.Alias{
+		"name":     {Field: "hospital_name", Op: query.OpLike},
+		"province": {Field: "province", Op: query.OpEq},
+	},
+	Sorts: map[string]string{
+		"id":            "id",
+		"hospital_name": "hospital_name",
+		"province":      "province",
+		"district":      "district",
+		"created_at":    "created_at",
+		"updated_at":    "updated_at",
+	},
+	Fields: map[string]string{
+		"id":            "id",
+		"hospital_name": "hospital_name",
+		"code":          "code",
+		"address":       "address",
+		"subdistrict":   "subdistrict",
+		"district":      "district",
+		"province":      "province",
+		"postal_code":   "postal_code",
+		"latitude":      "latitude",
+		"longitude":     "longitude",
+		"version":       "version",
+		"created_at":    "created_at",
+		"updated_at":    "updated_at",
+	},
+	Required:     []string{"id"},
+	DefaultLimit: 10,
+	MaxLimit:     100,
+}
 
 type (
+	// Hospital keeps the street part of its address in Address and the Thai
+	// administrative divisions in their own fields. Deleting a hospital only
+	// sets DeletedAt, so the records that point at it stay readable.
 	Hospital struct {
-		ID           uint      `gorm:"primaryKey" json:"id"`
-		HospitalName string    `gorm:"unique;not null" json:"hospital_name"`
-		Address      string    `gorm:"not null" json:"address"`
-		CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
-		UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+		ID           uint           `gorm:"primaryKey" json:"id"`
+		HospitalName string         `gorm:"unique;not null" json:"hospital_name"`
+		Code         string         `gorm:"type:varchar(5);not null;default:'';uniqueIndex:idx_hospitals_code,where:code <> ''" json:"code"`
+		Address      string         `gorm:"not null" json:"address"`
+		Subdistrict  string         `json:"subdistrict"`
+		District     string         `json:"district"`
+		Province     string         `gorm:"index" json:"province"`
+		PostalCode   string         `gorm:"type:varchar(10)" json:"postal_code"`
+		Latitude     *float64       `gorm:"index:idx_hospitals_location" json:"latitude"`
+		Longitude    *float64       `gorm:"index:idx_hospitals_location" json:"longitude"`
+		Version      uint           `gorm:"not null;default:1" json:"version"`
+		CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+		DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 		// Relations
-		Staffs   []Staff   `gorm:"foreignKey:HospitalID" json:"-"`
-		Patients []Patient `gorm:"foreignKey:HospitalID" json:"-"`
+		Staffs      []Staff             `gorm:"foreignKey:HospitalID" json:"-"`
+		Patients    []Patient           `gorm:"foreignKey:HospitalID" json:"-"`
+		FormerNames []HospitalNameAlias `gorm:"foreignKey:HospitalID;constraint:OnDelete:CASCADE" json:"-"`
+	}
+
+	// HospitalNameAlias keeps a name the hospital was known by before a
+	// rename, so staff can still log in with it and no other hospital can
+	// take it.
+	HospitalNameAlias struct {
+		ID         uint      `gorm:"primaryKey" json:"id"`
+		HospitalID uint      `gorm:"not null;index" json:"hospital_id"`
+		Name       string    `gorm:"uniqueIndex;not null" json:"name"`
+		CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 	}
 
 	HospitalRepository interface {
 		Create(hospital *Hospital) (*Hospital, error)
 		Update(hospital *Hospital) (*Hospital, error)
-		Delete(id uint) error
-		FindHospitalCount() (int64, error)
-		FindAll(page int, limit int) ([]Hospital, error)
+		Delete(id uint, policy consts.HospitalDeletePolicy, targetHospitalId uint) error
+		FindDeletionImpact(id uint, targetHospitalId uint) (*HospitalDeletionImpact, error)
+		FindHospitalCount(spec *query.Spec) (int64, error)
+		FindAll(spec *query.Spec) ([]Hospital, error)
 		FindById(id uint) (*Hospital, error)
 		FindByName(name string) (*Hospital, error)
+		FindByCode(code string) (*Hospital, error)
+		FindNearby(lat float64, lng float64, radiusKm float64, limit int) ([]HospitalDistance, error)
 	}
 
 	HospitalUseCase interface {
 		Create(hospital *Hospital) (*Hospital, error)
 		Update(hospital *Hospital) (*Hospital, error)
-		Delete(id uint) error
-		FindAll(page int, limit int) ([]Hospital, int, error)
+		Delete(id uint, req *HospitalDeleteRequest) (*HospitalDeletionImpact, error)
+		FindAll(spec *query.Spec) ([]Hospital, int, error)
 		FindById(id uint) (*Hospital, error)
 		FindByName(name string) (*Hospital, error)
+		FindNearby(lat float64, lng float64, radiusKm float64, limit int) ([]HospitalDistance, error)
+	}
+
+	// HospitalDeleteRequest picks the cascade policy for a deletion. With
+	// DryRun set nothing is changed and only the impact is reported.
+	HospitalDeleteRequest struct {
+		Policy           consts.HospitalDeletePolicy
+		TargetHospitalID uint
+		DryRun           bool
+	}
+
+	// HospitalDeletionImpact counts what a deletion touches. Active staff
+	// and patients are the ones not archived yet. When reassigning, it also
+	// counts the active patients' records that move with them and lists the
+	// HNs already used at the target hospital, which block the move.
+	HospitalDeletionImpact struct {
+		HospitalID         uint                        `json:"hospital_id"`
+		Policy             consts.HospitalDeletePolicy `json:"policy"`
+		TargetHospitalID   uint                        `json:"target_hospital_id,omitempty"`
+		ActiveStaff        int64                       `json:"active_staff"`
+		ActivePatients     int64                       `json:"active_patients"`
+		Departments        int64                       `json:"departments"`
+		Encounters         int64                       `json:"encounters,omitempty"`
+		Appointments       int64                       `json:"appointments,omitempty"`
+		BedAssignments     int64                       `json:"bed_assignments,omitempty"`
+		OpenBedAssignments int64                       `json:"open_bed_assignments,omitempty"`
+		CollidingHNs       []string                    `json:"colliding_hns,omitempty"`
+		Blocked            bool                        `json:"blocked"`
+		DryRun             bool                        `json:"dry_run"`
+	}
+
+	// HospitalDistance is a hospital found by FindNearby.
+	HospitalDistance struct {
+		Hospital
+		DistanceKm float64 `json:"distance_km"`
 	}
 
 	HospitalCreateRequest struct {
-		HospitalName string `json:"hospital_name" binding:"required"`
-		Address      string `json:"address" binding:"required"`
+		HospitalName string   `json:"hospital_name" binding:"required"`
+		Code         string   `json:"code"`
+		Address      string   `json:"address" binding:"required"`
+		Subdistrict  string   `json:"subdistrict"`
+		District     string   `json:"district"`
+		Province     string   `json:"province"`
+		PostalCode   string   `json:"postal_code"`
+		Latitude     *float64 `json:"latitude"`
+		Longitude    *float64 `json:"longitude"`
+	}
+
+	HospitalUpdateRequest struct {
+		HospitalName string   `json:"hospital_name" binding:"required"`
+		Code         string   `json:"code"`
+		Address      string   `json:"address" binding:"required"`
+		Subdistrict  string   `json:"subdistrict"`
+		District     string   `json:"district"`
+		Province     string   `json:"province"`
+		PostalCode   string   `json:"postal_code"`
+		Latitude     *float64 `json:"latitude"`
+		Longitude    *float64 `json:"longitude"`
+	}
+
+	// HospitalPatchRequest changes only the fields that are present.
+	HospitalPatchRequest struct {
+		HospitalName *string  `json:"hospital_name"`
+		Code         *string  `json:"code"`
+		Address      *string  `json:"address"`
+		Subdistrict  *string  `json:"subdistrict"`
+		District     *string  `json:"district"`
+		Province     *string  `json:"province"`
+		PostalCode   *string  `json:"postal_code"`
+		Latitude     *float64 `json:"latitude"`
+		Longitude    *float64 `json:"longitude"`
 	}
 )
+
+// ValidateCode checks that the hospital code, when set, is a 5 digit MOPH
+// hospital code.
+func (h *Hospital) ValidateCode() error {
+	if h.Code != "" && !HospitalCodePattern.MatchString(h.Code) {
+		return &FieldError{Field: "code", Message: "code must be the 5 digit MOPH hospital code"}
+	}
+	return nil
+}
+
+// ValidateLocation checks the postal code and that the coordinates are
+// either both set and in range or both empty.
+func (h *Hospital) ValidateLocation() error {
+	if h.PostalCode != "" && !postalCodePattern.MatchString(h.PostalCode) {
+		return &FieldError{Field: "postal_code", Message: "postal_code must be 5 digits"}
+	}
+
+	if (h.Latitude == nil) != (h.Longitude == nil) {
+		return &FieldError{Field: "latitude", Message: "latitude and longitude must be set together"}
+	}
+
+	if h.Latitude != nil {
+		if err := (geo.Point{Lat: *h.Latitude, Lng: *h.Longitude}).Validate(); err != nil {
+			return &FieldError{Field: "latitude", Message: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// HasName reports whether name is the hospital's current name or one of the
+// former names loaded with it.
+func (h *Hospital) HasName(name string) bool {
+	if h.HospitalName == name {
+		return true
+	}
+	for _, alias := range h.FormerNames {
+		if alias.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,8 @@ type (
 		Username   string
 		Hospital   string
 		HospitalID uint
+		Role       string
+		Scopes     []string
 		jwt.RegisteredClaims
 	}
 
@@ -16,5 +18,7 @@ type (
 		Username   string
 		Hospital   string
 		HospitalID uint
+		Role       string
+		Scopes     []string
 	}
 )
@@ -1,74 +1,281 @@
 package entities
 
-import "time"
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+)
+
+// PatientQuery is what patient listings accept. Listings are always limited
+// to the caller's hospital and leave out archived patients, and the
+// encrypted identifiers cannot be filtered on; use the advanced search for
+// those.
+var PatientQuery = &query.Resource{
+	Filters: map[string]query.Field{
+		"first_name_th":  {Column: "first_name_th", Ops: []query.Op{query.OpEq, query.OpLike}},
+		"last_name_th":   {Column: "last_name_th", Ops: []query.Op{query.OpEq, query.OpLike}},
+		"first_name_en":  {Column: "first_name_en", Ops: []query.Op{query.OpEq, query.OpLike}},
+		"last_name_en":   {Column: "last_name_en", Ops: []query.Op{query.OpEq, query.OpLike}},
+		"patient_hn":     {Column: "patient_hn", Ops: []query.Op{query.OpEq, query.OpIn}},
+		"date_of_birth":  {Column: "date_of_birth", Kind: query.Date, Ops: []query.Op{query.OpEq, query.OpGt, query.OpGte, query.OpLt, query.OpLte}},
+		"gender":         {Column: "gender", Ops: []query.Op{query.OpEq, query.OpNe, query.OpIn}, Valid: func(v string) bool { return consts.Gender(v).IsValid() }},
+		"blood_group":    {Column: "blood_group", Ops: []query.Op{query.OpEq, query.OpIn}, Valid: func(v string) bool { return consts.BloodGroup(v).IsValid() }},
+		"nationality":    {Column: "nationality", Ops: []query.Op{query.OpEq, query.OpIn}},
+		"marital_status": {Column: "marital_status", Ops: []query.Op{query.OpEq, query.OpIn}, Valid: func(v string) bool { return consts.MaritalStatus(v).IsValid() }},
+		"created_at":     {Column: "created_at", Kind: query.Time, Ops: []query.Op{query.OpGt, query.OpGte, query.OpLt, query.OpLte}},
+	},
+	Sorts: map[string]string{
+		"id":            "id",
+		"first_name_th": "first_name_th",
+		"last_name_th":  "last_name_th",
+		"first_name_en": "first_name_en",
+		"last_name_en":  "last_name_en",
+		"patient_hn":    "patient_hn",
+		"date_of_birth": "date_of_birth",
+		"created_at":    "created_at",
+		"updated_at":    "updated_at",
+	},
+	Fields: map[string]string{
+		"id":             "id",
+		"first_name_th":  "first_name_th",
+		"middle_name_th": "middle_name_th",
+		"last_name_th":   "last_name_th",
+		"first_name_en":  "first_name_en",
+		"middle_name_en": "middle_name_en",
+		"last_name_en":   "last_name_en",
+		"date_of_birth":  "date_of_birth",
+		"patient_hn":     "patient_hn",
+		"national_id":    "national_id",
+		"passport_id":    "passport_id",
+		"phone_number":   "phone_number",
+		"email":          "email",
+		"gender":         "gender",
+		"blood_group":    "blood_group",
+		"rh_factor":      "rh_factor",
+		"nationality":    "nationality",
+		"religion":       "religion",
+		"marital_status": "marital_status",
+		"occupation":     "occupation",
+		"hospital_id":    "hospital_id",
+		"version":        "version",
+		"archived_at":    "archived_at",
+		"created_at":     "created_at",
+		"updated_at":     "updated_at",
+	},
+	Required: []string{"id"},
+	Includes: map[string]string{
+		"addresses":          "Addresses",
+		"emergency_contacts": "EmergencyContacts",
+	},
+	DefaultLimit: 10,
+	MaxLimit:     100,
+}
 
 type (
 	Patient struct {
-		ID           uint       `gorm:"primaryKey autoIncrement" json:"id"`
-		FirstNameTH  string     `gorm:"not null" json:"first_name_th"`
-		MiddleNameTH string     `json:"middle_name_th"`
-		LastNameTH   string     `gorm:"not null" json:"last_name_th"`
-		FirstNameEN  string     `gorm:"not null" json:"first_name_en"`
-		MiddleNameEN string     `json:"middle_name_en"`
-		LastNameEN   string     `gorm:"not null" json:"last_name_en"`
-		DateOfBirth  *time.Time `gorm:"not null" json:"date_of_birth"`
-		PatientHN    string     `json:"patient_hn"`
-		NationalID   string     `json:"national_id"`
-		PassportID   string     `json:"passport_id"`
-		PhoneNumber  string     `json:"phone_number"`
-		Email        string     `json:"email"`
-		Gender       string     `gorm:"type:char(1); default 'M'" json:"gender"`
-		HospitalID   uint       `gorm:"not null" json:"hospital_id"`
-		Hospital     Hospital   `gorm:"foreignKey:HospitalID" json:"-"`
-		CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
-		UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+		ID            uint                 `gorm:"primaryKey autoIncrement" json:"id"`
+		FirstNameTH   string               `gorm:"not null" json:"first_name_th"`
+		MiddleNameTH  string               `json:"middle_name_th"`
+		LastNameTH    string               `gorm:"not null" json:"last_name_th"`
+		FirstNameEN   string               `gorm:"not null" json:"first_name_en"`
+		MiddleNameEN  string               `json:"middle_name_en"`
+		LastNameEN    string               `gorm:"not null" json:"last_name_en"`
+		DateOfBirth   *civil.Date          `gorm:"type:date;not null" json:"date_of_birth"`
+		PatientHN     string               `json:"patient_hn"`
+		NationalID    string               `gorm:"serializer:encrypted" json:"national_id"`
+		NationalIDIdx string               `gorm:"column:national_id_index;type:varchar(64);index" json:"-"`
+		PassportID    string               `gorm:"serializer:encrypted" json:"passport_id"`
+		PassportIDIdx string               `gorm:"column:passport_id_index;type:varchar(64);index" json:"-"`
+		PhoneNumber   string               `json:"phone_number"`
+		Email         string               `json:"email"`
+		Gender        consts.Gender        `gorm:"type:varchar(8);not null;default:'unknown';check:chk_patients_gender,gender IN ('male','female','other','unknown')" json:"gender"`
+		GenderLabel   string               `gorm:"-" json:"gender_label,omitempty"`
+		BloodGroup    consts.BloodGroup    `gorm:"type:varchar(2)" json:"blood_group,omitempty"`
+		RhFactor      consts.RhFactor      `gorm:"type:char(1)" json:"rh_factor,omitempty"`
+		Nationality   string               `json:"nationality,omitempty"`
+		Religion      string               `json:"religion,omitempty"`
+		MaritalStatus consts.MaritalStatus `gorm:"type:varchar(16)" json:"marital_status,omitempty"`
+		Occupation    string               `json:"occupation,omitempty"`
+		HospitalID    uint                 `gorm:"not null" json:"hospital_id"`
+		Hospital      Hospital             `gorm:"foreignKey:HospitalID" json:"-"`
+		Version       uint                 `gorm:"not null;default:1" json:"version"`
+		ArchivedAt    *time.Time           `gorm:"index" json:"archived_at,omitempty"`
+		CreatedAt     time.Time            `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt     time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+
+		// Relations
+		Addresses         []PatientAddress          `gorm:"foreignKey:PatientID;constraint:OnDelete:CASCADE" json:"addresses,omitempty"`
+		EmergencyContacts []PatientEmergencyContact `gorm:"foreignKey:PatientID;constraint:OnDelete:CASCADE" json:"emergency_contacts,omitempty"`
 	}
 
 	PatientRepository interface {
 		Create(patient *Patient) (*Patient, error)
 		Update(patient *Patient) (*Patient, error)
 		Delete(id uint) (*Patient, error)
-		FindAll(page int, limit int) ([]Patient, error)
+		FindAll(hospitalId uint, spec *query.Spec) ([]Patient, int, error)
 		FindById(id uint) (*Patient, error)
-		FindByIdNationalOrPassport(id string) (*Patient, error)
-		FindByName(firstName string, lastName string) ([]Patient, error)
+		FindByIdNationalOrPassport(id string, hospitalId uint) (*Patient, error)
+		FindDuplicates(hospitalId uint, patients []Patient) ([]Patient, error)
 		FindByAdvanceSearch(input PatientSearchInput, page int, limit int) ([]Patient, int, error)
+		CountByAdvanceSearch(input PatientSearchInput) (int, error)
+		StreamByAdvanceSearch(input PatientSearchInput, fn func(patient *Patient) error) error
 	}
 
 	PatientUseCase interface {
 		Create(patient *Patient) (*Patient, error)
 		Update(patient *Patient, staffHospitalId uint) (*Patient, error)
 		Delete(id uint, staffHospitalId uint) (*Patient, error)
+		FindById(id uint, staffHospitalId uint) (*Patient, error)
 		FindByIdNationalOrPassport(id string, staffHospitalId uint) (*Patient, error)
+		FindAll(spec *query.Spec, staffHospitalId uint) ([]Patient, int, error)
 		FindByAdvanceSearch(input PatientSearchInput, page int, limit int) ([]Patient, int, error)
 	}
 
+	// PatientCreateRequest holds the patient fields a client may set when
+	// creating a patient. Ids, versions, hospital, archival and blind
+	// indexes are the server's, so they cannot be sent. Patient.Validate
+	// checks the values.
 	PatientCreateRequest struct {
-		FirstNameTH  string     `json:"first_name_th" binding:"required"`
-		MiddleNameTH string     `json:"middle_name_th,omitempty"`
-		LastNameTH   string     `json:"last_name_th" binding:"required"`
-		FirstNameEN  string     `json:"first_name_en" binding:"required"`
-		MiddleNameEN string     `json:"middle_name_en,omitempty"`
-		LastNameEN   string     `json:"last_name_en" binding:"required"`
-		DateOfBirth  *time.Time `json:"date_of_birth" binding:"required"`
-		PatientHN    string     `json:"patient_hn" binding:"required"`
-		NationalID   string     `json:"national_id"`
-		PassportID   string     `json:"passport_id"`
-		PhoneNumber  string     `json:"phone_number,omitempty"`
-		Email        string     `json:"email,omitempty"`
-		Gender       string     `json:"gender" binding:"required"`
-		HospitalID   uint
+		FirstNameTH   string               `json:"first_name_th"`
+		MiddleNameTH  string               `json:"middle_name_th,omitempty"`
+		LastNameTH    string               `json:"last_name_th"`
+		FirstNameEN   string               `json:"first_name_en"`
+		MiddleNameEN  string               `json:"middle_name_en,omitempty"`
+		LastNameEN    string               `json:"last_name_en"`
+		DateOfBirth   *civil.Date          `json:"date_of_birth"`
+		PatientHN     string               `json:"patient_hn"`
+		NationalID    string               `json:"national_id"`
+		PassportID    string               `json:"passport_id"`
+		PhoneNumber   string               `json:"phone_number,omitempty"`
+		Email         string               `json:"email,omitempty"`
+		Gender        consts.Gender        `json:"gender"`
+		BloodGroup    consts.BloodGroup    `json:"blood_group,omitempty"`
+		RhFactor      consts.RhFactor      `json:"rh_factor,omitempty"`
+		Nationality   string               `json:"nationality,omitempty"`
+		Religion      string               `json:"religion,omitempty"`
+		MaritalStatus consts.MaritalStatus `json:"marital_status,omitempty"`
+		Occupation    string               `json:"occupation,omitempty"`
+	}
+
+	// PatientPatchRequest holds the patient fields a PATCH may change. Fields
+	// left out of the body are nil and keep their value.
+	PatientPatchRequest struct {
+		FirstNameTH   *string               `json:"first_name_th"`
+		MiddleNameTH  *string               `json:"middle_name_th"`
+		LastNameTH    *string               `json:"last_name_th"`
+		FirstNameEN   *string               `json:"first_name_en"`
+		MiddleNameEN  *string               `json:"middle_name_en"`
+		LastNameEN    *string               `json:"last_name_en"`
+		DateOfBirth   *civil.Date           `json:"date_of_birth"`
+		PatientHN     *string               `json:"patient_hn"`
+		NationalID    *string               `json:"national_id"`
+		PassportID    *string               `json:"passport_id"`
+		PhoneNumber   *string               `json:"phone_number"`
+		Email         *string               `json:"email"`
+		Gender        *consts.Gender        `json:"gender"`
+		BloodGroup    *consts.BloodGroup    `json:"blood_group"`
+		RhFactor      *consts.RhFactor      `json:"rh_factor"`
+		Nationality   *string               `json:"nationality"`
+		Religion      *string               `json:"religion"`
+		MaritalStatus *consts.MaritalStatus `json:"marital_status"`
+		Occupation    *string               `json:"occupation"`
+	}
+
+	// PatientUpdateRequest is the body of the v1 update, which names the
+	// patient in the body instead of the path. Like a PATCH it only changes
+	// the fields present.
+	PatientUpdateRequest struct {
+		ID uint `json:"id" binding:"required"`
+		PatientPatchRequest
 	}
 
+	// PatientSearchInput filters patients. The date_of_birth bounds and the
+	// age bounds are inclusive and may be combined.
 	PatientSearchInput struct {
-		HospitalID  uint
-		NationalID  string     `json:"national_id"`
-		PassportID  string     `json:"passport_id"`
-		FirstName   string     `json:"first_name"`
-		MiddleName  string     `json:"middle_name"`
-		LastName    string     `json:"last_name"`
-		DateOfBirth *time.Time `json:"date_of_birth"`
-		PhoneNumber string     `json:"phone_number"`
-		Email       string     `json:"email"`
+		HospitalID      uint
+		NationalID      string      `json:"national_id"`
+		PassportID      string      `json:"passport_id"`
+		FirstName       string      `json:"first_name"`
+		MiddleName      string      `json:"middle_name"`
+		LastName        string      `json:"last_name"`
+		DateOfBirth     *civil.Date `json:"date_of_birth"`
+		DateOfBirthFrom *civil.Date `json:"date_of_birth_from"`
+		DateOfBirthTo   *civil.Date `json:"date_of_birth_to"`
+		AgeMin          *int        `json:"age_min"`
+		AgeMax          *int        `json:"age_max"`
+		PhoneNumber     string      `json:"phone_number"`
+		Email           string      `json:"email"`
 	}
 )
+
+// Patient builds the new patient of the hospital from the request.
+func (r *PatientCreateRequest) Patient(hospitalId uint) *Patient {
+	return &Patient{
+		FirstNameTH:   r.FirstNameTH,
+		MiddleNameTH:  r.MiddleNameTH,
+		LastNameTH:    r.LastNameTH,
+		FirstNameEN:   r.FirstNameEN,
+		MiddleNameEN:  r.MiddleNameEN,
+		LastNameEN:    r.LastNameEN,
+		DateOfBirth:   r.DateOfBirth,
+		PatientHN:     r.PatientHN,
+		NationalID:    r.NationalID,
+		PassportID:    r.PassportID,
+		PhoneNumber:   r.PhoneNumber,
+		Email:         r.Email,
+		Gender:        r.Gender,
+		BloodGroup:    r.BloodGroup,
+		RhFactor:      r.RhFactor,
+		Nationality:   r.Nationality,
+		Religion:      r.Religion,
+		MaritalStatus: r.MaritalStatus,
+		Occupation:    r.Occupation,
+		HospitalID:    hospitalId,
+	}
+}
+
+// Apply copies the fields present in the request onto the patient.
+func (r *PatientPatchRequest) Apply(p *Patient) {
+	fields := []struct {
+		value  *string
+		target *string
+	}{
+		{r.FirstNameTH, &p.FirstNameTH},
+		{r.MiddleNameTH, &p.MiddleNameTH},
+		{r.LastNameTH, &p.LastNameTH},
+		{r.FirstNameEN, &p.FirstNameEN},
+		{r.MiddleNameEN, &p.MiddleNameEN},
+		{r.LastNameEN, &p.LastNameEN},
+		{r.PatientHN, &p.PatientHN},
+		{r.NationalID, &p.NationalID},
+		{r.PassportID, &p.PassportID},
+		{r.PhoneNumber, &p.PhoneNumber},
+		{r.Email, &p.Email},
+		{r.Nationality, &p.Nationality},
+		{r.Religion, &p.Religion},
+		{r.Occupation, &p.Occupation},
+	}
+	for _, field := range fields {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+
+	if r.DateOfBirth != nil {
+		p.DateOfBirth = r.DateOfBirth
+	}
+	if r.Gender != nil {
+		p.Gender = *r.Gender
+	}
+	if r.BloodGroup != nil {
+		p.BloodGroup = *r.BloodGroup
+	}
+	if r.RhFactor != nil {
+		p.RhFactor = *r.RhFactor
+	}
+	if r.MaritalStatus != nil {
+		p.MaritalStatus = *r.MaritalStatus
+	}
+}
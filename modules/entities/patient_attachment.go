@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"io"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	PatientAttachment struct {
+		ID           uint                      `gorm:"primaryKey autoIncrement" json:"id"`
+		PatientID    uint                      `gorm:"not null;index" json:"patient_id"`
+		HospitalID   uint                      `gorm:"not null" json:"hospital_id"`
+		Category     consts.AttachmentCategory `gorm:"type:varchar(32);not null" json:"category"`
+		FileName     string                    `gorm:"not null" json:"file_name"`
+		ContentType  string                    `gorm:"type:varchar(100);not null" json:"content_type"`
+		Size         int64                     `gorm:"not null" json:"size"`
+		Checksum     string                    `gorm:"type:char(64);not null" json:"checksum_sha256"`
+		StorageKey   string                    `gorm:"not null;uniqueIndex" json:"-"`
+		UploadedByID uint                      `gorm:"not null" json:"uploaded_by_id"`
+		CreatedAt    time.Time                 `gorm:"autoCreateTime" json:"created_at"`
+	}
+
+	PatientAttachmentUpload struct {
+		PatientID uint
+		Category  consts.AttachmentCategory
+		FileName  string
+		Body      io.Reader
+	}
+
+	PatientAttachmentRepository interface {
+		Create(attachment *PatientAttachment) (*PatientAttachment, error)
+		Delete(id uint) error
+		FindById(id uint) (*PatientAttachment, error)
+		FindByPatientId(patientId uint) ([]PatientAttachment, error)
+	}
+
+	PatientAttachmentUseCase interface {
+		Upload(upload *PatientAttachmentUpload, staffHospitalId uint, staffId uint) (*PatientAttachment, error)
+		Download(patientId uint, attachmentId uint, staffHospitalId uint) (*PatientAttachment, io.ReadCloser, error)
+		Delete(patientId uint, attachmentId uint, staffHospitalId uint) error
+		FindAll(patientId uint, staffHospitalId uint) ([]PatientAttachment, error)
+	}
+)
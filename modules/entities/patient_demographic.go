@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	PatientAddress struct {
+		ID          uint               `gorm:"primaryKey autoIncrement" json:"id"`
+		PatientID   uint               `gorm:"not null;index" json:"patient_id"`
+		Type        consts.AddressType `gorm:"type:varchar(16);not null" json:"type"`
+		HouseNo     string             `json:"house_no"`
+		Village     string             `json:"village,omitempty"`
+		Moo         string             `json:"moo,omitempty"`
+		Soi         string             `json:"soi,omitempty"`
+		Road        string             `json:"road,omitempty"`
+		Subdistrict string             `json:"subdistrict"`
+		District    string             `json:"district"`
+		Province    string             `json:"province"`
+		PostalCode  string             `gorm:"type:varchar(10)" json:"postal_code"`
+		Country     string             `gorm:"default:'TH'" json:"country"`
+		CreatedAt   time.Time          `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt   time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	PatientEmergencyContact struct {
+		ID           uint                `gorm:"primaryKey autoIncrement" json:"id"`
+		PatientID    uint                `gorm:"not null;index" json:"patient_id"`
+		FullName     string              `gorm:"not null" json:"full_name"`
+		Relationship consts.Relationship `gorm:"type:varchar(16);not null" json:"relationship"`
+		PhoneNumber  string              `gorm:"not null" json:"phone_number"`
+		Email        string              `json:"email,omitempty"`
+		Address      string              `json:"address,omitempty"`
+		CreatedAt    time.Time           `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt    time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	PatientDemographicRepository interface {
+		CreateAddress(address *PatientAddress) (*PatientAddress, error)
+		UpdateAddress(address *PatientAddress) (*PatientAddress, error)
+		DeleteAddress(id uint) error
+		FindAddressById(id uint) (*PatientAddress, error)
+		FindAddressesByPatientId(patientId uint) ([]PatientAddress, error)
+		CreateEmergencyContact(contact *PatientEmergencyContact) (*PatientEmergencyContact, error)
+		UpdateEmergencyContact(contact *PatientEmergencyContact) (*PatientEmergencyContact, error)
+		DeleteEmergencyContact(id uint) error
+		FindEmergencyContactById(id uint) (*PatientEmergencyContact, error)
+		FindEmergencyContactsByPatientId(patientId uint) ([]PatientEmergencyContact, error)
+	}
+
+	PatientDemographicUseCase interface {
+		CreateAddress(address *PatientAddress, staffHospitalId uint) (*PatientAddress, error)
+		UpdateAddress(address *PatientAddress, staffHospitalId uint) (*PatientAddress, error)
+		DeleteAddress(patientId uint, addressId uint, staffHospitalId uint) error
+		FindAddresses(patientId uint, staffHospitalId uint) ([]PatientAddress, error)
+		CreateEmergencyContact(contact *PatientEmergencyContact, staffHospitalId uint) (*PatientEmergencyContact, error)
+		UpdateEmergencyContact(contact *PatientEmergencyContact, staffHospitalId uint) (*PatientEmergencyContact, error)
+		DeleteEmergencyContact(patientId uint, contactId uint, staffHospitalId uint) error
+		FindEmergencyContacts(patientId uint, staffHospitalId uint) ([]PatientEmergencyContact, error)
+	}
+
+	PatientAddressRequest struct {
+		Type        consts.AddressType `json:"type" binding:"required"`
+		HouseNo     string             `json:"house_no" binding:"required"`
+		Village     string             `json:"village"`
+		Moo         string             `json:"moo"`
+		Soi         string             `json:"soi"`
+		Road        string             `json:"road"`
+		Subdistrict string             `json:"subdistrict" binding:"required"`
+		District    string             `json:"district" binding:"required"`
+		Province    string             `json:"province" binding:"required"`
+		PostalCode  string             `json:"postal_code" binding:"required"`
+		Country     string             `json:"country"`
+	}
+
+	PatientEmergencyContactRequest struct {
+		FullName     string              `json:"full_name" binding:"required"`
+		Relationship consts.Relationship `json:"relationship" binding:"required"`
+		PhoneNumber  string              `json:"phone_number" binding:"required"`
+		Email        string              `json:"email"`
+		Address      string              `json:"address"`
+	}
+)
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"io"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	// PatientExportJob is a background export of a patient search. The file
+	// is shaped with the masking view of the staff member who requested it,
+	// so only they can download it.
+	PatientExportJob struct {
+		ID           uint                `gorm:"primaryKey autoIncrement" json:"id"`
+		HospitalID   uint                `gorm:"not null;index" json:"hospital_id"`
+		StaffID      uint                `gorm:"not null;index" json:"staff_id"`
+		Format       consts.ExportFormat `gorm:"type:varchar(8);not null" json:"format"`
+		Filter       PatientSearchInput  `gorm:"serializer:json" json:"filter"`
+		Role         string              `gorm:"type:varchar(16);not null" json:"-"`
+		Reveal       []string            `gorm:"serializer:json" json:"reveal,omitempty"`
+		Status       consts.ExportStatus `gorm:"type:varchar(16);not null;index" json:"status"`
+		TotalRows    int                 `gorm:"not null;default:0" json:"total_rows"`
+		ExportedRows int                 `gorm:"not null;default:0" json:"exported_rows"`
+		Progress     float64             `gorm:"-" json:"progress"`
+		ObjectKey    string              `json:"-"`
+		Size         int64               `json:"size,omitempty"`
+		Error        string              `json:"error,omitempty"`
+		StartedAt    *time.Time          `json:"started_at,omitempty"`
+		FinishedAt   *time.Time          `json:"finished_at,omitempty"`
+		ExpiresAt    *time.Time          `json:"expires_at,omitempty"`
+		Version      uint                `gorm:"not null;default:1" json:"version"`
+		CreatedAt    time.Time           `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt    time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	// PatientExportRequest carries the search filter and the caller's masking
+	// view. Reveal must already have been checked against the role.
+	PatientExportRequest struct {
+		HospitalID uint
+		StaffID    uint
+		Format     consts.ExportFormat
+		Filter     PatientSearchInput
+		Role       string
+		Reveal     []string
+	}
+
+	PatientExportRepository interface {
+		CreateJob(job *PatientExportJob) (*PatientExportJob, error)
+		UpdateJob(job *PatientExportJob) (*PatientExportJob, error)
+		FindJobById(id uint) (*PatientExportJob, error)
+	}
+
+	PatientExportUseCase interface {
+		Stream(req *PatientExportRequest, w io.Writer) error
+		Create(req *PatientExportRequest) (*PatientExportJob, error)
+		Start(job *PatientExportJob)
+		Run(job *PatientExportJob) (*PatientExportJob, error)
+		FindById(id uint, staffHospitalId uint, staffId uint) (*PatientExportJob, error)
+		Open(id uint, staffHospitalId uint, staffId uint) (*PatientExportJob, io.ReadCloser, error)
+	}
+)
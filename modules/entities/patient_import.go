@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"io"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	// PatientImportJob tracks one bulk import file. ProcessedRows counts the
+	// data rows already committed, so a failed job resumes right after them.
+	// For a dry run InsertedRows counts the rows that would be inserted.
+	PatientImportJob struct {
+		ID            uint                `gorm:"primaryKey autoIncrement" json:"id"`
+		HospitalID    uint                `gorm:"not null;index" json:"hospital_id"`
+		StaffID       uint                `json:"staff_id,omitempty"`
+		FileName      string              `gorm:"not null" json:"file_name"`
+		Format        string              `gorm:"type:varchar(8);not null" json:"format"`
+		SourceKey     string              `gorm:"not null" json:"-"`
+		Mapping       map[string]string   `gorm:"serializer:json" json:"mapping,omitempty"`
+		DryRun        bool                `gorm:"not null" json:"dry_run"`
+		BatchSize     int                 `gorm:"not null" json:"batch_size"`
+		Status        consts.ImportStatus `gorm:"type:varchar(16);not null;index" json:"status"`
+		TotalRows     int                 `gorm:"not null;default:0" json:"total_rows"`
+		ProcessedRows int                 `gorm:"not null;default:0" json:"processed_rows"`
+		InsertedRows  int                 `gorm:"not null;default:0" json:"inserted_rows"`
+		DuplicateRows int                 `gorm:"not null;default:0" json:"duplicate_rows"`
+		InvalidRows   int                 `gorm:"not null;default:0" json:"invalid_rows"`
+		Progress      float64             `gorm:"-" json:"progress"`
+		Error         string              `json:"error,omitempty"`
+		StartedAt     *time.Time          `json:"started_at,omitempty"`
+		FinishedAt    *time.Time          `json:"finished_at,omitempty"`
+		Version       uint                `gorm:"not null;default:1" json:"version"`
+		CreatedAt     time.Time           `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt     time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	// PatientImportIssue is one line of a job's error report. Row is the
+	// spreadsheet line number, with the header on line 1.
+	PatientImportIssue struct {
+		ID        uint                   `gorm:"primaryKey autoIncrement" json:"id"`
+		JobID     uint                   `gorm:"not null;index" json:"job_id"`
+		Row       int                    `gorm:"not null" json:"row"`
+		Kind      consts.ImportIssueKind `gorm:"type:varchar(16);not null" json:"kind"`
+		Field     string                 `json:"field,omitempty"`
+		Message   string                 `gorm:"not null" json:"message"`
+		CreatedAt time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	}
+
+	PatientImportRequest struct {
+		HospitalID uint
+		StaffID    uint
+		FileName   string
+		Format     string
+		Mapping    map[string]string
+		DryRun     bool
+		BatchSize  int
+		Body       io.Reader
+		Size       int64
+	}
+
+	PatientImportRepository interface {
+		CreateJob(job *PatientImportJob) (*PatientImportJob, error)
+		UpdateJob(job *PatientImportJob) (*PatientImportJob, error)
+		FindJobById(id uint) (*PatientImportJob, error)
+		FindDuplicates(hospitalId uint, patients []Patient) ([]Patient, error)
+		SaveBatch(job *PatientImportJob, patients []Patient, issues []PatientImportIssue) error
+		FindIssues(jobId uint, afterId uint, limit int) ([]PatientImportIssue, error)
+	}
+
+	PatientImportUseCase interface {
+		Create(req *PatientImportRequest) (*PatientImportJob, error)
+		Commit(id uint, staffHospitalId uint, staffId uint) (*PatientImportJob, error)
+		Resume(id uint, staffHospitalId uint) (*PatientImportJob, error)
+		Start(job *PatientImportJob)
+		Run(job *PatientImportJob, progress func(*PatientImportJob)) (*PatientImportJob, error)
+		FindById(id uint, staffHospitalId uint) (*PatientImportJob, error)
+		WriteReport(id uint, staffHospitalId uint, w io.Writer) error
+	}
+)
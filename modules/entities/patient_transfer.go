@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type (
+	// PatientTransfer moves a patient to another hospital. The source record
+	// is never changed; on acceptance the destination gets its own copy with
+	// a new HN, or is linked to a patient it already holds with the same
+	// national ID or passport.
+	PatientTransfer struct {
+		ID                    uint                  `gorm:"primaryKey autoIncrement" json:"id"`
+		PatientID             uint                  `gorm:"not null;index" json:"patient_id"`
+		Patient               Patient               `gorm:"foreignKey:PatientID" json:"-"`
+		SourceHospitalID      uint                  `gorm:"not null;index" json:"source_hospital_id"`
+		DestinationHospitalID uint                  `gorm:"not null;index" json:"destination_hospital_id"`
+		DestinationPatientID  *uint                 `json:"destination_patient_id,omitempty"`
+		DestinationHN         string                `json:"destination_hn,omitempty"`
+		Linked                bool                  `gorm:"not null;default:false" json:"linked"`
+		Status                consts.TransferStatus `gorm:"type:varchar(16);not null;index" json:"status"`
+		Reason                string                `gorm:"not null" json:"reason"`
+		RejectReason          string                `json:"reject_reason,omitempty"`
+		RequestedByID         uint                  `gorm:"not null" json:"requested_by_id"`
+		RequestedAt           time.Time             `gorm:"not null" json:"requested_at"`
+		DecidedByID           *uint                 `json:"decided_by_id,omitempty"`
+		DecidedAt             *time.Time            `json:"decided_at,omitempty"`
+		CancelledByID         *uint                 `json:"cancelled_by_id,omitempty"`
+		CancelledAt           *time.Time            `json:"cancelled_at,omitempty"`
+		Version               uint                  `gorm:"not null;default:1" json:"version"`
+		CreatedAt             time.Time             `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt             time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+	}
+
+	// PatientTransferFilter lists a hospital's transfers. Direction is
+	// "incoming", "outgoing" or empty for both.
+	PatientTransferFilter struct {
+		HospitalID uint
+		Direction  string                `form:"direction"`
+		Status     consts.TransferStatus `form:"status"`
+	}
+
+	PatientTransferRepository interface {
+		Create(transfer *PatientTransfer) (*PatientTransfer, error)
+		Update(transfer *PatientTransfer) (*PatientTransfer, error)
+		Accept(transfer *PatientTransfer, patient *Patient) (*PatientTransfer, error)
+		FindById(id uint) (*PatientTransfer, error)
+		FindAll(filter PatientTransferFilter, page int, limit int) ([]PatientTransfer, int, error)
+		FindDestinationMatches(hospitalId uint, patient *Patient) ([]Patient, error)
+	}
+
+	PatientTransferUseCase interface {
+		Create(req *PatientTransferCreateRequest, staffHospitalId uint, staffId uint) (*PatientTransfer, error)
+		Accept(id uint, req *PatientTransferAcceptRequest, staffHospitalId uint, staffId uint) (*PatientTransfer, error)
+		Reject(id uint, req *PatientTransferRejectRequest, staffHospitalId uint, staffId uint) (*PatientTransfer, error)
+		Cancel(id uint, staffHospitalId uint, staffId uint) (*PatientTransfer, error)
+		FindById(id uint, staffHospitalId uint) (*PatientTransfer, error)
+		FindAll(filter PatientTransferFilter, page int, limit int) ([]PatientTransfer, int, error)
+	}
+
+	PatientTransferCreateRequest struct {
+		PatientID             uint   `json:"patient_id" binding:"required"`
+		DestinationHospitalID uint   `json:"destination_hospital_id" binding:"required"`
+		Reason                string `json:"reason" binding:"required"`
+	}
+
+	// PatientTransferAcceptRequest carries the HN the destination assigns.
+	// It can be left out when the patient is already registered there.
+	PatientTransferAcceptRequest struct {
+		PatientHN string `json:"patient_hn"`
+	}
+
+	PatientTransferRejectRequest struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+)
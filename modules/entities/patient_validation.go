@@ -0,0 +1,73 @@
+package entities
+
+import "strings"
+
+// Validate checks a new patient record. It is shared by the create endpoint
+// and the bulk importer so both accept exactly the same rows.
+func (p *Patient) Validate() error {
+	required := []struct {
+		field string
+		value string
+	}{
+		{"first_name_th", p.FirstNameTH},
+		{"last_name_th", p.LastNameTH},
+		{"first_name_en", p.FirstNameEN},
+		{"last_name_en", p.LastNameEN},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			return &FieldError{Field: r.field, Message: r.field + " is required"}
+		}
+	}
+
+	if p.DateOfBirth == nil {
+		return &FieldError{Field: "date_of_birth", Message: "date_of_birth is required"}
+	}
+
+	if p.NationalID == "" && p.PassportID == "" {
+		return &FieldError{Field: "national_id", Message: "national_id or passport_id is required"}
+	}
+
+	if len(p.NationalID) > 13 {
+		return &FieldError{Field: "national_id", Message: "national_id must be less than 13 characters"}
+	}
+
+	if !p.Gender.IsValid() {
+		return &FieldError{Field: "gender", Message: "gender must be male, female, other or unknown"}
+	}
+
+	if p.BloodGroup != "" && !p.BloodGroup.IsValid() {
+		return &FieldError{Field: "blood_group", Message: "blood_group must be A, B, AB or O"}
+	}
+
+	if p.RhFactor != "" && !p.RhFactor.IsValid() {
+		return &FieldError{Field: "rh_factor", Message: "rh_factor must be + or -"}
+	}
+
+	if p.MaritalStatus != "" && !p.MaritalStatus.IsValid() {
+		return &FieldError{Field: "marital_status", Message: "marital_status must be single, married, divorced, widowed or separated"}
+	}
+
+	return nil
+}
+
+// Validate checks the date of birth and age bounds of a search.
+func (in PatientSearchInput) Validate() error {
+	if in.DateOfBirthFrom != nil && in.DateOfBirthTo != nil && in.DateOfBirthFrom.After(*in.DateOfBirthTo) {
+		return &FieldError{Field: "date_of_birth_from", Message: "date_of_birth_from must not be after date_of_birth_to"}
+	}
+
+	if in.AgeMin != nil && *in.AgeMin < 0 {
+		return &FieldError{Field: "age_min", Message: "age_min must not be negative"}
+	}
+
+	if in.AgeMax != nil && *in.AgeMax < 0 {
+		return &FieldError{Field: "age_max", Message: "age_max must not be negative"}
+	}
+
+	if in.AgeMin != nil && in.AgeMax != nil && *in.AgeMin > *in.AgeMax {
+		return &FieldError{Field: "age_min", Message: "age_min must not be greater than age_max"}
+	}
+
+	return nil
+}
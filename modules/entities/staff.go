@@ -4,32 +4,91 @@ import (
 	"time"
 
 	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 )
 
+// StaffQuery is what staff listings accept. The department_id filter also
+// matches staff of the department's descendants, name matches part of the
+// Thai or English full name and active keeps staff that are (or are not)
+// archived; the repository applies these three itself. The plain
+// ?department_id=, ?hospital_id=, ?name=, ?role=, ?gender= and ?active=
+// parameters are kept as shorthands.
+var StaffQuery = &query.Resource{
+	Filters: map[string]query.Field{
+		"hospital_id":   {Column: "hospital_id", Kind: query.Int, Ops: []query.Op{query.OpEq, query.OpIn}},
+		"department_id": {Kind: query.Int, Ops: []query.Op{query.OpEq}},
+		"name":          {Ops: []query.Op{query.OpLike}},
+		"role":          {Column: "role", Ops: []query.Op{query.OpEq, query.OpNe, query.OpIn}, Valid: func(v string) bool { return consts.StaffRole(v).IsValid() }},
+		"gender":        {Column: "gender", Ops: []query.Op{query.OpEq, query.OpNe, query.OpIn}, Valid: func(v string) bool { return consts.Gender(v).IsValid() }},
+		"active":        {Kind: query.Bool, Ops: []query.Op{query.OpEq}},
+		"created_at":    {Column: "created_at", Kind: query.Time, Ops: []query.Op{query.OpGt, query.OpGte, query.OpLt, query.OpLte}},
+	},
+	Aliases: map[string]query.Alias{
+		"department_id": {Field: "department_id", Op: query.OpEq},
+		"hospital_id":   {Field: "hospital_id", Op: query.OpEq},
+		"name":          {Field: "name", Op: query.OpLike},
+		"role":          {Field: "role", Op: query.OpEq},
+		"gender":        {Field: "gender", Op: query.OpEq},
+		"active":        {Field: "active", Op: query.OpEq},
+	},
+	Sorts: map[string]string{
+		"id":            "id",
+		"first_name_th": "first_name_th",
+		"last_name_th":  "last_name_th",
+		"first_name_en": "first_name_en",
+		"last_name_en":  "last_name_en",
+		"role":          "role",
+		"gender":        "gender",
+		"hospital_id":   "hospital_id",
+		"created_at":    "created_at",
+	},
+	Fields: map[string]string{
+		"id":             "id",
+		"first_name_th":  "first_name_th",
+		"middle_name_th": "middle_name_th",
+		"last_name_th":   "last_name_th",
+		"first_name_en":  "first_name_en",
+		"middle_name_en": "middle_name_en",
+		"last_name_en":   "last_name_en",
+		"gender":         "gender",
+		"role":           "role",
+		"version":        "version",
+	},
+	Required:        []string{"id", "hospital_id"},
+	Includes:        map[string]string{"hospital": "Hospital"},
+	DefaultIncludes: []string{"hospital"},
+	DefaultLimit:    10,
+	MaxLimit:        100,
+}
+
 type (
 	Staff struct {
-		ID           uint      `gorm:"primaryKey autoIncrement" json:"id"`
-		Username     string    `gorm:"unique;not null" json:"username"`
-		Password     string    `gorm:"not null" json:"password"`
-		FirstNameTH  string    `gorm:"not null" json:"first_name_th"`
-		MiddleNameTH string    `json:"middle_name_th,omitempty"`
-		LastNameTH   string    `gorm:"not null" json:"last_name_th"`
-		FirstNameEN  string    `gorm:"not null" json:"first_name_en"`
-		MiddleNameEN string    `json:"middle_name_en,omitempty"`
-		LastNameEN   string    `gorm:"not null" json:"last_name_en"`
-		Gender       string    `gorm:"type:char(1);default:'M'" json:"gender"`
-		HospitalID   uint      `gorm:"not null" json:"hospital_id"`
-		Hospital     Hospital  `gorm:"foreignKey:HospitalID" json:"-"`
-		CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
-		UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+		ID           uint             `gorm:"primaryKey autoIncrement" json:"id"`
+		Username     string           `gorm:"unique;not null" json:"username"`
+		Password     string           `gorm:"not null" json:"password"`
+		FirstNameTH  string           `gorm:"not null" json:"first_name_th"`
+		MiddleNameTH string           `json:"middle_name_th,omitempty"`
+		LastNameTH   string           `gorm:"not null" json:"last_name_th"`
+		FirstNameEN  string           `gorm:"not null" json:"first_name_en"`
+		MiddleNameEN string           `json:"middle_name_en,omitempty"`
+		LastNameEN   string           `gorm:"not null" json:"last_name_en"`
+		Gender       consts.Gender    `gorm:"type:varchar(8);not null;default:'unknown';check:chk_staffs_gender,gender IN ('male','female','other','unknown')" json:"gender"`
+		Role         consts.StaffRole `gorm:"type:varchar(16);not null;default:'clerk'" json:"role"`
+		HospitalID   uint             `gorm:"not null" json:"hospital_id"`
+		Hospital     Hospital         `gorm:"foreignKey:HospitalID" json:"-"`
+		Version      uint             `gorm:"not null;default:1" json:"version"`
+		ArchivedAt   *time.Time       `gorm:"index" json:"archived_at,omitempty"`
+		CreatedAt    time.Time        `gorm:"autoCreateTime" json:"created_at"`
+		UpdatedAt    time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
 	}
 
 	StaffRepository interface {
 		Create(staff *Staff) (*Staff, error)
 		Update(staff *Staff) (*Staff, error)
 		Delete(id uint) error
-		FindStaffCount() (int64, error)
-		FindAll(page int, limit int) ([]Staff, error)
+		FindStaffCount(spec *query.Spec) (int64, error)
+		FindAll(spec *query.Spec) ([]Staff, error)
 		FindById(id uint) (*Staff, error)
 		FindByUsername(username string) (*Staff, error)
 	}
@@ -38,7 +97,7 @@ type (
 		Create(staff *StaffCreateRequest) (*StaffCreateResponse, error)
 		Update(staff *StaffUpdateRequest) (*Staff, error)
 		Delete(id uint) error
-		FindAll(page int, limit int) ([]Staff, int, error)
+		FindAll(spec *query.Spec) ([]Staff, int, error)
 		FindById(id uint) (*Staff, error)
 		FindByUsername(username string) (*Staff, error)
 		Login(cfg *configs.Config, loginRequest *StaffLoginRequest) (*StaffLoginResponse, error)
@@ -51,21 +110,25 @@ type (
 	}
 
 	StaffCreateResponse struct {
-		ID           uint   `json:"id"`
-		Username     string `json:"username"`
-		FirstNameTH  string `json:"first_name_th"`
-		MiddleNameTH string `json:"middle_name_th,omitempty"`
-		LastNameTH   string `json:"last_name_th"`
-		FirstNameEN  string `json:"first_name_en"`
-		MiddleNameEN string `json:"middle_name_en,omitempty"`
-		LastNameEN   string `json:"last_name_en"`
-		Gender       string `json:"gender"`
+		ID           uint          `json:"id"`
+		Username     string        `json:"username"`
+		FirstNameTH  string        `json:"first_name_th"`
+		MiddleNameTH string        `json:"middle_name_th,omitempty"`
+		LastNameTH   string        `json:"last_name_th"`
+		FirstNameEN  string        `json:"first_name_en"`
+		MiddleNameEN string        `json:"middle_name_en,omitempty"`
+		LastNameEN   string        `json:"last_name_en"`
+		Gender       consts.Gender `json:"gender"`
+		GenderLabel  string        `json:"gender_label,omitempty"`
 	}
 
+	// StaffLoginRequest identifies the hospital by its MOPH code or, for
+	// hospitals without one, by its current or a former name.
 	StaffLoginRequest struct {
-		Username string `json:"username" binding:"required"`
-		Password string `json:"password" binding:"required"`
-		Hospital string `json:"hospital" binding:"required"`
+		Username     string `json:"username" binding:"required"`
+		Password     string `json:"password" binding:"required"`
+		Hospital     string `json:"hospital" binding:"required_without=HospitalCode"`
+		HospitalCode string `json:"hospital_code" binding:"required_without=Hospital"`
 	}
 
 	StaffLoginResponse struct {
@@ -75,37 +138,44 @@ type (
 
 	StaffUpdateRequest struct {
 		ID           uint
-		FirstNameTH  string `json:"first_name_th"`
-		MiddleNameTH string `json:"middle_name_th"`
-		LastNameTH   string `json:"last_name_th"`
-		FirstNameEN  string `json:"first_name_en"`
-		MiddleNameEN string `json:"middle_name_en"`
-		LastNameEN   string `json:"last_name_en"`
-		Gender       string `json:"gender"`
+		Version      uint
+		FirstNameTH  string        `json:"first_name_th"`
+		MiddleNameTH string        `json:"middle_name_th"`
+		LastNameTH   string        `json:"last_name_th"`
+		FirstNameEN  string        `json:"first_name_en"`
+		MiddleNameEN string        `json:"middle_name_en"`
+		LastNameEN   string        `json:"last_name_en"`
+		Gender       consts.Gender `json:"gender"`
 	}
 
 	StaffResponse struct {
-		ID           uint     `json:"id"`
-		FirstNameTH  string   `json:"first_name_th"`
-		MiddleNameTH string   `json:"middle_name_th"`
-		LastNameTH   string   `json:"last_name_th"`
-		FirstNameEN  string   `json:"first_name_en"`
-		MiddleNameEN string   `json:"middle_name_en"`
-		LastNameEN   string   `json:"last_name_en"`
-		Gender       string   `json:"gender"`
-		Hospital     Hospital `json:"hospital"`
+		ID           uint             `json:"id"`
+		FirstNameTH  string           `json:"first_name_th"`
+		MiddleNameTH string           `json:"middle_name_th"`
+		LastNameTH   string           `json:"last_name_th"`
+		FirstNameEN  string           `json:"first_name_en"`
+		MiddleNameEN string           `json:"middle_name_en"`
+		LastNameEN   string           `json:"last_name_en"`
+		Gender       consts.Gender    `json:"gender"`
+		GenderLabel  string           `json:"gender_label,omitempty"`
+		Role         consts.StaffRole `json:"role"`
+		Version      uint             `json:"version"`
+		Hospital     *Hospital        `json:"hospital,omitempty"`
 	}
 
 	StaffMeResponse struct {
-		ID           uint     `json:"id"`
-		Username     string   `json:"username"`
-		FirstNameTH  string   `json:"first_name_th"`
-		MiddleNameTH string   `json:"middle_name_th"`
-		LastNameTH   string   `json:"last_name_th"`
-		FirstNameEN  string   `json:"first_name_en"`
-		MiddleNameEN string   `json:"middle_name_en"`
-		LastNameEN   string   `json:"last_name_en"`
-		Gender       string   `json:"gender"`
-		Hospital     Hospital `json:"hospital"`
+		ID           uint             `json:"id"`
+		Username     string           `json:"username"`
+		FirstNameTH  string           `json:"first_name_th"`
+		MiddleNameTH string           `json:"middle_name_th"`
+		LastNameTH   string           `json:"last_name_th"`
+		FirstNameEN  string           `json:"first_name_en"`
+		MiddleNameEN string           `json:"middle_name_en"`
+		LastNameEN   string           `json:"last_name_en"`
+		Gender       consts.Gender    `json:"gender"`
+		GenderLabel  string           `json:"gender_label,omitempty"`
+		Role         consts.StaffRole `json:"role"`
+		Version      uint             `json:"version"`
+		Hospital     Hospital         `json:"hospital"`
 	}
 )
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type HospitalCodeCon struct {
+	Cfg                 configs.Config
+	HospitalCodeUsecase entities.HospitalCodeUseCase
+	AuthMiddleware      middlewares.AuthMiddleware
+}
+
+// NewHospitalCodeController registers the hospital code lookup on the
+// hospital group. Like the hospital listing it is public, so the login
+// page can resolve a code.
+func NewHospitalCodeController(c *gin.RouterGroup, cfg configs.Config, hospitalCodeUsecase entities.HospitalCodeUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &HospitalCodeCon{
+		Cfg:                 cfg,
+		HospitalCodeUsecase: hospitalCodeUsecase,
+		AuthMiddleware:      authMiddleware,
+	}
+
+	c.GET("/codes/:code", controller.Lookup)
+}
+
+func (a *HospitalCodeCon) Lookup(c *gin.Context) {
+	lookup, err := a.HospitalCodeUsecase.Lookup(c.Param("code"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, lookup)
+}
@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/hospitals/controllers"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCodeRouter(usecase entities.HospitalCodeUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := testConfig()
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/hospitals")
+	controllers.NewHospitalCodeController(group, *cfg, usecase, *authMiddleware)
+	return r
+}
+
+func TestLookupHospitalCodeHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalCodeUseCase()
+		mockUsecase.On("Lookup", "13781").Return(&entities.HospitalCodeLookup{
+			Code:     "13781",
+			Registry: &entities.HospitalCodeEntry{Code: "13781", Name: "โรงพยาบาลศิริราช"},
+		}, nil)
+
+		r := setupCodeRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/codes/13781", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"hospital":null`)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalCodeUseCase()
+		mockUsecase.On("Lookup", "99999").Return((*entities.HospitalCodeLookup)(nil), entities.NotFound("hospital code"))
+
+		r := setupCodeRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/codes/99999", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("Malformed Code", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalCodeUseCase()
+		mockUsecase.On("Lookup", "abc").Return((*entities.HospitalCodeLookup)(nil), &entities.FieldError{Field: "code", Message: "code must be the 5 digit MOPH hospital code"})
+
+		r := setupCodeRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/codes/abc", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
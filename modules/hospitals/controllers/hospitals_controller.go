@@ -1,10 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/Teemo4621/Hospital-Api/configs"
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
 	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -12,66 +15,57 @@ import (
 type HospitalCon struct {
 	Cfg             configs.Config
 	HospitalUsecase entities.HospitalUseCase
+	AuthMiddleware  middlewares.AuthMiddleware
 }
 
-func NewHospitalController(c *gin.RouterGroup, cfg configs.Config, hospitalUsecase entities.HospitalUseCase) {
+func NewHospitalController(c *gin.RouterGroup, cfg configs.Config, hospitalUsecase entities.HospitalUseCase, authMiddleware middlewares.AuthMiddleware) {
 	controller := &HospitalCon{
 		Cfg:             cfg,
 		HospitalUsecase: hospitalUsecase,
+		AuthMiddleware:  authMiddleware,
 	}
+
+	// Hospitals are listed before login, so reads stay public; changes are
+	// for system administrators only.
 	c.GET("/", controller.FindAll)
+	c.GET("/nearby", controller.FindNearby)
 	c.GET("/:id", controller.FindById)
-	c.POST("/", controller.Create)
-	c.DELETE("/:id", controller.Delete)
+	c.POST("/", controller.AuthMiddleware.JwtAuthentication(), controller.AuthMiddleware.RequireScope(consts.ScopeHospitalManage), controller.Create)
+	c.PUT("/:id", controller.AuthMiddleware.JwtAuthentication(), controller.AuthMiddleware.RequireScope(consts.ScopeHospitalManage), controller.Update)
+	c.PATCH("/:id", controller.AuthMiddleware.JwtAuthentication(), controller.AuthMiddleware.RequireScope(consts.ScopeHospitalManage), controller.Patch)
+	c.DELETE("/:id", controller.AuthMiddleware.JwtAuthentication(), controller.AuthMiddleware.RequireScope(consts.ScopeHospitalManage), controller.Delete)
 }
 
+// FindAll lists hospitals. It accepts the HospitalQuery parameters, for
+// example filter[province][in]=Bangkok,Nonthaburi&sort=-created_at&fields=id,hospital_name.
 func (a *HospitalCon) FindAll(c *gin.Context) {
-	page := c.Query("page")
-	limit := c.Query("limit")
-
-	if page == "" {
-		page = "1"
-	}
-
-	if limit == "" {
-		limit = "10"
-	}
-
-	limitInt, err := strconv.Atoi(limit)
+	spec, err := entities.HospitalQuery.Parse(c.Request.URL.Query())
 	if err != nil {
-		utils.BadRequestResponse(c, "limit is required and must be an integer")
+		c.Error(err)
 		return
 	}
 
-	pageInt, err := strconv.Atoi(page)
+	hospital, totalPage, err := a.HospitalUsecase.FindAll(spec)
 	if err != nil {
-		utils.BadRequestResponse(c, "page is required and must be an integer")
+		c.Error(err)
 		return
 	}
 
-	if pageInt < 1 {
-		pageInt = 1
-	}
-
-	if limitInt < 1 {
-		limitInt = 10
+	if len(hospital) == 0 {
+		hospital = []entities.Hospital{}
 	}
 
-	hospital, totalPage, err := a.HospitalUsecase.FindAll(pageInt, limitInt)
+	hospitals, err := spec.Pick(hospital)
 	if err != nil {
-		utils.ErrorResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
-	if len(hospital) == 0 {
-		hospital = []entities.Hospital{}
-	}
-
 	utils.OkResponse(c, gin.H{
-		"hospitals": hospital,
+		"hospitals": hospitals,
 		"meta": gin.H{
-			"page":       pageInt,
-			"limit":      limitInt,
+			"page":       spec.Page,
+			"limit":      spec.Limit,
 			"page_total": totalPage,
 		},
 	})
@@ -88,34 +82,197 @@ func (a *HospitalCon) FindById(c *gin.Context) {
 
 	hospital, err := a.HospitalUsecase.FindById(uint(idInt))
 	if err != nil {
-		utils.NotFoundResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
+	utils.SetETag(c, hospital.Version)
 	utils.OkResponse(c, hospital)
 }
 
 func (a *HospitalCon) Create(c *gin.Context) {
 	var reqHospital entities.HospitalCreateRequest
 	if err := c.ShouldBindJSON(&reqHospital); err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		utils.BindErrorResponse(c, err)
 		return
 	}
 
 	hospital := &entities.Hospital{
 		HospitalName: reqHospital.HospitalName,
+		Code:         reqHospital.Code,
 		Address:      reqHospital.Address,
+		Subdistrict:  reqHospital.Subdistrict,
+		District:     reqHospital.District,
+		Province:     reqHospital.Province,
+		PostalCode:   reqHospital.PostalCode,
+		Latitude:     reqHospital.Latitude,
+		Longitude:    reqHospital.Longitude,
 	}
 
 	hospital, err := a.HospitalUsecase.Create(hospital)
 	if err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, hospital.Version)
+	utils.OkResponse(c, hospital)
+}
+
+// FindNearby lists hospitals around ?lat=&lng=, closest first. radius is in
+// kilometres.
+func (a *HospitalCon) FindNearby(c *gin.Context) {
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	if err != nil {
+		utils.BadRequestResponse(c, "lat is required and must be a number")
+		return
+	}
+
+	lng, err := strconv.ParseFloat(c.Query("lng"), 64)
+	if err != nil {
+		utils.BadRequestResponse(c, "lng is required and must be a number")
+		return
+	}
+
+	var radius float64
+	if value := c.Query("radius"); value != "" {
+		if radius, err = strconv.ParseFloat(value, 64); err != nil {
+			utils.BadRequestResponse(c, "radius must be a number")
+			return
+		}
+	}
+
+	var limit int
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			utils.BadRequestResponse(c, "limit must be an integer")
+			return
+		}
+	}
+
+	hospitals, err := a.HospitalUsecase.FindNearby(lat, lng, radius, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(hospitals) == 0 {
+		hospitals = []entities.HospitalDistance{}
+	}
+
+	utils.OkResponse(c, gin.H{"hospitals": hospitals})
+}
+
+// Update replaces the hospital's name and address. It needs the version
+// from the hospital's ETag in If-Match.
+func (a *HospitalCon) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.HospitalUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
 		return
 	}
 
+	version, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	hospital, err := a.HospitalUsecase.Update(&entities.Hospital{
+		ID:           uint(id),
+		HospitalName: req.HospitalName,
+		Code:         req.Code,
+		Address:      req.Address,
+		Subdistrict:  req.Subdistrict,
+		District:     req.District,
+		Province:     req.Province,
+		PostalCode:   req.PostalCode,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		Version:      version,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, hospital.Version)
 	utils.OkResponse(c, hospital)
 }
 
+// Patch changes only the fields present in the body. Like Update it needs
+// If-Match.
+func (a *HospitalCon) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.HospitalPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	version, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	hospital, err := a.HospitalUsecase.FindById(uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	hospital.Version = version
+	if req.HospitalName != nil {
+		hospital.HospitalName = *req.HospitalName
+	}
+	if req.Code != nil {
+		hospital.Code = *req.Code
+	}
+	if req.Address != nil {
+		hospital.Address = *req.Address
+	}
+	if req.Subdistrict != nil {
+		hospital.Subdistrict = *req.Subdistrict
+	}
+	if req.District != nil {
+		hospital.District = *req.District
+	}
+	if req.Province != nil {
+		hospital.Province = *req.Province
+	}
+	if req.PostalCode != nil {
+		hospital.PostalCode = *req.PostalCode
+	}
+	if req.Latitude != nil {
+		hospital.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		hospital.Longitude = req.Longitude
+	}
+
+	hospital, err = a.HospitalUsecase.Update(hospital)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, hospital.Version)
+	utils.OkResponse(c, hospital)
+}
+
+// Delete soft deletes a hospital. ?policy= is refuse (the default),
+// archive-all or reassign-to-hospital with ?target_hospital_id=, and
+// ?dry_run=true only reports the impact.
 func (a *HospitalCon) Delete(c *gin.Context) {
 	id := c.Param("id")
 
@@ -125,11 +282,35 @@ func (a *HospitalCon) Delete(c *gin.Context) {
 		return
 	}
 
-	err = a.HospitalUsecase.Delete(uint(hospitalID))
+	req := &entities.HospitalDeleteRequest{Policy: consts.HospitalDeletePolicy(c.Query("policy"))}
+
+	if target := c.Query("target_hospital_id"); target != "" {
+		targetID, err := strconv.Atoi(target)
+		if err != nil {
+			utils.BadRequestResponse(c, "target_hospital_id must be an integer")
+			return
+		}
+		req.TargetHospitalID = uint(targetID)
+	}
+
+	if dryRun := c.Query("dry_run"); dryRun != "" {
+		if req.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			utils.BadRequestResponse(c, "dry_run must be true or false")
+			return
+		}
+	}
+
+	impact, err := a.HospitalUsecase.Delete(uint(hospitalID), req)
+	if errors.Is(err, entities.ErrHospitalInUse) || errors.Is(err, entities.ErrHospitalHNCollision) {
+		problem := utils.ProblemFromError(err)
+		problem.Data = impact
+		utils.WriteProblem(c, problem)
+		return
+	}
 	if err != nil {
-		utils.NotFoundResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
-	utils.OkResponse(c, nil)
+	utils.OkResponse(c, impact)
 }
@@ -3,16 +3,20 @@ package controllers_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Teemo4621/Hospital-Api/configs"
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
 	"github.com/Teemo4621/Hospital-Api/modules/hospitals/controllers"
 	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,14 +24,30 @@ import (
 
 // ----------- Test Setup ----------- //
 
+func testConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	return cfg
+}
+
 func setupRouter(usecase entities.HospitalUseCase) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := testConfig()
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
 	group := r.Group("/hospitals")
-	controllers.NewHospitalController(group, configs.Config{}, usecase)
+	controllers.NewHospitalController(group, *cfg, usecase, *authMiddleware)
 	return r
 }
 
+func withRole(req *http.Request, role consts.StaffRole) *http.Request {
+	token, _ := utils.GenerateAccessToken(testConfig(), &entities.Jwtpassport{Id: 1, HospitalID: 1, Role: string(role), Scopes: role.Scopes()})
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	return req
+}
+
 // ----------- Tests ----------- //
 
 func TestFindAllHospitalHandler(t *testing.T) {
@@ -37,7 +57,7 @@ func TestFindAllHospitalHandler(t *testing.T) {
 		hospitals := []entities.Hospital{
 			{ID: 1, HospitalName: "Test A", Address: "Bangkok"},
 		}
-		mockUsecase.On("FindAll", 1, 10).Return(hospitals, 1, nil)
+		mockUsecase.On("FindAll", &query.Spec{Page: 1, Limit: 10}).Return(hospitals, 1, nil)
 		req, _ := http.NewRequest(http.MethodGet, "/hospitals/", nil)
 		resp := httptest.NewRecorder()
 		r.ServeHTTP(resp, req)
@@ -46,13 +66,105 @@ func TestFindAllHospitalHandler(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 
+	t.Run("Filter And Sort", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+		mockUsecase.On("FindAll", &query.Spec{
+			Filters: []query.Filter{
+				{Field: "hospital_name", Column: "hospital_name", Op: query.OpLike, Value: "siriraj"},
+				{Field: "province", Column: "province", Op: query.OpEq, Value: "Bangkok"},
+			},
+			Sort:  []query.SortField{{Column: "province"}, {Column: "created_at", Desc: true}},
+			Page:  1,
+			Limit: 10,
+		}).Return([]entities.Hospital{}, 0, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/?name=siriraj&province=Bangkok&sort=province,created_at:desc", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Sort By Unknown Column", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/?sort=hospital_name%3BDROP%20TABLE%20hospitals", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUsecase.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+
+	t.Run("Filter Operators", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+		mockUsecase.On("FindAll", &query.Spec{
+			Filters: []query.Filter{
+				{Field: "created_at", Column: "created_at", Op: query.OpGte, Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{Field: "province", Column: "province", Op: query.OpIn, Value: []any{"Bangkok", "Nonthaburi"}},
+			},
+			Page:  1,
+			Limit: 10,
+		}).Return([]entities.Hospital{}, 0, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/?filter[province][in]=Bangkok,Nonthaburi&filter[created_at][gte]=2024-01-01", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Filter With Unsupported Operator", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/?filter[address][eq]=x", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"field":"filter[address][eq]"`)
+		mockUsecase.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+
+	t.Run("Sparse Fields", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+		hospitals := []entities.Hospital{
+			{ID: 1, HospitalName: "Test A", Address: "Bangkok"},
+		}
+		mockUsecase.On("FindAll", &query.Spec{
+			Fields:  []string{"hospital_name"},
+			Columns: []string{"id", "hospital_name"},
+			Page:    1,
+			Limit:   10,
+		}).Return(hospitals, 1, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/?fields=hospital_name", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		items := body["data"].(map[string]interface{})["hospitals"].([]interface{})
+		assert.Equal(t, map[string]interface{}{"hospital_name": "Test A"}, items[0])
+		mockUsecase.AssertExpectations(t)
+	})
+
 	t.Run("Pagination Page > Page Total", func(t *testing.T) {
 		mockUsecase := mocks.NewMockHospitalUseCase()
 		r := setupRouter(mockUsecase)
 		hospitals := []entities.Hospital{
 			{ID: 1, HospitalName: "Test A", Address: "Bangkok"},
 		}
-		mockUsecase.On("FindAll", 2, 10).Return(hospitals, 1, nil)
+		mockUsecase.On("FindAll", &query.Spec{Page: 2, Limit: 10}).Return(hospitals, 1, nil)
 
 		req, _ := http.NewRequest(http.MethodGet, "/hospitals/?page=2", nil)
 		resp := httptest.NewRecorder()
@@ -106,7 +218,7 @@ func TestFindAllHospitalHandler(t *testing.T) {
 		mockUsecase := mocks.NewMockHospitalUseCase()
 		r := setupRouter(mockUsecase)
 		hospitals := []entities.Hospital{}
-		mockUsecase.On("FindAll", 2, 10).Return(hospitals, 0, nil)
+		mockUsecase.On("FindAll", &query.Spec{Page: 2, Limit: 10}).Return(hospitals, 0, nil)
 
 		req, _ := http.NewRequest(http.MethodGet, "/hospitals/?page=2", nil)
 		resp := httptest.NewRecorder()
@@ -153,7 +265,7 @@ func TestFindByIdHospitalHandler(t *testing.T) {
 
 	t.Run("Not Found", func(t *testing.T) {
 		mockUsecase := mocks.NewMockHospitalUseCase()
-		mockUsecase.On("FindById", uint(99)).Return((*entities.Hospital)(nil), errors.New("hospital not found"))
+		mockUsecase.On("FindById", uint(99)).Return((*entities.Hospital)(nil), entities.NotFound("hospital"))
 
 		r := setupRouter(mockUsecase)
 		req, _ := http.NewRequest(http.MethodGet, "/hospitals/99", nil)
@@ -177,7 +289,38 @@ func TestFindByIdHospitalHandler(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		assert.Equal(t, "id is not a number", body["message"])
+		assert.Equal(t, "id is not a number", body["detail"])
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestFindNearbyHospitalHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		hospitals := []entities.HospitalDistance{
+			{Hospital: entities.Hospital{ID: 1, HospitalName: "Test A"}, DistanceKm: 2.5},
+		}
+		mockUsecase.On("FindNearby", 13.75, 100.5, 5.0, 0).Return(hospitals, nil)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/nearby?lat=13.75&lng=100.5&radius=5", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"distance_km":2.5`)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Missing Longitude", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/nearby?lat=13.75", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
 		mockUsecase.AssertExpectations(t)
 	})
 }
@@ -194,6 +337,7 @@ func TestCreate_Success(t *testing.T) {
 		body := `{"hospital_name":"Test","address":"Bangkok"}`
 		req, _ := http.NewRequest(http.MethodPost, "/hospitals/", bytes.NewBufferString(body))
 		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleSysAdmin)
 
 		resp := httptest.NewRecorder()
 		r.ServeHTTP(resp, req)
@@ -206,18 +350,19 @@ func TestCreate_Success(t *testing.T) {
 		mockUsecase := mocks.NewMockHospitalUseCase()
 
 		hospital := &entities.Hospital{HospitalName: "Test", Address: "Bangkok"}
-		mockUsecase.On("Create", mock.AnythingOfType("*entities.Hospital")).Return(hospital, errors.New("hospital name already exists"))
+		mockUsecase.On("Create", mock.AnythingOfType("*entities.Hospital")).Return(hospital, entities.Conflict("hospital_name_taken", "hospital name already exists"))
 
 		r := setupRouter(mockUsecase)
 
 		body := `{"hospital_name":"Test","address":"Bangkok"}`
 		req, _ := http.NewRequest(http.MethodPost, "/hospitals/", bytes.NewBufferString(body))
 		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleSysAdmin)
 
 		resp := httptest.NewRecorder()
 		r.ServeHTTP(resp, req)
 
-		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, http.StatusConflict, resp.Code)
 		mockUsecase.AssertExpectations(t)
 	})
 
@@ -229,6 +374,7 @@ func TestCreate_Success(t *testing.T) {
 		body := `{"hospital_name":"Test"}`
 		req, _ := http.NewRequest(http.MethodPost, "/hospitals/", bytes.NewBufferString(body))
 		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleSysAdmin)
 
 		resp := httptest.NewRecorder()
 		r.ServeHTTP(resp, req)
@@ -241,11 +387,12 @@ func TestCreate_Success(t *testing.T) {
 func TestDelete_Success(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockUsecase := mocks.NewMockHospitalUseCase()
-		mockUsecase.On("Delete", uint(1)).Return(nil)
+		mockUsecase.On("Delete", uint(1), &entities.HospitalDeleteRequest{}).Return(&entities.HospitalDeletionImpact{HospitalID: 1}, nil)
 
 		r := setupRouter(mockUsecase)
 
 		req, _ := http.NewRequest(http.MethodDelete, "/hospitals/1", nil)
+		withRole(req, consts.StaffRoleSysAdmin)
 		resp := httptest.NewRecorder()
 		r.ServeHTTP(resp, req)
 
@@ -255,11 +402,12 @@ func TestDelete_Success(t *testing.T) {
 
 	t.Run("Not Found", func(t *testing.T) {
 		mockUsecase := mocks.NewMockHospitalUseCase()
-		mockUsecase.On("Delete", uint(99)).Return(errors.New("hospital not found"))
+		mockUsecase.On("Delete", uint(99), &entities.HospitalDeleteRequest{}).Return((*entities.HospitalDeletionImpact)(nil), entities.NotFound("hospital"))
 
 		r := setupRouter(mockUsecase)
 
 		req, _ := http.NewRequest(http.MethodDelete, "/hospitals/99", nil)
+		withRole(req, consts.StaffRoleSysAdmin)
 		resp := httptest.NewRecorder()
 		r.ServeHTTP(resp, req)
 
@@ -267,12 +415,65 @@ func TestDelete_Success(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 
+	t.Run("Blocked Returns Impact", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		impact := &entities.HospitalDeletionImpact{HospitalID: 1, Policy: consts.HospitalDeleteRefuse, ActiveStaff: 2, Blocked: true}
+		mockUsecase.On("Delete", uint(1), &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteRefuse}).Return(impact, entities.ErrHospitalInUse)
+
+		r := setupRouter(mockUsecase)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/hospitals/1?policy=refuse", nil)
+		withRole(req, consts.StaffRoleSysAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"active_staff":2`)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("HN Collision Returns Impact", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		expected := &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteReassign, TargetHospitalID: 2}
+		impact := &entities.HospitalDeletionImpact{HospitalID: 1, Policy: consts.HospitalDeleteReassign, TargetHospitalID: 2, CollidingHNs: []string{"HN001"}, Blocked: true}
+		mockUsecase.On("Delete", uint(1), expected).Return(impact, entities.ErrHospitalHNCollision)
+
+		r := setupRouter(mockUsecase)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/hospitals/1?policy=reassign-to-hospital&target_hospital_id=2", nil)
+		withRole(req, consts.StaffRoleSysAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"code":"patient_hn_taken"`)
+		assert.Contains(t, resp.Body.String(), `"colliding_hns":["HN001"]`)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Dry Run Reassign", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		expected := &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteReassign, TargetHospitalID: 2, DryRun: true}
+		mockUsecase.On("Delete", uint(1), expected).Return(&entities.HospitalDeletionImpact{HospitalID: 1, DryRun: true}, nil)
+
+		r := setupRouter(mockUsecase)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/hospitals/1?policy=reassign-to-hospital&target_hospital_id=2&dry_run=true", nil)
+		withRole(req, consts.StaffRoleSysAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
 	t.Run("Id is not a number", func(t *testing.T) {
 		mockUsecase := mocks.NewMockHospitalUseCase()
 
 		r := setupRouter(mockUsecase)
 
 		req, _ := http.NewRequest(http.MethodDelete, "/hospitals/dsawd", nil)
+		withRole(req, consts.StaffRoleSysAdmin)
 		resp := httptest.NewRecorder()
 		r.ServeHTTP(resp, req)
 
@@ -280,3 +481,105 @@ func TestDelete_Success(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 }
+
+func TestHospitalManagementAccess(t *testing.T) {
+	t.Run("Unauthenticated", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/hospitals/1", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		mockUsecase.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("Hospital Admin Is Forbidden", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+
+		body := `{"hospital_name":"Test","address":"Bangkok"}`
+		req, _ := http.NewRequest(http.MethodPost, "/hospitals/", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockUsecase.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestUpdateHospitalHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+
+		mockUsecase.On("Update", &entities.Hospital{ID: 1, HospitalName: "New", Address: "Bangkok", Version: 3}).
+			Return(&entities.Hospital{ID: 1, HospitalName: "New", Address: "Bangkok", Version: 4}, nil)
+
+		body := `{"hospital_name":"New","address":"Bangkok"}`
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"3"`)
+		withRole(req, consts.StaffRoleSysAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"4"`, resp.Header().Get("ETag"))
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Missing If-Match", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+
+		body := `{"hospital_name":"New","address":"Bangkok"}`
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleSysAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+		mockUsecase.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Stale Version", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+
+		mockUsecase.On("Update", mock.Anything).Return((*entities.Hospital)(nil), entities.ErrVersionConflict)
+
+		body := `{"hospital_name":"New","address":"Bangkok"}`
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"2"`)
+		withRole(req, consts.StaffRoleSysAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	})
+
+	t.Run("Patch Keeps Missing Fields", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalUseCase()
+		r := setupRouter(mockUsecase)
+
+		mockUsecase.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1, HospitalName: "Old", Address: "Bangkok", Version: 3}, nil)
+		mockUsecase.On("Update", &entities.Hospital{ID: 1, HospitalName: "New", Address: "Bangkok", Version: 3}).
+			Return(&entities.Hospital{ID: 1, HospitalName: "New", Address: "Bangkok", Version: 4}, nil)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/hospitals/1", bytes.NewBufferString(`{"hospital_name":"New"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"3"`)
+		withRole(req, consts.StaffRoleSysAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HospitalCodeRepo struct {
+	Db *gorm.DB
+}
+
+func NewHospitalCodeRepository(db *gorm.DB) entities.HospitalCodeRepository {
+	return &HospitalCodeRepo{Db: db}
+}
+
+// Upsert inserts the entries and overwrites the ones already known by code.
+func (r *HospitalCodeRepo) Upsert(entries []entities.HospitalCodeEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.Db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		UpdateAll: true,
+	}).Create(&entries).Error
+}
+
+func (r *HospitalCodeRepo) FindByCode(code string) (*entities.HospitalCodeEntry, error) {
+	var entry entities.HospitalCodeEntry
+	if err := r.Db.First(&entry, "code = ?", code).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
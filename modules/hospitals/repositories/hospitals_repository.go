@@ -1,12 +1,24 @@
 package repositories
 
 import (
+	"sort"
+	"sync"
+	"time"
+
+	beds "github.com/Teemo4621/Hospital-Api/modules/beds/repositories"
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/geo"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type HospitalRepo struct {
 	Db *gorm.DB
+
+	postgisOnce sync.Once
+	postgis     bool
 }
 
 func NewHospitalRepository(db *gorm.DB) entities.HospitalRepository {
@@ -21,29 +33,220 @@ func (r *HospitalRepo) Create(hospital *entities.Hospital) (*entities.Hospital,
 	return hospital, nil
 }
 
+// Update saves the hospital under optimistic locking. A rename keeps the old
+// name as an alias, and renaming back to an alias of the same hospital
+// drops that alias again.
 func (r *HospitalRepo) Update(hospital *entities.Hospital) (*entities.Hospital, error) {
-	if err := r.Db.Save(&hospital).Error; err != nil {
+	version := hospital.Version
+	hospital.Version = version + 1
+
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		var current entities.Hospital
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "hospital_name").First(&current, hospital.ID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(hospital).
+			Where("version = ?", version).
+			Select("*").
+			Omit("id", "created_at", "deleted_at", clause.Associations).
+			Updates(hospital)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrVersionConflict
+		}
+
+		if current.HospitalName == hospital.HospitalName {
+			return nil
+		}
+
+		if err := tx.Where("hospital_id = ? AND name = ?", hospital.ID, hospital.HospitalName).Delete(&entities.HospitalNameAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entities.HospitalNameAlias{HospitalID: hospital.ID, Name: current.HospitalName}).Error
+	})
+	if err != nil {
+		hospital.Version = version
 		return nil, err
 	}
 
 	return hospital, nil
 }
 
-func (r *HospitalRepo) Delete(id uint) error {
-	return r.Db.Delete(&entities.Hospital{}, id).Error
+// Delete soft deletes the hospital after applying the policy to its active
+// staff and patients. The hospital row stays locked while the counts are
+// taken, so nobody can be added between the check and the deletion.
+func (r *HospitalRepo) Delete(id uint, policy consts.HospitalDeletePolicy, targetHospitalId uint) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		var hospital entities.Hospital
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&hospital, id).Error; err != nil {
+			return err
+		}
+
+		staffs := tx.Model(&entities.Staff{}).Where("hospital_id = ? AND archived_at IS NULL", id)
+		patients := tx.Model(&entities.Patient{}).Where("hospital_id = ? AND archived_at IS NULL", id)
+
+		switch policy {
+		case consts.HospitalDeleteArchiveAll:
+			now := time.Now()
+			if err := staffs.UpdateColumn("archived_at", now).Error; err != nil {
+				return err
+			}
+			if err := patients.UpdateColumn("archived_at", now).Error; err != nil {
+				return err
+			}
+
+		case consts.HospitalDeleteReassign:
+			// Locking the target for update keeps new patients, and so new
+			// HNs, out of it until the move is done.
+			var target entities.Hospital
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&target, targetHospitalId).Error; err != nil {
+				return err
+			}
+
+			hns, err := collidingHNs(tx, id, target.ID)
+			if err != nil {
+				return err
+			}
+			if len(hns) > 0 {
+				return entities.ErrHospitalHNCollision
+			}
+
+			// The beds stay in the old hospital's wards, so admitted
+			// patients leave them and get a bed at the target.
+			moving := activePatientIDs(tx, id)
+			var admitted []uint
+			if err := tx.Model(&entities.BedAssignment{}).
+				Where("hospital_id = ? AND released_at IS NULL AND patient_id IN (?)", id, moving).
+				Pluck("encounter_id", &admitted).Error; err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, encounterId := range admitted {
+				if _, err := beds.ReleaseOpen(tx, encounterId, now, "hospital deleted"); err != nil {
+					return err
+				}
+			}
+
+			for _, model := range []interface{}{&entities.Encounter{}, &entities.Appointment{}, &entities.BedAssignment{}} {
+				if err := tx.Model(model).
+					Where("hospital_id = ? AND patient_id IN (?)", id, moving).
+					UpdateColumn("hospital_id", target.ID).Error; err != nil {
+					return err
+				}
+			}
+
+			// Departments belong to the old hospital, so the moved staff
+			// leave them.
+			departments := tx.Model(&entities.Department{}).Select("id").Where("hospital_id = ?", id)
+			if err := tx.Where("department_id IN (?)", departments).Delete(&entities.StaffDepartment{}).Error; err != nil {
+				return err
+			}
+			if err := staffs.UpdateColumn("hospital_id", target.ID).Error; err != nil {
+				return err
+			}
+			if err := patients.UpdateColumn("hospital_id", target.ID).Error; err != nil {
+				return err
+			}
+
+		default:
+			var staffCount, patientCount int64
+			if err := staffs.Count(&staffCount).Error; err != nil {
+				return err
+			}
+			if err := patients.Count(&patientCount).Error; err != nil {
+				return err
+			}
+			if staffCount > 0 || patientCount > 0 {
+				return entities.ErrHospitalInUse
+			}
+		}
+
+		return tx.Delete(&hospital).Error
+	})
+}
+
+// FindDeletionImpact counts what deleting the hospital touches. With a
+// target hospital it also counts what a reassignment moves there and which
+// HNs collide.
+func (r *HospitalRepo) FindDeletionImpact(id uint, targetHospitalId uint) (*entities.HospitalDeletionImpact, error) {
+	impact := &entities.HospitalDeletionImpact{HospitalID: id}
+
+	if err := r.Db.Model(&entities.Staff{}).Where("hospital_id = ? AND archived_at IS NULL", id).Count(&impact.ActiveStaff).Error; err != nil {
+		return nil, err
+	}
+	if err := r.Db.Model(&entities.Patient{}).Where("hospital_id = ? AND archived_at IS NULL", id).Count(&impact.ActivePatients).Error; err != nil {
+		return nil, err
+	}
+	if err := r.Db.Model(&entities.Department{}).Where("hospital_id = ?", id).Count(&impact.Departments).Error; err != nil {
+		return nil, err
+	}
+
+	if targetHospitalId == 0 {
+		return impact, nil
+	}
+
+	moving := activePatientIDs(r.Db, id)
+	counts := []struct {
+		model interface{}
+		where string
+		count *int64
+	}{
+		{&entities.Encounter{}, "hospital_id = ? AND patient_id IN (?)", &impact.Encounters},
+		{&entities.Appointment{}, "hospital_id = ? AND patient_id IN (?)", &impact.Appointments},
+		{&entities.BedAssignment{}, "hospital_id = ? AND patient_id IN (?)", &impact.BedAssignments},
+		{&entities.BedAssignment{}, "hospital_id = ? AND patient_id IN (?) AND released_at IS NULL", &impact.OpenBedAssignments},
+	}
+	for _, c := range counts {
+		if err := r.Db.Model(c.model).Where(c.where, id, moving).Count(c.count).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	hns, err := collidingHNs(r.Db, id, targetHospitalId)
+	if err != nil {
+		return nil, err
+	}
+	impact.CollidingHNs = hns
+
+	return impact, nil
+}
+
+// activePatientIDs selects the ids of the hospital's active patients, for
+// use as a subquery.
+func activePatientIDs(db *gorm.DB, hospitalId uint) *gorm.DB {
+	return db.Model(&entities.Patient{}).Select("id").Where("hospital_id = ? AND archived_at IS NULL", hospitalId)
 }
 
-func (r *HospitalRepo) FindHospitalCount() (int64, error) {
+// collidingHNs lists the HNs of the hospital's active patients that any
+// patient of the target hospital, archived or not, already uses.
+func collidingHNs(db *gorm.DB, hospitalId uint, targetHospitalId uint) ([]string, error) {
+	moving := db.Model(&entities.Patient{}).Select("patient_hn").Where("hospital_id = ? AND archived_at IS NULL AND patient_hn <> ''", hospitalId)
+
+	var hns []string
+	if err := db.Model(&entities.Patient{}).
+		Distinct("patient_hn").
+		Where("hospital_id = ? AND patient_hn IN (?)", targetHospitalId, moving).
+		Order("patient_hn").
+		Pluck("patient_hn", &hns).Error; err != nil {
+		return nil, err
+	}
+	return hns, nil
+}
+
+func (r *HospitalRepo) FindHospitalCount(spec *query.Spec) (int64, error) {
 	var count int64
-	if err := r.Db.Model(&entities.Hospital{}).Count(&count).Error; err != nil {
+	if err := r.Db.Model(&entities.Hospital{}).Scopes(spec.Where(nil)).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (r *HospitalRepo) FindAll(page int, limit int) ([]entities.Hospital, error) {
+func (r *HospitalRepo) FindAll(spec *query.Spec) ([]entities.Hospital, error) {
 	var hospitals []entities.Hospital
-	if err := r.Db.Offset((page - 1) * limit).Limit(limit).Find(&hospitals).Error; err != nil {
+	if err := r.Db.Scopes(spec.Where(nil), spec.List).Find(&hospitals).Error; err != nil {
 		return nil, err
 	}
 	return hospitals, nil
@@ -57,10 +260,78 @@ func (r *HospitalRepo) FindById(id uint) (*entities.Hospital, error) {
 	return &hospital, nil
 }
 
+func (r *HospitalRepo) FindByCode(code string) (*entities.Hospital, error) {
+	var hospital entities.Hospital
+	if err := r.Db.Where("code = ?", code).First(&hospital).Error; err != nil {
+		return nil, err
+	}
+	return &hospital, nil
+}
+
+// FindByName matches the current name or a former name of a hospital.
 func (r *HospitalRepo) FindByName(name string) (*entities.Hospital, error) {
 	var hospital entities.Hospital
-	if err := r.Db.Where("hospital_name = ?", name).First(&hospital).Error; err != nil {
+	aliases := r.Db.Model(&entities.HospitalNameAlias{}).Select("hospital_id").Where("name = ?", name)
+	if err := r.Db.Where("hospital_name = ? OR id IN (?)", name, aliases).First(&hospital).Error; err != nil {
 		return nil, err
 	}
 	return &hospital, nil
 }
+
+// FindNearby returns hospitals with coordinates within radiusKm of the
+// point, closest first. It lets PostGIS compute the distance when the
+// extension is installed and falls back to haversine in Go otherwise; both
+// paths prefilter on the bounding box so the location index is used.
+func (r *HospitalRepo) FindNearby(lat float64, lng float64, radiusKm float64, limit int) ([]entities.HospitalDistance, error) {
+	center := geo.Point{Lat: lat, Lng: lng}
+	box := geo.BoundingBox(center, radiusKm)
+	query := r.Db.Model(&entities.Hospital{}).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", box.MinLat, box.MaxLat, box.MinLng, box.MaxLng)
+
+	if r.hasPostGIS() {
+		var hospitals []entities.HospitalDistance
+		distance := "ST_DistanceSphere(ST_MakePoint(longitude, latitude), ST_MakePoint(?, ?)) / 1000"
+		err := query.
+			Select("hospitals.*, "+distance+" AS distance_km", lng, lat).
+			Where(distance+" <= ?", lng, lat, radiusKm).
+			Order("distance_km").
+			Limit(limit).
+			Scan(&hospitals).Error
+		if err != nil {
+			return nil, err
+		}
+		return hospitals, nil
+	}
+
+	var candidates []entities.Hospital
+	if err := query.Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	hospitals := make([]entities.HospitalDistance, 0, len(candidates))
+	for _, hospital := range candidates {
+		distance := geo.DistanceKm(center, geo.Point{Lat: *hospital.Latitude, Lng: *hospital.Longitude})
+		if distance <= radiusKm {
+			hospitals = append(hospitals, entities.HospitalDistance{Hospital: hospital, DistanceKm: distance})
+		}
+	}
+
+	sort.SliceStable(hospitals, func(i, j int) bool {
+		return hospitals[i].DistanceKm < hospitals[j].DistanceKm
+	})
+	if len(hospitals) > limit {
+		hospitals = hospitals[:limit]
+	}
+	return hospitals, nil
+}
+
+// hasPostGIS checks once whether the postgis extension is installed.
+func (r *HospitalRepo) hasPostGIS() bool {
+	r.postgisOnce.Do(func() {
+		var count int64
+		if err := r.Db.Raw("SELECT COUNT(*) FROM pg_extension WHERE extname = 'postgis'").Scan(&count).Error; err == nil {
+			r.postgis = count > 0
+		}
+	})
+	return r.postgis
+}
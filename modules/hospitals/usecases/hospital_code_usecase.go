@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/tabular"
+)
+
+// hospitalCodeBatchSize is the number of entries upserted per statement.
+const hospitalCodeBatchSize = 500
+
+// maxHospitalCodeIssues caps the skipped rows listed in an import result.
+const maxHospitalCodeIssues = 100
+
+// hospitalCodeColumns lists, for each entry field, the headers it is read
+// from: the English names and those of the ministry's own export.
+var hospitalCodeColumns = []struct {
+	name    string
+	headers []string
+	set     func(entry *entities.HospitalCodeEntry, value string)
+}{
+	{"code", []string{"code", "hcode", "hospcode", "รหัส 5 หลัก", "รหัสหน่วยบริการ", "รหัสหน่วยงาน"}, func(e *entities.HospitalCodeEntry, v string) { e.Code = v }},
+	{"name", []string{"name", "hname", "hospital_name", "ชื่อหน่วยบริการ", "ชื่อหน่วยงาน", "ชื่อ"}, func(e *entities.HospitalCodeEntry, v string) { e.Name = v }},
+	{"type", []string{"type", "ประเภทหน่วยบริการ", "ประเภทหน่วยงาน", "ประเภท"}, func(e *entities.HospitalCodeEntry, v string) { e.Type = v }},
+	{"affiliation", []string{"affiliation", "สังกัด"}, func(e *entities.HospitalCodeEntry, v string) { e.Affiliation = v }},
+	{"subdistrict", []string{"subdistrict", "tambon", "ตำบล/แขวง", "ตำบล"}, func(e *entities.HospitalCodeEntry, v string) { e.Subdistrict = v }},
+	{"district", []string{"district", "amphur", "อำเภอ/เขต", "อำเภอ"}, func(e *entities.HospitalCodeEntry, v string) { e.District = v }},
+	{"province", []string{"province", "changwat", "จังหวัด"}, func(e *entities.HospitalCodeEntry, v string) { e.Province = v }},
+	{"postal_code", []string{"postal_code", "zipcode", "รหัสไปรษณีย์"}, func(e *entities.HospitalCodeEntry, v string) { e.PostalCode = v }},
+}
+
+type HospitalCodeUseCase struct {
+	repo         entities.HospitalCodeRepository
+	hospitalRepo entities.HospitalRepository
+}
+
+func NewHospitalCodeUseCase(repo entities.HospitalCodeRepository, hospitalRepo entities.HospitalRepository) entities.HospitalCodeUseCase {
+	return &HospitalCodeUseCase{repo: repo, hospitalRepo: hospitalRepo}
+}
+
+// Import reads the ministry's hospital code list from CSV. Rows without a
+// valid code or a name, and repeats of a code, are skipped and reported;
+// the rest are upserted in batches.
+func (u *HospitalCodeUseCase) Import(r io.Reader) (*entities.HospitalCodeImportResult, error) {
+	reader, err := tabular.NewReader(tabular.FormatCSV, r)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := resolveHospitalCodeColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.HospitalCodeImportResult{Issues: []entities.HospitalCodeImportIssue{}}
+	skip := func(line int, message string) {
+		result.Skipped++
+		if len(result.Issues) < maxHospitalCodeIssues {
+			result.Issues = append(result.Issues, entities.HospitalCodeImportIssue{Row: line, Message: message})
+		}
+	}
+
+	now := time.Now()
+	seen := make(map[string]int)
+	var batch []entities.HospitalCodeEntry
+
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if isBlankRow(row) {
+			continue
+		}
+
+		entry := entities.HospitalCodeEntry{ImportedAt: now}
+		for _, column := range hospitalCodeColumns {
+			if position, ok := columns[column.name]; ok && position < len(row) {
+				column.set(&entry, strings.TrimSpace(row[position]))
+			}
+		}
+
+		entry.Code = normalizeHospitalCode(entry.Code)
+		if !entities.HospitalCodePattern.MatchString(entry.Code) {
+			skip(line, "code must be the 5 digit MOPH hospital code")
+			continue
+		}
+		if entry.Name == "" {
+			skip(line, "name is required")
+			continue
+		}
+		if first, ok := seen[entry.Code]; ok {
+			skip(line, fmt.Sprintf("code %s repeats row %d", entry.Code, first))
+			continue
+		}
+		seen[entry.Code] = line
+
+		batch = append(batch, entry)
+		if len(batch) == hospitalCodeBatchSize {
+			if err := u.repo.Upsert(batch); err != nil {
+				return nil, err
+			}
+			result.Imported += len(batch)
+			batch = nil
+		}
+	}
+
+	if err := u.repo.Upsert(batch); err != nil {
+		return nil, err
+	}
+	result.Imported += len(batch)
+
+	return result, nil
+}
+
+// Lookup finds a code in the ministry list and among the hospitals
+// registered here.
+func (u *HospitalCodeUseCase) Lookup(code string) (*entities.HospitalCodeLookup, error) {
+	code = strings.TrimSpace(code)
+	if !entities.HospitalCodePattern.MatchString(code) {
+		return nil, &entities.FieldError{Field: "code", Message: "code must be the 5 digit MOPH hospital code"}
+	}
+
+	lookup := &entities.HospitalCodeLookup{Code: code}
+	if entry, err := u.repo.FindByCode(code); err == nil {
+		lookup.Registry = entry
+	}
+	if hospital, err := u.hospitalRepo.FindByCode(code); err == nil {
+		lookup.Hospital = hospital
+	}
+
+	if lookup.Registry == nil && lookup.Hospital == nil {
+		return nil, entities.NotFound("hospital code")
+	}
+	return lookup, nil
+}
+
+func resolveHospitalCodeColumns(header []string) (map[string]int, error) {
+	positions := map[string]int{}
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, exists := positions[key]; !exists {
+			positions[key] = i
+		}
+	}
+
+	columns := map[string]int{}
+	for _, column := range hospitalCodeColumns {
+		for _, name := range column.headers {
+			if position, ok := positions[name]; ok {
+				columns[column.name] = position
+				break
+			}
+		}
+	}
+
+	for _, required := range []string{"code", "name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("the file has no %s column", required)
+		}
+	}
+	return columns, nil
+}
+
+// normalizeHospitalCode restores the leading zeros spreadsheet programs
+// drop from numeric codes, so 1234 is read as 01234.
+func normalizeHospitalCode(code string) string {
+	if code == "" || len(code) >= 5 {
+		return code
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return code
+		}
+	}
+	return strings.Repeat("0", 5-len(code)) + code
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package usecases_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/hospitals/usecases"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportHospitalCodes(t *testing.T) {
+	t.Run("Ministry Headers", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalCodeRepository()
+		usecase := usecases.NewHospitalCodeUseCase(mockRepo, mocks.NewMockHospitalRepository())
+
+		file := "\ufeffรหัส 5 หลัก,ชื่อหน่วยงาน,ประเภทหน่วยงาน,จังหวัด,รหัสไปรษณีย์\n" +
+			"13781,โรงพยาบาลศิริราช,โรงพยาบาลศูนย์,กรุงเทพมหานคร,10700\n" +
+			"1234,โรงพยาบาลตัวอย่าง,โรงพยาบาลชุมชน,นนทบุรี,11000\n" +
+			"ABCDE,โรงพยาบาลผิดรหัส,,,\n" +
+			"13781,โรงพยาบาลซ้ำ,,,\n" +
+			",,,,\n" +
+			"10661,,,,\n"
+
+		mockRepo.On("Upsert", mock.MatchedBy(func(entries []entities.HospitalCodeEntry) bool {
+			return len(entries) == 2 &&
+				entries[0].Code == "13781" && entries[0].Name == "โรงพยาบาลศิริราช" && entries[0].PostalCode == "10700" &&
+				entries[1].Code == "01234" && entries[1].Province == "นนทบุรี"
+		})).Return(nil)
+
+		result, err := usecase.Import(strings.NewReader(file))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 3, result.Skipped)
+		assert.Equal(t, []entities.HospitalCodeImportIssue{
+			{Row: 4, Message: "code must be the 5 digit MOPH hospital code"},
+			{Row: 5, Message: "code 13781 repeats row 2"},
+			{Row: 7, Message: "name is required"},
+		}, result.Issues)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Missing Code Column", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalCodeRepository()
+		usecase := usecases.NewHospitalCodeUseCase(mockRepo, mocks.NewMockHospitalRepository())
+
+		_, err := usecase.Import(strings.NewReader("name,province\nA,B\n"))
+		assert.EqualError(t, err, "the file has no code column")
+		mockRepo.AssertNotCalled(t, "Upsert", mock.Anything)
+	})
+}
+
+func TestLookupHospitalCode(t *testing.T) {
+	t.Run("Registered Hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalCodeRepository()
+		mockHospitalRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalCodeUseCase(mockRepo, mockHospitalRepo)
+
+		mockRepo.On("FindByCode", "13781").Return(&entities.HospitalCodeEntry{Code: "13781", Name: "โรงพยาบาลศิริราช"}, nil)
+		mockHospitalRepo.On("FindByCode", "13781").Return(&entities.Hospital{ID: 3, Code: "13781"}, nil)
+
+		lookup, err := usecase.Lookup("13781")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), lookup.Hospital.ID)
+		assert.Equal(t, "โรงพยาบาลศิริราช", lookup.Registry.Name)
+	})
+
+	t.Run("Unknown Code", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalCodeRepository()
+		mockHospitalRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalCodeUseCase(mockRepo, mockHospitalRepo)
+
+		mockRepo.On("FindByCode", "99999").Return((*entities.HospitalCodeEntry)(nil), errors.New("record not found"))
+		mockHospitalRepo.On("FindByCode", "99999").Return((*entities.Hospital)(nil), errors.New("record not found"))
+
+		_, err := usecase.Lookup("99999")
+		assert.EqualError(t, err, "hospital code not found")
+	})
+
+	t.Run("Malformed Code", func(t *testing.T) {
+		usecase := usecases.NewHospitalCodeUseCase(mocks.NewMockHospitalCodeRepository(), mocks.NewMockHospitalRepository())
+
+		_, err := usecase.Lookup("13-78")
+		var fieldErr *entities.FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+	})
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
 	"github.com/Teemo4621/Hospital-Api/modules/hospitals/usecases"
 	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // ---------- TEST CASES ---------- //
@@ -37,6 +40,84 @@ func TestCreateHospital(t *testing.T) {
 		_, err := usecase.Create(input)
 		assert.EqualError(t, err, "hospital name already exists")
 	})
+
+	t.Run("Latitude without longitude", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+		lat := 13.75
+		input := &entities.Hospital{HospitalName: "Test Hospital", Address: "Bangkok", Latitude: &lat}
+
+		_, err := usecase.Create(input)
+		assert.EqualError(t, err, "latitude and longitude must be set together")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Invalid postal code", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+		input := &entities.Hospital{HospitalName: "Test Hospital", Address: "Bangkok", PostalCode: "1050"}
+
+		_, err := usecase.Create(input)
+		assert.EqualError(t, err, "postal_code must be 5 digits")
+	})
+
+	t.Run("Invalid hospital code", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+		input := &entities.Hospital{HospitalName: "Test Hospital", Address: "Bangkok", Code: "H1234"}
+
+		_, err := usecase.Create(input)
+		assert.EqualError(t, err, "code must be the 5 digit MOPH hospital code")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Hospital code already used", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+		input := &entities.Hospital{HospitalName: "Test Hospital", Address: "Bangkok", Code: " 10661 "}
+
+		mockRepo.On("FindByName", "Test Hospital").Return((*entities.Hospital)(nil), nil)
+		mockRepo.On("FindByCode", "10661").Return(&entities.Hospital{ID: 2, Code: "10661"}, nil)
+
+		_, err := usecase.Create(input)
+		assert.EqualError(t, err, "hospital code already exists")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestFindNearbyHospitals(t *testing.T) {
+	t.Run("Defaults radius and limit", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		hospitals := []entities.HospitalDistance{{Hospital: entities.Hospital{ID: 1}, DistanceKm: 1.2}}
+		mockRepo.On("FindNearby", 13.75, 100.5, 10.0, 20).Return(hospitals, nil)
+
+		result, err := usecase.FindNearby(13.75, 100.5, 0, 0)
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Caps radius", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindNearby", 13.75, 100.5, 500.0, 5).Return([]entities.HospitalDistance{}, nil)
+
+		_, err := usecase.FindNearby(13.75, 100.5, 10000, 5)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid point", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		_, err := usecase.FindNearby(95, 100.5, 10, 20)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "FindNearby", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestUpdateHospital(t *testing.T) {
@@ -48,6 +129,7 @@ func TestUpdateHospital(t *testing.T) {
 		updated := &entities.Hospital{ID: 1, HospitalName: "New", Address: "New Addr"}
 
 		mockRepo.On("FindById", uint(1)).Return(existing, nil)
+		mockRepo.On("FindByName", "New").Return((*entities.Hospital)(nil), errors.New("record not found"))
 		mockRepo.On("Update", existing).Return(updated, nil)
 
 		result, err := usecase.Update(updated)
@@ -55,6 +137,42 @@ func TestUpdateHospital(t *testing.T) {
 		assert.Equal(t, "New", result.HospitalName)
 	})
 
+	t.Run("Name taken by another hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1, HospitalName: "Old"}, nil)
+		mockRepo.On("FindByName", "Taken").Return(&entities.Hospital{ID: 2, HospitalName: "Taken"}, nil)
+
+		_, err := usecase.Update(&entities.Hospital{ID: 1, HospitalName: " Taken "})
+		assert.EqualError(t, err, "hospital name already exists")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Rename back to a former name", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		existing := &entities.Hospital{ID: 1, HospitalName: "New"}
+		mockRepo.On("FindById", uint(1)).Return(existing, nil)
+		mockRepo.On("FindByName", "Old").Return(&entities.Hospital{ID: 1, HospitalName: "New"}, nil)
+		mockRepo.On("Update", existing).Return(existing, nil)
+
+		result, err := usecase.Update(&entities.Hospital{ID: 1, HospitalName: "Old"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Old", result.HospitalName)
+	})
+
+	t.Run("Empty name", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1, HospitalName: "Old"}, nil)
+
+		_, err := usecase.Update(&entities.Hospital{ID: 1, HospitalName: "  "})
+		assert.EqualError(t, err, "hospital name is required")
+	})
+
 	t.Run("Hospital not found", func(t *testing.T) {
 		mockRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewHospitalUseCase(mockRepo)
@@ -64,6 +182,17 @@ func TestUpdateHospital(t *testing.T) {
 		_, err := usecase.Update(&entities.Hospital{ID: 1})
 		assert.Equal(t, "hospital not found", err.Error())
 	})
+
+	t.Run("Version conflict", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1, Version: 4}, nil)
+
+		_, err := usecase.Update(&entities.Hospital{ID: 1, HospitalName: "New", Version: 3})
+		assert.ErrorIs(t, err, entities.ErrVersionConflict)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
 }
 
 func TestDeleteHospital(t *testing.T) {
@@ -73,10 +202,105 @@ func TestDeleteHospital(t *testing.T) {
 
 		h := &entities.Hospital{ID: 1}
 		mockRepo.On("FindById", uint(1)).Return(h, nil)
-		mockRepo.On("Delete", uint(1)).Return(nil)
+		mockRepo.On("FindDeletionImpact", uint(1), uint(0)).Return(&entities.HospitalDeletionImpact{HospitalID: 1}, nil)
+		mockRepo.On("Delete", uint(1), consts.HospitalDeleteRefuse, uint(0)).Return(nil)
 
-		err := usecase.Delete(1)
+		impact, err := usecase.Delete(1, &entities.HospitalDeleteRequest{})
 		assert.NoError(t, err)
+		assert.Equal(t, consts.HospitalDeleteRefuse, impact.Policy)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Refused while in use", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
+		mockRepo.On("FindDeletionImpact", uint(1), uint(0)).Return(&entities.HospitalDeletionImpact{HospitalID: 1, ActiveStaff: 3}, nil)
+
+		impact, err := usecase.Delete(1, &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteRefuse})
+		assert.ErrorIs(t, err, entities.ErrHospitalInUse)
+		assert.True(t, impact.Blocked)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
+		mockRepo.On("FindDeletionImpact", uint(1), uint(0)).Return(&entities.HospitalDeletionImpact{HospitalID: 1, ActivePatients: 12}, nil)
+
+		impact, err := usecase.Delete(1, &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteArchiveAll, DryRun: true})
+		assert.NoError(t, err)
+		assert.False(t, impact.Blocked)
+		assert.Equal(t, int64(12), impact.ActivePatients)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reassign", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
+		mockRepo.On("FindById", uint(2)).Return(&entities.Hospital{ID: 2}, nil)
+		mockRepo.On("FindDeletionImpact", uint(1), uint(2)).Return(&entities.HospitalDeletionImpact{HospitalID: 1, ActiveStaff: 3, ActivePatients: 2, Encounters: 4, Appointments: 1}, nil)
+		mockRepo.On("Delete", uint(1), consts.HospitalDeleteReassign, uint(2)).Return(nil)
+
+		impact, err := usecase.Delete(1, &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteReassign, TargetHospitalID: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), impact.TargetHospitalID)
+		assert.Equal(t, int64(4), impact.Encounters)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reassign refused on HN collision", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
+		mockRepo.On("FindById", uint(2)).Return(&entities.Hospital{ID: 2}, nil)
+		mockRepo.On("FindDeletionImpact", uint(1), uint(2)).Return(&entities.HospitalDeletionImpact{HospitalID: 1, ActivePatients: 2, CollidingHNs: []string{"HN001"}}, nil)
+
+		impact, err := usecase.Delete(1, &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteReassign, TargetHospitalID: 2})
+		assert.ErrorIs(t, err, entities.ErrHospitalHNCollision)
+		assert.True(t, impact.Blocked)
+		assert.Equal(t, []string{"HN001"}, impact.CollidingHNs)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reassign dry run reports HN collision", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
+		mockRepo.On("FindById", uint(2)).Return(&entities.Hospital{ID: 2}, nil)
+		mockRepo.On("FindDeletionImpact", uint(1), uint(2)).Return(&entities.HospitalDeletionImpact{HospitalID: 1, ActivePatients: 2, CollidingHNs: []string{"HN001"}}, nil)
+
+		impact, err := usecase.Delete(1, &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteReassign, TargetHospitalID: 2, DryRun: true})
+		assert.NoError(t, err)
+		assert.True(t, impact.Blocked)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reassign to itself", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
+
+		_, err := usecase.Delete(1, &entities.HospitalDeleteRequest{Policy: consts.HospitalDeleteReassign, TargetHospitalID: 1})
+		assert.EqualError(t, err, "target_hospital_id must be another hospital")
+	})
+
+	t.Run("Unknown policy", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewHospitalUseCase(mockRepo)
+
+		mockRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
+
+		_, err := usecase.Delete(1, &entities.HospitalDeleteRequest{Policy: "cascade"})
+		assert.EqualError(t, err, "policy must be refuse, archive-all or reassign-to-hospital")
 	})
 
 	t.Run("Hospital not found", func(t *testing.T) {
@@ -85,34 +309,37 @@ func TestDeleteHospital(t *testing.T) {
 
 		mockRepo.On("FindById", uint(1)).Return((*entities.Hospital)(nil), errors.New("hospital not found"))
 
-		err := usecase.Delete(1)
+		_, err := usecase.Delete(1, &entities.HospitalDeleteRequest{})
 		assert.EqualError(t, err, "hospital not found")
 	})
 }
 
 func TestFindAllHospitals(t *testing.T) {
+	spec := &query.Spec{Page: 1, Limit: 10}
+
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewHospitalUseCase(mockRepo)
 
 		hospitals := []entities.Hospital{{ID: 1}, {ID: 2}}
 
-		mockRepo.On("FindHospitalCount").Return(int64(1), nil)
-		mockRepo.On("FindAll", 1, 10).Return(hospitals, nil)
+		mockRepo.On("FindHospitalCount", spec).Return(int64(21), nil)
+		mockRepo.On("FindAll", spec).Return(hospitals, nil)
 
-		result, _, err := usecase.FindAll(1, 10)
+		result, totalPage, err := usecase.FindAll(spec)
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
+		assert.Equal(t, 3, totalPage)
 	})
 
 	t.Run("Failed", func(t *testing.T) {
 		mockRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewHospitalUseCase(mockRepo)
 
-		mockRepo.On("FindHospitalCount").Return(int64(1), errors.New("failed to find hospitals"))
-		mockRepo.On("FindAll", 1, 10).Return(nil, errors.New("failed to find hospitals"))
+		mockRepo.On("FindHospitalCount", spec).Return(int64(1), errors.New("failed to find hospitals"))
+		mockRepo.On("FindAll", spec).Return(nil, errors.New("failed to find hospitals"))
 
-		_, _, err := usecase.FindAll(1, 10)
+		_, _, err := usecase.FindAll(spec)
 		assert.EqualError(t, err, "failed to find hospitals")
 	})
 }
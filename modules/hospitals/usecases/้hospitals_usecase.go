@@ -2,8 +2,12 @@ package usecases
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/geo"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 )
 
 type HospitalUseCase struct {
@@ -14,11 +18,30 @@ func NewHospitalUseCase(repo entities.HospitalRepository) entities.HospitalUseCa
 	return &HospitalUseCase{repo: repo}
 }
 
+const (
+	defaultNearbyRadiusKm = 10
+	maxNearbyRadiusKm     = 500
+)
+
 func (u *HospitalUseCase) Create(hospital *entities.Hospital) (*entities.Hospital, error) {
+	hospital.Code = strings.TrimSpace(hospital.Code)
+	if err := hospital.ValidateCode(); err != nil {
+		return nil, err
+	}
+	if err := hospital.ValidateLocation(); err != nil {
+		return nil, err
+	}
+
 	exist, _ := u.repo.FindByName(hospital.HospitalName)
 
 	if exist != nil {
-		return nil, errors.New("hospital name already exists")
+		return nil, entities.Conflict("hospital_name_taken", "hospital name already exists")
+	}
+
+	if hospital.Code != "" {
+		if other, _ := u.repo.FindByCode(hospital.Code); other != nil {
+			return nil, entities.Conflict("hospital_code_taken", "hospital code already exists")
+		}
 	}
 
 	hospital, err := u.repo.Create(hospital)
@@ -35,11 +58,48 @@ func (u *HospitalUseCase) Update(hospital *entities.Hospital) (*entities.Hospita
 		return nil, err
 	}
 	if exist == nil {
-		return nil, errors.New("hospital not found")
+		return nil, entities.NotFound("hospital")
+	}
+	if exist.Version != hospital.Version {
+		return nil, entities.ErrVersionConflict
+	}
+
+	hospital.HospitalName = strings.TrimSpace(hospital.HospitalName)
+	if hospital.HospitalName == "" {
+		return nil, entities.Invalid("hospital name is required")
+	}
+	hospital.Code = strings.TrimSpace(hospital.Code)
+	if err := hospital.ValidateCode(); err != nil {
+		return nil, err
+	}
+	if err := hospital.ValidateLocation(); err != nil {
+		return nil, err
+	}
+
+	if hospital.Code != "" && hospital.Code != exist.Code {
+		other, _ := u.repo.FindByCode(hospital.Code)
+		if other != nil && other.ID != exist.ID {
+			return nil, entities.Conflict("hospital_code_taken", "hospital code already exists")
+		}
+	}
+
+	// The name must not be the current or former name of another hospital.
+	if hospital.HospitalName != exist.HospitalName {
+		other, _ := u.repo.FindByName(hospital.HospitalName)
+		if other != nil && other.ID != exist.ID {
+			return nil, entities.Conflict("hospital_name_taken", "hospital name already exists")
+		}
 	}
 
 	exist.HospitalName = hospital.HospitalName
+	exist.Code = hospital.Code
 	exist.Address = hospital.Address
+	exist.Subdistrict = hospital.Subdistrict
+	exist.District = hospital.District
+	exist.Province = hospital.Province
+	exist.PostalCode = hospital.PostalCode
+	exist.Latitude = hospital.Latitude
+	exist.Longitude = hospital.Longitude
 
 	hospital, err = u.repo.Update(exist)
 	if err != nil {
@@ -49,28 +109,81 @@ func (u *HospitalUseCase) Update(hospital *entities.Hospital) (*entities.Hospita
 	return hospital, err
 }
 
-func (u *HospitalUseCase) Delete(id uint) error {
+// Delete soft deletes the hospital under the chosen policy, refuse when
+// none is given, and reports what it touched. A refused deletion, a
+// reassignment onto HNs the target already uses, or any dry run, only
+// returns the impact.
+func (u *HospitalUseCase) Delete(id uint, req *entities.HospitalDeleteRequest) (*entities.HospitalDeletionImpact, error) {
 	exist, err := u.repo.FindById(id)
+	if err != nil || exist == nil {
+		return nil, entities.NotFound("hospital")
+	}
+
+	policy := req.Policy
+	if policy == "" {
+		policy = consts.HospitalDeleteRefuse
+	}
+	if !policy.IsValid() {
+		return nil, entities.Invalid("policy must be refuse, archive-all or reassign-to-hospital")
+	}
+
+	if policy == consts.HospitalDeleteReassign {
+		if req.TargetHospitalID == 0 || req.TargetHospitalID == id {
+			return nil, entities.Invalid("target_hospital_id must be another hospital")
+		}
+		if target, err := u.repo.FindById(req.TargetHospitalID); err != nil || target == nil {
+			return nil, entities.NotFound("target hospital")
+		}
+	}
+
+	var targetHospitalId uint
+	if policy == consts.HospitalDeleteReassign {
+		targetHospitalId = req.TargetHospitalID
+	}
+
+	impact, err := u.repo.FindDeletionImpact(id, targetHospitalId)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	impact.Policy = policy
+	impact.DryRun = req.DryRun
+	impact.TargetHospitalID = targetHospitalId
+
+	var blockedBy error
+	switch {
+	case policy == consts.HospitalDeleteRefuse && (impact.ActiveStaff > 0 || impact.ActivePatients > 0):
+		blockedBy = entities.ErrHospitalInUse
+	case len(impact.CollidingHNs) > 0:
+		blockedBy = entities.ErrHospitalHNCollision
 	}
+	impact.Blocked = blockedBy != nil
 
-	if exist == nil {
-		return errors.New("hospital not found")
+	if req.DryRun {
+		return impact, nil
+	}
+	if impact.Blocked {
+		return impact, blockedBy
+	}
+
+	if err := u.repo.Delete(id, policy, targetHospitalId); err != nil {
+		if errors.Is(err, entities.ErrHospitalInUse) || errors.Is(err, entities.ErrHospitalHNCollision) {
+			impact.Blocked = true
+		}
+		return impact, err
 	}
 
-	return u.repo.Delete(id)
+	return impact, nil
 }
 
-func (u *HospitalUseCase) FindAll(page int, limit int) ([]entities.Hospital, int, error) {
-	totalCount, err := u.repo.FindHospitalCount()
+func (u *HospitalUseCase) FindAll(spec *query.Spec) ([]entities.Hospital, int, error) {
+	totalCount, err := u.repo.FindHospitalCount(spec)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	totalPage := int((totalCount + int64(limit) - 1) / int64(limit))
+	totalPage := spec.TotalPages(totalCount)
 
-	hospitals, err := u.repo.FindAll(page, limit)
+	hospitals, err := u.repo.FindAll(spec)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -81,11 +194,11 @@ func (u *HospitalUseCase) FindAll(page int, limit int) ([]entities.Hospital, int
 func (u *HospitalUseCase) FindById(id uint) (*entities.Hospital, error) {
 	exist, err := u.repo.FindById(id)
 	if err != nil {
-		return nil, errors.New("hospital not found")
+		return nil, entities.NotFound("hospital")
 	}
 
 	if exist == nil {
-		return nil, errors.New("hospital not found")
+		return nil, entities.NotFound("hospital")
 	}
 
 	return exist, nil
@@ -98,7 +211,31 @@ func (u *HospitalUseCase) FindByName(name string) (*entities.Hospital, error) {
 	}
 
 	if exist == nil {
-		return nil, errors.New("hospital not found")
+		return nil, entities.NotFound("hospital")
 	}
 	return exist, nil
 }
+
+// FindNearby returns hospitals within radiusKm of the point, closest first.
+// A zero radius means 10 km and the radius is capped at 500 km.
+func (u *HospitalUseCase) FindNearby(lat float64, lng float64, radiusKm float64, limit int) ([]entities.HospitalDistance, error) {
+	if err := (geo.Point{Lat: lat, Lng: lng}).Validate(); err != nil {
+		return nil, err
+	}
+
+	if radiusKm < 0 {
+		return nil, entities.Invalid("radius must not be negative")
+	}
+	if radiusKm == 0 {
+		radiusKm = defaultNearbyRadiusKm
+	}
+	if radiusKm > maxNearbyRadiusKm {
+		radiusKm = maxNearbyRadiusKm
+	}
+
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return u.repo.FindNearby(lat, lng, radiusKm, limit)
+}
@@ -0,0 +1,79 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAppointmentRepository struct {
+	mock.Mock
+}
+
+func NewMockAppointmentRepository() *MockAppointmentRepository {
+	return &MockAppointmentRepository{}
+}
+
+func (m *MockAppointmentRepository) Book(appointment *entities.Appointment) (*entities.Appointment, error) {
+	args := m.Called(appointment)
+	return args.Get(0).(*entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) Reschedule(previous *entities.Appointment, next *entities.Appointment) (*entities.Appointment, error) {
+	args := m.Called(previous, next)
+	return args.Get(0).(*entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) Update(appointment *entities.Appointment) (*entities.Appointment, error) {
+	args := m.Called(appointment)
+	return args.Get(0).(*entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindById(id uint) (*entities.Appointment, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindByDay(filter entities.AppointmentDayFilter) ([]entities.Appointment, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) CreateAvailability(availability *entities.StaffAvailability) (*entities.StaffAvailability, error) {
+	args := m.Called(availability)
+	return args.Get(0).(*entities.StaffAvailability), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) DeleteAvailability(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) FindAvailabilityById(id uint) (*entities.StaffAvailability, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.StaffAvailability), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindAvailabilities(staffId uint) ([]entities.StaffAvailability, error) {
+	args := m.Called(staffId)
+	return args.Get(0).([]entities.StaffAvailability), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) CreateException(exception *entities.StaffAvailabilityException) (*entities.StaffAvailabilityException, error) {
+	args := m.Called(exception)
+	return args.Get(0).(*entities.StaffAvailabilityException), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) DeleteException(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentRepository) FindExceptionById(id uint) (*entities.StaffAvailabilityException, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.StaffAvailabilityException), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindExceptions(staffId uint, fromDate string, toDate string) ([]entities.StaffAvailabilityException, error) {
+	args := m.Called(staffId, fromDate, toDate)
+	return args.Get(0).([]entities.StaffAvailabilityException), args.Error(1)
+}
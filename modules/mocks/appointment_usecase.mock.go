@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAppointmentUseCase struct {
+	mock.Mock
+}
+
+func NewMockAppointmentUseCase() *MockAppointmentUseCase {
+	return &MockAppointmentUseCase{}
+}
+
+func (m *MockAppointmentUseCase) Book(req *entities.AppointmentBookRequest, staffHospitalId uint, bookedById uint) (*entities.Appointment, error) {
+	args := m.Called(req, staffHospitalId, bookedById)
+	return args.Get(0).(*entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentUseCase) Cancel(id uint, req *entities.AppointmentCancelRequest, staffHospitalId uint, staffId uint) (*entities.Appointment, error) {
+	args := m.Called(id, req, staffHospitalId, staffId)
+	return args.Get(0).(*entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentUseCase) Reschedule(id uint, req *entities.AppointmentRescheduleRequest, staffHospitalId uint, staffId uint) (*entities.Appointment, error) {
+	args := m.Called(id, req, staffHospitalId, staffId)
+	return args.Get(0).(*entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentUseCase) FindById(id uint, staffHospitalId uint) (*entities.Appointment, error) {
+	args := m.Called(id, staffHospitalId)
+	return args.Get(0).(*entities.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentUseCase) FindHospitalDay(date string, staffHospitalId uint) (*entities.AppointmentHospitalDay, error) {
+	args := m.Called(date, staffHospitalId)
+	return args.Get(0).(*entities.AppointmentHospitalDay), args.Error(1)
+}
+
+func (m *MockAppointmentUseCase) FindStaffDay(staffId uint, date string, staffHospitalId uint) (*entities.AppointmentStaffDay, error) {
+	args := m.Called(staffId, date, staffHospitalId)
+	return args.Get(0).(*entities.AppointmentStaffDay), args.Error(1)
+}
+
+func (m *MockAppointmentUseCase) CreateAvailability(req *entities.StaffAvailabilityRequest, staffId uint, staffHospitalId uint) (*entities.StaffAvailability, error) {
+	args := m.Called(req, staffId, staffHospitalId)
+	return args.Get(0).(*entities.StaffAvailability), args.Error(1)
+}
+
+func (m *MockAppointmentUseCase) DeleteAvailability(staffId uint, availabilityId uint, staffHospitalId uint) error {
+	args := m.Called(staffId, availabilityId, staffHospitalId)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentUseCase) CreateException(req *entities.StaffAvailabilityExceptionRequest, staffId uint, staffHospitalId uint) (*entities.StaffAvailabilityException, error) {
+	args := m.Called(req, staffId, staffHospitalId)
+	return args.Get(0).(*entities.StaffAvailabilityException), args.Error(1)
+}
+
+func (m *MockAppointmentUseCase) DeleteException(staffId uint, exceptionId uint, staffHospitalId uint) error {
+	args := m.Called(staffId, exceptionId, staffHospitalId)
+	return args.Error(0)
+}
+
+func (m *MockAppointmentUseCase) FindAvailability(staffId uint, staffHospitalId uint) (*entities.StaffAvailabilityResponse, error) {
+	args := m.Called(staffId, staffHospitalId)
+	return args.Get(0).(*entities.StaffAvailabilityResponse), args.Error(1)
+}
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditLogUseCase struct {
+	mock.Mock
+}
+
+func NewMockAuditLogUseCase() *MockAuditLogUseCase {
+	return &MockAuditLogUseCase{}
+}
+
+func (m *MockAuditLogUseCase) Record(logs ...entities.AuditLog) error {
+	args := m.Called(logs)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogUseCase) FindAll(filter entities.AuditLogFilter, page int, limit int) ([]entities.AuditLog, int, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).([]entities.AuditLog), args.Int(1), args.Error(2)
+}
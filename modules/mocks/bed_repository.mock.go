@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBedRepository struct {
+	mock.Mock
+}
+
+func NewMockBedRepository() *MockBedRepository {
+	return &MockBedRepository{}
+}
+
+func (m *MockBedRepository) CreateWard(ward *entities.Ward) (*entities.Ward, error) {
+	args := m.Called(ward)
+	return args.Get(0).(*entities.Ward), args.Error(1)
+}
+
+func (m *MockBedRepository) FindWardById(id uint) (*entities.Ward, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Ward), args.Error(1)
+}
+
+func (m *MockBedRepository) FindWards(hospitalId uint) ([]entities.Ward, error) {
+	args := m.Called(hospitalId)
+	return args.Get(0).([]entities.Ward), args.Error(1)
+}
+
+func (m *MockBedRepository) CreateBed(bed *entities.Bed) (*entities.Bed, error) {
+	args := m.Called(bed)
+	return args.Get(0).(*entities.Bed), args.Error(1)
+}
+
+func (m *MockBedRepository) FindBedById(id uint) (*entities.Bed, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Bed), args.Error(1)
+}
+
+func (m *MockBedRepository) UpdateBedStatus(id uint, from consts.BedStatus, to consts.BedStatus, note string) error {
+	args := m.Called(id, from, to, note)
+	return args.Error(0)
+}
+
+func (m *MockBedRepository) Assign(assignment *entities.BedAssignment) (*entities.BedAssignment, error) {
+	args := m.Called(assignment)
+	return args.Get(0).(*entities.BedAssignment), args.Error(1)
+}
+
+func (m *MockBedRepository) Release(encounterId uint, reason string) (*entities.BedAssignment, error) {
+	args := m.Called(encounterId, reason)
+	return args.Get(0).(*entities.BedAssignment), args.Error(1)
+}
+
+func (m *MockBedRepository) FindAssignments(encounterId uint) ([]entities.BedAssignment, error) {
+	args := m.Called(encounterId)
+	return args.Get(0).([]entities.BedAssignment), args.Error(1)
+}
+
+func (m *MockBedRepository) FindOccupancy(hospitalId uint) ([]entities.WardOccupancy, error) {
+	args := m.Called(hospitalId)
+	return args.Get(0).([]entities.WardOccupancy), args.Error(1)
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBedUseCase struct {
+	mock.Mock
+}
+
+func NewMockBedUseCase() *MockBedUseCase {
+	return &MockBedUseCase{}
+}
+
+func (m *MockBedUseCase) CreateWard(req *entities.WardCreateRequest, staffHospitalId uint) (*entities.Ward, error) {
+	args := m.Called(req, staffHospitalId)
+	return args.Get(0).(*entities.Ward), args.Error(1)
+}
+
+func (m *MockBedUseCase) FindWardById(id uint, staffHospitalId uint) (*entities.Ward, error) {
+	args := m.Called(id, staffHospitalId)
+	return args.Get(0).(*entities.Ward), args.Error(1)
+}
+
+func (m *MockBedUseCase) FindWards(staffHospitalId uint) ([]entities.Ward, error) {
+	args := m.Called(staffHospitalId)
+	return args.Get(0).([]entities.Ward), args.Error(1)
+}
+
+func (m *MockBedUseCase) CreateBed(wardId uint, req *entities.BedCreateRequest, staffHospitalId uint) (*entities.Bed, error) {
+	args := m.Called(wardId, req, staffHospitalId)
+	return args.Get(0).(*entities.Bed), args.Error(1)
+}
+
+func (m *MockBedUseCase) UpdateBedStatus(id uint, req *entities.BedStatusRequest, staffHospitalId uint) (*entities.Bed, error) {
+	args := m.Called(id, req, staffHospitalId)
+	return args.Get(0).(*entities.Bed), args.Error(1)
+}
+
+func (m *MockBedUseCase) Assign(bedId uint, req *entities.BedAssignRequest, staffHospitalId uint, staffId uint) (*entities.BedAssignment, error) {
+	args := m.Called(bedId, req, staffHospitalId, staffId)
+	return args.Get(0).(*entities.BedAssignment), args.Error(1)
+}
+
+func (m *MockBedUseCase) Release(encounterId uint, req *entities.BedReleaseRequest, staffHospitalId uint) (*entities.BedAssignment, error) {
+	args := m.Called(encounterId, req, staffHospitalId)
+	return args.Get(0).(*entities.BedAssignment), args.Error(1)
+}
+
+func (m *MockBedUseCase) FindAssignments(encounterId uint, staffHospitalId uint) ([]entities.BedAssignment, error) {
+	args := m.Called(encounterId, staffHospitalId)
+	return args.Get(0).([]entities.BedAssignment), args.Error(1)
+}
+
+func (m *MockBedUseCase) FindOccupancy(staffHospitalId uint) ([]entities.WardOccupancy, error) {
+	args := m.Called(staffHospitalId)
+	return args.Get(0).([]entities.WardOccupancy), args.Error(1)
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDepartmentRepository struct {
+	mock.Mock
+}
+
+func NewMockDepartmentRepository() *MockDepartmentRepository {
+	return &MockDepartmentRepository{}
+}
+
+func (m *MockDepartmentRepository) Create(department *entities.Department) (*entities.Department, error) {
+	args := m.Called(department)
+	return args.Get(0).(*entities.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) Update(department *entities.Department, oldPath string) (*entities.Department, error) {
+	args := m.Called(department, oldPath)
+	return args.Get(0).(*entities.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepository) FindById(id uint) (*entities.Department, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) FindByHospital(hospitalId uint) ([]entities.Department, error) {
+	args := m.Called(hospitalId)
+	return args.Get(0).([]entities.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) CountChildren(id uint) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDepartmentRepository) AssignStaff(assignment *entities.StaffDepartment) error {
+	args := m.Called(assignment)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepository) UnassignStaff(departmentId uint, staffId uint) error {
+	args := m.Called(departmentId, staffId)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepository) FindStaff(departmentId uint) ([]entities.Staff, error) {
+	args := m.Called(departmentId)
+	return args.Get(0).([]entities.Staff), args.Error(1)
+}
@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDepartmentUseCase struct {
+	mock.Mock
+}
+
+func NewMockDepartmentUseCase() *MockDepartmentUseCase {
+	return &MockDepartmentUseCase{}
+}
+
+func (m *MockDepartmentUseCase) Create(hospitalId uint, req *entities.DepartmentCreateRequest) (*entities.Department, error) {
+	args := m.Called(hospitalId, req)
+	return args.Get(0).(*entities.Department), args.Error(1)
+}
+
+func (m *MockDepartmentUseCase) Update(hospitalId uint, id uint, version uint, req *entities.DepartmentUpdateRequest) (*entities.Department, error) {
+	args := m.Called(hospitalId, id, version, req)
+	return args.Get(0).(*entities.Department), args.Error(1)
+}
+
+func (m *MockDepartmentUseCase) Delete(hospitalId uint, id uint) error {
+	args := m.Called(hospitalId, id)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentUseCase) FindById(hospitalId uint, id uint) (*entities.Department, error) {
+	args := m.Called(hospitalId, id)
+	return args.Get(0).(*entities.Department), args.Error(1)
+}
+
+func (m *MockDepartmentUseCase) FindTree(hospitalId uint) ([]entities.Department, error) {
+	args := m.Called(hospitalId)
+	return args.Get(0).([]entities.Department), args.Error(1)
+}
+
+func (m *MockDepartmentUseCase) AssignStaff(hospitalId uint, id uint, staffId uint) error {
+	args := m.Called(hospitalId, id, staffId)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentUseCase) UnassignStaff(hospitalId uint, id uint, staffId uint) error {
+	args := m.Called(hospitalId, id, staffId)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentUseCase) FindStaff(hospitalId uint, id uint) ([]entities.Staff, error) {
+	args := m.Called(hospitalId, id)
+	return args.Get(0).([]entities.Staff), args.Error(1)
+}
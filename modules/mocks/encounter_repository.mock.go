@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEncounterRepository struct {
+	mock.Mock
+}
+
+func NewMockEncounterRepository() *MockEncounterRepository {
+	return &MockEncounterRepository{}
+}
+
+func (m *MockEncounterRepository) Create(encounter *entities.Encounter) (*entities.Encounter, error) {
+	args := m.Called(encounter)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) Update(encounter *entities.Encounter) (*entities.Encounter, error) {
+	args := m.Called(encounter)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) Transfer(encounter *entities.Encounter, transfer *entities.EncounterTransfer) (*entities.Encounter, error) {
+	args := m.Called(encounter, transfer)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) Close(encounter *entities.Encounter, releaseReason string) (*entities.Encounter, error) {
+	args := m.Called(encounter, releaseReason)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) FindById(id uint) (*entities.Encounter, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) FindAll(filter entities.EncounterFilter, page int, limit int) ([]entities.Encounter, int64, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).([]entities.Encounter), args.Get(1).(int64), args.Error(2)
+}
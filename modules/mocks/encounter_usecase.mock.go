@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEncounterUseCase struct {
+	mock.Mock
+}
+
+func NewMockEncounterUseCase() *MockEncounterUseCase {
+	return &MockEncounterUseCase{}
+}
+
+func (m *MockEncounterUseCase) Create(req *entities.EncounterCreateRequest, staffHospitalId uint) (*entities.Encounter, error) {
+	args := m.Called(req, staffHospitalId)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterUseCase) Admit(id uint, req *entities.EncounterAdmitRequest, staffHospitalId uint) (*entities.Encounter, error) {
+	args := m.Called(id, req, staffHospitalId)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterUseCase) Discharge(id uint, req *entities.EncounterDischargeRequest, staffHospitalId uint) (*entities.Encounter, error) {
+	args := m.Called(id, req, staffHospitalId)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterUseCase) Transfer(id uint, req *entities.EncounterTransferRequest, staffHospitalId uint, staffId uint) (*entities.Encounter, error) {
+	args := m.Called(id, req, staffHospitalId, staffId)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterUseCase) Cancel(id uint, req *entities.EncounterCancelRequest, staffHospitalId uint) (*entities.Encounter, error) {
+	args := m.Called(id, req, staffHospitalId)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterUseCase) FindById(id uint, staffHospitalId uint) (*entities.Encounter, error) {
+	args := m.Called(id, staffHospitalId)
+	return args.Get(0).(*entities.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterUseCase) FindAll(filter entities.EncounterFilter, page int, limit int) ([]entities.Encounter, int, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).([]entities.Encounter), args.Get(1).(int), args.Error(2)
+}
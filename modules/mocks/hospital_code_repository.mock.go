@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHospitalCodeRepository struct {
+	mock.Mock
+}
+
+func NewMockHospitalCodeRepository() *MockHospitalCodeRepository {
+	return &MockHospitalCodeRepository{}
+}
+
+func (m *MockHospitalCodeRepository) Upsert(entries []entities.HospitalCodeEntry) error {
+	args := m.Called(entries)
+	return args.Error(0)
+}
+
+func (m *MockHospitalCodeRepository) FindByCode(code string) (*entities.HospitalCodeEntry, error) {
+	args := m.Called(code)
+	return args.Get(0).(*entities.HospitalCodeEntry), args.Error(1)
+}
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"io"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHospitalCodeUseCase struct {
+	mock.Mock
+}
+
+func NewMockHospitalCodeUseCase() *MockHospitalCodeUseCase {
+	return &MockHospitalCodeUseCase{}
+}
+
+func (m *MockHospitalCodeUseCase) Import(r io.Reader) (*entities.HospitalCodeImportResult, error) {
+	args := m.Called(r)
+	return args.Get(0).(*entities.HospitalCodeImportResult), args.Error(1)
+}
+
+func (m *MockHospitalCodeUseCase) Lookup(code string) (*entities.HospitalCodeLookup, error) {
+	args := m.Called(code)
+	return args.Get(0).(*entities.HospitalCodeLookup), args.Error(1)
+}
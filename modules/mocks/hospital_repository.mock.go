@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -23,18 +25,23 @@ func (m *MockHospitalRepository) Update(hospital *entities.Hospital) (*entities.
 	return args.Get(0).(*entities.Hospital), args.Error(1)
 }
 
-func (m *MockHospitalRepository) Delete(id uint) error {
-	args := m.Called(id)
+func (m *MockHospitalRepository) Delete(id uint, policy consts.HospitalDeletePolicy, targetHospitalId uint) error {
+	args := m.Called(id, policy, targetHospitalId)
 	return args.Error(0)
 }
 
-func (m *MockHospitalRepository) FindHospitalCount() (int64, error) {
-	args := m.Called()
+func (m *MockHospitalRepository) FindDeletionImpact(id uint, targetHospitalId uint) (*entities.HospitalDeletionImpact, error) {
+	args := m.Called(id, targetHospitalId)
+	return args.Get(0).(*entities.HospitalDeletionImpact), args.Error(1)
+}
+
+func (m *MockHospitalRepository) FindHospitalCount(spec *query.Spec) (int64, error) {
+	args := m.Called(spec)
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockHospitalRepository) FindAll(page int, limit int) ([]entities.Hospital, error) {
-	args := m.Called(page, limit)
+func (m *MockHospitalRepository) FindAll(spec *query.Spec) ([]entities.Hospital, error) {
+	args := m.Called(spec)
 	return args.Get(0).([]entities.Hospital), args.Error(1)
 }
 
@@ -47,3 +54,13 @@ func (m *MockHospitalRepository) FindByName(name string) (*entities.Hospital, er
 	args := m.Called(name)
 	return args.Get(0).(*entities.Hospital), args.Error(1)
 }
+
+func (m *MockHospitalRepository) FindNearby(lat float64, lng float64, radiusKm float64, limit int) ([]entities.HospitalDistance, error) {
+	args := m.Called(lat, lng, radiusKm, limit)
+	return args.Get(0).([]entities.HospitalDistance), args.Error(1)
+}
+
+func (m *MockHospitalRepository) FindByCode(code string) (*entities.Hospital, error) {
+	args := m.Called(code)
+	return args.Get(0).(*entities.Hospital), args.Error(1)
+}
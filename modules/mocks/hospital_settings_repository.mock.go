@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHospitalSettingsRepository struct {
+	mock.Mock
+}
+
+func NewMockHospitalSettingsRepository() *MockHospitalSettingsRepository {
+	return &MockHospitalSettingsRepository{}
+}
+
+func (m *MockHospitalSettingsRepository) FindByHospital(hospitalId uint) (*entities.HospitalSettings, error) {
+	args := m.Called(hospitalId)
+	return args.Get(0).(*entities.HospitalSettings), args.Error(1)
+}
+
+func (m *MockHospitalSettingsRepository) Save(settings *entities.HospitalSettings, changes []entities.HospitalSettingsChange) (*entities.HospitalSettings, error) {
+	args := m.Called(settings, changes)
+	return args.Get(0).(*entities.HospitalSettings), args.Error(1)
+}
+
+func (m *MockHospitalSettingsRepository) FindHistory(hospitalId uint, page int, limit int) ([]entities.HospitalSettingsChange, int64, error) {
+	args := m.Called(hospitalId, page, limit)
+	return args.Get(0).([]entities.HospitalSettingsChange), args.Get(1).(int64), args.Error(2)
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHospitalSettingsUseCase struct {
+	mock.Mock
+}
+
+func NewMockHospitalSettingsUseCase() *MockHospitalSettingsUseCase {
+	return &MockHospitalSettingsUseCase{}
+}
+
+func (m *MockHospitalSettingsUseCase) Get(hospitalId uint) (*entities.HospitalSettings, error) {
+	args := m.Called(hospitalId)
+	return args.Get(0).(*entities.HospitalSettings), args.Error(1)
+}
+
+func (m *MockHospitalSettingsUseCase) Update(hospitalId uint, version uint, req *entities.HospitalSettingsRequest, staffId uint) (*entities.HospitalSettings, error) {
+	args := m.Called(hospitalId, version, req, staffId)
+	return args.Get(0).(*entities.HospitalSettings), args.Error(1)
+}
+
+func (m *MockHospitalSettingsUseCase) FindHistory(hospitalId uint, page int, limit int) ([]entities.HospitalSettingsChange, int, error) {
+	args := m.Called(hospitalId, page, limit)
+	return args.Get(0).([]entities.HospitalSettingsChange), args.Int(1), args.Error(2)
+}
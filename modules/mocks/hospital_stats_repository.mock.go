@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHospitalStatsRepository struct {
+	mock.Mock
+}
+
+func NewMockHospitalStatsRepository() *MockHospitalStatsRepository {
+	return &MockHospitalStatsRepository{}
+}
+
+func (m *MockHospitalStatsRepository) Refresh() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockHospitalStatsRepository) FindDemographics(hospitalId uint) ([]entities.HospitalDemographicStat, error) {
+	args := m.Called(hospitalId)
+	return args.Get(0).([]entities.HospitalDemographicStat), args.Error(1)
+}
+
+func (m *MockHospitalStatsRepository) CountRegistrations(hospitalId uint, from civil.Date, to civil.Date) (int64, error) {
+	args := m.Called(hospitalId, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockHospitalStatsRepository) CountRegistrationsBefore(hospitalId uint, day civil.Date) (int64, error) {
+	args := m.Called(hospitalId, day)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockHospitalStatsRepository) FindRegistrationTrend(hospitalId uint, from civil.Date, to civil.Date, interval consts.StatsInterval) ([]entities.HospitalStatsPoint, error) {
+	args := m.Called(hospitalId, from, to, interval)
+	return args.Get(0).([]entities.HospitalStatsPoint), args.Error(1)
+}
+
+func (m *MockHospitalStatsRepository) CountActiveStaff(hospitalId uint) (int64, error) {
+	args := m.Called(hospitalId)
+	return args.Get(0).(int64), args.Error(1)
+}
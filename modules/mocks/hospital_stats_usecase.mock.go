@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHospitalStatsUseCase struct {
+	mock.Mock
+}
+
+func NewMockHospitalStatsUseCase() *MockHospitalStatsUseCase {
+	return &MockHospitalStatsUseCase{}
+}
+
+func (m *MockHospitalStatsUseCase) Find(hospitalId uint, req *entities.HospitalStatsRequest) (*entities.HospitalStats, error) {
+	args := m.Called(hospitalId, req)
+	return args.Get(0).(*entities.HospitalStats), args.Error(1)
+}
+
+func (m *MockHospitalStatsUseCase) Refresh() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockHospitalStatsUseCase) StartRefresh(interval time.Duration) {
+	m.Called(interval)
+}
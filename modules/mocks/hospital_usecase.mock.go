@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -23,13 +24,13 @@ func (m *MockHospitalUseCase) Update(hospital *entities.Hospital) (*entities.Hos
 	return args.Get(0).(*entities.Hospital), args.Error(1)
 }
 
-func (m *MockHospitalUseCase) Delete(id uint) error {
-	args := m.Called(id)
-	return args.Error(0)
+func (m *MockHospitalUseCase) Delete(id uint, req *entities.HospitalDeleteRequest) (*entities.HospitalDeletionImpact, error) {
+	args := m.Called(id, req)
+	return args.Get(0).(*entities.HospitalDeletionImpact), args.Error(1)
 }
 
-func (m *MockHospitalUseCase) FindAll(page int, limit int) ([]entities.Hospital, int, error) {
-	args := m.Called(page, limit)
+func (m *MockHospitalUseCase) FindAll(spec *query.Spec) ([]entities.Hospital, int, error) {
+	args := m.Called(spec)
 	return args.Get(0).([]entities.Hospital), args.Int(1), args.Error(2)
 }
 
@@ -42,3 +43,8 @@ func (m *MockHospitalUseCase) FindByName(name string) (*entities.Hospital, error
 	args := m.Called(name)
 	return args.Get(0).(*entities.Hospital), args.Error(1)
 }
+
+func (m *MockHospitalUseCase) FindNearby(lat float64, lng float64, radiusKm float64, limit int) ([]entities.HospitalDistance, error) {
+	args := m.Called(lat, lng, radiusKm, limit)
+	return args.Get(0).([]entities.HospitalDistance), args.Error(1)
+}
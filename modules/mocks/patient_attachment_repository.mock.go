@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientAttachmentRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientAttachmentRepository() *MockPatientAttachmentRepository {
+	return &MockPatientAttachmentRepository{}
+}
+
+func (m *MockPatientAttachmentRepository) Create(attachment *entities.PatientAttachment) (*entities.PatientAttachment, error) {
+	args := m.Called(attachment)
+	return args.Get(0).(*entities.PatientAttachment), args.Error(1)
+}
+
+func (m *MockPatientAttachmentRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPatientAttachmentRepository) FindById(id uint) (*entities.PatientAttachment, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.PatientAttachment), args.Error(1)
+}
+
+func (m *MockPatientAttachmentRepository) FindByPatientId(patientId uint) ([]entities.PatientAttachment, error) {
+	args := m.Called(patientId)
+	return args.Get(0).([]entities.PatientAttachment), args.Error(1)
+}
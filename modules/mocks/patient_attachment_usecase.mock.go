@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"io"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientAttachmentUseCase struct {
+	mock.Mock
+}
+
+func NewMockPatientAttachmentUseCase() *MockPatientAttachmentUseCase {
+	return &MockPatientAttachmentUseCase{}
+}
+
+func (m *MockPatientAttachmentUseCase) Upload(upload *entities.PatientAttachmentUpload, staffHospitalId uint, staffId uint) (*entities.PatientAttachment, error) {
+	args := m.Called(upload, staffHospitalId, staffId)
+	return args.Get(0).(*entities.PatientAttachment), args.Error(1)
+}
+
+func (m *MockPatientAttachmentUseCase) Download(patientId uint, attachmentId uint, staffHospitalId uint) (*entities.PatientAttachment, io.ReadCloser, error) {
+	args := m.Called(patientId, attachmentId, staffHospitalId)
+	body, _ := args.Get(1).(io.ReadCloser)
+	return args.Get(0).(*entities.PatientAttachment), body, args.Error(2)
+}
+
+func (m *MockPatientAttachmentUseCase) Delete(patientId uint, attachmentId uint, staffHospitalId uint) error {
+	args := m.Called(patientId, attachmentId, staffHospitalId)
+	return args.Error(0)
+}
+
+func (m *MockPatientAttachmentUseCase) FindAll(patientId uint, staffHospitalId uint) ([]entities.PatientAttachment, error) {
+	args := m.Called(patientId, staffHospitalId)
+	return args.Get(0).([]entities.PatientAttachment), args.Error(1)
+}
@@ -0,0 +1,64 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientDemographicRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientDemographicRepository() *MockPatientDemographicRepository {
+	return &MockPatientDemographicRepository{}
+}
+
+func (m *MockPatientDemographicRepository) CreateAddress(address *entities.PatientAddress) (*entities.PatientAddress, error) {
+	args := m.Called(address)
+	return args.Get(0).(*entities.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientDemographicRepository) UpdateAddress(address *entities.PatientAddress) (*entities.PatientAddress, error) {
+	args := m.Called(address)
+	return args.Get(0).(*entities.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientDemographicRepository) DeleteAddress(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPatientDemographicRepository) FindAddressById(id uint) (*entities.PatientAddress, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientDemographicRepository) FindAddressesByPatientId(patientId uint) ([]entities.PatientAddress, error) {
+	args := m.Called(patientId)
+	return args.Get(0).([]entities.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientDemographicRepository) CreateEmergencyContact(contact *entities.PatientEmergencyContact) (*entities.PatientEmergencyContact, error) {
+	args := m.Called(contact)
+	return args.Get(0).(*entities.PatientEmergencyContact), args.Error(1)
+}
+
+func (m *MockPatientDemographicRepository) UpdateEmergencyContact(contact *entities.PatientEmergencyContact) (*entities.PatientEmergencyContact, error) {
+	args := m.Called(contact)
+	return args.Get(0).(*entities.PatientEmergencyContact), args.Error(1)
+}
+
+func (m *MockPatientDemographicRepository) DeleteEmergencyContact(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPatientDemographicRepository) FindEmergencyContactById(id uint) (*entities.PatientEmergencyContact, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.PatientEmergencyContact), args.Error(1)
+}
+
+func (m *MockPatientDemographicRepository) FindEmergencyContactsByPatientId(patientId uint) ([]entities.PatientEmergencyContact, error) {
+	args := m.Called(patientId)
+	return args.Get(0).([]entities.PatientEmergencyContact), args.Error(1)
+}
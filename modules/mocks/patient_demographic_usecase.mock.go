@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientDemographicUseCase struct {
+	mock.Mock
+}
+
+func NewMockPatientDemographicUseCase() *MockPatientDemographicUseCase {
+	return &MockPatientDemographicUseCase{}
+}
+
+func (m *MockPatientDemographicUseCase) CreateAddress(address *entities.PatientAddress, staffHospitalId uint) (*entities.PatientAddress, error) {
+	args := m.Called(address, staffHospitalId)
+	return args.Get(0).(*entities.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientDemographicUseCase) UpdateAddress(address *entities.PatientAddress, staffHospitalId uint) (*entities.PatientAddress, error) {
+	args := m.Called(address, staffHospitalId)
+	return args.Get(0).(*entities.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientDemographicUseCase) DeleteAddress(patientId uint, addressId uint, staffHospitalId uint) error {
+	args := m.Called(patientId, addressId, staffHospitalId)
+	return args.Error(0)
+}
+
+func (m *MockPatientDemographicUseCase) FindAddresses(patientId uint, staffHospitalId uint) ([]entities.PatientAddress, error) {
+	args := m.Called(patientId, staffHospitalId)
+	return args.Get(0).([]entities.PatientAddress), args.Error(1)
+}
+
+func (m *MockPatientDemographicUseCase) CreateEmergencyContact(contact *entities.PatientEmergencyContact, staffHospitalId uint) (*entities.PatientEmergencyContact, error) {
+	args := m.Called(contact, staffHospitalId)
+	return args.Get(0).(*entities.PatientEmergencyContact), args.Error(1)
+}
+
+func (m *MockPatientDemographicUseCase) UpdateEmergencyContact(contact *entities.PatientEmergencyContact, staffHospitalId uint) (*entities.PatientEmergencyContact, error) {
+	args := m.Called(contact, staffHospitalId)
+	return args.Get(0).(*entities.PatientEmergencyContact), args.Error(1)
+}
+
+func (m *MockPatientDemographicUseCase) DeleteEmergencyContact(patientId uint, contactId uint, staffHospitalId uint) error {
+	args := m.Called(patientId, contactId, staffHospitalId)
+	return args.Error(0)
+}
+
+func (m *MockPatientDemographicUseCase) FindEmergencyContacts(patientId uint, staffHospitalId uint) ([]entities.PatientEmergencyContact, error) {
+	args := m.Called(patientId, staffHospitalId)
+	return args.Get(0).([]entities.PatientEmergencyContact), args.Error(1)
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientExportRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientExportRepository() *MockPatientExportRepository {
+	return &MockPatientExportRepository{}
+}
+
+func (m *MockPatientExportRepository) CreateJob(job *entities.PatientExportJob) (*entities.PatientExportJob, error) {
+	args := m.Called(job)
+	return args.Get(0).(*entities.PatientExportJob), args.Error(1)
+}
+
+func (m *MockPatientExportRepository) UpdateJob(job *entities.PatientExportJob) (*entities.PatientExportJob, error) {
+	args := m.Called(job)
+	return args.Get(0).(*entities.PatientExportJob), args.Error(1)
+}
+
+func (m *MockPatientExportRepository) FindJobById(id uint) (*entities.PatientExportJob, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.PatientExportJob), args.Error(1)
+}
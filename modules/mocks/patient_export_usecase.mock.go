@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"io"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientExportUseCase struct {
+	mock.Mock
+}
+
+func NewMockPatientExportUseCase() *MockPatientExportUseCase {
+	return &MockPatientExportUseCase{}
+}
+
+func (m *MockPatientExportUseCase) Stream(req *entities.PatientExportRequest, w io.Writer) error {
+	args := m.Called(req, w)
+	return args.Error(0)
+}
+
+func (m *MockPatientExportUseCase) Create(req *entities.PatientExportRequest) (*entities.PatientExportJob, error) {
+	args := m.Called(req)
+	return args.Get(0).(*entities.PatientExportJob), args.Error(1)
+}
+
+func (m *MockPatientExportUseCase) Start(job *entities.PatientExportJob) {
+	m.Called(job)
+}
+
+func (m *MockPatientExportUseCase) Run(job *entities.PatientExportJob) (*entities.PatientExportJob, error) {
+	args := m.Called(job)
+	return args.Get(0).(*entities.PatientExportJob), args.Error(1)
+}
+
+func (m *MockPatientExportUseCase) FindById(id uint, staffHospitalId uint, staffId uint) (*entities.PatientExportJob, error) {
+	args := m.Called(id, staffHospitalId, staffId)
+	return args.Get(0).(*entities.PatientExportJob), args.Error(1)
+}
+
+func (m *MockPatientExportUseCase) Open(id uint, staffHospitalId uint, staffId uint) (*entities.PatientExportJob, io.ReadCloser, error) {
+	args := m.Called(id, staffHospitalId, staffId)
+	body, _ := args.Get(1).(io.ReadCloser)
+	return args.Get(0).(*entities.PatientExportJob), body, args.Error(2)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientImportRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientImportRepository() *MockPatientImportRepository {
+	return &MockPatientImportRepository{}
+}
+
+func (m *MockPatientImportRepository) CreateJob(job *entities.PatientImportJob) (*entities.PatientImportJob, error) {
+	args := m.Called(job)
+	return args.Get(0).(*entities.PatientImportJob), args.Error(1)
+}
+
+func (m *MockPatientImportRepository) UpdateJob(job *entities.PatientImportJob) (*entities.PatientImportJob, error) {
+	args := m.Called(job)
+	return args.Get(0).(*entities.PatientImportJob), args.Error(1)
+}
+
+func (m *MockPatientImportRepository) FindJobById(id uint) (*entities.PatientImportJob, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.PatientImportJob), args.Error(1)
+}
+
+func (m *MockPatientImportRepository) FindDuplicates(hospitalId uint, patients []entities.Patient) ([]entities.Patient, error) {
+	args := m.Called(hospitalId, patients)
+	return args.Get(0).([]entities.Patient), args.Error(1)
+}
+
+func (m *MockPatientImportRepository) SaveBatch(job *entities.PatientImportJob, patients []entities.Patient, issues []entities.PatientImportIssue) error {
+	args := m.Called(job, patients, issues)
+	return args.Error(0)
+}
+
+func (m *MockPatientImportRepository) FindIssues(jobId uint, afterId uint, limit int) ([]entities.PatientImportIssue, error) {
+	args := m.Called(jobId, afterId, limit)
+	return args.Get(0).([]entities.PatientImportIssue), args.Error(1)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"io"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientImportUseCase struct {
+	mock.Mock
+}
+
+func NewMockPatientImportUseCase() *MockPatientImportUseCase {
+	return &MockPatientImportUseCase{}
+}
+
+func (m *MockPatientImportUseCase) Create(req *entities.PatientImportRequest) (*entities.PatientImportJob, error) {
+	args := m.Called(req)
+	return args.Get(0).(*entities.PatientImportJob), args.Error(1)
+}
+
+func (m *MockPatientImportUseCase) Commit(id uint, staffHospitalId uint, staffId uint) (*entities.PatientImportJob, error) {
+	args := m.Called(id, staffHospitalId, staffId)
+	return args.Get(0).(*entities.PatientImportJob), args.Error(1)
+}
+
+func (m *MockPatientImportUseCase) Resume(id uint, staffHospitalId uint) (*entities.PatientImportJob, error) {
+	args := m.Called(id, staffHospitalId)
+	return args.Get(0).(*entities.PatientImportJob), args.Error(1)
+}
+
+func (m *MockPatientImportUseCase) Start(job *entities.PatientImportJob) {
+	m.Called(job)
+}
+
+func (m *MockPatientImportUseCase) Run(job *entities.PatientImportJob, progress func(*entities.PatientImportJob)) (*entities.PatientImportJob, error) {
+	args := m.Called(job, progress)
+	return args.Get(0).(*entities.PatientImportJob), args.Error(1)
+}
+
+func (m *MockPatientImportUseCase) FindById(id uint, staffHospitalId uint) (*entities.PatientImportJob, error) {
+	args := m.Called(id, staffHospitalId)
+	return args.Get(0).(*entities.PatientImportJob), args.Error(1)
+}
+
+func (m *MockPatientImportUseCase) WriteReport(id uint, staffHospitalId uint, w io.Writer) error {
+	args := m.Called(id, staffHospitalId, w)
+	return args.Error(0)
+}
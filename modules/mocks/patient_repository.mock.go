@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -28,9 +29,9 @@ func (m *MockPatientRepository) Delete(id uint) (*entities.Patient, error) {
 	return args.Get(0).(*entities.Patient), args.Error(1)
 }
 
-func (m *MockPatientRepository) FindAll(page int, limit int) ([]entities.Patient, error) {
-	args := m.Called(page, limit)
-	return args.Get(0).([]entities.Patient), args.Error(1)
+func (m *MockPatientRepository) FindAll(hospitalId uint, spec *query.Spec) ([]entities.Patient, int, error) {
+	args := m.Called(hospitalId, spec)
+	return args.Get(0).([]entities.Patient), args.Get(1).(int), args.Error(2)
 }
 
 func (m *MockPatientRepository) FindById(id uint) (*entities.Patient, error) {
@@ -43,13 +44,13 @@ func (m *MockPatientRepository) FindPatientCount() (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockPatientRepository) FindByIdNationalOrPassport(id string) (*entities.Patient, error) {
-	args := m.Called(id)
+func (m *MockPatientRepository) FindByIdNationalOrPassport(id string, hospitalId uint) (*entities.Patient, error) {
+	args := m.Called(id, hospitalId)
 	return args.Get(0).(*entities.Patient), args.Error(1)
 }
 
-func (m *MockPatientRepository) FindByName(firstName string, lastName string) ([]entities.Patient, error) {
-	args := m.Called(firstName, lastName)
+func (m *MockPatientRepository) FindDuplicates(hospitalId uint, patients []entities.Patient) ([]entities.Patient, error) {
+	args := m.Called(hospitalId, patients)
 	return args.Get(0).([]entities.Patient), args.Error(1)
 }
 
@@ -57,3 +58,20 @@ func (m *MockPatientRepository) FindByAdvanceSearch(input entities.PatientSearch
 	args := m.Called(input, page, limit)
 	return args.Get(0).([]entities.Patient), args.Get(1).(int), args.Error(2)
 }
+
+func (m *MockPatientRepository) CountByAdvanceSearch(input entities.PatientSearchInput) (int, error) {
+	args := m.Called(input)
+	return args.Int(0), args.Error(1)
+}
+
+// StreamByAdvanceSearch feeds the patients given to Return through fn, then
+// returns the configured error.
+func (m *MockPatientRepository) StreamByAdvanceSearch(input entities.PatientSearchInput, fn func(patient *entities.Patient) error) error {
+	args := m.Called(input, fn)
+	for _, patient := range args.Get(0).([]entities.Patient) {
+		if err := fn(&patient); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientTransferRepository struct {
+	mock.Mock
+}
+
+func NewMockPatientTransferRepository() *MockPatientTransferRepository {
+	return &MockPatientTransferRepository{}
+}
+
+func (m *MockPatientTransferRepository) Create(transfer *entities.PatientTransfer) (*entities.PatientTransfer, error) {
+	args := m.Called(transfer)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferRepository) Update(transfer *entities.PatientTransfer) (*entities.PatientTransfer, error) {
+	args := m.Called(transfer)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferRepository) Accept(transfer *entities.PatientTransfer, patient *entities.Patient) (*entities.PatientTransfer, error) {
+	args := m.Called(transfer, patient)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferRepository) FindById(id uint) (*entities.PatientTransfer, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferRepository) FindAll(filter entities.PatientTransferFilter, page int, limit int) ([]entities.PatientTransfer, int, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).([]entities.PatientTransfer), args.Int(1), args.Error(2)
+}
+
+func (m *MockPatientTransferRepository) FindDestinationMatches(hospitalId uint, patient *entities.Patient) ([]entities.Patient, error) {
+	args := m.Called(hospitalId, patient)
+	return args.Get(0).([]entities.Patient), args.Error(1)
+}
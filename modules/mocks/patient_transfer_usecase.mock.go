@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPatientTransferUseCase struct {
+	mock.Mock
+}
+
+func NewMockPatientTransferUseCase() *MockPatientTransferUseCase {
+	return &MockPatientTransferUseCase{}
+}
+
+func (m *MockPatientTransferUseCase) Create(req *entities.PatientTransferCreateRequest, staffHospitalId uint, staffId uint) (*entities.PatientTransfer, error) {
+	args := m.Called(req, staffHospitalId, staffId)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferUseCase) Accept(id uint, req *entities.PatientTransferAcceptRequest, staffHospitalId uint, staffId uint) (*entities.PatientTransfer, error) {
+	args := m.Called(id, req, staffHospitalId, staffId)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferUseCase) Reject(id uint, req *entities.PatientTransferRejectRequest, staffHospitalId uint, staffId uint) (*entities.PatientTransfer, error) {
+	args := m.Called(id, req, staffHospitalId, staffId)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferUseCase) Cancel(id uint, staffHospitalId uint, staffId uint) (*entities.PatientTransfer, error) {
+	args := m.Called(id, staffHospitalId, staffId)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferUseCase) FindById(id uint, staffHospitalId uint) (*entities.PatientTransfer, error) {
+	args := m.Called(id, staffHospitalId)
+	return args.Get(0).(*entities.PatientTransfer), args.Error(1)
+}
+
+func (m *MockPatientTransferUseCase) FindAll(filter entities.PatientTransferFilter, page int, limit int) ([]entities.PatientTransfer, int, error) {
+	args := m.Called(filter, page, limit)
+	return args.Get(0).([]entities.PatientTransfer), args.Int(1), args.Error(2)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -28,11 +29,21 @@ func (m *MockPatientUseCase) Delete(id uint, staffHospitalId uint) (*entities.Pa
 	return args.Get(0).(*entities.Patient), args.Error(1)
 }
 
+func (m *MockPatientUseCase) FindById(id uint, staffHospitalId uint) (*entities.Patient, error) {
+	args := m.Called(id, staffHospitalId)
+	return args.Get(0).(*entities.Patient), args.Error(1)
+}
+
 func (m *MockPatientUseCase) FindByIdNationalOrPassport(id string, staffHospitalId uint) (*entities.Patient, error) {
 	args := m.Called(id, staffHospitalId)
 	return args.Get(0).(*entities.Patient), args.Error(1)
 }
 
+func (m *MockPatientUseCase) FindAll(spec *query.Spec, staffHospitalId uint) ([]entities.Patient, int, error) {
+	args := m.Called(spec, staffHospitalId)
+	return args.Get(0).([]entities.Patient), args.Get(1).(int), args.Error(2)
+}
+
 func (m *MockPatientUseCase) FindByAdvanceSearch(input entities.PatientSearchInput, page int, limit int) ([]entities.Patient, int, error) {
 	args := m.Called(input, page, limit)
 	return args.Get(0).([]entities.Patient), args.Get(1).(int), args.Error(2)
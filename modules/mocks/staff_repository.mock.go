@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -28,13 +29,13 @@ func (m *MockStaffRepository) Delete(id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockStaffRepository) FindStaffCount() (int64, error) {
-	args := m.Called()
+func (m *MockStaffRepository) FindStaffCount(spec *query.Spec) (int64, error) {
+	args := m.Called(spec)
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockStaffRepository) FindAll(page int, limit int) ([]entities.Staff, error) {
-	args := m.Called(page, limit)
+func (m *MockStaffRepository) FindAll(spec *query.Spec) ([]entities.Staff, error) {
+	args := m.Called(spec)
 	return args.Get(0).([]entities.Staff), args.Error(1)
 }
 
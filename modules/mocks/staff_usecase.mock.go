@@ -3,6 +3,7 @@ package mocks
 import (
 	"github.com/Teemo4621/Hospital-Api/configs"
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -29,8 +30,8 @@ func (m *MockStaffUseCase) Delete(id uint) error {
 	return args.Error(0)
 }
 
-func (m *MockStaffUseCase) FindAll(page, limit int) ([]entities.Staff, int, error) {
-	args := m.Called(page, limit)
+func (m *MockStaffUseCase) FindAll(spec *query.Spec) ([]entities.Staff, int, error) {
+	args := m.Called(spec)
 	return args.Get(0).([]entities.Staff), args.Get(1).(int), args.Error(2)
 }
 
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead leaves room for the form boundaries and the other fields
+// on top of the file itself.
+const multipartOverhead = 1 << 20
+
+type PatientAttachmentCon struct {
+	Cfg                      configs.Config
+	PatientAttachmentUsecase entities.PatientAttachmentUseCase
+	AuthMiddleware           middlewares.AuthMiddleware
+}
+
+func NewPatientAttachmentController(c *gin.RouterGroup, cfg configs.Config, patientAttachmentUsecase entities.PatientAttachmentUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &PatientAttachmentCon{
+		Cfg:                      cfg,
+		PatientAttachmentUsecase: patientAttachmentUsecase,
+		AuthMiddleware:           authMiddleware,
+	}
+
+	c.GET("/:id/attachments", controller.AuthMiddleware.JwtAuthentication(), controller.FindAll)
+	c.POST("/:id/attachments", controller.AuthMiddleware.JwtAuthentication(), controller.Upload)
+	c.GET("/:id/attachments/:attachmentId", controller.AuthMiddleware.JwtAuthentication(), controller.Download)
+	c.DELETE("/:id/attachments/:attachmentId", controller.AuthMiddleware.JwtAuthentication(), controller.Delete)
+}
+
+func (a *PatientAttachmentCon) FindAll(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	attachments, err := a.PatientAttachmentUsecase.FindAll(patientID, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(attachments) == 0 {
+		attachments = []entities.PatientAttachment{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"attachments": attachments,
+	})
+}
+
+func (a *PatientAttachmentCon) Upload(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	maxSize := a.Cfg.Storage.MaxUploadSize
+	if maxSize <= 0 {
+		maxSize = usecases.DefaultMaxAttachmentSize
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.BadRequestResponse(c, "file exceeds the "+strconv.FormatInt(maxSize>>20, 10)+" MB limit")
+			return
+		}
+		utils.BadRequestResponse(c, "file is required")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	attachment, err := a.PatientAttachmentUsecase.Upload(&entities.PatientAttachmentUpload{
+		PatientID: patientID,
+		Category:  consts.AttachmentCategory(c.PostForm("category")),
+		FileName:  fileHeader.Filename,
+		Body:      file,
+	}, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, attachment)
+}
+
+func (a *PatientAttachmentCon) Download(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	attachmentID, ok := paramId(c, "attachmentId")
+	if !ok {
+		return
+	}
+
+	attachment, body, err := a.PatientAttachmentUsecase.Download(patientID, attachmentID, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer body.Close()
+
+	disposition := "attachment"
+	if c.Query("inline") == "true" {
+		disposition = "inline"
+	}
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}),
+		"X-Checksum-Sha256":      attachment.Checksum,
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
+}
+
+func (a *PatientAttachmentCon) Delete(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	attachmentID, ok := paramId(c, "attachmentId")
+	if !ok {
+		return
+	}
+
+	if err := a.PatientAttachmentUsecase.Delete(patientID, attachmentID, userData.(*entities.JwtClaim).HospitalID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, nil)
+}
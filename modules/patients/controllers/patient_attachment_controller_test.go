@@ -0,0 +1,136 @@
+package controllers_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupAttachmentRouter(mockUseCase *mocks.MockPatientAttachmentUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	cfg.Storage.MaxUploadSize = 1 << 20
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/patient")
+	controllers.NewPatientAttachmentController(group, *cfg, mockUseCase, *authMiddleware)
+	return r, cfg
+}
+
+func newUploadRequest(url string, category string, content []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("category", category)
+	part, _ := writer.CreateFormFile("file", "consent.pdf")
+	part.Write(content)
+	writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, url, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// ----------- Tests ----------- //
+
+func TestUploadAttachmentController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientAttachmentUseCase()
+		r, cfg := setupAttachmentRouter(mockUseCase)
+
+		mockUseCase.On("Upload", mock.MatchedBy(func(upload *entities.PatientAttachmentUpload) bool {
+			return upload.PatientID == 1 && upload.Category == consts.AttachmentCategoryConsentForm && upload.FileName == "consent.pdf"
+		}), uint(1), uint(9)).Return(&entities.PatientAttachment{ID: 1}, nil)
+
+		req := newUploadRequest("/patient/1/attachments", "consent_form", []byte("%PDF-1.7"))
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientAttachmentUseCase()
+		r, cfg := setupAttachmentRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/1/attachments", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Body Too Large", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientAttachmentUseCase()
+		r, cfg := setupAttachmentRouter(mockUseCase)
+
+		req := newUploadRequest("/patient/1/attachments", "other", make([]byte, 3<<20))
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDownloadAttachmentController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientAttachmentUseCase()
+		r, cfg := setupAttachmentRouter(mockUseCase)
+
+		attachment := &entities.PatientAttachment{ID: 5, PatientID: 1, FileName: "ใบส่งตัว.pdf", ContentType: "application/pdf", Size: 8, Checksum: "abc"}
+		mockUseCase.On("Download", uint(1), uint(5), uint(1)).Return(attachment, io.NopCloser(bytes.NewReader([]byte("%PDF-1.7"))), nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/1/attachments/5", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "application/pdf", resp.Header().Get("Content-Type"))
+		assert.Equal(t, "abc", resp.Header().Get("X-Checksum-Sha256"))
+		assert.Contains(t, resp.Header().Get("Content-Disposition"), "attachment; filename*=utf-8''")
+		assert.Equal(t, "%PDF-1.7", resp.Body.String())
+	})
+
+	t.Run("Other Hospital", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientAttachmentUseCase()
+		r, cfg := setupAttachmentRouter(mockUseCase)
+
+		mockUseCase.On("Download", uint(1), uint(5), uint(2)).Return((*entities.PatientAttachment)(nil), nil, entities.NotFound("patient"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/1/attachments/5", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 2}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
@@ -5,7 +5,10 @@ import (
 
 	"github.com/Teemo4621/Hospital-Api/configs"
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/masking"
 	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -13,16 +16,19 @@ import (
 type PatientCon struct {
 	Cfg            configs.Config
 	PatientUsecase entities.PatientUseCase
+	AuditUsecase   entities.AuditLogUseCase
 	AuthMiddleware middlewares.AuthMiddleware
 }
 
-func NewPatientController(c *gin.RouterGroup, cfg configs.Config, patientUsecase entities.PatientUseCase, authMiddleware middlewares.AuthMiddleware) {
+func NewPatientController(c *gin.RouterGroup, cfg configs.Config, patientUsecase entities.PatientUseCase, auditUsecase entities.AuditLogUseCase, authMiddleware middlewares.AuthMiddleware) {
 	controller := &PatientCon{
 		Cfg:            cfg,
 		PatientUsecase: patientUsecase,
+		AuditUsecase:   auditUsecase,
 		AuthMiddleware: authMiddleware,
 	}
 
+	c.GET("/", controller.AuthMiddleware.JwtAuthentication(), controller.FindAll)
 	c.GET("/search/:id", controller.AuthMiddleware.JwtAuthentication(), controller.FindById)
 	c.POST("/create", controller.AuthMiddleware.JwtAuthentication(), controller.Create)
 	c.POST("/update", controller.AuthMiddleware.JwtAuthentication(), controller.Update)
@@ -30,6 +36,25 @@ func NewPatientController(c *gin.RouterGroup, cfg configs.Config, patientUsecase
 	c.POST("/search", controller.AuthMiddleware.JwtAuthentication(), controller.FindByAdvanceSearch)
 }
 
+// NewPatientV2Controller maps the resource style patient routes of v2 on the
+// handlers of v1. Lookups by national or passport ID stay a POST to /search
+// so that the identifiers are not written into URLs and access logs.
+func NewPatientV2Controller(c *gin.RouterGroup, cfg configs.Config, patientUsecase entities.PatientUseCase, auditUsecase entities.AuditLogUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &PatientCon{
+		Cfg:            cfg,
+		PatientUsecase: patientUsecase,
+		AuditUsecase:   auditUsecase,
+		AuthMiddleware: authMiddleware,
+	}
+
+	c.GET("", controller.AuthMiddleware.JwtAuthentication(), controller.FindAll)
+	c.POST("", controller.AuthMiddleware.JwtAuthentication(), controller.Create)
+	c.POST("/search", controller.AuthMiddleware.JwtAuthentication(), controller.FindByAdvanceSearch)
+	c.GET("/:id", controller.AuthMiddleware.JwtAuthentication(), controller.Get)
+	c.PATCH("/:id", controller.AuthMiddleware.JwtAuthentication(), controller.Patch)
+	c.DELETE("/:id", controller.AuthMiddleware.JwtAuthentication(), controller.Delete)
+}
+
 func (a *PatientCon) Create(c *gin.Context) {
 	userData, exists := c.Get("user_data")
 	if !exists {
@@ -37,64 +62,205 @@ func (a *PatientCon) Create(c *gin.Context) {
 		return
 	}
 
-	HospitalID := userData.(*entities.JwtClaim).HospitalID
+	claim := userData.(*entities.JwtClaim)
+	HospitalID := claim.HospitalID
 
-	var patient entities.Patient
+	viewer, ok := patientViewer(c, claim)
+	if !ok {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&patient); err != nil {
-		utils.BadRequestResponse(c, err.Error())
+	era, ok := patientEra(c)
+	if !ok {
+		return
+	}
+
+	// Addresses and emergency contacts are added through their own routes,
+	// which validate them, so the request has no place for them either.
+	var req entities.PatientCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
 		return
 	}
 
-	if patient.NationalID == "" && patient.PassportID == "" {
-		utils.BadRequestResponse(c, "national_id or passport_id is required")
+	patient := req.Patient(HospitalID)
+	patient.DateOfBirth = dateFromEra(patient.DateOfBirth, era)
+
+	if err := patient.Validate(); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if len(patient.NationalID) > 13 {
-		utils.BadRequestResponse(c, "national_id must be less than 13 characters")
+	createdPatient, err := a.PatientUsecase.Create(patient)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	if patient.Gender != "M" && patient.Gender != "F" {
-		utils.BadRequestResponse(c, "gender must be M or F")
+	shaped, ok := a.shapePatient(c, claim, viewer, era, createdPatient)
+	if !ok {
 		return
 	}
 
-	patient.HospitalID = HospitalID
+	utils.SetETag(c, createdPatient.Version)
+	utils.OkResponse(c, shaped)
+}
 
-	createdPatient, err := a.PatientUsecase.Create(&patient)
+// Update changes the patient named by the id in the body. Only the fields
+// present are changed, and identifiers sent back as they were shown, masked or
+// blank, keep their stored value. Like Patch it needs If-Match.
+func (a *PatientCon) Update(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	viewer, ok := patientViewer(c, claim)
+	if !ok {
+		return
+	}
+
+	era, ok := patientEra(c)
+	if !ok {
+		return
+	}
+
+	var req entities.PatientUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+	req.DateOfBirth = dateFromEra(req.DateOfBirth, era)
+
+	version, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	a.patch(c, claim, viewer, era, req.ID, version, &req.PatientPatchRequest)
+}
+
+// Patch changes only the fields present in the body of the patient with the
+// id in the path. Like Update it needs If-Match.
+func (a *PatientCon) Patch(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	viewer, ok := patientViewer(c, claim)
+	if !ok {
+		return
+	}
+
+	era, ok := patientEra(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.PatientPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
 		return
 	}
+	req.DateOfBirth = dateFromEra(req.DateOfBirth, era)
 
-	utils.OkResponse(c, createdPatient)
+	version, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	a.patch(c, claim, viewer, era, uint(id), version, &req)
 }
 
-func (a *PatientCon) Update(c *gin.Context) {
+// patch applies req to the stored patient and saves it as of version.
+func (a *PatientCon) patch(c *gin.Context, claim *entities.JwtClaim, viewer masking.Viewer, era civil.Era, id uint, version uint, req *entities.PatientPatchRequest) {
+	patient, err := a.PatientUsecase.FindById(id, claim.HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := viewer.Patch(*patient, req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	req.Apply(patient)
+	patient.Version = version
+	if err := patient.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	updatedPatient, err := a.PatientUsecase.Update(patient, claim.HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	shaped, ok := a.shapePatient(c, claim, viewer, era, updatedPatient)
+	if !ok {
+		return
+	}
+
+	utils.SetETag(c, updatedPatient.Version)
+	utils.OkResponse(c, shaped)
+}
+
+// Get finds a patient by its id. FindById takes a national or passport ID
+// instead.
+func (a *PatientCon) Get(c *gin.Context) {
 	userData, exists := c.Get("user_data")
 	if !exists {
 		utils.UnauthorizedResponse(c, "Unauthorized")
 		return
 	}
 
-	HospitalID := userData.(*entities.JwtClaim).HospitalID
+	claim := userData.(*entities.JwtClaim)
+	HospitalID := claim.HospitalID
 
-	var patient entities.Patient
+	viewer, ok := patientViewer(c, claim)
+	if !ok {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&patient); err != nil {
-		utils.BadRequestResponse(c, err.Error())
+	era, ok := patientEra(c)
+	if !ok {
 		return
 	}
 
-	updatedPatient, err := a.PatientUsecase.Update(&patient, HospitalID)
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		utils.NotFoundResponse(c, err.Error())
+		utils.BadRequestResponse(c, "id is not a number")
 		return
 	}
 
-	utils.OkResponse(c, updatedPatient)
+	patient, err := a.PatientUsecase.FindById(uint(id), HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	shaped, ok := a.shapePatient(c, claim, viewer, era, patient)
+	if !ok {
+		return
+	}
+
+	utils.SetETag(c, patient.Version)
+	utils.OkResponse(c, shaped)
 }
 
 func (a *PatientCon) FindById(c *gin.Context) {
@@ -104,17 +270,34 @@ func (a *PatientCon) FindById(c *gin.Context) {
 		return
 	}
 
-	HospitalID := userData.(*entities.JwtClaim).HospitalID
+	claim := userData.(*entities.JwtClaim)
+	HospitalID := claim.HospitalID
+
+	viewer, ok := patientViewer(c, claim)
+	if !ok {
+		return
+	}
+
+	era, ok := patientEra(c)
+	if !ok {
+		return
+	}
 
 	id := c.Param("id")
 
 	patient, err := a.PatientUsecase.FindByIdNationalOrPassport(id, HospitalID)
 	if err != nil {
-		utils.NotFoundResponse(c, "patient not found")
+		c.Error(err)
+		return
+	}
+
+	shaped, ok := a.shapePatient(c, claim, viewer, era, patient)
+	if !ok {
 		return
 	}
 
-	utils.OkResponse(c, patient)
+	utils.SetETag(c, patient.Version)
+	utils.OkResponse(c, shaped)
 }
 
 func (a *PatientCon) Delete(c *gin.Context) {
@@ -136,64 +319,126 @@ func (a *PatientCon) Delete(c *gin.Context) {
 
 	_, err = a.PatientUsecase.Delete(uint(patientID), HospitalID)
 	if err != nil {
-		utils.NotFoundResponse(c, "patient not found")
+		c.Error(err)
 		return
 	}
 
 	utils.OkResponse(c, "deleted successfully")
 }
 
-func (a *PatientCon) FindByAdvanceSearch(c *gin.Context) {
+// FindAll lists the patients of the caller's hospital. It accepts the
+// PatientQuery parameters, for example
+// filter[date_of_birth][gte]=2530-01-01&era=be&sort=last_name_th&include=addresses.
+// Dates in filters are read in the requested era.
+func (a *PatientCon) FindAll(c *gin.Context) {
 	userData, exists := c.Get("user_data")
 	if !exists {
 		utils.UnauthorizedResponse(c, "Unauthorized")
 		return
 	}
 
-	HospitalID := userData.(*entities.JwtClaim).HospitalID
+	claim := userData.(*entities.JwtClaim)
 
-	var input entities.PatientSearchInput
+	viewer, ok := patientViewer(c, claim)
+	if !ok {
+		return
+	}
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		utils.BadRequestResponse(c, err.Error())
+	era, ok := patientEra(c)
+	if !ok {
 		return
 	}
-	input.HospitalID = HospitalID
 
-	page := c.Query("page")
-	limit := c.Query("limit")
+	spec, err := entities.PatientQuery.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	specFromEra(spec, era)
 
-	if page == "" {
-		page = "1"
+	patients, totalPage, err := a.PatientUsecase.FindAll(spec, claim.HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	if limit == "" {
-		limit = "10"
+	shaped, ok := a.shapePatients(c, claim, viewer, era, patients)
+	if !ok {
+		return
 	}
 
-	limitInt, err := strconv.Atoi(limit)
+	picked, err := spec.Pick(shaped)
 	if err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, gin.H{
+		"patients": picked,
+		"meta": gin.H{
+			"page":       spec.Page,
+			"limit":      spec.Limit,
+			"page_total": totalPage,
+		},
+	})
+}
+
+func (a *PatientCon) FindByAdvanceSearch(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+	HospitalID := claim.HospitalID
+
+	viewer, ok := patientViewer(c, claim)
+	if !ok {
+		return
+	}
+
+	era, ok := patientEra(c)
+	if !ok {
 		return
 	}
 
-	pageInt, err := strconv.Atoi(page)
+	var input entities.PatientSearchInput
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+	input.HospitalID = HospitalID
+	searchInputFromEra(&input, era)
+
+	if err := input.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, limit, err := query.ParsePage(c.Request.URL.Query(), 10, 0)
 	if err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
-	patient, totalPage, err := a.PatientUsecase.FindByAdvanceSearch(input, pageInt, limitInt)
+	patient, totalPage, err := a.PatientUsecase.FindByAdvanceSearch(input, page, limit)
 	if err != nil {
-		utils.ErrorResponse(c, err.Error())
+		c.Error(err)
+		return
+	}
+
+	shaped, ok := a.shapePatients(c, claim, viewer, era, patient)
+	if !ok {
 		return
 	}
 
 	utils.OkResponse(c, gin.H{
-		"patients": patient,
+		"patients": shaped,
 		"meta": gin.H{
-			"page":       pageInt,
-			"limit":      limitInt,
+			"page":       page,
+			"limit":      limit,
 			"page_total": totalPage,
 		},
 	})
@@ -14,11 +14,15 @@ import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
 	"github.com/Teemo4621/Hospital-Api/modules/mocks"
 	"github.com/Teemo4621/Hospital-Api/modules/patients/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
 	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Placeholder for utils.APIResponse; replace with actual struct from utils package
@@ -28,17 +32,28 @@ type APIResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+type ProblemResponse struct {
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
 // ----------- Test Setup ----------- //
 
 func setupRouter(mockUseCase *mocks.MockPatientUseCase) (*gin.Engine, *configs.Config, *middlewares.AuthMiddleware) {
+	return setupRouterWithAudit(mockUseCase, mocks.NewMockAuditLogUseCase())
+}
+
+func setupRouterWithAudit(mockUseCase *mocks.MockPatientUseCase, mockAudit *mocks.MockAuditLogUseCase) (*gin.Engine, *configs.Config, *middlewares.AuthMiddleware) {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
 	cfg := &configs.Config{}
 	cfg.JWT.Secret = "test"
 	cfg.JWT.Expire = 1
 	authMiddleware := middlewares.NewAuthMiddleware(cfg)
 	group := r.Group("/patient")
-	controllers.NewPatientController(group, *cfg, mockUseCase, *authMiddleware)
+	controllers.NewPatientController(group, *cfg, mockUseCase, mockAudit, *authMiddleware)
 	return r, cfg, authMiddleware
 }
 
@@ -64,7 +79,7 @@ func TestCreatePatientController(t *testing.T) {
 		mockUseCase := mocks.NewMockPatientUseCase()
 		r, cfg, _ := setupRouter(mockUseCase)
 
-		date := time.Now().Truncate(time.Second)
+		date := civil.DateOf(time.Now())
 
 		expectedPatient := &entities.Patient{
 			FirstNameTH: "Test",
@@ -73,7 +88,7 @@ func TestCreatePatientController(t *testing.T) {
 			LastNameEN:  "A",
 			DateOfBirth: &date,
 			PatientHN:   "HN123",
-			Gender:      "M",
+			Gender:      consts.GenderMale,
 			HospitalID:  1,
 			NationalID:  "1234567890123",
 		}
@@ -88,7 +103,7 @@ func TestCreatePatientController(t *testing.T) {
             "patient_hn":"HN123",
             "gender":"M",
             "national_id":"1234567890123"
-        }`, date.Format(time.RFC3339))
+        }`, date.String())
 		req, _ := http.NewRequest(http.MethodPost, "/patient/create", bytes.NewBufferString(reqBody))
 		req.Header.Set("Content-Type", "application/json")
 		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
@@ -104,6 +119,72 @@ func TestCreatePatientController(t *testing.T) {
 		mockUseCase.AssertExpectations(t)
 	})
 
+	t.Run("Ignores Nested Demographics", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		mockUseCase.On("Create", mock.MatchedBy(func(p *entities.Patient) bool {
+			return p.Addresses == nil && p.EmergencyContacts == nil
+		})).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+
+		reqBody := `{
+            "first_name_th":"Test",
+            "last_name_th":"A",
+            "first_name_en":"Test",
+            "last_name_en":"A",
+            "date_of_birth":"1990-01-02",
+            "patient_hn":"HN123",
+            "gender":"M",
+            "national_id":"1234567890123",
+            "addresses":[{"type":"nonsense"}],
+            "emergency_contacts":[{"name":""}]
+        }`
+		req, _ := http.NewRequest(http.MethodPost, "/patient/create", bytes.NewBufferString(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Ignores Server Managed Fields", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		mockUseCase.On("Create", mock.MatchedBy(func(p *entities.Patient) bool {
+			return p.ID == 0 && p.Version == 0 && p.ArchivedAt == nil && p.HospitalID == 1 &&
+				p.NationalIDIdx == "" && p.PassportIDIdx == ""
+		})).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+
+		reqBody := `{
+            "id":99,
+            "version":7,
+            "archived_at":"2020-01-01T00:00:00Z",
+            "hospital_id":9,
+            "HospitalID":9,
+            "NationalIDIdx":"forged",
+            "PassportIDIdx":"forged",
+            "first_name_th":"Test",
+            "last_name_th":"A",
+            "first_name_en":"Test",
+            "last_name_en":"A",
+            "date_of_birth":"1990-01-02",
+            "patient_hn":"HN123",
+            "gender":"M",
+            "national_id":"1234567890123"
+        }`
+		req, _ := http.NewRequest(http.MethodPost, "/patient/create", bytes.NewBufferString(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
 	t.Run("Unauthorized", func(t *testing.T) {
 		mockUseCase := mocks.NewMockPatientUseCase()
 		r, _, _ := setupRouter(mockUseCase)
@@ -124,10 +205,10 @@ func TestCreatePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusUnauthorized, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "Unauthorized", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "Unauthorized", response.Detail)
 		mockUseCase.AssertNotCalled(t, "Create")
 	})
 
@@ -144,9 +225,9 @@ func TestCreatePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
 		mockUseCase.AssertNotCalled(t, "Create")
 	})
 
@@ -156,7 +237,6 @@ func TestCreatePatientController(t *testing.T) {
 
 		// Missing first_name_th, date_of_birth
 		reqBody := `{"last_name_th":"A","national_id":"1234567890123"}`
-		mockUseCase.On("Create", mock.Anything).Return((*entities.Patient)(nil), errors.New("missing required fields"))
 		req, _ := http.NewRequest(http.MethodPost, "/patient/create", bytes.NewBufferString(reqBody))
 		req.Header.Set("Content-Type", "application/json")
 		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
@@ -165,7 +245,10 @@ func TestCreatePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		mockUseCase.AssertExpectations(t)
+		var response ProblemResponse
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		assert.Equal(t, "first_name_th is required", response.Detail)
+		mockUseCase.AssertNotCalled(t, "Create")
 	})
 
 	t.Run("Missing NationalID and PassportID", func(t *testing.T) {
@@ -192,10 +275,10 @@ func TestCreatePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "national_id or passport_id is required", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "national_id or passport_id is required", response.Detail)
 		mockUseCase.AssertNotCalled(t, "Create")
 	})
 
@@ -224,10 +307,10 @@ func TestCreatePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "national_id must be less than 13 characters", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "national_id must be less than 13 characters", response.Detail)
 		mockUseCase.AssertNotCalled(t, "Create")
 	})
 
@@ -235,7 +318,7 @@ func TestCreatePatientController(t *testing.T) {
 		mockUseCase := mocks.NewMockPatientUseCase()
 		r, cfg, _ := setupRouter(mockUseCase)
 
-		mockUseCase.On("Create", mock.Anything).Return((*entities.Patient)(nil), errors.New("patient already exists"))
+		mockUseCase.On("Create", mock.Anything).Return((*entities.Patient)(nil), entities.Conflict("patient_exists", "patient already exists"))
 
 		date := time.Now().Truncate(time.Second)
 
@@ -256,13 +339,41 @@ func TestCreatePatientController(t *testing.T) {
 
 		r.ServeHTTP(resp, req)
 
-		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		var response APIResponse
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "patient already exists", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "patient already exists", response.Detail)
 		mockUseCase.AssertExpectations(t)
 	})
+
+	t.Run("Invalid Gender", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		reqBody := `{
+            "first_name_th":"Test",
+            "last_name_th":"A",
+            "first_name_en":"Test",
+            "last_name_en":"A",
+            "date_of_birth":"1990-05-01",
+            "patient_hn":"HN123",
+            "gender":"X",
+            "national_id":"1234567890123"
+        }`
+		req, _ := http.NewRequest(http.MethodPost, "/patient/create", bytes.NewBufferString(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		var response ProblemResponse
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		assert.Equal(t, "gender must be male, female, other or unknown", response.Detail)
+		mockUseCase.AssertNotCalled(t, "Create")
+	})
 }
 
 func TestFindByIdPatientController(t *testing.T) {
@@ -316,6 +427,34 @@ func TestFindByIdPatientController(t *testing.T) {
 		mockUseCase.AssertExpectations(t)
 	})
 
+	t.Run("Gender Label Follows Accept-Language", func(t *testing.T) {
+		for _, tt := range []struct {
+			language string
+			label    string
+		}{{"", "หญิง"}, {"en-US,en;q=0.9", "Female"}} {
+			mockUseCase := mocks.NewMockPatientUseCase()
+			r, cfg, _ := setupRouter(mockUseCase)
+
+			expectedPatient := &entities.Patient{ID: 1, HospitalID: 1, Gender: consts.GenderFemale}
+			mockUseCase.On("FindByIdNationalOrPassport", "1234567890123", uint(1)).Return(expectedPatient, nil)
+
+			req, _ := http.NewRequest(http.MethodGet, "/patient/search/1234567890123", nil)
+			req.Header.Set("Accept-Language", tt.language)
+			AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+			var response struct {
+				Data entities.Patient `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+			assert.Equal(t, tt.label, response.Data.GenderLabel)
+			assert.Empty(t, expectedPatient.GenderLabel)
+		}
+	})
+
 	t.Run("Unauthorized", func(t *testing.T) {
 		mockUseCase := mocks.NewMockPatientUseCase()
 		r, _, _ := setupRouter(mockUseCase)
@@ -326,10 +465,10 @@ func TestFindByIdPatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusUnauthorized, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "Unauthorized", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "Unauthorized", response.Detail)
 		mockUseCase.AssertNotCalled(t, "FindByIdNationalOrPassport")
 	})
 
@@ -337,7 +476,7 @@ func TestFindByIdPatientController(t *testing.T) {
 		mockUseCase := mocks.NewMockPatientUseCase()
 		r, cfg, _ := setupRouter(mockUseCase)
 
-		mockUseCase.On("FindByIdNationalOrPassport", "1234567890123", uint(1)).Return((*entities.Patient)(nil), errors.New("patient not found"))
+		mockUseCase.On("FindByIdNationalOrPassport", "1234567890123", uint(1)).Return((*entities.Patient)(nil), entities.NotFound("patient"))
 
 		req, _ := http.NewRequest(http.MethodGet, "/patient/search/1234567890123", nil)
 		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
@@ -346,9 +485,170 @@ func TestFindByIdPatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusNotFound, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestUpdatePatientController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		updatedPatient := &entities.Patient{ID: 7, FirstNameTH: "Test", HospitalID: 1, Version: 5}
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(storedPatient(), nil)
+		mockUseCase.On("Update", mock.MatchedBy(func(p *entities.Patient) bool {
+			return p.ID == 7 && p.Version == 4 && p.FirstNameTH == "Test" && p.LastNameTH == "ใจดี"
+		}), uint(1)).Return(updatedPatient, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/update", bytes.NewBufferString(`{"id":7,"first_name_th":"Test","hospital_id":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"5"`, resp.Header().Get("ETag"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Masked Response Sent Back Keeps The Identifiers", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		token := createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1, Role: string(consts.StaffRoleClerk)})
+
+		stored := storedPatient()
+		stored.PassportID = "AA1234567"
+		stored.PhoneNumber = "081-234-5678"
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(stored, nil)
+
+		get, _ := http.NewRequest(http.MethodGet, "/patient/search/1234567890123", nil)
+		mockUseCase.On("FindByIdNationalOrPassport", "1234567890123", uint(1)).Return(stored, nil)
+		AddAccessTokenCookie(get, token)
+		getResp := httptest.NewRecorder()
+		r.ServeHTTP(getResp, get)
+		require.Equal(t, http.StatusOK, getResp.Code)
+
+		var shown struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(getResp.Body.Bytes(), &shown))
+		require.Equal(t, "1-xxxx-xxxxx-12-3", shown.Data["national_id"])
+		shown.Data["first_name_en"] = "Somsak"
+		body, _ := json.Marshal(shown.Data)
+
+		mockUseCase.On("Update", mock.MatchedBy(func(p *entities.Patient) bool {
+			return p.FirstNameEN == "Somsak" &&
+				p.NationalID == "1234567890123" &&
+				p.PassportID == "AA1234567" &&
+				p.PhoneNumber == "081-234-5678" &&
+				p.Email == "old@example.com"
+		}), uint(1)).Return(&entities.Patient{ID: 7, HospitalID: 1, Version: 5}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/update", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		AddAccessTokenCookie(req, token)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Blank Identifiers From A Hidden View Are Kept", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(storedPatient(), nil)
+		mockUseCase.On("Update", mock.MatchedBy(func(p *entities.Patient) bool {
+			return p.NationalID == "1234567890123" && p.Email == "old@example.com"
+		}), uint(1)).Return(&entities.Patient{ID: 7, HospitalID: 1, Version: 5}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/update", bytes.NewBufferString(`{"id":7,"national_id":"","passport_id":"","email":""}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1, Role: string(consts.StaffRoleAuditor)}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Masked Value Is Refused", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(storedPatient(), nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/update", bytes.NewBufferString(`{"id":7,"national_id":"3-xxxx-xxxxx-45-6"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		var problem ProblemResponse
+		json.Unmarshal(resp.Body.Bytes(), &problem)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, "validation_failed", problem.Code)
+		mockUseCase.AssertNotCalled(t, "Update")
+	})
+
+	t.Run("Clearing Both Identifiers Is Refused", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(storedPatient(), nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/update", bytes.NewBufferString(`{"id":7,"national_id":"","passport_id":""}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1, Role: string(consts.StaffRoleDoctor)}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Update")
+	})
+
+	t.Run("Missing If-Match", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/update", bytes.NewBufferString(`{"id":7,"first_name_th":"Test"}`))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Update")
+	})
+
+	t.Run("Stale Version", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(storedPatient(), nil)
+		mockUseCase.On("Update", mock.Anything, uint(1)).Return((*entities.Patient)(nil), entities.ErrVersionConflict)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/update", bytes.NewBufferString(`{"id":7,"first_name_th":"Test"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1"`)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
 		mockUseCase.AssertExpectations(t)
 	})
 }
@@ -385,10 +685,10 @@ func TestDeletePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusUnauthorized, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "Unauthorized", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "Unauthorized", response.Detail)
 		mockUseCase.AssertNotCalled(t, "Delete")
 	})
 
@@ -403,9 +703,9 @@ func TestDeletePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
 		mockUseCase.AssertNotCalled(t, "Delete")
 	})
 
@@ -413,7 +713,7 @@ func TestDeletePatientController(t *testing.T) {
 		mockUseCase := mocks.NewMockPatientUseCase()
 		r, cfg, _ := setupRouter(mockUseCase)
 
-		mockUseCase.On("Delete", uint(1), uint(1)).Return((*entities.Patient)(nil), errors.New("patient not found"))
+		mockUseCase.On("Delete", uint(1), uint(1)).Return((*entities.Patient)(nil), entities.NotFound("patient"))
 
 		req, _ := http.NewRequest(http.MethodDelete, "/patient/1", nil)
 		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
@@ -422,10 +722,10 @@ func TestDeletePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusNotFound, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "patient not found", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "patient not found", response.Detail)
 		mockUseCase.AssertExpectations(t)
 	})
 
@@ -433,7 +733,7 @@ func TestDeletePatientController(t *testing.T) {
 		mockUseCase := mocks.NewMockPatientUseCase()
 		r, cfg, _ := setupRouter(mockUseCase)
 
-		mockUseCase.On("Delete", uint(1), uint(1)).Return((*entities.Patient)(nil), errors.New("patient not found"))
+		mockUseCase.On("Delete", uint(1), uint(1)).Return((*entities.Patient)(nil), entities.NotFound("patient"))
 
 		req, _ := http.NewRequest(http.MethodDelete, "/patient/1", nil)
 		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
@@ -442,12 +742,102 @@ func TestDeletePatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusNotFound, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "patient not found", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "patient not found", response.Detail)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestFindAllPatientController(t *testing.T) {
+	t.Run("Lists The Caller's Hospital", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		spec := &query.Spec{Page: 2, Limit: 5, Sort: []query.SortField{{Column: "last_name_th"}}}
+		mockUseCase.On("FindAll", spec, uint(3)).Return([]entities.Patient{{ID: 1, HospitalID: 3}}, 4, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/?page=2&limit=5&sort=last_name_th", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 3}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		meta := body["data"].(map[string]interface{})["meta"].(map[string]interface{})
+		assert.Equal(t, float64(2), meta["page"])
+		assert.Equal(t, float64(5), meta["limit"])
+		assert.Equal(t, float64(4), meta["page_total"])
 		mockUseCase.AssertExpectations(t)
 	})
+
+	t.Run("Buddhist Era Date Filter", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		spec := &query.Spec{
+			Filters: []query.Filter{{Field: "date_of_birth", Column: "date_of_birth", Op: query.OpGte, Value: civil.Date{Year: 1990, Month: time.January, Day: 1}}},
+			Page:    1,
+			Limit:   10,
+		}
+		mockUseCase.On("FindAll", spec, uint(1)).Return([]entities.Patient{}, 0, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/?era=be&filter[date_of_birth][gte]=2533-01-01", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Sparse Fields Are Masked", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		spec := &query.Spec{
+			Fields:   []string{"national_id"},
+			Columns:  []string{"id", "national_id"},
+			Includes: []string{"addresses"},
+			Preloads: []string{"Addresses"},
+			Page:     1,
+			Limit:    10,
+		}
+		patients := []entities.Patient{{ID: 1, NationalID: "1234567890123", Addresses: []entities.PatientAddress{{ID: 9}}}}
+		mockUseCase.On("FindAll", spec, uint(1)).Return(patients, 1, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/?fields=national_id&include=addresses", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1, Role: string(consts.StaffRoleNurse), Scopes: consts.StaffRoleNurse.Scopes()}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		item := body["data"].(map[string]interface{})["patients"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "1-xxxx-xxxxx-12-3", item["national_id"])
+		assert.Contains(t, item, "addresses")
+		assert.Len(t, item, 2)
+	})
+
+	t.Run("Encrypted Fields Cannot Be Filtered", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/?filter[national_id][eq]=1234567890123", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "FindAll")
+	})
 }
 
 func TestFindByAdvanceSearchPatientController(t *testing.T) {
@@ -497,10 +887,10 @@ func TestFindByAdvanceSearchPatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusUnauthorized, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
-		assert.Equal(t, "Unauthorized", response.Message)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "Unauthorized", response.Detail)
 		mockUseCase.AssertNotCalled(t, "FindByAdvanceSearch")
 	})
 
@@ -517,9 +907,9 @@ func TestFindByAdvanceSearchPatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
 		mockUseCase.AssertNotCalled(t, "FindByAdvanceSearch")
 	})
 
@@ -536,9 +926,368 @@ func TestFindByAdvanceSearchPatientController(t *testing.T) {
 		r.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		var response APIResponse
+		var response ProblemResponse
 		json.Unmarshal(resp.Body.Bytes(), &response)
-		assert.Equal(t, "error", response.Status)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
 		mockUseCase.AssertNotCalled(t, "FindByAdvanceSearch")
 	})
+
+	t.Run("Buddhist Era Range", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		ageMin := 30
+		input := entities.PatientSearchInput{
+			HospitalID:      1,
+			DateOfBirthFrom: &civil.Date{Year: 1990, Month: time.January, Day: 1},
+			DateOfBirthTo:   &civil.Date{Year: 1990, Month: time.December, Day: 31},
+			AgeMin:          &ageMin,
+		}
+		dob := civil.Date{Year: 1990, Month: time.May, Day: 1}
+		mockUseCase.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{{ID: 1, HospitalID: 1, DateOfBirth: &dob}}, 1, nil)
+
+		reqBody := `{"date_of_birth_from":"2533-01-01","date_of_birth_to":"2533-12-31","age_min":30}`
+		req, _ := http.NewRequest(http.MethodPost, "/patient/search?era=be", bytes.NewBufferString(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"date_of_birth":"2533-05-01"`)
+		assert.Equal(t, 1990, dob.Year)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Inverted Age Range", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/search", bytes.NewBufferString(`{"age_min":60,"age_max":20}`))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "FindByAdvanceSearch")
+	})
+
+	t.Run("Invalid Era", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/search?era=jp", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestPatientMaskingController(t *testing.T) {
+	findPatient := func(mockUseCase *mocks.MockPatientUseCase) {
+		mockUseCase.On("FindByIdNationalOrPassport", "1", uint(1)).Return(&entities.Patient{
+			ID:          1,
+			HospitalID:  1,
+			NationalID:  "1234567890123",
+			PhoneNumber: "0812345678",
+		}, nil)
+	}
+
+	passport := func(role consts.StaffRole) *entities.Jwtpassport {
+		return &entities.Jwtpassport{Id: 7, HospitalID: 1, Role: string(role), Scopes: role.Scopes()}
+	}
+
+	decodePatient := func(resp *httptest.ResponseRecorder) map[string]interface{} {
+		var body map[string]interface{}
+		json.Unmarshal(resp.Body.Bytes(), &body)
+		return body["data"].(map[string]interface{})
+	}
+
+	t.Run("Clerk Sees Masked Values", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		findPatient(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/search/1", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, passport(consts.StaffRoleClerk)))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		data := decodePatient(resp)
+		assert.Equal(t, "1-xxxx-xxxxx-12-3", data["national_id"])
+		assert.Equal(t, "xxxxxx5678", data["phone_number"])
+	})
+
+	t.Run("Doctor Sees Full Values", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		findPatient(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/search/1", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, passport(consts.StaffRoleDoctor)))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "1234567890123", decodePatient(resp)["national_id"])
+	})
+
+	t.Run("Auditor Sees Nothing", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+		findPatient(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/search/1", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, passport(consts.StaffRoleAuditor)))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		data := decodePatient(resp)
+		assert.Equal(t, "", data["national_id"])
+		assert.Equal(t, "", data["phone_number"])
+	})
+
+	t.Run("Reveal Is Audited", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg, _ := setupRouterWithAudit(mockUseCase, mockAudit)
+		findPatient(mockUseCase)
+		mockAudit.On("Record", mock.MatchedBy(func(logs []entities.AuditLog) bool {
+			return len(logs) == 1 &&
+				logs[0].StaffID == 7 &&
+				logs[0].HospitalID == 1 &&
+				logs[0].ResourceID == 1 &&
+				logs[0].Action == consts.AuditActionRevealPII &&
+				logs[0].Detail == "national_id"
+		})).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/search/1?reveal=national_id", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, passport(consts.StaffRoleClerk)))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		data := decodePatient(resp)
+		assert.Equal(t, "1234567890123", data["national_id"])
+		assert.Equal(t, "xxxxxx5678", data["phone_number"])
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("Reveal Fails Closed When Audit Fails", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg, _ := setupRouterWithAudit(mockUseCase, mockAudit)
+		findPatient(mockUseCase)
+		mockAudit.On("Record", mock.Anything).Return(errors.New("db down"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/search/1?reveal=national_id", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, passport(consts.StaffRoleClerk)))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.NotContains(t, resp.Body.String(), "1234567890123")
+	})
+
+	t.Run("Auditor Cannot Reveal", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg, _ := setupRouterWithAudit(mockUseCase, mockAudit)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/search/1?reveal=national_id", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, passport(consts.StaffRoleAuditor)))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockUseCase.AssertNotCalled(t, "FindByIdNationalOrPassport")
+		mockAudit.AssertNotCalled(t, "Record")
+	})
+
+	t.Run("Unknown Reveal Field", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/search/1?reveal=first_name_th", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, passport(consts.StaffRoleDoctor)))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "FindByIdNationalOrPassport")
+	})
+
+	t.Run("Search Results Are Masked", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg, _ := setupRouter(mockUseCase)
+
+		input := entities.PatientSearchInput{HospitalID: 1}
+		mockUseCase.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{{ID: 1, NationalID: "1234567890123"}}, 1, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/search", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, passport(consts.StaffRoleNurse)))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"national_id":"1-xxxx-xxxxx-12-3"`)
+		assert.NotContains(t, resp.Body.String(), "1234567890123")
+	})
+}
+
+func setupV2Router(mockUseCase *mocks.MockPatientUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/patients")
+	controllers.NewPatientV2Controller(group, *cfg, mockUseCase, mocks.NewMockAuditLogUseCase(), *authMiddleware)
+	return r, cfg
+}
+
+func storedPatient() *entities.Patient {
+	return &entities.Patient{
+		ID:          7,
+		FirstNameTH: "สมชาย",
+		LastNameTH:  "ใจดี",
+		FirstNameEN: "Somchai",
+		LastNameEN:  "Jaidee",
+		DateOfBirth: &civil.Date{Year: 1990, Month: time.January, Day: 2},
+		NationalID:  "1234567890123",
+		Email:       "old@example.com",
+		Gender:      consts.GenderMale,
+		HospitalID:  1,
+		Version:     4,
+	}
+}
+
+func TestPatientV2Controller(t *testing.T) {
+	t.Run("Get By Id", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg := setupV2Router(mockUseCase)
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(storedPatient(), nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patients/7", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"4"`, resp.Header().Get("ETag"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Get Of Another Hospital", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg := setupV2Router(mockUseCase)
+		mockUseCase.On("FindById", uint(7), uint(2)).Return((*entities.Patient)(nil), entities.NotFound("patient"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/patients/7", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 2}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		var problem ProblemResponse
+		json.Unmarshal(resp.Body.Bytes(), &problem)
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Equal(t, "patient_not_found", problem.Code)
+	})
+
+	t.Run("Patch Changes Only The Given Fields", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg := setupV2Router(mockUseCase)
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(storedPatient(), nil)
+		mockUseCase.On("Update", mock.MatchedBy(func(p *entities.Patient) bool {
+			return p.ID == 7 && p.Version == 4 && p.Email == "new@example.com" && p.FirstNameEN == "Somchai" && p.NationalID == "1234567890123"
+		}), uint(1)).Return(&entities.Patient{ID: 7, HospitalID: 1, Version: 5}, nil)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/patients/7", bytes.NewBufferString(`{"email":"new@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"5"`, resp.Header().Get("ETag"))
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Patch Keeps The Patient Valid", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg := setupV2Router(mockUseCase)
+		mockUseCase.On("FindById", uint(7), uint(1)).Return(storedPatient(), nil)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/patients/7", bytes.NewBufferString(`{"first_name_en":" "}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4"`)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		var problem ProblemResponse
+		json.Unmarshal(resp.Body.Bytes(), &problem)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, "validation_failed", problem.Code)
+		mockUseCase.AssertNotCalled(t, "Update")
+	})
+
+	t.Run("Patch Without If-Match", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg := setupV2Router(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPatch, "/patients/7", bytes.NewBufferString(`{"email":"new@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+		mockUseCase.AssertNotCalled(t, "FindById")
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientUseCase()
+		r, cfg := setupV2Router(mockUseCase)
+		mockUseCase.On("Create", mock.MatchedBy(func(p *entities.Patient) bool {
+			return p.FirstNameEN == "Somchai" && p.HospitalID == 1
+		})).Return(storedPatient(), nil)
+
+		body := `{"first_name_th":"สมชาย","last_name_th":"ใจดี","first_name_en":"Somchai","last_name_en":"Jaidee","date_of_birth":"1990-01-02","national_id":"1234567890123","gender":"male"}`
+		req, _ := http.NewRequest(http.MethodPost, "/patients", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
 }
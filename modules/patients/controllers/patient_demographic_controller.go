@@ -0,0 +1,322 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PatientDemographicCon struct {
+	Cfg                       configs.Config
+	PatientDemographicUsecase entities.PatientDemographicUseCase
+	AuthMiddleware            middlewares.AuthMiddleware
+}
+
+func NewPatientDemographicController(c *gin.RouterGroup, cfg configs.Config, patientDemographicUsecase entities.PatientDemographicUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &PatientDemographicCon{
+		Cfg:                       cfg,
+		PatientDemographicUsecase: patientDemographicUsecase,
+		AuthMiddleware:            authMiddleware,
+	}
+
+	c.GET("/:id/addresses", controller.AuthMiddleware.JwtAuthentication(), controller.FindAddresses)
+	c.POST("/:id/addresses", controller.AuthMiddleware.JwtAuthentication(), controller.CreateAddress)
+	c.PUT("/:id/addresses/:addressId", controller.AuthMiddleware.JwtAuthentication(), controller.UpdateAddress)
+	c.DELETE("/:id/addresses/:addressId", controller.AuthMiddleware.JwtAuthentication(), controller.DeleteAddress)
+	c.GET("/:id/emergency-contacts", controller.AuthMiddleware.JwtAuthentication(), controller.FindEmergencyContacts)
+	c.POST("/:id/emergency-contacts", controller.AuthMiddleware.JwtAuthentication(), controller.CreateEmergencyContact)
+	c.PUT("/:id/emergency-contacts/:contactId", controller.AuthMiddleware.JwtAuthentication(), controller.UpdateEmergencyContact)
+	c.DELETE("/:id/emergency-contacts/:contactId", controller.AuthMiddleware.JwtAuthentication(), controller.DeleteEmergencyContact)
+}
+
+func paramId(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id < 1 {
+		utils.BadRequestResponse(c, name+" is required and must be an integer")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (a *PatientDemographicCon) FindAddresses(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	HospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	addresses, err := a.PatientDemographicUsecase.FindAddresses(patientID, HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(addresses) == 0 {
+		addresses = []entities.PatientAddress{}
+	}
+
+	utils.OkResponse(c, addresses)
+}
+
+func (a *PatientDemographicCon) CreateAddress(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	HospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	var req entities.PatientAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	address := newPatientAddress(&req)
+	address.PatientID = patientID
+
+	createdAddress, err := a.PatientDemographicUsecase.CreateAddress(address, HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, createdAddress)
+}
+
+func (a *PatientDemographicCon) UpdateAddress(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	HospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	addressID, ok := paramId(c, "addressId")
+	if !ok {
+		return
+	}
+
+	var req entities.PatientAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	address := newPatientAddress(&req)
+	address.ID = addressID
+	address.PatientID = patientID
+
+	updatedAddress, err := a.PatientDemographicUsecase.UpdateAddress(address, HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, updatedAddress)
+}
+
+func (a *PatientDemographicCon) DeleteAddress(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	HospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	addressID, ok := paramId(c, "addressId")
+	if !ok {
+		return
+	}
+
+	if err := a.PatientDemographicUsecase.DeleteAddress(patientID, addressID, HospitalID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, "deleted successfully")
+}
+
+func (a *PatientDemographicCon) FindEmergencyContacts(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	HospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	contacts, err := a.PatientDemographicUsecase.FindEmergencyContacts(patientID, HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(contacts) == 0 {
+		contacts = []entities.PatientEmergencyContact{}
+	}
+
+	utils.OkResponse(c, contacts)
+}
+
+func (a *PatientDemographicCon) CreateEmergencyContact(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	HospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	var req entities.PatientEmergencyContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	contact := newPatientEmergencyContact(&req)
+	contact.PatientID = patientID
+
+	createdContact, err := a.PatientDemographicUsecase.CreateEmergencyContact(contact, HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, createdContact)
+}
+
+func (a *PatientDemographicCon) UpdateEmergencyContact(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	HospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	contactID, ok := paramId(c, "contactId")
+	if !ok {
+		return
+	}
+
+	var req entities.PatientEmergencyContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	contact := newPatientEmergencyContact(&req)
+	contact.ID = contactID
+	contact.PatientID = patientID
+
+	updatedContact, err := a.PatientDemographicUsecase.UpdateEmergencyContact(contact, HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, updatedContact)
+}
+
+func (a *PatientDemographicCon) DeleteEmergencyContact(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	HospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	patientID, ok := paramId(c, "id")
+	if !ok {
+		return
+	}
+
+	contactID, ok := paramId(c, "contactId")
+	if !ok {
+		return
+	}
+
+	if err := a.PatientDemographicUsecase.DeleteEmergencyContact(patientID, contactID, HospitalID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, "deleted successfully")
+}
+
+func newPatientAddress(req *entities.PatientAddressRequest) *entities.PatientAddress {
+	country := req.Country
+	if country == "" {
+		country = "TH"
+	}
+
+	return &entities.PatientAddress{
+		Type:        req.Type,
+		HouseNo:     req.HouseNo,
+		Village:     req.Village,
+		Moo:         req.Moo,
+		Soi:         req.Soi,
+		Road:        req.Road,
+		Subdistrict: req.Subdistrict,
+		District:    req.District,
+		Province:    req.Province,
+		PostalCode:  req.PostalCode,
+		Country:     country,
+	}
+}
+
+func newPatientEmergencyContact(req *entities.PatientEmergencyContactRequest) *entities.PatientEmergencyContact {
+	return &entities.PatientEmergencyContact{
+		FullName:     req.FullName,
+		Relationship: req.Relationship,
+		PhoneNumber:  req.PhoneNumber,
+		Email:        req.Email,
+		Address:      req.Address,
+	}
+}
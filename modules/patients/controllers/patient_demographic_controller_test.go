@@ -0,0 +1,152 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupDemographicRouter(mockUseCase *mocks.MockPatientDemographicUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/patient")
+	controllers.NewPatientDemographicController(group, *cfg, mockUseCase, *authMiddleware)
+	return r, cfg
+}
+
+// ----------- Tests ----------- //
+
+func TestFindAddressesController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientDemographicUseCase()
+		r, cfg := setupDemographicRouter(mockUseCase)
+
+		addresses := []entities.PatientAddress{{ID: 1, PatientID: 1, Type: consts.AddressTypeHome}}
+		mockUseCase.On("FindAddresses", uint(1), uint(1)).Return(addresses, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/1/addresses", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientDemographicUseCase()
+		r, _ := setupDemographicRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/1/addresses", nil)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		mockUseCase.AssertNotCalled(t, "FindAddresses")
+	})
+
+	t.Run("Patient Not Found", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientDemographicUseCase()
+		r, cfg := setupDemographicRouter(mockUseCase)
+
+		mockUseCase.On("FindAddresses", uint(1), uint(1)).Return([]entities.PatientAddress(nil), entities.NotFound("patient"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/1/addresses", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
+
+func TestCreateAddressController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientDemographicUseCase()
+		r, cfg := setupDemographicRouter(mockUseCase)
+
+		mockUseCase.On("CreateAddress", mock.MatchedBy(func(a *entities.PatientAddress) bool {
+			return a.PatientID == 1 && a.Type == consts.AddressTypeRegistered && a.Country == "TH"
+		}), uint(1)).Return(&entities.PatientAddress{ID: 1, PatientID: 1}, nil)
+
+		reqBody := `{"type":"registered","house_no":"99/1","subdistrict":"Lumphini","district":"Pathum Wan","province":"Bangkok","postal_code":"10330"}`
+		req, _ := http.NewRequest(http.MethodPost, "/patient/1/addresses", bytes.NewBufferString(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Missing Required Fields", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientDemographicUseCase()
+		r, cfg := setupDemographicRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/1/addresses", bytes.NewBufferString(`{"type":"home"}`))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "CreateAddress")
+	})
+}
+
+func TestDeleteEmergencyContactController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientDemographicUseCase()
+		r, cfg := setupDemographicRouter(mockUseCase)
+
+		mockUseCase.On("DeleteEmergencyContact", uint(1), uint(2), uint(1)).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/patient/1/emergency-contacts/2", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Contact ID", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientDemographicUseCase()
+		r, cfg := setupDemographicRouter(mockUseCase)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/patient/1/emergency-contacts/abc", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "DeleteEmergencyContact")
+	})
+}
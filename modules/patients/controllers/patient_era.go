@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// patientEra reads `?era=be|ce`, the calendar patient dates are sent and
+// returned in. It writes the error response itself and returns false on a
+// bad value.
+func patientEra(c *gin.Context) (civil.Era, bool) {
+	era, err := civil.ParseEra(c.Query("era"))
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return "", false
+	}
+	return era, true
+}
+
+// dateFromEra converts a date given in era to CE, leaving nil alone.
+func dateFromEra(date *civil.Date, era civil.Era) *civil.Date {
+	if date == nil {
+		return nil
+	}
+	converted := date.FromEra(era)
+	return &converted
+}
+
+func searchInputFromEra(input *entities.PatientSearchInput, era civil.Era) {
+	input.DateOfBirth = dateFromEra(input.DateOfBirth, era)
+	input.DateOfBirthFrom = dateFromEra(input.DateOfBirthFrom, era)
+	input.DateOfBirthTo = dateFromEra(input.DateOfBirthTo, era)
+}
+
+// specFromEra converts the date filters of a listing given in era to CE.
+func specFromEra(spec *query.Spec, era civil.Era) {
+	for i, filter := range spec.Filters {
+		switch value := filter.Value.(type) {
+		case civil.Date:
+			spec.Filters[i].Value = value.FromEra(era)
+		case []any:
+			for j, item := range value {
+				if date, ok := item.(civil.Date); ok {
+					value[j] = date.FromEra(era)
+				}
+			}
+		}
+	}
+}
+
+// patientsInEra flags the patients' dates to be written in era. The dates
+// are copied, so patients shared with the caller are left untouched.
+func patientsInEra(patients []entities.Patient, era civil.Era) {
+	for i := range patients {
+		if patients[i].DateOfBirth != nil {
+			date := patients[i].DateOfBirth.In(era)
+			patients[i].DateOfBirth = &date
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PatientExportCon struct {
+	Cfg                  configs.Config
+	PatientExportUsecase entities.PatientExportUseCase
+	AuditUsecase         entities.AuditLogUseCase
+	AuthMiddleware       middlewares.AuthMiddleware
+}
+
+func NewPatientExportController(c *gin.RouterGroup, cfg configs.Config, patientExportUsecase entities.PatientExportUseCase, auditUsecase entities.AuditLogUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &PatientExportCon{
+		Cfg:                  cfg,
+		PatientExportUsecase: patientExportUsecase,
+		AuditUsecase:         auditUsecase,
+		AuthMiddleware:       authMiddleware,
+	}
+
+	c.POST("/export", controller.AuthMiddleware.JwtAuthentication(), controller.Stream)
+	c.POST("/exports", controller.AuthMiddleware.JwtAuthentication(), controller.Create)
+	c.GET("/exports/:exportId", controller.AuthMiddleware.JwtAuthentication(), controller.FindById)
+	c.GET("/exports/:exportId/download", controller.AuthMiddleware.JwtAuthentication(), controller.Download)
+}
+
+// Stream answers with the export itself. It takes the same body as
+// /patient/search plus `?format=` and `?reveal=`, and is capped at
+// usecases.MaxStreamExportRows; larger exports go through /exports.
+func (a *PatientExportCon) Stream(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	req, ok := exportRequest(c, claim)
+	if !ok {
+		return
+	}
+
+	fileName := fmt.Sprintf("patients-%s.%s", time.Now().Format("20060102"), req.Format)
+	w := &exportResponseWriter{c: c, start: func() error {
+		if err := a.recordExport(c, claim, req, 0); err != nil {
+			return err
+		}
+		c.Header("Content-Type", req.Format.ContentType())
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+		c.Header("Cache-Control", "private, no-store")
+		c.Status(http.StatusOK)
+		return nil
+	}}
+
+	if err := a.PatientExportUsecase.Stream(req, w); err != nil {
+		if w.started {
+			// The status line is gone; all that is left is to cut the body short.
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if w.startErr != nil {
+			utils.ErrorResponse(c, w.startErr.Error())
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	// An empty NDJSON export writes nothing, but still needs its headers.
+	if _, err := w.Write(nil); err != nil {
+		c.Error(err)
+	}
+}
+
+// Create queues a background export and returns the job to poll.
+func (a *PatientExportCon) Create(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	req, ok := exportRequest(c, claim)
+	if !ok {
+		return
+	}
+
+	job, err := a.PatientExportUsecase.Create(req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := a.recordExport(c, claim, req, job.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	a.PatientExportUsecase.Start(job)
+	utils.OkResponse(c, job)
+}
+
+func (a *PatientExportCon) FindById(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	exportID, ok := paramId(c, "exportId")
+	if !ok {
+		return
+	}
+
+	job, err := a.PatientExportUsecase.FindById(exportID, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, job)
+}
+
+func (a *PatientExportCon) Download(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	exportID, ok := paramId(c, "exportId")
+	if !ok {
+		return
+	}
+
+	job, body, err := a.PatientExportUsecase.Open(exportID, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer body.Close()
+
+	fileName := fmt.Sprintf("patients-%d.%s", job.ID, job.Format)
+	c.Header("Content-Type", job.Format.ContentType())
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	c.Header("Cache-Control", "private, no-store")
+	if job.Size > 0 {
+		c.Header("Content-Length", strconv.FormatInt(job.Size, 10))
+	}
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, body); err != nil {
+		c.Error(err)
+	}
+}
+
+var errExportAudit = errors.New("failed to record audit log")
+
+// recordExport audits an export before any patient data leaves the server.
+func (a *PatientExportCon) recordExport(c *gin.Context, claim *entities.JwtClaim, req *entities.PatientExportRequest, exportID uint) error {
+	detail := "format=" + string(req.Format)
+	if len(req.Reveal) > 0 {
+		detail += ";reveal=" + strings.Join(req.Reveal, ",")
+	}
+
+	err := a.AuditUsecase.Record(entities.AuditLog{
+		HospitalID:   claim.HospitalID,
+		StaffID:      claim.Id,
+		Action:       consts.AuditActionExportPatients,
+		ResourceType: consts.AuditResourcePatientExport,
+		ResourceID:   exportID,
+		Detail:       detail,
+		IPAddress:    c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+	})
+	if err != nil {
+		return errExportAudit
+	}
+	return nil
+}
+
+// exportRequest reads the search filter, `?format=`, `?reveal=` and
+// `?era=`. It writes the error response itself and returns false on bad
+// input.
+func exportRequest(c *gin.Context, claim *entities.JwtClaim) (*entities.PatientExportRequest, bool) {
+	viewer, ok := patientViewer(c, claim)
+	if !ok {
+		return nil, false
+	}
+
+	era, ok := patientEra(c)
+	if !ok {
+		return nil, false
+	}
+
+	var input entities.PatientSearchInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		utils.BindErrorResponse(c, err)
+		return nil, false
+	}
+	searchInputFromEra(&input, era)
+
+	format := consts.ExportFormat(strings.ToLower(c.DefaultQuery("format", string(consts.ExportFormatCSV))))
+	if !format.IsValid() {
+		utils.BadRequestResponse(c, "format must be csv, ndjson or xlsx")
+		return nil, false
+	}
+
+	var reveal []string
+	for _, field := range viewer.Revealed() {
+		reveal = append(reveal, string(field))
+	}
+
+	return &entities.PatientExportRequest{
+		HospitalID: claim.HospitalID,
+		StaffID:    claim.Id,
+		Format:     format,
+		Filter:     input,
+		Role:       claim.Role,
+		Reveal:     reveal,
+	}, true
+}
+
+// exportResponseWriter defers the response headers and the audit entry to
+// the first write, so errors found before any data is produced can still be
+// answered with a normal JSON error.
+type exportResponseWriter struct {
+	c        *gin.Context
+	start    func() error
+	started  bool
+	startErr error
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if w.startErr != nil {
+		return 0, w.startErr
+	}
+	if !w.started {
+		if err := w.start(); err != nil {
+			w.startErr = err
+			return 0, err
+		}
+		w.started = true
+	}
+	return w.c.Writer.Write(p)
+}
@@ -0,0 +1,220 @@
+package controllers_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupExportRouter(mockUseCase *mocks.MockPatientExportUseCase, mockAudit *mocks.MockAuditLogUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/patient")
+	controllers.NewPatientExportController(group, *cfg, mockUseCase, mockAudit, *authMiddleware)
+	return r, cfg
+}
+
+func writeExport(content string) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		io.WriteString(args.Get(1).(io.Writer), content)
+	}
+}
+
+// ----------- Tests ----------- //
+
+func TestStreamPatientExportController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientExportUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupExportRouter(mockUseCase, mockAudit)
+
+		mockUseCase.On("Stream", mock.MatchedBy(func(req *entities.PatientExportRequest) bool {
+			return req.HospitalID == 1 &&
+				req.Format == consts.ExportFormatNDJSON &&
+				req.Filter.FirstName == "Som" &&
+				req.Role == string(consts.StaffRoleNurse) &&
+				len(req.Reveal) == 1 && req.Reveal[0] == "national_id"
+		}), mock.Anything).Run(writeExport("{}\n")).Return(nil)
+		mockAudit.On("Record", mock.MatchedBy(func(logs []entities.AuditLog) bool {
+			return len(logs) == 1 &&
+				logs[0].Action == consts.AuditActionExportPatients &&
+				logs[0].Detail == "format=ndjson;reveal=national_id"
+		})).Return(nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/export?format=ndjson&reveal=national_id", bytes.NewBufferString(`{"first_name":"Som"}`))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{
+			Id:         9,
+			HospitalID: 1,
+			Role:       string(consts.StaffRoleNurse),
+			Scopes:     consts.StaffRoleNurse.Scopes(),
+		}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "application/x-ndjson", resp.Header().Get("Content-Type"))
+		assert.Equal(t, "{}\n", resp.Body.String())
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("Rejected Before Writing", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientExportUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupExportRouter(mockUseCase, mockAudit)
+
+		mockUseCase.On("Stream", mock.Anything, mock.Anything).Return(entities.Invalid("export matches 20000 patients; use a background export for more than 10000"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/export", http.NoBody)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		mockAudit.AssertNotCalled(t, "Record", mock.Anything)
+	})
+
+	t.Run("Audit Failure Sends No Data", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientExportUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupExportRouter(mockUseCase, mockAudit)
+
+		var writeErr error
+		mockUseCase.On("Stream", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			_, writeErr = io.WriteString(args.Get(1).(io.Writer), "id\n")
+		}).Return(errors.New("write failed"))
+		mockAudit.On("Record", mock.Anything).Return(errors.New("db down"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/export", http.NoBody)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Error(t, writeErr)
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.NotContains(t, resp.Body.String(), "id\n")
+	})
+
+	t.Run("Reveal Not Allowed", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientExportUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupExportRouter(mockUseCase, mockAudit)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/export?reveal=national_id", http.NoBody)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{
+			Id:         9,
+			HospitalID: 1,
+			Role:       string(consts.StaffRoleSysAdmin),
+			Scopes:     consts.StaffRoleSysAdmin.Scopes(),
+		}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Stream", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Format", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientExportUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupExportRouter(mockUseCase, mockAudit)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/export?format=pdf", http.NoBody)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestCreatePatientExportController(t *testing.T) {
+	mockUseCase := mocks.NewMockPatientExportUseCase()
+	mockAudit := mocks.NewMockAuditLogUseCase()
+	r, cfg := setupExportRouter(mockUseCase, mockAudit)
+
+	job := &entities.PatientExportJob{ID: 5, Format: consts.ExportFormatXLSX, Status: consts.ExportStatusPending}
+	mockUseCase.On("Create", mock.MatchedBy(func(req *entities.PatientExportRequest) bool {
+		return req.Format == consts.ExportFormatXLSX && req.StaffID == 9
+	})).Return(job, nil)
+	mockUseCase.On("Start", job).Return()
+	mockAudit.On("Record", mock.MatchedBy(func(logs []entities.AuditLog) bool {
+		return len(logs) == 1 && logs[0].ResourceID == 5 && logs[0].ResourceType == consts.AuditResourcePatientExport
+	})).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodPost, "/patient/exports?format=xlsx", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockUseCase.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestDownloadPatientExportController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientExportUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupExportRouter(mockUseCase, mockAudit)
+
+		job := &entities.PatientExportJob{ID: 5, Format: consts.ExportFormatCSV, Size: 3}
+		mockUseCase.On("Open", uint(5), uint(1), uint(9)).Return(job, io.NopCloser(bytes.NewBufferString("id\n")), nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/exports/5/download", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+		assert.Equal(t, "attachment; filename=patients-5.csv", resp.Header().Get("Content-Disposition"))
+		assert.Equal(t, "id\n", resp.Body.String())
+	})
+
+	t.Run("Not Ready", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientExportUseCase()
+		mockAudit := mocks.NewMockAuditLogUseCase()
+		r, cfg := setupExportRouter(mockUseCase, mockAudit)
+
+		mockUseCase.On("Open", uint(5), uint(1), uint(9)).Return((*entities.PatientExportJob)(nil), nil, entities.Conflict("export_not_ready", "export is not ready"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/exports/5/download", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+}
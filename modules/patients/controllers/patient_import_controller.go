@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PatientImportCon struct {
+	Cfg                  configs.Config
+	PatientImportUsecase entities.PatientImportUseCase
+	AuthMiddleware       middlewares.AuthMiddleware
+}
+
+func NewPatientImportController(c *gin.RouterGroup, cfg configs.Config, patientImportUsecase entities.PatientImportUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &PatientImportCon{
+		Cfg:                  cfg,
+		PatientImportUsecase: patientImportUsecase,
+		AuthMiddleware:       authMiddleware,
+	}
+
+	c.POST("/imports", controller.AuthMiddleware.JwtAuthentication(), controller.Create)
+	c.GET("/imports/:importId", controller.AuthMiddleware.JwtAuthentication(), controller.FindById)
+	c.GET("/imports/:importId/report", controller.AuthMiddleware.JwtAuthentication(), controller.Report)
+	c.POST("/imports/:importId/commit", controller.AuthMiddleware.JwtAuthentication(), controller.Commit)
+	c.POST("/imports/:importId/resume", controller.AuthMiddleware.JwtAuthentication(), controller.Resume)
+}
+
+// Create accepts a multipart upload with the file, an optional JSON column
+// mapping, dry_run and batch_size. The import runs in the background; poll
+// the returned job for progress.
+func (a *PatientImportCon) Create(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, usecases.DefaultMaxImportSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.BadRequestResponse(c, fmt.Sprintf("file exceeds the %d MB limit", usecases.DefaultMaxImportSize>>20))
+			return
+		}
+		utils.BadRequestResponse(c, "file is required")
+		return
+	}
+
+	var mapping map[string]string
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			utils.BadRequestResponse(c, "mapping must be a JSON object of patient field to column name")
+			return
+		}
+	}
+
+	dryRun := false
+	if raw := c.PostForm("dry_run"); raw != "" {
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "dry_run must be true or false")
+			return
+		}
+	}
+
+	batchSize := 0
+	if raw := c.PostForm("batch_size"); raw != "" {
+		batchSize, err = strconv.Atoi(raw)
+		if err != nil {
+			utils.BadRequestResponse(c, "batch_size must be an integer")
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequestResponse(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	job, err := a.PatientImportUsecase.Create(&entities.PatientImportRequest{
+		HospitalID: claim.HospitalID,
+		StaffID:    claim.Id,
+		FileName:   fileHeader.Filename,
+		Format:     c.PostForm("format"),
+		Mapping:    mapping,
+		DryRun:     dryRun,
+		BatchSize:  batchSize,
+		Body:       file,
+		Size:       fileHeader.Size,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	a.PatientImportUsecase.Start(job)
+	utils.OkResponse(c, job)
+}
+
+func (a *PatientImportCon) FindById(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	importID, ok := paramId(c, "importId")
+	if !ok {
+		return
+	}
+
+	job, err := a.PatientImportUsecase.FindById(importID, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, job)
+}
+
+// Report downloads the rows that were rejected or flagged as duplicates.
+func (a *PatientImportCon) Report(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	hospitalID := userData.(*entities.JwtClaim).HospitalID
+
+	importID, ok := paramId(c, "importId")
+	if !ok {
+		return
+	}
+
+	job, err := a.PatientImportUsecase.FindById(importID, hospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	fileName := strings.TrimSuffix(job.FileName, "."+job.Format) + "-errors.csv"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	c.Header("Cache-Control", "private, no-store")
+	c.Status(http.StatusOK)
+
+	if err := a.PatientImportUsecase.WriteReport(job.ID, hospitalID, c.Writer); err != nil {
+		c.Error(err)
+	}
+}
+
+func (a *PatientImportCon) Commit(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	importID, ok := paramId(c, "importId")
+	if !ok {
+		return
+	}
+
+	job, err := a.PatientImportUsecase.Commit(importID, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	a.PatientImportUsecase.Start(job)
+	utils.OkResponse(c, job)
+}
+
+func (a *PatientImportCon) Resume(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	importID, ok := paramId(c, "importId")
+	if !ok {
+		return
+	}
+
+	job, err := a.PatientImportUsecase.Resume(importID, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	a.PatientImportUsecase.Start(job)
+	utils.OkResponse(c, job)
+}
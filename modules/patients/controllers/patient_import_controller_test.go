@@ -0,0 +1,218 @@
+package controllers_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupImportRouter(mockUseCase *mocks.MockPatientImportUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/patient")
+	controllers.NewPatientImportController(group, *cfg, mockUseCase, *authMiddleware)
+	return r, cfg
+}
+
+func newImportRequest(fields map[string]string, content string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	if content != "" {
+		part, _ := writer.CreateFormFile("file", "patients.csv")
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, "/patient/imports", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// ----------- Tests ----------- //
+
+func TestCreatePatientImportController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientImportUseCase()
+		r, cfg := setupImportRouter(mockUseCase)
+
+		job := &entities.PatientImportJob{ID: 1}
+		mockUseCase.On("Create", mock.MatchedBy(func(req *entities.PatientImportRequest) bool {
+			return req.HospitalID == 1 &&
+				req.StaffID == 9 &&
+				req.FileName == "patients.csv" &&
+				req.DryRun &&
+				req.BatchSize == 100 &&
+				req.Mapping["national_id"] == "CID"
+		})).Return(job, nil)
+		mockUseCase.On("Start", job).Return()
+
+		req := newImportRequest(map[string]string{
+			"dry_run":    "true",
+			"batch_size": "100",
+			"mapping":    `{"national_id":"CID"}`,
+		}, "CID\n1234567890123\n")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientImportUseCase()
+		r, cfg := setupImportRouter(mockUseCase)
+
+		req := newImportRequest(map[string]string{"dry_run": "true"}, "")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Invalid Mapping", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientImportUseCase()
+		r, cfg := setupImportRouter(mockUseCase)
+
+		req := newImportRequest(map[string]string{"mapping": `["national_id"]`}, "CID\n")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("Usecase Error", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientImportUseCase()
+		r, cfg := setupImportRouter(mockUseCase)
+
+		mockUseCase.On("Create", mock.Anything).Return((*entities.PatientImportJob)(nil), entities.Invalid("file format must be csv or xlsx"))
+
+		req := newImportRequest(nil, "CID\n")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Start", mock.Anything)
+	})
+}
+
+func TestFindPatientImportController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientImportUseCase()
+		r, cfg := setupImportRouter(mockUseCase)
+
+		mockUseCase.On("FindById", uint(3), uint(1)).Return(&entities.PatientImportJob{ID: 3, Progress: 50}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/imports/3", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"progress":50`)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientImportUseCase()
+		r, cfg := setupImportRouter(mockUseCase)
+
+		mockUseCase.On("FindById", uint(3), uint(1)).Return((*entities.PatientImportJob)(nil), entities.NotFound("import"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/imports/3", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
+
+func TestPatientImportReportController(t *testing.T) {
+	mockUseCase := mocks.NewMockPatientImportUseCase()
+	r, cfg := setupImportRouter(mockUseCase)
+
+	mockUseCase.On("FindById", uint(3), uint(1)).Return(&entities.PatientImportJob{ID: 3, FileName: "patients.csv", Format: "csv"}, nil)
+	mockUseCase.On("WriteReport", uint(3), uint(1), mock.Anything).Run(func(args mock.Arguments) {
+		io.WriteString(args.Get(2).(io.Writer), "row,kind,field,message\n")
+	}).Return(nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/patient/imports/3/report", nil)
+	AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=patients-errors.csv", resp.Header().Get("Content-Disposition"))
+	assert.Equal(t, "row,kind,field,message\n", resp.Body.String())
+}
+
+func TestCommitAndResumePatientImportController(t *testing.T) {
+	t.Run("Commit Starts New Job", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientImportUseCase()
+		r, cfg := setupImportRouter(mockUseCase)
+
+		job := &entities.PatientImportJob{ID: 4}
+		mockUseCase.On("Commit", uint(3), uint(1), uint(9)).Return(job, nil)
+		mockUseCase.On("Start", job).Return()
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/imports/3/commit", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Resume While Running", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientImportUseCase()
+		r, cfg := setupImportRouter(mockUseCase)
+
+		mockUseCase.On("Resume", uint(3), uint(1)).Return((*entities.PatientImportJob)(nil), entities.Conflict("import_running", "import is already running"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/imports/3/resume", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Start", mock.Anything)
+	})
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/masking"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// patientViewer resolves the masking view for the caller, including any
+// `?reveal=` fields. It writes the error response itself and returns false
+// when the request asks for something the caller may not see, so handlers
+// can check it before touching any data.
+func patientViewer(c *gin.Context, claim *entities.JwtClaim) (masking.Viewer, bool) {
+	viewer := masking.NewViewer(claim)
+
+	reveal, err := masking.ParseReveal(c.QueryArray("reveal"))
+	if err != nil {
+		c.Error(err)
+		return viewer, false
+	}
+
+	viewer, err = viewer.WithReveal(reveal)
+	if err != nil {
+		c.Error(err)
+		return viewer, false
+	}
+
+	return viewer, true
+}
+
+// shapePatients masks the patients for the viewer, writes their dates in
+// era and labels their gender in the request locale. Unmasked fields are
+// audited per patient before anything is returned, so a failed audit write
+// answers 500 instead of leaking the values.
+func (a *PatientCon) shapePatients(c *gin.Context, claim *entities.JwtClaim, viewer masking.Viewer, era civil.Era, patients []entities.Patient) ([]entities.Patient, bool) {
+	if revealed := viewer.Revealed(); len(revealed) > 0 && len(patients) > 0 {
+		names := make([]string, len(revealed))
+		for i, field := range revealed {
+			names[i] = string(field)
+		}
+
+		logs := make([]entities.AuditLog, len(patients))
+		for i, patient := range patients {
+			logs[i] = entities.AuditLog{
+				HospitalID:   claim.HospitalID,
+				StaffID:      claim.Id,
+				Action:       consts.AuditActionRevealPII,
+				ResourceType: consts.AuditResourcePatient,
+				ResourceID:   patient.ID,
+				Detail:       strings.Join(names, ","),
+				IPAddress:    c.ClientIP(),
+				UserAgent:    c.Request.UserAgent(),
+			}
+		}
+
+		if err := a.AuditUsecase.Record(logs...); err != nil {
+			utils.ErrorResponse(c, "failed to record audit log")
+			return nil, false
+		}
+	}
+
+	shaped := viewer.Patients(patients)
+	patientsInEra(shaped, era)
+
+	locale := utils.RequestLocale(c)
+	for i := range shaped {
+		shaped[i].GenderLabel = shaped[i].Gender.Label(locale)
+	}
+	return shaped, true
+}
+
+func (a *PatientCon) shapePatient(c *gin.Context, claim *entities.JwtClaim, viewer masking.Viewer, era civil.Era, patient *entities.Patient) (*entities.Patient, bool) {
+	shaped, ok := a.shapePatients(c, claim, viewer, era, []entities.Patient{*patient})
+	if !ok {
+		return nil, false
+	}
+	return &shaped[0], true
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PatientTransferCon struct {
+	Cfg                    configs.Config
+	PatientTransferUsecase entities.PatientTransferUseCase
+	AuthMiddleware         middlewares.AuthMiddleware
+}
+
+func NewPatientTransferController(c *gin.RouterGroup, cfg configs.Config, patientTransferUsecase entities.PatientTransferUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &PatientTransferCon{
+		Cfg:                    cfg,
+		PatientTransferUsecase: patientTransferUsecase,
+		AuthMiddleware:         authMiddleware,
+	}
+
+	c.GET("/transfers", controller.AuthMiddleware.JwtAuthentication(), controller.FindAll)
+	c.POST("/transfers", controller.AuthMiddleware.JwtAuthentication(), controller.Create)
+	c.GET("/transfers/:transferId", controller.AuthMiddleware.JwtAuthentication(), controller.FindById)
+	c.POST("/transfers/:transferId/accept", controller.AuthMiddleware.JwtAuthentication(), controller.Accept)
+	c.POST("/transfers/:transferId/reject", controller.AuthMiddleware.JwtAuthentication(), controller.Reject)
+	c.POST("/transfers/:transferId/cancel", controller.AuthMiddleware.JwtAuthentication(), controller.Cancel)
+}
+
+// FindAll lists transfers the staff member's hospital sends or receives,
+// narrowed by `?direction=incoming|outgoing` and `?status=`.
+func (a *PatientTransferCon) FindAll(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	var filter entities.PatientTransferFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+	filter.HospitalID = userData.(*entities.JwtClaim).HospitalID
+
+	page, limit, err := query.ParsePage(c.Request.URL.Query(), 20, 0)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	transfers, totalPage, err := a.PatientTransferUsecase.FindAll(filter, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(transfers) == 0 {
+		transfers = []entities.PatientTransfer{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"transfers": transfers,
+		"meta": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"page_total": totalPage,
+		},
+	})
+}
+
+func (a *PatientTransferCon) Create(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	var req entities.PatientTransferCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	transfer, err := a.PatientTransferUsecase.Create(&req, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, transfer.Version)
+	utils.OkResponse(c, transfer)
+}
+
+func (a *PatientTransferCon) FindById(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	transferID, ok := paramId(c, "transferId")
+	if !ok {
+		return
+	}
+
+	transfer, err := a.PatientTransferUsecase.FindById(transferID, userData.(*entities.JwtClaim).HospitalID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, transfer.Version)
+	utils.OkResponse(c, transfer)
+}
+
+// Accept takes an optional body; patient_hn is only needed when the patient
+// is not already registered at the destination.
+func (a *PatientTransferCon) Accept(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	transferID, ok := paramId(c, "transferId")
+	if !ok {
+		return
+	}
+
+	var req entities.PatientTransferAcceptRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	transfer, err := a.PatientTransferUsecase.Accept(transferID, &req, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, transfer.Version)
+	utils.OkResponse(c, transfer)
+}
+
+func (a *PatientTransferCon) Reject(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	transferID, ok := paramId(c, "transferId")
+	if !ok {
+		return
+	}
+
+	var req entities.PatientTransferRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	transfer, err := a.PatientTransferUsecase.Reject(transferID, &req, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, transfer.Version)
+	utils.OkResponse(c, transfer)
+}
+
+func (a *PatientTransferCon) Cancel(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	claim := userData.(*entities.JwtClaim)
+
+	transferID, ok := paramId(c, "transferId")
+	if !ok {
+		return
+	}
+
+	transfer, err := a.PatientTransferUsecase.Cancel(transferID, claim.HospitalID, claim.Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, transfer.Version)
+	utils.OkResponse(c, transfer)
+}
@@ -0,0 +1,206 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func setupTransferRouter(mockUseCase *mocks.MockPatientTransferUseCase) (*gin.Engine, *configs.Config) {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/patient")
+	controllers.NewPatientTransferController(group, *cfg, mockUseCase, *authMiddleware)
+	return r, cfg
+}
+
+// ----------- Tests ----------- //
+
+func TestCreatePatientTransferController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		mockUseCase.On("Create", &entities.PatientTransferCreateRequest{PatientID: 10, DestinationHospitalID: 2, Reason: "referral"}, uint(1), uint(9)).
+			Return(&entities.PatientTransfer{ID: 1, Status: consts.TransferStatusRequested, Version: 1}, nil)
+
+		body, _ := json.Marshal(gin.H{"patient_id": 10, "destination_hospital_id": 2, "reason": "referral"})
+		req, _ := http.NewRequest(http.MethodPost, "/patient/transfers", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"1"`, resp.Header().Get("ETag"))
+	})
+
+	t.Run("Missing Reason", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		body, _ := json.Marshal(gin.H{"patient_id": 10, "destination_hospital_id": 2})
+		req, _ := http.NewRequest(http.MethodPost, "/patient/transfers", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockUseCase.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Pending Transfer", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		mockUseCase.On("Create", mock.Anything, uint(1), uint(9)).Return((*entities.PatientTransfer)(nil), entities.ErrTransferPending)
+
+		body, _ := json.Marshal(gin.H{"patient_id": 10, "destination_hospital_id": 2, "reason": "referral"})
+		req, _ := http.NewRequest(http.MethodPost, "/patient/transfers", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+}
+
+func TestFindAllPatientTransfersController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		filter := entities.PatientTransferFilter{HospitalID: 2, Direction: "incoming", Status: consts.TransferStatusRequested}
+		mockUseCase.On("FindAll", filter, 1, 20).Return([]entities.PatientTransfer{{ID: 1}}, 1, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/transfers?direction=incoming&status=requested", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 20, HospitalID: 2}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"transfers"`)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Direction", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		mockUseCase.On("FindAll", mock.Anything, 1, 20).Return([]entities.PatientTransfer{}, 0, entities.Invalid("direction must be incoming or outgoing"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/patient/transfers?direction=sideways", nil)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 20, HospitalID: 2}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestAcceptPatientTransferController(t *testing.T) {
+	t.Run("Success Without Body", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		mockUseCase.On("Accept", uint(1), &entities.PatientTransferAcceptRequest{}, uint(2), uint(20)).
+			Return(&entities.PatientTransfer{ID: 1, Status: consts.TransferStatusAccepted, Linked: true, Version: 2}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/transfers/1/accept", http.NoBody)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 20, HospitalID: 2}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"2"`, resp.Header().Get("ETag"))
+	})
+
+	t.Run("Already Decided", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		mockUseCase.On("Accept", uint(1), mock.Anything, uint(2), uint(20)).Return((*entities.PatientTransfer)(nil), entities.ErrInvalidTransition)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/transfers/1/accept", bytes.NewBufferString(`{"patient_hn":"HN-900"}`))
+		req.Header.Set("Content-Type", "application/json")
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 20, HospitalID: 2}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+}
+
+func TestRejectPatientTransferController(t *testing.T) {
+	mockUseCase := mocks.NewMockPatientTransferUseCase()
+	r, cfg := setupTransferRouter(mockUseCase)
+
+	req, _ := http.NewRequest(http.MethodPost, "/patient/transfers/1/reject", http.NoBody)
+	AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 20, HospitalID: 2}))
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockUseCase.AssertNotCalled(t, "Reject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCancelPatientTransferController(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		mockUseCase.On("Cancel", uint(1), uint(1), uint(9)).Return(&entities.PatientTransfer{ID: 1, Status: consts.TransferStatusCancelled, Version: 2}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/transfers/1/cancel", http.NoBody)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 9, HospitalID: 1}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockUseCase := mocks.NewMockPatientTransferUseCase()
+		r, cfg := setupTransferRouter(mockUseCase)
+
+		mockUseCase.On("Cancel", uint(1), uint(3), uint(30)).Return((*entities.PatientTransfer)(nil), entities.NotFound("transfer"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/patient/transfers/1/cancel", http.NoBody)
+		AddAccessTokenCookie(req, createValidToken(cfg, &entities.Jwtpassport{Id: 30, HospitalID: 3}))
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
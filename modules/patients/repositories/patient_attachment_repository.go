@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+)
+
+type PatientAttachmentRepo struct {
+	Db *gorm.DB
+}
+
+func NewPatientAttachmentRepository(db *gorm.DB) entities.PatientAttachmentRepository {
+	return &PatientAttachmentRepo{Db: db}
+}
+
+func (r *PatientAttachmentRepo) Create(attachment *entities.PatientAttachment) (*entities.PatientAttachment, error) {
+	if err := r.Db.Create(attachment).Error; err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (r *PatientAttachmentRepo) Delete(id uint) error {
+	return r.Db.Delete(&entities.PatientAttachment{}, id).Error
+}
+
+func (r *PatientAttachmentRepo) FindById(id uint) (*entities.PatientAttachment, error) {
+	var attachment entities.PatientAttachment
+	if err := r.Db.First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *PatientAttachmentRepo) FindByPatientId(patientId uint) ([]entities.PatientAttachment, error) {
+	var attachments []entities.PatientAttachment
+	if err := r.Db.Where("patient_id = ?", patientId).Order("created_at DESC, id DESC").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
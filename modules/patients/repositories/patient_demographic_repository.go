@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+)
+
+type PatientDemographicRepo struct {
+	Db *gorm.DB
+}
+
+func NewPatientDemographicRepository(db *gorm.DB) entities.PatientDemographicRepository {
+	return &PatientDemographicRepo{Db: db}
+}
+
+func (r *PatientDemographicRepo) CreateAddress(address *entities.PatientAddress) (*entities.PatientAddress, error) {
+	if err := r.Db.Create(&address).Error; err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (r *PatientDemographicRepo) UpdateAddress(address *entities.PatientAddress) (*entities.PatientAddress, error) {
+	if err := r.Db.Save(&address).Error; err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (r *PatientDemographicRepo) DeleteAddress(id uint) error {
+	return r.Db.Delete(&entities.PatientAddress{}, id).Error
+}
+
+func (r *PatientDemographicRepo) FindAddressById(id uint) (*entities.PatientAddress, error) {
+	var address entities.PatientAddress
+	if err := r.Db.First(&address, id).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *PatientDemographicRepo) FindAddressesByPatientId(patientId uint) ([]entities.PatientAddress, error) {
+	var addresses []entities.PatientAddress
+	if err := r.Db.Where("patient_id = ?", patientId).Order("id").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (r *PatientDemographicRepo) CreateEmergencyContact(contact *entities.PatientEmergencyContact) (*entities.PatientEmergencyContact, error) {
+	if err := r.Db.Create(&contact).Error; err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+func (r *PatientDemographicRepo) UpdateEmergencyContact(contact *entities.PatientEmergencyContact) (*entities.PatientEmergencyContact, error) {
+	if err := r.Db.Save(&contact).Error; err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+func (r *PatientDemographicRepo) DeleteEmergencyContact(id uint) error {
+	return r.Db.Delete(&entities.PatientEmergencyContact{}, id).Error
+}
+
+func (r *PatientDemographicRepo) FindEmergencyContactById(id uint) (*entities.PatientEmergencyContact, error) {
+	var contact entities.PatientEmergencyContact
+	if err := r.Db.First(&contact, id).Error; err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+func (r *PatientDemographicRepo) FindEmergencyContactsByPatientId(patientId uint) ([]entities.PatientEmergencyContact, error) {
+	var contacts []entities.PatientEmergencyContact
+	if err := r.Db.Where("patient_id = ?", patientId).Order("id").Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
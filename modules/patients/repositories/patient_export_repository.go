@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+)
+
+type PatientExportRepo struct {
+	Db *gorm.DB
+}
+
+func NewPatientExportRepository(db *gorm.DB) entities.PatientExportRepository {
+	return &PatientExportRepo{Db: db}
+}
+
+func (r *PatientExportRepo) CreateJob(job *entities.PatientExportJob) (*entities.PatientExportJob, error) {
+	if err := r.Db.Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *PatientExportRepo) UpdateJob(job *entities.PatientExportJob) (*entities.PatientExportJob, error) {
+	version := job.Version
+	job.Version = version + 1
+
+	result := r.Db.Model(job).
+		Where("version = ?", version).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(job)
+	if result.Error != nil {
+		job.Version = version
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		job.Version = version
+		return nil, entities.ErrVersionConflict
+	}
+
+	return job, nil
+}
+
+func (r *PatientExportRepo) FindJobById(id uint) (*entities.PatientExportJob, error) {
+	var job entities.PatientExportJob
+	if err := r.Db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
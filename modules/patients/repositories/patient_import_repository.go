@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PatientImportRepo struct {
+	Db *gorm.DB
+}
+
+func NewPatientImportRepository(db *gorm.DB) entities.PatientImportRepository {
+	return &PatientImportRepo{Db: db}
+}
+
+func (r *PatientImportRepo) CreateJob(job *entities.PatientImportJob) (*entities.PatientImportJob, error) {
+	if err := r.Db.Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *PatientImportRepo) UpdateJob(job *entities.PatientImportJob) (*entities.PatientImportJob, error) {
+	if err := updateImportJob(r.Db, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (r *PatientImportRepo) FindJobById(id uint) (*entities.PatientImportJob, error) {
+	var job entities.PatientImportJob
+	if err := r.Db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindDuplicates returns patients of the hospital that a candidate would
+// duplicate. See findDuplicates.
+func (r *PatientImportRepo) FindDuplicates(hospitalId uint, patients []entities.Patient) ([]entities.Patient, error) {
+	return findDuplicates(r.Db, hospitalId, patients)
+}
+
+// SaveBatch inserts the batch's patients and report lines and advances the
+// job's counters in one transaction, so a crash never leaves rows committed
+// without the job knowing about them.
+func (r *PatientImportRepo) SaveBatch(job *entities.PatientImportJob, patients []entities.Patient, issues []entities.PatientImportIssue) error {
+	return r.Db.Transaction(func(tx *gorm.DB) error {
+		if len(patients) > 0 {
+			for i := range patients {
+				if err := setBlindIndexes(&patients[i]); err != nil {
+					return err
+				}
+			}
+			if err := tx.Omit(clause.Associations).CreateInBatches(patients, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(issues) > 0 {
+			if err := tx.CreateInBatches(issues, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		return updateImportJob(tx, job)
+	})
+}
+
+func (r *PatientImportRepo) FindIssues(jobId uint, afterId uint, limit int) ([]entities.PatientImportIssue, error) {
+	var issues []entities.PatientImportIssue
+	if err := r.Db.
+		Where("job_id = ? AND id > ?", jobId, afterId).
+		Order("id").
+		Limit(limit).
+		Find(&issues).Error; err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func updateImportJob(db *gorm.DB, job *entities.PatientImportJob) error {
+	version := job.Version
+	job.Version = version + 1
+
+	result := db.Model(job).
+		Where("version = ?", version).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(job)
+	if result.Error != nil {
+		job.Version = version
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		job.Version = version
+		return entities.ErrVersionConflict
+	}
+
+	return nil
+}
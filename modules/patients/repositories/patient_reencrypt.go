@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/ciphers"
+	"gorm.io/gorm"
+)
+
+// Blind index domains. Changing either value invalidates every stored index.
+const (
+	nationalIDIndex = "national_id"
+	passportIDIndex = "passport_id"
+)
+
+type encryptedPatientRow struct {
+	ID              uint
+	NationalID      string
+	PassportID      string
+	NationalIDIndex string
+	PassportIDIndex string
+}
+
+// ReencryptPatients walks the patients table and rewrites every identifier
+// that is still plaintext or sealed under an older key version, refreshing
+// the blind indexes on the way. It works on raw column values so it can run
+// while legacy plaintext rows still exist, and is safe to re-run.
+func ReencryptPatients(db *gorm.DB, keyring *ciphers.Keyring, batchSize int) (int, error) {
+	updated := 0
+	writer := db.Session(&gorm.Session{NewDB: true})
+
+	var rows []encryptedPatientRow
+	result := db.Table("patients").
+		Select("id, national_id, passport_id, national_id_index, passport_id_index").
+		FindInBatches(&rows, batchSize, func(tx *gorm.DB, batch int) error {
+			for _, row := range rows {
+				changes := map[string]interface{}{}
+
+				if err := reencryptColumn(keyring, changes, "national_id", row.NationalID, nationalIDIndex, row.NationalIDIndex); err != nil {
+					return fmt.Errorf("patient %d: %w", row.ID, err)
+				}
+				if err := reencryptColumn(keyring, changes, "passport_id", row.PassportID, passportIDIndex, row.PassportIDIndex); err != nil {
+					return fmt.Errorf("patient %d: %w", row.ID, err)
+				}
+
+				if len(changes) == 0 {
+					continue
+				}
+
+				if err := writer.Table("patients").Where("id = ?", row.ID).UpdateColumns(changes).Error; err != nil {
+					return fmt.Errorf("patient %d: %w", row.ID, err)
+				}
+				updated++
+			}
+			return nil
+		})
+
+	return updated, result.Error
+}
+
+func reencryptColumn(keyring *ciphers.Keyring, changes map[string]interface{}, column string, stored string, indexDomain string, storedIndex string) error {
+	plaintext, err := keyring.Decrypt(stored)
+	if err != nil {
+		return err
+	}
+
+	if keyring.NeedsRotation(stored) {
+		sealed, err := keyring.Encrypt(plaintext)
+		if err != nil {
+			return err
+		}
+		changes[column] = sealed
+	}
+
+	if index := keyring.BlindIndex(indexDomain, plaintext); index != storedIndex {
+		changes[column+"_index"] = index
+	}
+
+	return nil
+}
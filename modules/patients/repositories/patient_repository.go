@@ -1,10 +1,13 @@
 package repositories
 
 import (
-	"errors"
+	"strings"
 
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/ciphers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PatientRepo struct {
@@ -16,18 +19,49 @@ func NewPatientRepository(db *gorm.DB) entities.PatientRepository {
 }
 
 func (r *PatientRepo) Create(patient *entities.Patient) (*entities.Patient, error) {
-	if err := r.Db.Create(&patient).Error; err != nil {
+	if err := setBlindIndexes(patient); err != nil {
+		return nil, err
+	}
+
+	if err := r.Db.Omit(clause.Associations).Create(patient).Error; err != nil {
 		return nil, err
 	}
 
 	return patient, nil
 }
 
+// Update writes the patient if it still has the version the caller read.
+// Archived patients are never written; they are reported as not found.
 func (r *PatientRepo) Update(patient *entities.Patient) (*entities.Patient, error) {
-	if err := r.Db.Save(&patient).Error; err != nil {
+	if err := setBlindIndexes(patient); err != nil {
 		return nil, err
 	}
 
+	version := patient.Version
+	patient.Version = version + 1
+
+	result := r.Db.Model(patient).
+		Where("version = ? AND hospital_id = ? AND archived_at IS NULL", version, patient.HospitalID).
+		Select("*").
+		Omit("id", "created_at", "archived_at", clause.Associations).
+		Updates(patient)
+	if result.Error != nil {
+		patient.Version = version
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		patient.Version = version
+		exist, err := r.FindById(patient.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exist.ArchivedAt != nil {
+			return nil, entities.NotFound("patient")
+		}
+		return nil, entities.ErrVersionConflict
+	}
+
 	return patient, nil
 }
 
@@ -38,7 +72,7 @@ func (r *PatientRepo) Delete(id uint) (*entities.Patient, error) {
 	}
 
 	if patient == nil {
-		return nil, errors.New("patient not found")
+		return nil, entities.NotFound("patient")
 	}
 
 	if err := r.Db.Delete(&patient).Error; err != nil {
@@ -56,49 +90,136 @@ func (r *PatientRepo) FindPatientCount() (int64, error) {
 	return count, nil
 }
 
-func (r *PatientRepo) FindAll(page int, limit int) ([]entities.Patient, error) {
+func (r *PatientRepo) FindAll(hospitalId uint, spec *query.Spec) ([]entities.Patient, int, error) {
 	var patients []entities.Patient
-	if err := r.Db.Preload("Hospital").Offset((page - 1) * limit).Limit(limit).Find(&patients).Error; err != nil {
-		return nil, err
+	var totalCount int64
+
+	filtered := r.Db.Model(&entities.Patient{}).Where("hospital_id = ? AND archived_at IS NULL", hospitalId).Scopes(spec.Where(nil))
+	if err := filtered.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
 	}
-	return patients, nil
+
+	if err := filtered.Scopes(spec.List).Find(&patients).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return patients, int(totalCount), nil
 }
 
 func (r *PatientRepo) FindById(id uint) (*entities.Patient, error) {
 	var patient entities.Patient
-	if err := r.Db.Preload("Hospital").First(&patient, id).Error; err != nil {
+	if err := r.Db.Preload("Hospital").Preload("Addresses").Preload("EmergencyContacts").First(&patient, id).Error; err != nil {
 		return nil, err
 	}
 	return &patient, nil
 }
 
-func (r *PatientRepo) FindByIdNationalOrPassport(id string) (*entities.Patient, error) {
+// FindByIdNationalOrPassport finds the hospital's patient by national ID or
+// passport. It is scoped to the hospital because a transfer accepted by
+// copy leaves a row with the same blind index at the source hospital, and
+// archived patients are not found.
+func (r *PatientRepo) FindByIdNationalOrPassport(id string, hospitalId uint) (*entities.Patient, error) {
+	keyring, err := ciphers.Default()
+	if err != nil {
+		return nil, err
+	}
+
+	nationalIdx := keyring.BlindIndex(nationalIDIndex, id)
+	passportIdx := keyring.BlindIndex(passportIDIndex, id)
+	if nationalIdx == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
 	var patient entities.Patient
-	if err := r.Db.Preload("Hospital").Where("national_id = ? OR passport_id = ?", id, id).First(&patient).Error; err != nil {
+	if err := r.Db.Preload("Hospital").Preload("Addresses").Preload("EmergencyContacts").Where("hospital_id = ? AND archived_at IS NULL", hospitalId).Where("national_id_index = ? OR passport_id_index = ?", nationalIdx, passportIdx).First(&patient).Error; err != nil {
 		return nil, err
 	}
 	return &patient, nil
 }
 
-func (r *PatientRepo) FindByName(firstName string, lastName string) ([]entities.Patient, error) {
-	var patients []entities.Patient
-	if err := r.Db.Preload("Hospital").Where("first_name_th = ?", firstName).Where("last_name_th = ?", lastName).Find(&patients).Error; err != nil {
-		return nil, err
-	}
-	return patients, nil
+// FindDuplicates returns patients of the hospital that a candidate would
+// duplicate. See findDuplicates.
+func (r *PatientRepo) FindDuplicates(hospitalId uint, patients []entities.Patient) ([]entities.Patient, error) {
+	return findDuplicates(r.Db, hospitalId, patients)
 }
 
 func (r *PatientRepo) FindByAdvanceSearch(input entities.PatientSearchInput, page int, limit int) ([]entities.Patient, int, error) {
 	var patients []entities.Patient
 	var totalCount int64
 
-	query := r.Db.Model(&entities.Patient{}).Where("hospital_id = ?", input.HospitalID)
+	query, err := r.advanceSearchQuery(input)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset((page - 1) * limit).Limit(limit).Find(&patients).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return patients, int(totalCount), nil
+}
+
+func (r *PatientRepo) CountByAdvanceSearch(input entities.PatientSearchInput) (int, error) {
+	query, err := r.advanceSearchQuery(input)
+	if err != nil {
+		return 0, err
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return 0, err
+	}
+	return int(totalCount), nil
+}
+
+// StreamByAdvanceSearch walks every matching patient in id order over a
+// single database cursor, so memory use does not grow with the result set.
+// Relations are not loaded. Returning an error from fn stops the walk.
+func (r *PatientRepo) StreamByAdvanceSearch(input entities.PatientSearchInput, fn func(patient *entities.Patient) error) error {
+	query, err := r.advanceSearchQuery(input)
+	if err != nil {
+		return err
+	}
 
-	if input.NationalID != "" {
-		query = query.Where("national_id = ?", input.NationalID)
+	rows, err := query.Order("id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var patient entities.Patient
+		if err := r.Db.ScanRows(rows, &patient); err != nil {
+			return err
+		}
+		if err := fn(&patient); err != nil {
+			return err
+		}
 	}
-	if input.PassportID != "" {
-		query = query.Where("passport_id = ?", input.PassportID)
+
+	return rows.Err()
+}
+
+// advanceSearchQuery selects the hospital's patients that match the input.
+// Archived patients never match.
+func (r *PatientRepo) advanceSearchQuery(input entities.PatientSearchInput) (*gorm.DB, error) {
+	query := r.Db.Model(&entities.Patient{}).Where("hospital_id = ? AND archived_at IS NULL", input.HospitalID)
+
+	if input.NationalID != "" || input.PassportID != "" {
+		keyring, err := ciphers.Default()
+		if err != nil {
+			return nil, err
+		}
+		if input.NationalID != "" {
+			query = query.Where("national_id_index = ?", keyring.BlindIndex(nationalIDIndex, input.NationalID))
+		}
+		if input.PassportID != "" {
+			query = query.Where("passport_id_index = ?", keyring.BlindIndex(passportIDIndex, input.PassportID))
+		}
 	}
 	if input.FirstName != "" {
 		query = query.Where("first_name_th ILIKE ? OR first_name_en ILIKE ?", "%"+input.FirstName+"%", "%"+input.FirstName+"%")
@@ -112,6 +233,26 @@ func (r *PatientRepo) FindByAdvanceSearch(input entities.PatientSearchInput, pag
 	if input.DateOfBirth != nil {
 		query = query.Where("date_of_birth = ?", input.DateOfBirth)
 	}
+	if input.DateOfBirthFrom != nil {
+		query = query.Where("date_of_birth >= ?", input.DateOfBirthFrom)
+	}
+	if input.DateOfBirthTo != nil {
+		query = query.Where("date_of_birth <= ?", input.DateOfBirthTo)
+	}
+
+	// Ages are counted as of today in the hospital's time zone, falling back
+	// to the default one, the same way the statistics views count them.
+	if input.AgeMin != nil || input.AgeMax != nil {
+		timezone := r.Db.Model(&entities.HospitalSettings{}).Select("timezone").Where("hospital_id = ?", input.HospitalID)
+		age := gorm.Expr("date_part('year', age((now() AT TIME ZONE COALESCE((?), ?))::date, date_of_birth))",
+			timezone, entities.DefaultHospitalSettings(0).Timezone)
+		if input.AgeMin != nil {
+			query = query.Where("? >= ?", age, *input.AgeMin)
+		}
+		if input.AgeMax != nil {
+			query = query.Where("? <= ?", age, *input.AgeMax)
+		}
+	}
 	if input.PhoneNumber != "" {
 		query = query.Where("phone_number ILIKE ?", "%"+input.PhoneNumber+"%")
 	}
@@ -119,13 +260,63 @@ func (r *PatientRepo) FindByAdvanceSearch(input entities.PatientSearchInput, pag
 		query = query.Where("email ILIKE ?", "%"+input.Email+"%")
 	}
 
-	if err := query.Count(&totalCount).Error; err != nil {
-		return nil, 0, err
+	return query, nil
+}
+
+// findDuplicates returns patients of the hospital that share a national ID,
+// passport ID, HN or Thai first and last name with any of the candidates.
+// Creating a patient and importing patients both check new rows against it.
+func findDuplicates(db *gorm.DB, hospitalId uint, patients []entities.Patient) ([]entities.Patient, error) {
+	keyring, err := ciphers.Default()
+	if err != nil {
+		return nil, err
 	}
 
-	if err := query.Offset((page - 1) * limit).Limit(limit).Find(&patients).Error; err != nil {
-		return nil, 0, err
+	var nationalIdx, passportIdx, hns []string
+	var names [][]any
+	for _, patient := range patients {
+		if idx := keyring.BlindIndex(nationalIDIndex, patient.NationalID); idx != "" {
+			nationalIdx = append(nationalIdx, idx)
+		}
+		if idx := keyring.BlindIndex(passportIDIndex, patient.PassportID); idx != "" {
+			passportIdx = append(passportIdx, idx)
+		}
+		if patient.PatientHN != "" {
+			hns = append(hns, patient.PatientHN)
+		}
+		first, last := strings.TrimSpace(patient.FirstNameTH), strings.TrimSpace(patient.LastNameTH)
+		if first != "" && last != "" {
+			names = append(names, []any{first, last})
+		}
 	}
 
-	return patients, int(totalCount), nil
+	if len(nationalIdx) == 0 && len(passportIdx) == 0 && len(hns) == 0 && len(names) == 0 {
+		return nil, nil
+	}
+
+	matches := db.Where("national_id_index IN ?", nationalIdx).
+		Or("passport_id_index IN ?", passportIdx).
+		Or("patient_hn IN ?", hns)
+	if len(names) > 0 {
+		matches = matches.Or("(first_name_th, last_name_th) IN ?", names)
+	}
+
+	var existing []entities.Patient
+	if err := db.Where("hospital_id = ?", hospitalId).Where(matches).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// setBlindIndexes refreshes the lookup hashes of the encrypted identifiers so
+// they always match the values being written.
+func setBlindIndexes(patient *entities.Patient) error {
+	keyring, err := ciphers.Default()
+	if err != nil {
+		return err
+	}
+
+	patient.NationalIDIdx = keyring.BlindIndex(nationalIDIndex, patient.NationalID)
+	patient.PassportIDIdx = keyring.BlindIndex(passportIDIndex, patient.PassportID)
+	return nil
 }
@@ -0,0 +1,142 @@
+package repositories_test
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/repositories"
+	"github.com/Teemo4621/Hospital-Api/pkgs/ciphers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	_ "github.com/Teemo4621/Hospital-Api/pkgs/databases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDB opens the database in TEST_DATABASE_URL inside a transaction that
+// is rolled back after the test, and skips the test when it is not set.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{TranslateError: true})
+	require.NoError(t, err)
+
+	tx := db.Begin()
+	require.NoError(t, tx.Error)
+	t.Cleanup(func() { tx.Rollback() })
+
+	require.NoError(t, tx.AutoMigrate(&entities.Hospital{}, &entities.Patient{}, &entities.PatientAddress{}, &entities.PatientEmergencyContact{}))
+
+	key := func(b byte) string { return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32))) }
+	keyring, err := ciphers.NewKeyring(configs.Encryption{Keys: "1:" + key('a'), ActiveVersion: 1, IndexKey: key('i')})
+	require.NoError(t, err)
+	previous, _ := ciphers.Default()
+	ciphers.SetDefault(keyring)
+	t.Cleanup(func() { ciphers.SetDefault(previous) })
+
+	return tx
+}
+
+func TestFindByIdNationalOrPassport(t *testing.T) {
+	t.Run("Transferred Copy Is Found At Its Hospital", func(t *testing.T) {
+		db := testDB(t)
+		repo := repositories.NewPatientRepository(db)
+
+		source := &entities.Hospital{HospitalName: "Source Test Hospital", Address: "1 Test Road"}
+		destination := &entities.Hospital{HospitalName: "Destination Test Hospital", Address: "2 Test Road"}
+		require.NoError(t, db.Create(source).Error)
+		require.NoError(t, db.Create(destination).Error)
+
+		born, err := civil.Parse("1990-05-01")
+		require.NoError(t, err)
+		newPatient := func(hospitalId uint) *entities.Patient {
+			return &entities.Patient{
+				FirstNameTH: "ทดสอบ", LastNameTH: "ระบบ", FirstNameEN: "Test", LastNameEN: "System",
+				DateOfBirth: &born, NationalID: "1103700012345", Gender: "male", HospitalID: hospitalId,
+			}
+		}
+
+		original, err := repo.Create(newPatient(source.ID))
+		require.NoError(t, err)
+		copied, err := repo.Create(newPatient(destination.ID))
+		require.NoError(t, err)
+		require.Equal(t, original.NationalIDIdx, copied.NationalIDIdx)
+
+		found, err := repo.FindByIdNationalOrPassport("1103700012345", destination.ID)
+		require.NoError(t, err)
+		assert.Equal(t, copied.ID, found.ID)
+
+		found, err = repo.FindByIdNationalOrPassport("1103700012345", source.ID)
+		require.NoError(t, err)
+		assert.Equal(t, original.ID, found.ID)
+	})
+}
+
+func TestArchivedPatients(t *testing.T) {
+	setup := func(t *testing.T) (entities.PatientRepository, *entities.Hospital, *entities.Patient, *entities.Patient) {
+		db := testDB(t)
+		repo := repositories.NewPatientRepository(db)
+
+		hospital := &entities.Hospital{HospitalName: "Archive Test Hospital", Address: "1 Test Road"}
+		require.NoError(t, db.Create(hospital).Error)
+
+		born, err := civil.Parse("1990-05-01")
+		require.NoError(t, err)
+		active, err := repo.Create(&entities.Patient{
+			FirstNameTH: "ทดสอบ", LastNameTH: "ปกติ", FirstNameEN: "Test", LastNameEN: "Active",
+			DateOfBirth: &born, NationalID: "1103700012345", Gender: "male", HospitalID: hospital.ID,
+		})
+		require.NoError(t, err)
+		archived, err := repo.Create(&entities.Patient{
+			FirstNameTH: "ทดสอบ", LastNameTH: "เก็บ", FirstNameEN: "Test", LastNameEN: "Archived",
+			DateOfBirth: &born, NationalID: "1103700054321", Gender: "male", HospitalID: hospital.ID,
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.Model(archived).UpdateColumn("archived_at", time.Now()).Error)
+
+		return repo, hospital, active, archived
+	}
+
+	t.Run("Left Out Of Listings", func(t *testing.T) {
+		repo, hospital, active, _ := setup(t)
+
+		patients, total, err := repo.FindAll(hospital.ID, &query.Spec{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, patients, 1)
+		assert.Equal(t, active.ID, patients[0].ID)
+	})
+
+	t.Run("Left Out Of Searches", func(t *testing.T) {
+		repo, hospital, _, _ := setup(t)
+
+		patients, total, err := repo.FindByAdvanceSearch(entities.PatientSearchInput{HospitalID: hospital.ID, FirstName: "Test"}, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, patients, 1)
+
+		_, err = repo.FindByIdNationalOrPassport("1103700054321", hospital.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Cannot Be Updated", func(t *testing.T) {
+		repo, _, _, archived := setup(t)
+
+		archived.ArchivedAt = nil
+		archived.FirstNameEN = "Changed"
+		_, err := repo.Update(archived)
+		assert.ErrorIs(t, err, entities.ErrNotFound)
+	})
+}
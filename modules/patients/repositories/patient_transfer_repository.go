@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PatientTransferRepo struct {
+	Db *gorm.DB
+}
+
+func NewPatientTransferRepository(db *gorm.DB) entities.PatientTransferRepository {
+	return &PatientTransferRepo{Db: db}
+}
+
+// Create locks the patient row so two staff members cannot open competing
+// transfers for the same patient at once.
+func (r *PatientTransferRepo) Create(transfer *entities.PatientTransfer) (*entities.PatientTransfer, error) {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		var patient entities.Patient
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&patient, transfer.PatientID).Error; err != nil {
+			return err
+		}
+
+		var pending int64
+		if err := tx.Model(&entities.PatientTransfer{}).
+			Where("patient_id = ? AND status = ?", transfer.PatientID, consts.TransferStatusRequested).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return entities.ErrTransferPending
+		}
+
+		return tx.Omit(clause.Associations).Create(transfer).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (r *PatientTransferRepo) Update(transfer *entities.PatientTransfer) (*entities.PatientTransfer, error) {
+	if err := updatePatientTransfer(r.Db, transfer); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// Accept registers the copied patient, when there is one, and records the
+// decision in one transaction. A concurrent decision fails the version check
+// and rolls the copy back.
+func (r *PatientTransferRepo) Accept(transfer *entities.PatientTransfer, patient *entities.Patient) (*entities.PatientTransfer, error) {
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if patient != nil {
+			if err := setBlindIndexes(patient); err != nil {
+				return err
+			}
+			if err := tx.Omit("Hospital").Create(patient).Error; err != nil {
+				return err
+			}
+			transfer.DestinationPatientID = &patient.ID
+		}
+
+		return updatePatientTransfer(tx, transfer)
+	})
+	if err != nil {
+		if patient != nil {
+			transfer.DestinationPatientID = nil
+		}
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (r *PatientTransferRepo) FindById(id uint) (*entities.PatientTransfer, error) {
+	var transfer entities.PatientTransfer
+	if err := r.Db.First(&transfer, id).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *PatientTransferRepo) FindAll(filter entities.PatientTransferFilter, page int, limit int) ([]entities.PatientTransfer, int, error) {
+	var transfers []entities.PatientTransfer
+	var totalCount int64
+
+	query := r.Db.Model(&entities.PatientTransfer{})
+	switch filter.Direction {
+	case "incoming":
+		query = query.Where("destination_hospital_id = ?", filter.HospitalID)
+	case "outgoing":
+		query = query.Where("source_hospital_id = ?", filter.HospitalID)
+	default:
+		query = query.Where("source_hospital_id = ? OR destination_hospital_id = ?", filter.HospitalID, filter.HospitalID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("requested_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&transfers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return transfers, int(totalCount), nil
+}
+
+// FindDestinationMatches returns the destination's patients that share the
+// given patient's national ID, passport or HN.
+func (r *PatientTransferRepo) FindDestinationMatches(hospitalId uint, patient *entities.Patient) ([]entities.Patient, error) {
+	var patients []entities.Patient
+
+	match := r.Db.Where("1 = 0")
+	if patient.NationalIDIdx != "" {
+		match = match.Or("national_id_index = ?", patient.NationalIDIdx)
+	}
+	if patient.PassportIDIdx != "" {
+		match = match.Or("passport_id_index = ?", patient.PassportIDIdx)
+	}
+	if patient.PatientHN != "" {
+		match = match.Or("patient_hn = ?", patient.PatientHN)
+	}
+
+	if err := r.Db.Where("hospital_id = ?", hospitalId).Where(match).Find(&patients).Error; err != nil {
+		return nil, err
+	}
+	return patients, nil
+}
+
+func updatePatientTransfer(db *gorm.DB, transfer *entities.PatientTransfer) error {
+	version := transfer.Version
+	transfer.Version = version + 1
+
+	result := db.Model(transfer).
+		Where("version = ?", version).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Updates(transfer)
+	if result.Error != nil {
+		transfer.Version = version
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		transfer.Version = version
+		return entities.ErrVersionConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/storages"
+	"github.com/gabriel-vasile/mimetype"
+	"gorm.io/gorm"
+)
+
+const DefaultMaxAttachmentSize int64 = 10 << 20
+
+// allowedAttachmentTypes are the sniffed content types accepted for upload.
+// The type declared by the client is ignored.
+var allowedAttachmentTypes = []string{
+	"application/pdf",
+	"image/jpeg",
+	"image/png",
+	"image/tiff",
+	"image/webp",
+	"image/heic",
+}
+
+type PatientAttachmentUseCase struct {
+	repo        entities.PatientAttachmentRepository
+	patientRepo entities.PatientRepository
+	storage     storages.Storage
+	maxSize     int64
+}
+
+func NewPatientAttachmentUseCase(repo entities.PatientAttachmentRepository, patientRepo entities.PatientRepository, storage storages.Storage, maxSize int64) entities.PatientAttachmentUseCase {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxAttachmentSize
+	}
+	return &PatientAttachmentUseCase{repo: repo, patientRepo: patientRepo, storage: storage, maxSize: maxSize}
+}
+
+// checkPatient answers not found for a patient that does not exist or
+// belongs to another hospital, and passes any other lookup error through.
+func (u *PatientAttachmentUseCase) checkPatient(patientId uint, staffHospitalId uint) error {
+	patient, err := u.patientRepo.FindById(patientId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil || patient == nil || patient.HospitalID != staffHospitalId {
+		return entities.NotFound("patient")
+	}
+	return nil
+}
+
+func (u *PatientAttachmentUseCase) Upload(upload *entities.PatientAttachmentUpload, staffHospitalId uint, staffId uint) (*entities.PatientAttachment, error) {
+	if !upload.Category.IsValid() {
+		return nil, entities.Invalid("category must be id_card, consent_form, referral_letter or other")
+	}
+
+	if err := u.checkPatient(upload.PatientID, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(upload.Body, u.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, entities.Invalid("file is empty")
+	}
+
+	if int64(len(data)) > u.maxSize {
+		return nil, entities.Invalid(fmt.Sprintf("file exceeds the %d MB limit", u.maxSize>>20))
+	}
+
+	detected := mimetype.Detect(data)
+	if !mimetype.EqualsAny(detected.String(), allowedAttachmentTypes...) {
+		return nil, entities.Invalid(fmt.Sprintf("file type %s is not allowed", detected.String()))
+	}
+
+	checksum := sha256.Sum256(data)
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("patients/%d/%s%s", upload.PatientID, hex.EncodeToString(suffix), detected.Extension())
+
+	if err := u.storage.Put(context.Background(), key, bytes.NewReader(data), int64(len(data)), detected.String()); err != nil {
+		return nil, err
+	}
+
+	attachment, err := u.repo.Create(&entities.PatientAttachment{
+		PatientID:    upload.PatientID,
+		HospitalID:   staffHospitalId,
+		Category:     upload.Category,
+		FileName:     attachmentFileName(upload.FileName, detected.Extension()),
+		ContentType:  detected.String(),
+		Size:         int64(len(data)),
+		Checksum:     hex.EncodeToString(checksum[:]),
+		StorageKey:   key,
+		UploadedByID: staffId,
+	})
+	if err != nil {
+		if delErr := u.storage.Delete(context.Background(), key); delErr != nil {
+			log.Printf("failed to remove orphaned attachment %s: %v", key, delErr)
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (u *PatientAttachmentUseCase) Download(patientId uint, attachmentId uint, staffHospitalId uint) (*entities.PatientAttachment, io.ReadCloser, error) {
+	attachment, err := u.findAttachment(patientId, attachmentId, staffHospitalId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := u.storage.Get(context.Background(), attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, body, nil
+}
+
+func (u *PatientAttachmentUseCase) Delete(patientId uint, attachmentId uint, staffHospitalId uint) error {
+	attachment, err := u.findAttachment(patientId, attachmentId, staffHospitalId)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.Delete(attachment.ID); err != nil {
+		return err
+	}
+
+	if err := u.storage.Delete(context.Background(), attachment.StorageKey); err != nil {
+		log.Printf("failed to remove attachment object %s: %v", attachment.StorageKey, err)
+	}
+
+	return nil
+}
+
+func (u *PatientAttachmentUseCase) FindAll(patientId uint, staffHospitalId uint) ([]entities.PatientAttachment, error) {
+	if err := u.checkPatient(patientId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindByPatientId(patientId)
+}
+
+func (u *PatientAttachmentUseCase) findAttachment(patientId uint, attachmentId uint, staffHospitalId uint) (*entities.PatientAttachment, error) {
+	if err := u.checkPatient(patientId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	attachment, err := u.repo.FindById(attachmentId)
+	if err != nil || attachment == nil || attachment.PatientID != patientId {
+		return nil, entities.NotFound("attachment")
+	}
+
+	return attachment, nil
+}
+
+func attachmentFileName(name string, ext string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment" + ext
+	}
+	return name
+}
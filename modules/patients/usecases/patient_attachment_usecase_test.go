@@ -0,0 +1,164 @@
+package usecases_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/storages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var pdfContent = []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\n%%EOF\n")
+
+func newAttachmentUseCase(t *testing.T, maxSize int64) (entities.PatientAttachmentUseCase, *mocks.MockPatientAttachmentRepository, *mocks.MockPatientRepository, storages.Storage) {
+	mockRepo := mocks.NewMockPatientAttachmentRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	storage := storages.NewLocalStorage(t.TempDir())
+	return usecases.NewPatientAttachmentUseCase(mockRepo, mockPatientRepo, storage, maxSize), mockRepo, mockPatientRepo, storage
+}
+
+func TestUploadAttachment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, storage := newAttachmentUseCase(t, 0)
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockRepo.On("Create", mock.Anything).Return(&entities.PatientAttachment{ID: 1}, nil)
+
+		_, err := usecase.Upload(&entities.PatientAttachmentUpload{
+			PatientID: 1,
+			Category:  consts.AttachmentCategoryConsentForm,
+			FileName:  `C:\scans\consent.pdf`,
+			Body:      bytes.NewReader(pdfContent),
+		}, 1, 9)
+		assert.NoError(t, err)
+
+		created := mockRepo.Calls[0].Arguments.Get(0).(*entities.PatientAttachment)
+		sum := sha256.Sum256(pdfContent)
+		assert.Equal(t, "application/pdf", created.ContentType)
+		assert.Equal(t, hex.EncodeToString(sum[:]), created.Checksum)
+		assert.Equal(t, int64(len(pdfContent)), created.Size)
+		assert.Equal(t, "consent.pdf", created.FileName)
+		assert.Equal(t, uint(9), created.UploadedByID)
+
+		stored, err := storage.Get(context.Background(), created.StorageKey)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(stored)
+		stored.Close()
+		assert.Equal(t, pdfContent, data)
+	})
+
+	t.Run("Disallowed Type", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newAttachmentUseCase(t, 0)
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+
+		_, err := usecase.Upload(&entities.PatientAttachmentUpload{
+			PatientID: 1,
+			Category:  consts.AttachmentCategoryOther,
+			FileName:  "scan.pdf",
+			Body:      bytes.NewReader([]byte("#!/bin/sh\nrm -rf /\n")),
+		}, 1, 9)
+		assert.ErrorContains(t, err, "is not allowed")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Too Large", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newAttachmentUseCase(t, 16)
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+
+		_, err := usecase.Upload(&entities.PatientAttachmentUpload{
+			PatientID: 1,
+			Category:  consts.AttachmentCategoryOther,
+			Body:      bytes.NewReader(pdfContent),
+		}, 1, 9)
+		assert.ErrorContains(t, err, "exceeds")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Other Hospital", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newAttachmentUseCase(t, 0)
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 2}, nil)
+
+		_, err := usecase.Upload(&entities.PatientAttachmentUpload{
+			PatientID: 1,
+			Category:  consts.AttachmentCategoryIDCard,
+			Body:      bytes.NewReader(pdfContent),
+		}, 1, 9)
+		assert.EqualError(t, err, "patient not found")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Missing Patient", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newAttachmentUseCase(t, 0)
+		mockPatientRepo.On("FindById", uint(1)).Return((*entities.Patient)(nil), gorm.ErrRecordNotFound)
+
+		_, err := usecase.Upload(&entities.PatientAttachmentUpload{
+			PatientID: 1,
+			Category:  consts.AttachmentCategoryIDCard,
+			Body:      bytes.NewReader(pdfContent),
+		}, 1, 9)
+		assert.ErrorIs(t, err, entities.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Patient Lookup Failure", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newAttachmentUseCase(t, 0)
+		outage := errors.New("connection refused")
+		mockPatientRepo.On("FindById", uint(1)).Return((*entities.Patient)(nil), outage)
+
+		_, err := usecase.Upload(&entities.PatientAttachmentUpload{
+			PatientID: 1,
+			Category:  consts.AttachmentCategoryIDCard,
+			Body:      bytes.NewReader(pdfContent),
+		}, 1, 9)
+		assert.ErrorIs(t, err, outage)
+		assert.NotErrorIs(t, err, entities.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Database Failure Removes Object", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, storage := newAttachmentUseCase(t, 0)
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockRepo.On("Create", mock.Anything).Return((*entities.PatientAttachment)(nil), errors.New("db down"))
+
+		_, err := usecase.Upload(&entities.PatientAttachmentUpload{
+			PatientID: 1,
+			Category:  consts.AttachmentCategoryIDCard,
+			Body:      bytes.NewReader(pdfContent),
+		}, 1, 9)
+		assert.EqualError(t, err, "db down")
+
+		key := mockRepo.Calls[0].Arguments.Get(0).(*entities.PatientAttachment).StorageKey
+		_, err = storage.Get(context.Background(), key)
+		assert.ErrorIs(t, err, storages.ErrObjectNotFound)
+	})
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	t.Run("Other Hospital", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newAttachmentUseCase(t, 0)
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 2}, nil)
+
+		_, _, err := usecase.Download(1, 5, 1)
+		assert.EqualError(t, err, "patient not found")
+		mockRepo.AssertNotCalled(t, "FindById", mock.Anything)
+	})
+
+	t.Run("Attachment Of Another Patient", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newAttachmentUseCase(t, 0)
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockRepo.On("FindById", uint(5)).Return(&entities.PatientAttachment{ID: 5, PatientID: 2}, nil)
+
+		_, _, err := usecase.Download(1, 5, 1)
+		assert.EqualError(t, err, "attachment not found")
+	})
+}
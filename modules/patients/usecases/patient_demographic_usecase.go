@@ -0,0 +1,127 @@
+package usecases
+
+import "github.com/Teemo4621/Hospital-Api/modules/entities"
+
+type PatientDemographicUseCase struct {
+	repo        entities.PatientDemographicRepository
+	patientRepo entities.PatientRepository
+}
+
+func NewPatientDemographicUseCase(repo entities.PatientDemographicRepository, patientRepo entities.PatientRepository) entities.PatientDemographicUseCase {
+	return &PatientDemographicUseCase{repo: repo, patientRepo: patientRepo}
+}
+
+func (u *PatientDemographicUseCase) checkPatient(patientId uint, staffHospitalId uint) error {
+	patient, err := u.patientRepo.FindById(patientId)
+	if err != nil || patient == nil {
+		return entities.NotFound("patient")
+	}
+
+	if patient.HospitalID != staffHospitalId {
+		return entities.NotFound("patient")
+	}
+
+	return nil
+}
+
+func (u *PatientDemographicUseCase) CreateAddress(address *entities.PatientAddress, staffHospitalId uint) (*entities.PatientAddress, error) {
+	if err := u.checkPatient(address.PatientID, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	if !address.Type.IsValid() {
+		return nil, entities.Invalid("address type must be home, work or registered")
+	}
+
+	return u.repo.CreateAddress(address)
+}
+
+func (u *PatientDemographicUseCase) UpdateAddress(address *entities.PatientAddress, staffHospitalId uint) (*entities.PatientAddress, error) {
+	if err := u.checkPatient(address.PatientID, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	if !address.Type.IsValid() {
+		return nil, entities.Invalid("address type must be home, work or registered")
+	}
+
+	exist, err := u.repo.FindAddressById(address.ID)
+	if err != nil || exist == nil || exist.PatientID != address.PatientID {
+		return nil, entities.NotFound("address")
+	}
+	address.CreatedAt = exist.CreatedAt
+
+	return u.repo.UpdateAddress(address)
+}
+
+func (u *PatientDemographicUseCase) DeleteAddress(patientId uint, addressId uint, staffHospitalId uint) error {
+	if err := u.checkPatient(patientId, staffHospitalId); err != nil {
+		return err
+	}
+
+	exist, err := u.repo.FindAddressById(addressId)
+	if err != nil || exist == nil || exist.PatientID != patientId {
+		return entities.NotFound("address")
+	}
+
+	return u.repo.DeleteAddress(addressId)
+}
+
+func (u *PatientDemographicUseCase) FindAddresses(patientId uint, staffHospitalId uint) ([]entities.PatientAddress, error) {
+	if err := u.checkPatient(patientId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindAddressesByPatientId(patientId)
+}
+
+func (u *PatientDemographicUseCase) CreateEmergencyContact(contact *entities.PatientEmergencyContact, staffHospitalId uint) (*entities.PatientEmergencyContact, error) {
+	if err := u.checkPatient(contact.PatientID, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	if !contact.Relationship.IsValid() {
+		return nil, entities.Invalid("relationship is not valid")
+	}
+
+	return u.repo.CreateEmergencyContact(contact)
+}
+
+func (u *PatientDemographicUseCase) UpdateEmergencyContact(contact *entities.PatientEmergencyContact, staffHospitalId uint) (*entities.PatientEmergencyContact, error) {
+	if err := u.checkPatient(contact.PatientID, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	if !contact.Relationship.IsValid() {
+		return nil, entities.Invalid("relationship is not valid")
+	}
+
+	exist, err := u.repo.FindEmergencyContactById(contact.ID)
+	if err != nil || exist == nil || exist.PatientID != contact.PatientID {
+		return nil, entities.NotFound("emergency contact")
+	}
+	contact.CreatedAt = exist.CreatedAt
+
+	return u.repo.UpdateEmergencyContact(contact)
+}
+
+func (u *PatientDemographicUseCase) DeleteEmergencyContact(patientId uint, contactId uint, staffHospitalId uint) error {
+	if err := u.checkPatient(patientId, staffHospitalId); err != nil {
+		return err
+	}
+
+	exist, err := u.repo.FindEmergencyContactById(contactId)
+	if err != nil || exist == nil || exist.PatientID != patientId {
+		return entities.NotFound("emergency contact")
+	}
+
+	return u.repo.DeleteEmergencyContact(contactId)
+}
+
+func (u *PatientDemographicUseCase) FindEmergencyContacts(patientId uint, staffHospitalId uint) ([]entities.PatientEmergencyContact, error) {
+	if err := u.checkPatient(patientId, staffHospitalId); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindEmergencyContactsByPatientId(patientId)
+}
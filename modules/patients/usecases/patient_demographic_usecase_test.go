@@ -0,0 +1,105 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAddress(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientDemographicRepository()
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientDemographicUseCase(mockRepo, mockPatientRepo)
+		address := &entities.PatientAddress{PatientID: 1, Type: consts.AddressTypeHome, Province: "Bangkok"}
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockRepo.On("CreateAddress", address).Return(address, nil)
+
+		result, err := usecase.CreateAddress(address, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "Bangkok", result.Province)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other Hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientDemographicRepository()
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientDemographicUseCase(mockRepo, mockPatientRepo)
+		address := &entities.PatientAddress{PatientID: 1, Type: consts.AddressTypeHome}
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 2}, nil)
+
+		_, err := usecase.CreateAddress(address, 1)
+		assert.EqualError(t, err, "patient not found")
+		mockRepo.AssertNotCalled(t, "CreateAddress", mock.Anything)
+	})
+
+	t.Run("Invalid Type", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientDemographicRepository()
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientDemographicUseCase(mockRepo, mockPatientRepo)
+		address := &entities.PatientAddress{PatientID: 1, Type: "holiday"}
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+
+		_, err := usecase.CreateAddress(address, 1)
+		assert.EqualError(t, err, "address type must be home, work or registered")
+	})
+}
+
+func TestUpdateAddress(t *testing.T) {
+	t.Run("Address Of Another Patient", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientDemographicRepository()
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientDemographicUseCase(mockRepo, mockPatientRepo)
+		address := &entities.PatientAddress{ID: 5, PatientID: 1, Type: consts.AddressTypeWork}
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockRepo.On("FindAddressById", uint(5)).Return(&entities.PatientAddress{ID: 5, PatientID: 2}, nil)
+
+		_, err := usecase.UpdateAddress(address, 1)
+		assert.EqualError(t, err, "address not found")
+		mockRepo.AssertNotCalled(t, "UpdateAddress", mock.Anything)
+	})
+}
+
+func TestDeleteEmergencyContact(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientDemographicRepository()
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientDemographicUseCase(mockRepo, mockPatientRepo)
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+		mockRepo.On("FindEmergencyContactById", uint(3)).Return(&entities.PatientEmergencyContact{ID: 3, PatientID: 1}, nil)
+		mockRepo.On("DeleteEmergencyContact", uint(3)).Return(nil)
+
+		err := usecase.DeleteEmergencyContact(1, 3, 1)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Patient Not Found", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientDemographicRepository()
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientDemographicUseCase(mockRepo, mockPatientRepo)
+		mockPatientRepo.On("FindById", uint(1)).Return((*entities.Patient)(nil), errors.New("record not found"))
+
+		err := usecase.DeleteEmergencyContact(1, 3, 1)
+		assert.EqualError(t, err, "patient not found")
+	})
+}
+
+func TestCreateEmergencyContact(t *testing.T) {
+	t.Run("Invalid Relationship", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientDemographicRepository()
+		mockPatientRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientDemographicUseCase(mockRepo, mockPatientRepo)
+		contact := &entities.PatientEmergencyContact{PatientID: 1, FullName: "Somchai", Relationship: "boss", PhoneNumber: "0812345678"}
+		mockPatientRepo.On("FindById", uint(1)).Return(&entities.Patient{ID: 1, HospitalID: 1}, nil)
+
+		_, err := usecase.CreateEmergencyContact(contact, 1)
+		assert.EqualError(t, err, "relationship is not valid")
+	})
+}
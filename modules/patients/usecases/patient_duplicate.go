@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+)
+
+// duplicateKeys lists what makes a new patient a duplicate of one already at
+// the hospital, as "field:value": the national ID, passport ID and HN, with
+// the same normalization as the blind indexes, and the Thai first and last
+// name. PatientUseCase.Create and the import both refuse a patient that
+// shares any of them, so a row the import accepts could also be created one
+// at a time, and the other way round.
+func duplicateKeys(patient *entities.Patient) []string {
+	var keys []string
+	if value := normalizeIdentifier(patient.NationalID); value != "" {
+		keys = append(keys, "national_id:"+value)
+	}
+	if value := normalizeIdentifier(patient.PassportID); value != "" {
+		keys = append(keys, "passport_id:"+value)
+	}
+	if value := strings.TrimSpace(patient.PatientHN); value != "" {
+		keys = append(keys, "patient_hn:"+value)
+	}
+	first, last := strings.TrimSpace(patient.FirstNameTH), strings.TrimSpace(patient.LastNameTH)
+	if first != "" && last != "" {
+		keys = append(keys, "name:"+first+" "+last)
+	}
+	return keys
+}
+
+// knownDuplicates maps the duplicate keys of stored patients to their ids.
+func knownDuplicates(existing []entities.Patient) map[string]uint {
+	known := map[string]uint{}
+	for _, patient := range existing {
+		for _, key := range duplicateKeys(&patient) {
+			known[key] = patient.ID
+		}
+	}
+	return known
+}
+
+// findDuplicate returns the first key that is already stored or was used by
+// an earlier row, with a message for the report.
+func findDuplicate(keys []string, known map[string]uint, seen map[string]int) (string, string) {
+	for _, key := range keys {
+		field := strings.SplitN(key, ":", 2)[0]
+		if id, ok := known[key]; ok {
+			return field, fmt.Sprintf("%s matches existing patient %d", field, id)
+		}
+		if line, ok := seen[key]; ok {
+			return field, fmt.Sprintf("%s repeats row %d", field, line)
+		}
+	}
+	return "", ""
+}
+
+func normalizeIdentifier(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if unicode.IsSpace(r) || r == '-' {
+			continue
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
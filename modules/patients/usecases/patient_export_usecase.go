@@ -0,0 +1,381 @@
+package usecases
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/masking"
+	"github.com/Teemo4621/Hospital-Api/pkgs/storages"
+	"github.com/Teemo4621/Hospital-Api/pkgs/tabular"
+)
+
+const (
+	// MaxStreamExportRows caps exports served directly in the response.
+	// Anything larger has to go through a background job.
+	MaxStreamExportRows = 10000
+	DefaultExportTTL    = 24 * time.Hour
+
+	exportProgressInterval = 1000
+)
+
+// exportColumns are the CSV and XLSX columns. Names match the import fields
+// so an export can be imported again. Columns tied to a masking field are
+// left out entirely when the viewer may not see that field.
+var exportColumns = []struct {
+	name  string
+	field masking.Field
+	value func(patient *entities.Patient) string
+}{
+	{"id", "", func(p *entities.Patient) string { return strconv.FormatUint(uint64(p.ID), 10) }},
+	{"patient_hn", "", func(p *entities.Patient) string { return p.PatientHN }},
+	{"first_name_th", "", func(p *entities.Patient) string { return p.FirstNameTH }},
+	{"middle_name_th", "", func(p *entities.Patient) string { return p.MiddleNameTH }},
+	{"last_name_th", "", func(p *entities.Patient) string { return p.LastNameTH }},
+	{"first_name_en", "", func(p *entities.Patient) string { return p.FirstNameEN }},
+	{"middle_name_en", "", func(p *entities.Patient) string { return p.MiddleNameEN }},
+	{"last_name_en", "", func(p *entities.Patient) string { return p.LastNameEN }},
+	{"date_of_birth", "", func(p *entities.Patient) string {
+		if p.DateOfBirth == nil {
+			return ""
+		}
+		return p.DateOfBirth.String()
+	}},
+	{"national_id", masking.FieldNationalID, func(p *entities.Patient) string { return p.NationalID }},
+	{"passport_id", masking.FieldPassportID, func(p *entities.Patient) string { return p.PassportID }},
+	{"phone_number", masking.FieldPhoneNumber, func(p *entities.Patient) string { return p.PhoneNumber }},
+	{"email", masking.FieldEmail, func(p *entities.Patient) string { return p.Email }},
+	{"gender", "", func(p *entities.Patient) string { return string(p.Gender) }},
+	{"blood_group", "", func(p *entities.Patient) string { return string(p.BloodGroup) }},
+	{"rh_factor", "", func(p *entities.Patient) string { return string(p.RhFactor) }},
+	{"nationality", "", func(p *entities.Patient) string { return p.Nationality }},
+	{"religion", "", func(p *entities.Patient) string { return p.Religion }},
+	{"marital_status", "", func(p *entities.Patient) string { return string(p.MaritalStatus) }},
+	{"occupation", "", func(p *entities.Patient) string { return p.Occupation }},
+	{"created_at", "", func(p *entities.Patient) string { return p.CreatedAt.Format(time.RFC3339) }},
+	{"updated_at", "", func(p *entities.Patient) string { return p.UpdatedAt.Format(time.RFC3339) }},
+}
+
+type PatientExportUseCase struct {
+	repo        entities.PatientExportRepository
+	patientRepo entities.PatientRepository
+	storage     storages.Storage
+}
+
+func NewPatientExportUseCase(repo entities.PatientExportRepository, patientRepo entities.PatientRepository, storage storages.Storage) entities.PatientExportUseCase {
+	return &PatientExportUseCase{repo: repo, patientRepo: patientRepo, storage: storage}
+}
+
+// Stream writes a small export straight to w. Nothing is written when the
+// request is rejected, so the caller can still answer with an error.
+func (u *PatientExportUseCase) Stream(req *entities.PatientExportRequest, w io.Writer) error {
+	viewer, err := exportViewer(req.Format, req.Role, req.Reveal)
+	if err != nil {
+		return err
+	}
+
+	filter := req.Filter
+	filter.HospitalID = req.HospitalID
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	total, err := u.patientRepo.CountByAdvanceSearch(filter)
+	if err != nil {
+		return err
+	}
+	if total > MaxStreamExportRows {
+		return entities.Invalid(fmt.Sprintf("export matches %d patients; use a background export for more than %d", total, MaxStreamExportRows))
+	}
+
+	_, err = u.export(req.Format, viewer, filter, w, nil)
+	return err
+}
+
+func (u *PatientExportUseCase) Create(req *entities.PatientExportRequest) (*entities.PatientExportJob, error) {
+	if _, err := exportViewer(req.Format, req.Role, req.Reveal); err != nil {
+		return nil, err
+	}
+
+	filter := req.Filter
+	filter.HospitalID = req.HospitalID
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	total, err := u.patientRepo.CountByAdvanceSearch(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.repo.CreateJob(&entities.PatientExportJob{
+		HospitalID: req.HospitalID,
+		StaffID:    req.StaffID,
+		Format:     req.Format,
+		Filter:     filter,
+		Role:       req.Role,
+		Reveal:     req.Reveal,
+		Status:     consts.ExportStatusPending,
+		TotalRows:  total,
+	})
+}
+
+// Start runs the job in the background. The caller keeps its own copy of
+// the job, so it can still be serialized safely.
+func (u *PatientExportUseCase) Start(job *entities.PatientExportJob) {
+	background := *job
+	go func() {
+		if _, err := u.Run(&background); err != nil {
+			log.Printf("patient export %d failed: %v", background.ID, err)
+		}
+	}()
+}
+
+// Run writes the export to a temporary file and uploads it once complete,
+// so a failed run never leaves a partial file behind for download.
+func (u *PatientExportUseCase) Run(job *entities.PatientExportJob) (*entities.PatientExportJob, error) {
+	if job.Status == consts.ExportStatusCompleted {
+		return nil, entities.Conflict("export_completed", "export has already completed")
+	}
+
+	now := time.Now()
+	job.Status = consts.ExportStatusRunning
+	job.Error = ""
+	job.ExportedRows = 0
+	job.StartedAt = &now
+	if _, err := u.repo.UpdateJob(job); err != nil {
+		return nil, err
+	}
+
+	if err := u.process(job); err != nil {
+		job.Status = consts.ExportStatusFailed
+		job.Error = err.Error()
+		if _, updateErr := u.repo.UpdateJob(job); updateErr != nil {
+			log.Printf("failed to mark patient export %d as failed: %v", job.ID, updateErr)
+		}
+		return job, err
+	}
+
+	finished := time.Now()
+	expires := finished.Add(DefaultExportTTL)
+	job.Status = consts.ExportStatusCompleted
+	job.FinishedAt = &finished
+	job.ExpiresAt = &expires
+	if _, err := u.repo.UpdateJob(job); err != nil {
+		return job, err
+	}
+
+	setExportProgress(job)
+	return job, nil
+}
+
+// FindById only returns jobs requested by the same staff member, because
+// the file was masked for their role.
+func (u *PatientExportUseCase) FindById(id uint, staffHospitalId uint, staffId uint) (*entities.PatientExportJob, error) {
+	job, err := u.repo.FindJobById(id)
+	if err != nil || job == nil || job.HospitalID != staffHospitalId || job.StaffID != staffId {
+		return nil, entities.NotFound("export")
+	}
+
+	setExportProgress(job)
+	return job, nil
+}
+
+// Open returns the finished export file. The caller must close it.
+func (u *PatientExportUseCase) Open(id uint, staffHospitalId uint, staffId uint) (*entities.PatientExportJob, io.ReadCloser, error) {
+	job, err := u.FindById(id, staffHospitalId, staffId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Status != consts.ExportStatusCompleted {
+		return nil, nil, entities.Conflict("export_not_ready", "export is not ready")
+	}
+	if job.ExpiresAt != nil && time.Now().After(*job.ExpiresAt) {
+		return nil, nil, &entities.DomainError{Kind: entities.ErrNotFound, Code: "export_expired", Message: "export has expired"}
+	}
+
+	body, err := u.storage.Get(context.Background(), job.ObjectKey)
+	if err != nil {
+		if errors.Is(err, storages.ErrObjectNotFound) {
+			return nil, nil, entities.NotFound("export file")
+		}
+		return nil, nil, err
+	}
+
+	return job, body, nil
+}
+
+func (u *PatientExportUseCase) process(job *entities.PatientExportJob) error {
+	viewer, err := exportViewer(job.Format, job.Role, job.Reveal)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "patient-export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	count, err := u.export(job.Format, viewer, job.Filter, tmp, func(count int) error {
+		job.ExportedRows = count
+		_, err := u.repo.UpdateJob(job)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	key := fmt.Sprintf("exports/%d/%s.%s", job.HospitalID, hex.EncodeToString(suffix), job.Format)
+
+	if err := u.storage.Put(context.Background(), key, tmp, size, job.Format.ContentType()); err != nil {
+		return err
+	}
+
+	job.ObjectKey = key
+	job.Size = size
+	job.ExportedRows = count
+	return nil
+}
+
+// export streams every matching patient through the viewer into w and
+// reports progress every exportProgressInterval rows.
+func (u *PatientExportUseCase) export(format consts.ExportFormat, viewer masking.Viewer, filter entities.PatientSearchInput, w io.Writer, progress func(count int) error) (int, error) {
+	encoder, err := newExportEncoder(format, viewer, w)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = u.patientRepo.StreamByAdvanceSearch(filter, func(patient *entities.Patient) error {
+		if err := encoder.encode(viewer.Patient(*patient)); err != nil {
+			return err
+		}
+
+		count++
+		if progress != nil && count%exportProgressInterval == 0 {
+			return progress(count)
+		}
+		return nil
+	})
+	if err != nil {
+		encoder.close()
+		return count, err
+	}
+
+	return count, encoder.close()
+}
+
+// exportViewer rebuilds the requester's masking view. Scopes come from the
+// role so background jobs apply the same rules as the request did.
+func exportViewer(format consts.ExportFormat, role string, reveal []string) (masking.Viewer, error) {
+	if !format.IsValid() {
+		return masking.Viewer{}, entities.Invalid("format must be csv, ndjson or xlsx")
+	}
+
+	viewer := masking.NewViewer(&entities.JwtClaim{Role: role, Scopes: consts.StaffRole(role).Scopes()})
+
+	fields, err := masking.ParseReveal(reveal)
+	if err != nil {
+		return viewer, err
+	}
+	return viewer.WithReveal(fields)
+}
+
+type exportEncoder interface {
+	encode(patient entities.Patient) error
+	close() error
+}
+
+func newExportEncoder(format consts.ExportFormat, viewer masking.Viewer, w io.Writer) (exportEncoder, error) {
+	if format == consts.ExportFormatNDJSON {
+		buffer := bufio.NewWriter(w)
+		return &ndjsonEncoder{buffer: buffer, encoder: json.NewEncoder(buffer)}, nil
+	}
+
+	writer, err := tabular.NewWriter(tabular.Format(format), w)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := &tabularEncoder{writer: writer}
+	var header []string
+	for i, column := range exportColumns {
+		if column.field != "" && viewer.Visibility(column.field) == masking.Hidden {
+			continue
+		}
+		encoder.columns = append(encoder.columns, i)
+		header = append(header, column.name)
+	}
+
+	if err := writer.Write(header); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	return encoder, nil
+}
+
+type tabularEncoder struct {
+	writer  tabular.Writer
+	columns []int
+}
+
+func (e *tabularEncoder) encode(patient entities.Patient) error {
+	record := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		record[i] = exportColumns[column].value(&patient)
+	}
+	return e.writer.Write(record)
+}
+
+func (e *tabularEncoder) close() error {
+	return e.writer.Close()
+}
+
+type ndjsonEncoder struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) encode(patient entities.Patient) error {
+	return e.encoder.Encode(patient)
+}
+
+func (e *ndjsonEncoder) close() error {
+	return e.buffer.Flush()
+}
+
+func setExportProgress(job *entities.PatientExportJob) {
+	switch {
+	case job.Status == consts.ExportStatusCompleted:
+		job.Progress = 100
+	case job.TotalRows > 0:
+		job.Progress = math.Round(float64(job.ExportedRows)/float64(job.TotalRows)*10000) / 100
+	}
+}
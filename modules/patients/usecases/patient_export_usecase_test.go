@@ -0,0 +1,260 @@
+package usecases_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/masking"
+	"github.com/Teemo4621/Hospital-Api/pkgs/storages"
+	"github.com/Teemo4621/Hospital-Api/pkgs/tabular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newExportUseCase(t *testing.T) (entities.PatientExportUseCase, *mocks.MockPatientExportRepository, *mocks.MockPatientRepository) {
+	mockRepo := mocks.NewMockPatientExportRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	storage := storages.NewLocalStorage(t.TempDir())
+	return usecases.NewPatientExportUseCase(mockRepo, mockPatientRepo, storage), mockRepo, mockPatientRepo
+}
+
+func exportPatients() []entities.Patient {
+	dob := civil.Date{Year: 1990, Month: time.May, Day: 1}
+	return []entities.Patient{
+		{ID: 1, FirstNameTH: "สมชาย", LastNameTH: "ใจดี", DateOfBirth: &dob, NationalID: "1234567890123", PhoneNumber: "081-234-5678", Email: "somchai@example.com", HospitalID: 1},
+		{ID: 2, FirstNameTH: "สมหญิง", LastNameTH: "ใจงาม", DateOfBirth: &dob, PassportID: "AB1234567", HospitalID: 1},
+	}
+}
+
+func readExportCSV(t *testing.T, body []byte) [][]string {
+	reader, err := tabular.NewReader(tabular.FormatCSV, bytes.NewReader(body))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	var rows [][]string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func column(header []string, name string) int {
+	for i, value := range header {
+		if value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestStreamPatientExport(t *testing.T) {
+	filter := entities.PatientSearchInput{HospitalID: 1, FirstName: "สม"}
+
+	t.Run("CSV Is Masked For Clerk", func(t *testing.T) {
+		usecase, _, mockPatientRepo := newExportUseCase(t)
+		mockPatientRepo.On("CountByAdvanceSearch", filter).Return(2, nil)
+		mockPatientRepo.On("StreamByAdvanceSearch", filter, mock.Anything).Return(exportPatients(), nil)
+
+		var out bytes.Buffer
+		err := usecase.Stream(&entities.PatientExportRequest{
+			HospitalID: 1,
+			Format:     consts.ExportFormatCSV,
+			Filter:     entities.PatientSearchInput{HospitalID: 99, FirstName: "สม"},
+			Role:       string(consts.StaffRoleClerk),
+		}, &out)
+		require.NoError(t, err)
+
+		rows := readExportCSV(t, out.Bytes())
+		require.Len(t, rows, 3)
+		header := rows[0]
+		assert.Equal(t, "สมชาย", rows[1][column(header, "first_name_th")])
+		assert.Equal(t, "1990-05-01", rows[1][column(header, "date_of_birth")])
+		assert.Equal(t, masking.NationalID("1234567890123"), rows[1][column(header, "national_id")])
+		assert.Equal(t, masking.Email("somchai@example.com"), rows[1][column(header, "email")])
+	})
+
+	t.Run("Hidden Fields Drop Columns", func(t *testing.T) {
+		usecase, _, mockPatientRepo := newExportUseCase(t)
+		mockPatientRepo.On("CountByAdvanceSearch", mock.Anything).Return(2, nil)
+		mockPatientRepo.On("StreamByAdvanceSearch", mock.Anything, mock.Anything).Return(exportPatients(), nil)
+
+		var out bytes.Buffer
+		err := usecase.Stream(&entities.PatientExportRequest{
+			HospitalID: 1,
+			Format:     consts.ExportFormatCSV,
+			Role:       string(consts.StaffRoleAuditor),
+		}, &out)
+		require.NoError(t, err)
+
+		header := readExportCSV(t, out.Bytes())[0]
+		assert.Equal(t, -1, column(header, "national_id"))
+		assert.Equal(t, -1, column(header, "phone_number"))
+		assert.NotEqual(t, -1, column(header, "patient_hn"))
+	})
+
+	t.Run("NDJSON With Reveal", func(t *testing.T) {
+		usecase, _, mockPatientRepo := newExportUseCase(t)
+		mockPatientRepo.On("CountByAdvanceSearch", mock.Anything).Return(2, nil)
+		mockPatientRepo.On("StreamByAdvanceSearch", mock.Anything, mock.Anything).Return(exportPatients(), nil)
+
+		var out bytes.Buffer
+		err := usecase.Stream(&entities.PatientExportRequest{
+			HospitalID: 1,
+			Format:     consts.ExportFormatNDJSON,
+			Role:       string(consts.StaffRoleNurse),
+			Reveal:     []string{"national_id"},
+		}, &out)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 2)
+		var first entities.Patient
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		assert.Equal(t, "1234567890123", first.NationalID)
+		assert.Equal(t, "081-234-5678", first.PhoneNumber)
+	})
+
+	t.Run("Too Many Rows", func(t *testing.T) {
+		usecase, _, mockPatientRepo := newExportUseCase(t)
+		mockPatientRepo.On("CountByAdvanceSearch", mock.Anything).Return(usecases.MaxStreamExportRows+1, nil)
+
+		var out bytes.Buffer
+		err := usecase.Stream(&entities.PatientExportRequest{HospitalID: 1, Format: consts.ExportFormatCSV}, &out)
+		assert.ErrorContains(t, err, "use a background export")
+		assert.Zero(t, out.Len())
+		mockPatientRepo.AssertNotCalled(t, "StreamByAdvanceSearch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Format", func(t *testing.T) {
+		usecase, _, _ := newExportUseCase(t)
+
+		err := usecase.Stream(&entities.PatientExportRequest{HospitalID: 1, Format: "pdf"}, &bytes.Buffer{})
+		assert.EqualError(t, err, "format must be csv, ndjson or xlsx")
+	})
+}
+
+func TestCreatePatientExport(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo := newExportUseCase(t)
+		mockPatientRepo.On("CountByAdvanceSearch", entities.PatientSearchInput{HospitalID: 1}).Return(42, nil)
+		mockRepo.On("CreateJob", mock.Anything).Return(&entities.PatientExportJob{ID: 1}, nil)
+
+		_, err := usecase.Create(&entities.PatientExportRequest{
+			HospitalID: 1,
+			StaffID:    9,
+			Format:     consts.ExportFormatXLSX,
+			Role:       string(consts.StaffRoleDoctor),
+		})
+		require.NoError(t, err)
+
+		created := mockRepo.Calls[0].Arguments.Get(0).(*entities.PatientExportJob)
+		assert.Equal(t, consts.ExportStatusPending, created.Status)
+		assert.Equal(t, 42, created.TotalRows)
+		assert.Equal(t, uint(1), created.Filter.HospitalID)
+	})
+
+	t.Run("Reveal Not Allowed", func(t *testing.T) {
+		usecase, mockRepo, _ := newExportUseCase(t)
+
+		_, err := usecase.Create(&entities.PatientExportRequest{
+			HospitalID: 1,
+			Format:     consts.ExportFormatCSV,
+			Role:       string(consts.StaffRoleSysAdmin),
+			Reveal:     []string{"national_id"},
+		})
+		assert.ErrorIs(t, err, masking.ErrRevealNotAllowed)
+		mockRepo.AssertNotCalled(t, "CreateJob", mock.Anything)
+	})
+}
+
+func TestRunPatientExport(t *testing.T) {
+	t.Run("Completed Export Can Be Downloaded", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo := newExportUseCase(t)
+		mockRepo.On("UpdateJob", mock.Anything).Return(&entities.PatientExportJob{}, nil)
+		mockPatientRepo.On("StreamByAdvanceSearch", mock.Anything, mock.Anything).Return(exportPatients(), nil)
+
+		job := &entities.PatientExportJob{
+			ID:         1,
+			HospitalID: 1,
+			StaffID:    9,
+			Format:     consts.ExportFormatCSV,
+			Filter:     entities.PatientSearchInput{HospitalID: 1},
+			Role:       string(consts.StaffRoleDoctor),
+			Status:     consts.ExportStatusPending,
+			TotalRows:  2,
+		}
+		finished, err := usecase.Run(job)
+		require.NoError(t, err)
+		assert.Equal(t, consts.ExportStatusCompleted, finished.Status)
+		assert.Equal(t, 2, finished.ExportedRows)
+		assert.Equal(t, float64(100), finished.Progress)
+		assert.NotNil(t, finished.ExpiresAt)
+		assert.NotZero(t, finished.Size)
+
+		mockRepo.On("FindJobById", uint(1)).Return(finished, nil)
+		_, body, err := usecase.Open(1, 1, 9)
+		require.NoError(t, err)
+		defer body.Close()
+
+		content, err := io.ReadAll(body)
+		require.NoError(t, err)
+		rows := readExportCSV(t, content)
+		require.Len(t, rows, 3)
+		assert.Equal(t, "1234567890123", rows[1][column(rows[0], "national_id")])
+	})
+
+	t.Run("Stream Failure Marks Job Failed", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo := newExportUseCase(t)
+		mockRepo.On("UpdateJob", mock.Anything).Return(&entities.PatientExportJob{}, nil)
+		mockPatientRepo.On("StreamByAdvanceSearch", mock.Anything, mock.Anything).Return([]entities.Patient{}, errors.New("connection reset"))
+
+		job := &entities.PatientExportJob{ID: 1, HospitalID: 1, Format: consts.ExportFormatNDJSON, Status: consts.ExportStatusPending}
+		failed, err := usecase.Run(job)
+		assert.EqualError(t, err, "connection reset")
+		assert.Equal(t, consts.ExportStatusFailed, failed.Status)
+		assert.Equal(t, "connection reset", failed.Error)
+		assert.Empty(t, failed.ObjectKey)
+	})
+}
+
+func TestOpenPatientExport(t *testing.T) {
+	t.Run("Other Staff", func(t *testing.T) {
+		usecase, mockRepo, _ := newExportUseCase(t)
+		mockRepo.On("FindJobById", uint(1)).Return(&entities.PatientExportJob{ID: 1, HospitalID: 1, StaffID: 9, Status: consts.ExportStatusCompleted}, nil)
+
+		_, _, err := usecase.Open(1, 1, 10)
+		assert.EqualError(t, err, "export not found")
+	})
+
+	t.Run("Not Ready", func(t *testing.T) {
+		usecase, mockRepo, _ := newExportUseCase(t)
+		mockRepo.On("FindJobById", uint(1)).Return(&entities.PatientExportJob{ID: 1, HospitalID: 1, StaffID: 9, Status: consts.ExportStatusRunning}, nil)
+
+		_, _, err := usecase.Open(1, 1, 9)
+		assert.EqualError(t, err, "export is not ready")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		usecase, mockRepo, _ := newExportUseCase(t)
+		expired := time.Now().Add(-time.Minute)
+		mockRepo.On("FindJobById", uint(1)).Return(&entities.PatientExportJob{ID: 1, HospitalID: 1, StaffID: 9, Status: consts.ExportStatusCompleted, ExpiresAt: &expired}, nil)
+
+		_, _, err := usecase.Open(1, 1, 9)
+		assert.EqualError(t, err, "export has expired")
+	})
+}
@@ -0,0 +1,588 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/storages"
+	"github.com/Teemo4621/Hospital-Api/pkgs/tabular"
+)
+
+const (
+	DefaultImportBatchSize       = 500
+	MaxImportBatchSize           = 5000
+	DefaultMaxImportSize   int64 = 64 << 20
+
+	importReportPageSize = 1000
+)
+
+// importDateLayouts are the date_of_birth formats accepted in import files.
+var importDateLayouts = []string{"2006-01-02", time.RFC3339, "02/01/2006", "2/1/2006"}
+
+// importFields lists the patient columns an import can fill, in the order
+// they are validated. Keys match the patient JSON names used in mappings.
+var importFields = []struct {
+	name string
+	set  func(patient *entities.Patient, value string) error
+}{
+	{"first_name_th", func(p *entities.Patient, v string) error { p.FirstNameTH = v; return nil }},
+	{"middle_name_th", func(p *entities.Patient, v string) error { p.MiddleNameTH = v; return nil }},
+	{"last_name_th", func(p *entities.Patient, v string) error { p.LastNameTH = v; return nil }},
+	{"first_name_en", func(p *entities.Patient, v string) error { p.FirstNameEN = v; return nil }},
+	{"middle_name_en", func(p *entities.Patient, v string) error { p.MiddleNameEN = v; return nil }},
+	{"last_name_en", func(p *entities.Patient, v string) error { p.LastNameEN = v; return nil }},
+	{"date_of_birth", func(p *entities.Patient, v string) error {
+		for _, layout := range importDateLayouts {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				date := civil.DateOf(parsed)
+				p.DateOfBirth = &date
+				return nil
+			}
+		}
+		return &entities.FieldError{Field: "date_of_birth", Message: "date_of_birth must be YYYY-MM-DD"}
+	}},
+	{"patient_hn", func(p *entities.Patient, v string) error { p.PatientHN = v; return nil }},
+	{"national_id", func(p *entities.Patient, v string) error { p.NationalID = v; return nil }},
+	{"passport_id", func(p *entities.Patient, v string) error { p.PassportID = v; return nil }},
+	{"phone_number", func(p *entities.Patient, v string) error { p.PhoneNumber = v; return nil }},
+	{"email", func(p *entities.Patient, v string) error { p.Email = v; return nil }},
+	{"gender", func(p *entities.Patient, v string) error { p.Gender = consts.ParseGender(v); return nil }},
+	{"blood_group", func(p *entities.Patient, v string) error {
+		p.BloodGroup = consts.BloodGroup(strings.ToUpper(v))
+		return nil
+	}},
+	{"rh_factor", func(p *entities.Patient, v string) error { p.RhFactor = consts.RhFactor(v); return nil }},
+	{"nationality", func(p *entities.Patient, v string) error { p.Nationality = v; return nil }},
+	{"religion", func(p *entities.Patient, v string) error { p.Religion = v; return nil }},
+	{"marital_status", func(p *entities.Patient, v string) error {
+		p.MaritalStatus = consts.MaritalStatus(strings.ToLower(v))
+		return nil
+	}},
+	{"occupation", func(p *entities.Patient, v string) error { p.Occupation = v; return nil }},
+}
+
+type PatientImportUseCase struct {
+	repo    entities.PatientImportRepository
+	storage storages.Storage
+
+	mu     sync.Mutex
+	active map[uint]bool
+}
+
+func NewPatientImportUseCase(repo entities.PatientImportRepository, storage storages.Storage) entities.PatientImportUseCase {
+	return &PatientImportUseCase{repo: repo, storage: storage, active: map[uint]bool{}}
+}
+
+func (u *PatientImportUseCase) Create(req *entities.PatientImportRequest) (*entities.PatientImportJob, error) {
+	format := tabular.Format(strings.ToLower(req.Format))
+	if format == "" {
+		detected, err := tabular.FormatFromFilename(req.FileName)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+	if !format.IsValid() {
+		return nil, entities.Invalid(tabular.ErrUnsupportedFormat.Error())
+	}
+
+	batchSize := req.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultImportBatchSize
+	}
+	if batchSize < 1 || batchSize > MaxImportBatchSize {
+		return nil, entities.Invalid(fmt.Sprintf("batch_size must be between 1 and %d", MaxImportBatchSize))
+	}
+
+	for field := range req.Mapping {
+		if !isImportField(field) {
+			return nil, entities.Invalid(fmt.Sprintf("mapping has unknown patient field %q", field))
+		}
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("imports/%d/%s.%s", req.HospitalID, hex.EncodeToString(suffix), format)
+
+	if err := u.storage.Put(context.Background(), key, req.Body, req.Size, "application/octet-stream"); err != nil {
+		return nil, err
+	}
+
+	job, err := u.repo.CreateJob(&entities.PatientImportJob{
+		HospitalID: req.HospitalID,
+		StaffID:    req.StaffID,
+		FileName:   req.FileName,
+		Format:     string(format),
+		SourceKey:  key,
+		Mapping:    req.Mapping,
+		DryRun:     req.DryRun,
+		BatchSize:  batchSize,
+		Status:     consts.ImportStatusPending,
+	})
+	if err != nil {
+		if delErr := u.storage.Delete(context.Background(), key); delErr != nil {
+			log.Printf("failed to remove orphaned import file %s: %v", key, delErr)
+		}
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Commit queues a real import of a file that has passed a dry run, reusing
+// the uploaded file and its column mapping.
+func (u *PatientImportUseCase) Commit(id uint, staffHospitalId uint, staffId uint) (*entities.PatientImportJob, error) {
+	dryRun, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dryRun.DryRun || dryRun.Status != consts.ImportStatusCompleted {
+		return nil, entities.Conflict("import_not_dry_run", "only a completed dry run can be committed")
+	}
+
+	return u.repo.CreateJob(&entities.PatientImportJob{
+		HospitalID: dryRun.HospitalID,
+		StaffID:    staffId,
+		FileName:   dryRun.FileName,
+		Format:     dryRun.Format,
+		SourceKey:  dryRun.SourceKey,
+		Mapping:    dryRun.Mapping,
+		BatchSize:  dryRun.BatchSize,
+		Status:     consts.ImportStatusPending,
+	})
+}
+
+// Resume checks that a job can pick up where it stopped. Jobs left running
+// by a process that has since exited count as failed.
+func (u *PatientImportUseCase) Resume(id uint, staffHospitalId uint) (*entities.PatientImportJob, error) {
+	job, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case job.Status == consts.ImportStatusCompleted:
+		return nil, entities.Conflict("import_completed", "import has already completed")
+	case job.Status == consts.ImportStatusRunning && u.isActive(job.ID):
+		return nil, entities.Conflict("import_running", "import is already running")
+	}
+
+	return job, nil
+}
+
+// Start runs the job in the background. The caller keeps its own copy of
+// the job, so it can still be serialized safely.
+func (u *PatientImportUseCase) Start(job *entities.PatientImportJob) {
+	background := *job
+	go func() {
+		if _, err := u.Run(&background, nil); err != nil {
+			log.Printf("patient import %d failed: %v", background.ID, err)
+		}
+	}()
+}
+
+func (u *PatientImportUseCase) Run(job *entities.PatientImportJob, progress func(*entities.PatientImportJob)) (*entities.PatientImportJob, error) {
+	if !u.claim(job.ID) {
+		return nil, entities.Conflict("import_running", "import is already running")
+	}
+	defer u.release(job.ID)
+
+	if job.Status == consts.ImportStatusCompleted {
+		return nil, entities.Conflict("import_completed", "import has already completed")
+	}
+
+	now := time.Now()
+	job.Status = consts.ImportStatusRunning
+	job.Error = ""
+	if job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	if _, err := u.repo.UpdateJob(job); err != nil {
+		return nil, err
+	}
+
+	if err := u.process(job, progress); err != nil {
+		job.Status = consts.ImportStatusFailed
+		job.Error = err.Error()
+		if _, updateErr := u.repo.UpdateJob(job); updateErr != nil {
+			log.Printf("failed to mark patient import %d as failed: %v", job.ID, updateErr)
+		}
+		return job, err
+	}
+
+	finished := time.Now()
+	job.Status = consts.ImportStatusCompleted
+	job.FinishedAt = &finished
+	if _, err := u.repo.UpdateJob(job); err != nil {
+		return job, err
+	}
+
+	setImportProgress(job)
+	return job, nil
+}
+
+func (u *PatientImportUseCase) FindById(id uint, staffHospitalId uint) (*entities.PatientImportJob, error) {
+	job, err := u.repo.FindJobById(id)
+	if err != nil || job == nil || job.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("import")
+	}
+
+	setImportProgress(job)
+	return job, nil
+}
+
+// WriteReport streams the job's invalid and duplicate rows as CSV.
+func (u *PatientImportUseCase) WriteReport(id uint, staffHospitalId uint, w io.Writer) error {
+	job, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"row", "kind", "field", "message"}); err != nil {
+		return err
+	}
+
+	var afterId uint
+	for {
+		issues, err := u.repo.FindIssues(job.ID, afterId, importReportPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range issues {
+			if err := writer.Write([]string{strconv.Itoa(issue.Row), string(issue.Kind), issue.Field, issue.Message}); err != nil {
+				return err
+			}
+			afterId = issue.ID
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+
+		if len(issues) < importReportPageSize {
+			return nil
+		}
+	}
+}
+
+type importRow struct {
+	line    int
+	patient entities.Patient
+}
+
+type importBatch struct {
+	consumed int
+	rows     []importRow
+	issues   []entities.PatientImportIssue
+}
+
+func (u *PatientImportUseCase) process(job *entities.PatientImportJob, progress func(*entities.PatientImportJob)) error {
+	if job.TotalRows == 0 {
+		total, err := u.countRows(job)
+		if err != nil {
+			return err
+		}
+		job.TotalRows = total
+	}
+
+	reader, closeSource, err := u.openSource(job)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return entities.Invalid("file is empty")
+	}
+	if err != nil {
+		return err
+	}
+
+	columns, err := resolveImportColumns(header, job.Mapping)
+	if err != nil {
+		return err
+	}
+
+	for skipped := 0; skipped < job.ProcessedRows; skipped++ {
+		if _, err := reader.Read(); err != nil {
+			return fmt.Errorf("file changed since the import started: %w", err)
+		}
+	}
+
+	// seen maps normalized identifiers to the line that first used them, so
+	// rows repeated within the file are reported as duplicates too.
+	seen := map[string]int{}
+	batch := &importBatch{}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// Line numbers are 1-based and the header is line 1.
+		line := job.ProcessedRows + batch.consumed + 2
+		batch.consumed++
+
+		if isBlankRow(row) {
+			continue
+		}
+
+		patient, err := parseImportRow(row, columns, job.HospitalID)
+		if err != nil {
+			batch.issues = append(batch.issues, importIssue(job.ID, line, consts.ImportIssueInvalid, err))
+		} else {
+			batch.rows = append(batch.rows, importRow{line: line, patient: *patient})
+		}
+
+		if batch.consumed >= job.BatchSize {
+			if err := u.flush(job, batch, seen, progress); err != nil {
+				return err
+			}
+			batch = &importBatch{}
+		}
+	}
+
+	if batch.consumed > 0 {
+		return u.flush(job, batch, seen, progress)
+	}
+	return nil
+}
+
+func (u *PatientImportUseCase) flush(job *entities.PatientImportJob, batch *importBatch, seen map[string]int, progress func(*entities.PatientImportJob)) error {
+	candidates := make([]entities.Patient, len(batch.rows))
+	for i, row := range batch.rows {
+		candidates[i] = row.patient
+	}
+
+	existing, err := u.repo.FindDuplicates(job.HospitalID, candidates)
+	if err != nil {
+		return err
+	}
+
+	known := knownDuplicates(existing)
+
+	issues := batch.issues
+	var accepted []entities.Patient
+	duplicates := 0
+	for _, row := range batch.rows {
+		keys := duplicateKeys(&row.patient)
+		if field, message := findDuplicate(keys, known, seen); message != "" {
+			duplicates++
+			issues = append(issues, entities.PatientImportIssue{
+				JobID:   job.ID,
+				Row:     row.line,
+				Kind:    consts.ImportIssueDuplicate,
+				Field:   field,
+				Message: message,
+			})
+			continue
+		}
+
+		for _, key := range keys {
+			seen[key] = row.line
+		}
+		accepted = append(accepted, row.patient)
+	}
+
+	previous := *job
+	job.ProcessedRows += batch.consumed
+	job.InsertedRows += len(accepted)
+	job.DuplicateRows += duplicates
+	job.InvalidRows += len(batch.issues)
+
+	inserts := accepted
+	if job.DryRun {
+		inserts = nil
+	}
+
+	if err := u.repo.SaveBatch(job, inserts, issues); err != nil {
+		*job = previous
+		return err
+	}
+
+	if progress != nil {
+		setImportProgress(job)
+		progress(job)
+	}
+	return nil
+}
+
+func (u *PatientImportUseCase) openSource(job *entities.PatientImportJob) (tabular.Reader, func(), error) {
+	body, err := u.storage.Get(context.Background(), job.SourceKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader, err := tabular.NewReader(tabular.Format(job.Format), body)
+	if err != nil {
+		body.Close()
+		return nil, nil, err
+	}
+
+	return reader, func() {
+		reader.Close()
+		body.Close()
+	}, nil
+}
+
+func (u *PatientImportUseCase) countRows(job *entities.PatientImportJob) (int, error) {
+	reader, closeSource, err := u.openSource(job)
+	if err != nil {
+		return 0, err
+	}
+	defer closeSource()
+
+	count := -1 // the header is not a data row
+	for {
+		if _, err := reader.Read(); errors.Is(err, io.EOF) {
+			return max(count, 0), nil
+		} else if err != nil {
+			return 0, err
+		}
+		count++
+	}
+}
+
+func (u *PatientImportUseCase) claim(id uint) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.active[id] {
+		return false
+	}
+	u.active[id] = true
+	return true
+}
+
+func (u *PatientImportUseCase) release(id uint) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delete(u.active, id)
+}
+
+func (u *PatientImportUseCase) isActive(id uint) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.active[id]
+}
+
+func isImportField(name string) bool {
+	for _, field := range importFields {
+		if field.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveImportColumns maps patient fields to column positions. Mapped
+// fields must exist in the header; the rest are matched by their own name.
+func resolveImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	positions := map[string]int{}
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, exists := positions[key]; !exists {
+			positions[key] = i
+		}
+	}
+
+	columns := map[string]int{}
+	for _, field := range importFields {
+		source, mapped := mapping[field.name]
+		if !mapped {
+			source = field.name
+		}
+
+		position, found := positions[strings.ToLower(strings.TrimSpace(source))]
+		if !found {
+			if mapped {
+				return nil, entities.Invalid(fmt.Sprintf("column %q mapped to %s is not in the file", source, field.name))
+			}
+			continue
+		}
+		columns[field.name] = position
+	}
+
+	if len(columns) == 0 {
+		return nil, entities.Invalid("no columns match patient fields; provide a mapping")
+	}
+	return columns, nil
+}
+
+func parseImportRow(row []string, columns map[string]int, hospitalId uint) (*entities.Patient, error) {
+	patient := &entities.Patient{HospitalID: hospitalId}
+	for _, field := range importFields {
+		position, ok := columns[field.name]
+		if !ok || position >= len(row) {
+			continue
+		}
+
+		value := strings.TrimSpace(row[position])
+		if value == "" {
+			continue
+		}
+
+		if err := field.set(patient, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := patient.Validate(); err != nil {
+		return nil, err
+	}
+	return patient, nil
+}
+
+func importIssue(jobId uint, line int, kind consts.ImportIssueKind, err error) entities.PatientImportIssue {
+	issue := entities.PatientImportIssue{JobID: jobId, Row: line, Kind: kind, Message: err.Error()}
+
+	var fieldErr *entities.FieldError
+	if errors.As(err, &fieldErr) {
+		issue.Field = fieldErr.Field
+	}
+	return issue
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func setImportProgress(job *entities.PatientImportJob) {
+	switch {
+	case job.Status == consts.ImportStatusCompleted:
+		job.Progress = 100
+	case job.TotalRows > 0:
+		job.Progress = math.Round(float64(job.ProcessedRows)/float64(job.TotalRows)*10000) / 100
+	}
+}
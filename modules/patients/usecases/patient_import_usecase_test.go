@@ -0,0 +1,328 @@
+package usecases_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/storages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importHeader = "first_name_th,last_name_th,first_name_en,last_name_en,date_of_birth,gender,national_id,patient_hn\n"
+
+func newImportUseCase(t *testing.T) (entities.PatientImportUseCase, *mocks.MockPatientImportRepository, storages.Storage) {
+	mockRepo := mocks.NewMockPatientImportRepository()
+	storage := storages.NewLocalStorage(t.TempDir())
+	return usecases.NewPatientImportUseCase(mockRepo, storage), mockRepo, storage
+}
+
+// importJob stores the CSV in storage and returns a pending job for it.
+func importJob(t *testing.T, storage storages.Storage, csv string, dryRun bool, batchSize int) *entities.PatientImportJob {
+	key := "imports/1/source.csv"
+	require.NoError(t, storage.Put(context.Background(), key, strings.NewReader(csv), int64(len(csv)), "text/csv"))
+	return &entities.PatientImportJob{
+		ID:         1,
+		HospitalID: 1,
+		FileName:   "patients.csv",
+		Format:     "csv",
+		SourceKey:  key,
+		DryRun:     dryRun,
+		BatchSize:  batchSize,
+		Status:     consts.ImportStatusPending,
+		Version:    1,
+	}
+}
+
+func expectJobUpdates(mockRepo *mocks.MockPatientImportRepository) {
+	mockRepo.On("UpdateJob", mock.Anything).Return(&entities.PatientImportJob{}, nil)
+}
+
+func savedBatches(mockRepo *mocks.MockPatientImportRepository) ([][]entities.Patient, [][]entities.PatientImportIssue) {
+	var patients [][]entities.Patient
+	var issues [][]entities.PatientImportIssue
+	for _, call := range mockRepo.Calls {
+		if call.Method == "SaveBatch" {
+			patients = append(patients, call.Arguments.Get(1).([]entities.Patient))
+			issues = append(issues, call.Arguments.Get(2).([]entities.PatientImportIssue))
+		}
+	}
+	return patients, issues
+}
+
+func TestCreatePatientImport(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		mockRepo.On("CreateJob", mock.Anything).Return(&entities.PatientImportJob{ID: 1}, nil)
+
+		body := importHeader
+		_, err := usecase.Create(&entities.PatientImportRequest{
+			HospitalID: 1,
+			StaffID:    9,
+			FileName:   "Patients.CSV",
+			Mapping:    map[string]string{"first_name_th": "ชื่อ"},
+			DryRun:     true,
+			Body:       strings.NewReader(body),
+			Size:       int64(len(body)),
+		})
+		assert.NoError(t, err)
+
+		created := mockRepo.Calls[0].Arguments.Get(0).(*entities.PatientImportJob)
+		assert.Equal(t, "csv", created.Format)
+		assert.Equal(t, usecases.DefaultImportBatchSize, created.BatchSize)
+		assert.Equal(t, consts.ImportStatusPending, created.Status)
+		assert.True(t, created.DryRun)
+
+		_, err = storage.Get(context.Background(), created.SourceKey)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		usecase, mockRepo, _ := newImportUseCase(t)
+
+		_, err := usecase.Create(&entities.PatientImportRequest{HospitalID: 1, FileName: "patients.xls", Body: strings.NewReader("")})
+		assert.EqualError(t, err, "file format must be csv or xlsx")
+		mockRepo.AssertNotCalled(t, "CreateJob")
+	})
+
+	t.Run("Unknown Mapping Field", func(t *testing.T) {
+		usecase, mockRepo, _ := newImportUseCase(t)
+
+		_, err := usecase.Create(&entities.PatientImportRequest{
+			HospitalID: 1,
+			FileName:   "patients.csv",
+			Mapping:    map[string]string{"password": "pw"},
+			Body:       strings.NewReader(""),
+		})
+		assert.EqualError(t, err, `mapping has unknown patient field "password"`)
+		mockRepo.AssertNotCalled(t, "CreateJob")
+	})
+}
+
+func TestRunPatientImport(t *testing.T) {
+	t.Run("Dry Run Reports Invalid And Duplicate Rows", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		job := importJob(t, storage, importHeader+
+			"สมชาย,ใจดี,Somchai,Jaidee,1990-05-01,m,1234567890123,HN1\n"+
+			"สมหญิง,ใจดี,Somying,Jaidee,1991-05-01,X,1234567890124,HN2\n"+
+			"ซ้ำ,ในไฟล์,Dup,InFile,1992-05-01,F,1234567890125,HN1\n"+
+			",,,,,,,\n"+
+			"มี,อยู่แล้ว,Already,There,1993-05-01,F,1111111111111,HN4\n", true, 500)
+
+		expectJobUpdates(mockRepo)
+		mockRepo.On("FindDuplicates", uint(1), mock.Anything).Return([]entities.Patient{{ID: 42, NationalID: "1111111111111"}}, nil)
+		mockRepo.On("SaveBatch", job, mock.Anything, mock.Anything).Return(nil)
+
+		result, err := usecase.Run(job, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, consts.ImportStatusCompleted, result.Status)
+		assert.Equal(t, 5, result.TotalRows)
+		assert.Equal(t, 5, result.ProcessedRows)
+		assert.Equal(t, 1, result.InsertedRows)
+		assert.Equal(t, 2, result.DuplicateRows)
+		assert.Equal(t, 1, result.InvalidRows)
+		assert.Equal(t, float64(100), result.Progress)
+
+		patients, issues := savedBatches(mockRepo)
+		require.Len(t, patients, 1)
+		assert.Nil(t, patients[0])
+		assert.Equal(t, []entities.PatientImportIssue{
+			{JobID: 1, Row: 3, Kind: consts.ImportIssueInvalid, Field: "gender", Message: "gender must be male, female, other or unknown"},
+			{JobID: 1, Row: 4, Kind: consts.ImportIssueDuplicate, Field: "patient_hn", Message: "patient_hn repeats row 2"},
+			{JobID: 1, Row: 6, Kind: consts.ImportIssueDuplicate, Field: "national_id", Message: "national_id matches existing patient 42"},
+		}, issues[0])
+	})
+
+	t.Run("Duplicate Names Are Refused Like Create", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		job := importJob(t, storage, importHeader+
+			"สมชาย,ใจดี,Somchai,Jaidee,1990-05-01,M,1234567890123,HN1\n"+
+			"สมชาย,ใจดี,Somchai,Jaidee,1990-05-01,M,1234567890124,HN2\n"+
+			"มี,อยู่แล้ว,Already,There,1993-05-01,F,1234567890125,HN3\n", true, 500)
+
+		expectJobUpdates(mockRepo)
+		mockRepo.On("FindDuplicates", uint(1), mock.Anything).Return([]entities.Patient{{ID: 42, FirstNameTH: "มี", LastNameTH: "อยู่แล้ว"}}, nil)
+		mockRepo.On("SaveBatch", job, mock.Anything, mock.Anything).Return(nil)
+
+		result, err := usecase.Run(job, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.InsertedRows)
+		assert.Equal(t, 2, result.DuplicateRows)
+
+		_, issues := savedBatches(mockRepo)
+		assert.Equal(t, []entities.PatientImportIssue{
+			{JobID: 1, Row: 3, Kind: consts.ImportIssueDuplicate, Field: "name", Message: "name repeats row 2"},
+			{JobID: 1, Row: 4, Kind: consts.ImportIssueDuplicate, Field: "name", Message: "name matches existing patient 42"},
+		}, issues[0])
+	})
+
+	t.Run("Commit Inserts In Batches", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		job := importJob(t, storage, importHeader+
+			"ก,ข,A,B,1990-05-01,M,1234567890123,HN1\n"+
+			"ค,ง,C,D,01/02/1991,F,1234567890124,HN2\n"+
+			"จ,ฉ,E,F,1992-05-01,F,,HN3\n", false, 2)
+
+		expectJobUpdates(mockRepo)
+		mockRepo.On("FindDuplicates", uint(1), mock.Anything).Return([]entities.Patient{}, nil)
+		mockRepo.On("SaveBatch", job, mock.Anything, mock.Anything).Return(nil)
+
+		var reported []int
+		result, err := usecase.Run(job, func(progress *entities.PatientImportJob) {
+			reported = append(reported, progress.ProcessedRows)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.InsertedRows)
+		assert.Equal(t, 1, result.InvalidRows)
+		assert.Equal(t, []int{2, 3}, reported)
+
+		patients, issues := savedBatches(mockRepo)
+		require.Len(t, patients, 2)
+		assert.Len(t, patients[0], 2)
+		assert.Equal(t, uint(1), patients[0][0].HospitalID)
+		assert.Equal(t, "1991-02-01", patients[0][1].DateOfBirth.String())
+		assert.Len(t, patients[1], 0)
+		assert.Equal(t, "national_id or passport_id is required", issues[1][0].Message)
+	})
+
+	t.Run("Resume Skips Committed Rows", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		job := importJob(t, storage, importHeader+
+			"ก,ข,A,B,1990-05-01,M,1234567890123,HN1\n"+
+			"ค,ง,C,D,1991-05-01,F,1234567890124,HN2\n"+
+			"จ,ฉ,E,F,1992-05-01,F,1234567890125,HN3\n", false, 500)
+		job.Status = consts.ImportStatusFailed
+		job.TotalRows = 3
+		job.ProcessedRows = 2
+		job.InsertedRows = 2
+
+		expectJobUpdates(mockRepo)
+		mockRepo.On("FindDuplicates", uint(1), mock.Anything).Return([]entities.Patient{}, nil)
+		mockRepo.On("SaveBatch", job, mock.Anything, mock.Anything).Return(nil)
+
+		result, err := usecase.Run(job, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.ProcessedRows)
+		assert.Equal(t, 3, result.InsertedRows)
+
+		patients, _ := savedBatches(mockRepo)
+		require.Len(t, patients, 1)
+		require.Len(t, patients[0], 1)
+		assert.Equal(t, "HN3", patients[0][0].PatientHN)
+	})
+
+	t.Run("Custom Mapping", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		job := importJob(t, storage, "ชื่อ,นามสกุล,First,Last,DOB,Sex,CID\n"+
+			"ก,ข,A,B,1990-05-01,M,1234567890123\n", false, 500)
+		job.Mapping = map[string]string{
+			"first_name_th": "ชื่อ",
+			"last_name_th":  "นามสกุล",
+			"first_name_en": "first",
+			"last_name_en":  "last",
+			"date_of_birth": "DOB",
+			"gender":        "Sex",
+			"national_id":   "CID",
+		}
+
+		expectJobUpdates(mockRepo)
+		mockRepo.On("FindDuplicates", uint(1), mock.Anything).Return([]entities.Patient{}, nil)
+		mockRepo.On("SaveBatch", job, mock.Anything, mock.Anything).Return(nil)
+
+		result, err := usecase.Run(job, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.InsertedRows)
+	})
+
+	t.Run("Missing Mapped Column Fails The Job", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		job := importJob(t, storage, importHeader, false, 500)
+		job.Mapping = map[string]string{"national_id": "CID"}
+
+		expectJobUpdates(mockRepo)
+
+		result, err := usecase.Run(job, nil)
+		assert.EqualError(t, err, `column "CID" mapped to national_id is not in the file`)
+		assert.Equal(t, consts.ImportStatusFailed, result.Status)
+		assert.Equal(t, err.Error(), result.Error)
+	})
+
+	t.Run("Failed Batch Keeps Previous Progress", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		job := importJob(t, storage, importHeader+
+			"ก,ข,A,B,1990-05-01,M,1234567890123,HN1\n", false, 500)
+
+		expectJobUpdates(mockRepo)
+		mockRepo.On("FindDuplicates", uint(1), mock.Anything).Return([]entities.Patient{}, nil)
+		mockRepo.On("SaveBatch", job, mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+		result, err := usecase.Run(job, nil)
+		assert.EqualError(t, err, "connection reset")
+		assert.Equal(t, consts.ImportStatusFailed, result.Status)
+		assert.Equal(t, 0, result.ProcessedRows)
+		assert.Equal(t, 0, result.InsertedRows)
+	})
+
+	t.Run("Completed Job Cannot Run Again", func(t *testing.T) {
+		usecase, mockRepo, storage := newImportUseCase(t)
+		job := importJob(t, storage, importHeader, false, 500)
+		job.Status = consts.ImportStatusCompleted
+
+		_, err := usecase.Run(job, nil)
+		assert.EqualError(t, err, "import has already completed")
+		mockRepo.AssertNotCalled(t, "UpdateJob", mock.Anything)
+	})
+}
+
+func TestCommitPatientImport(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, _ := newImportUseCase(t)
+		mockRepo.On("FindJobById", uint(1)).Return(&entities.PatientImportJob{ID: 1, HospitalID: 1, DryRun: true, Status: consts.ImportStatusCompleted, SourceKey: "imports/1/a.csv", Format: "csv", BatchSize: 100}, nil)
+		mockRepo.On("CreateJob", mock.Anything).Return(&entities.PatientImportJob{ID: 2}, nil)
+
+		_, err := usecase.Commit(1, 1, 9)
+		assert.NoError(t, err)
+
+		created := mockRepo.Calls[1].Arguments.Get(0).(*entities.PatientImportJob)
+		assert.False(t, created.DryRun)
+		assert.Equal(t, "imports/1/a.csv", created.SourceKey)
+		assert.Equal(t, uint(9), created.StaffID)
+	})
+
+	t.Run("Not A Dry Run", func(t *testing.T) {
+		usecase, mockRepo, _ := newImportUseCase(t)
+		mockRepo.On("FindJobById", uint(1)).Return(&entities.PatientImportJob{ID: 1, HospitalID: 1, Status: consts.ImportStatusCompleted}, nil)
+
+		_, err := usecase.Commit(1, 1, 9)
+		assert.EqualError(t, err, "only a completed dry run can be committed")
+	})
+
+	t.Run("Other Hospital", func(t *testing.T) {
+		usecase, mockRepo, _ := newImportUseCase(t)
+		mockRepo.On("FindJobById", uint(1)).Return(&entities.PatientImportJob{ID: 1, HospitalID: 2, DryRun: true, Status: consts.ImportStatusCompleted}, nil)
+
+		_, err := usecase.Commit(1, 1, 9)
+		assert.EqualError(t, err, "import not found")
+	})
+}
+
+func TestWritePatientImportReport(t *testing.T) {
+	usecase, mockRepo, _ := newImportUseCase(t)
+	mockRepo.On("FindJobById", uint(1)).Return(&entities.PatientImportJob{ID: 1, HospitalID: 1}, nil)
+	mockRepo.On("FindIssues", uint(1), uint(0), 1000).Return([]entities.PatientImportIssue{
+		{ID: 5, Row: 3, Kind: consts.ImportIssueInvalid, Field: "gender", Message: "gender must be male, female, other or unknown"},
+	}, nil)
+
+	var buf bytes.Buffer
+	err := usecase.WriteReport(1, 1, &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "row,kind,field,message\n3,invalid,gender,\"gender must be male, female, other or unknown\"\n", buf.String())
+}
@@ -0,0 +1,225 @@
+package usecases
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type PatientTransferUseCase struct {
+	repo         entities.PatientTransferRepository
+	patientRepo  entities.PatientRepository
+	hospitalRepo entities.HospitalRepository
+}
+
+func NewPatientTransferUseCase(repo entities.PatientTransferRepository, patientRepo entities.PatientRepository, hospitalRepo entities.HospitalRepository) entities.PatientTransferUseCase {
+	return &PatientTransferUseCase{repo: repo, patientRepo: patientRepo, hospitalRepo: hospitalRepo}
+}
+
+// Create opens a transfer from the staff member's hospital.
+func (u *PatientTransferUseCase) Create(req *entities.PatientTransferCreateRequest, staffHospitalId uint, staffId uint) (*entities.PatientTransfer, error) {
+	patient, err := u.patientRepo.FindById(req.PatientID)
+	if err != nil || patient == nil || patient.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("patient")
+	}
+
+	if req.DestinationHospitalID == staffHospitalId {
+		return nil, entities.Invalid("destination must be a different hospital")
+	}
+
+	if _, err := u.hospitalRepo.FindById(req.DestinationHospitalID); err != nil {
+		return nil, entities.NotFound("destination hospital")
+	}
+
+	return u.repo.Create(&entities.PatientTransfer{
+		PatientID:             patient.ID,
+		SourceHospitalID:      staffHospitalId,
+		DestinationHospitalID: req.DestinationHospitalID,
+		Status:                consts.TransferStatusRequested,
+		Reason:                req.Reason,
+		RequestedByID:         staffId,
+		RequestedAt:           time.Now(),
+	})
+}
+
+// Accept registers the patient at the destination. A destination patient
+// with the same national ID or passport is linked instead of duplicated;
+// otherwise the source record and its demographics are copied under the
+// HN given in the request.
+func (u *PatientTransferUseCase) Accept(id uint, req *entities.PatientTransferAcceptRequest, staffHospitalId uint, staffId uint) (*entities.PatientTransfer, error) {
+	transfer, err := u.decidable(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := u.patientRepo.FindById(transfer.PatientID)
+	if err != nil || source == nil {
+		return nil, entities.NotFound("patient")
+	}
+
+	hn := strings.TrimSpace(req.PatientHN)
+	probe := entities.Patient{NationalIDIdx: source.NationalIDIdx, PassportIDIdx: source.PassportIDIdx, PatientHN: hn}
+	matches, err := u.repo.FindDestinationMatches(transfer.DestinationHospitalID, &probe)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied *entities.Patient
+	if existing := matchingIdentity(source, matches); existing != nil {
+		transfer.DestinationPatientID = &existing.ID
+		transfer.DestinationHN = existing.PatientHN
+		transfer.Linked = true
+	} else {
+		if hn == "" {
+			return nil, entities.Invalid("patient_hn is required")
+		}
+		if len(matches) > 0 {
+			return nil, entities.Conflict("patient_hn_taken", "patient_hn is already used at the destination hospital")
+		}
+		copied = copyPatient(source, transfer.DestinationHospitalID, hn)
+		transfer.DestinationHN = hn
+	}
+
+	now := time.Now()
+	transfer.Status = consts.TransferStatusAccepted
+	transfer.DecidedByID = &staffId
+	transfer.DecidedAt = &now
+
+	return u.repo.Accept(transfer, copied)
+}
+
+func (u *PatientTransferUseCase) Reject(id uint, req *entities.PatientTransferRejectRequest, staffHospitalId uint, staffId uint) (*entities.PatientTransfer, error) {
+	transfer, err := u.decidable(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transfer.Status = consts.TransferStatusRejected
+	transfer.RejectReason = req.Reason
+	transfer.DecidedByID = &staffId
+	transfer.DecidedAt = &now
+
+	return u.repo.Update(transfer)
+}
+
+// Cancel withdraws a transfer that the destination has not decided yet.
+func (u *PatientTransferUseCase) Cancel(id uint, staffHospitalId uint, staffId uint) (*entities.PatientTransfer, error) {
+	transfer, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.SourceHospitalID != staffHospitalId {
+		return nil, entities.Forbidden("not_transfer_source", "only the source hospital can cancel a transfer")
+	}
+	if transfer.Status != consts.TransferStatusRequested {
+		return nil, entities.ErrInvalidTransition
+	}
+
+	now := time.Now()
+	transfer.Status = consts.TransferStatusCancelled
+	transfer.CancelledByID = &staffId
+	transfer.CancelledAt = &now
+
+	return u.repo.Update(transfer)
+}
+
+// FindById returns transfers that the staff member's hospital is a party to.
+func (u *PatientTransferUseCase) FindById(id uint, staffHospitalId uint) (*entities.PatientTransfer, error) {
+	transfer, err := u.repo.FindById(id)
+	if err != nil || transfer == nil {
+		return nil, entities.NotFound("transfer")
+	}
+
+	if transfer.SourceHospitalID != staffHospitalId && transfer.DestinationHospitalID != staffHospitalId {
+		return nil, entities.NotFound("transfer")
+	}
+
+	return transfer, nil
+}
+
+func (u *PatientTransferUseCase) FindAll(filter entities.PatientTransferFilter, page int, limit int) ([]entities.PatientTransfer, int, error) {
+	switch filter.Direction {
+	case "", "incoming", "outgoing":
+	default:
+		return nil, 0, entities.Invalid("direction must be incoming or outgoing")
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, 0, entities.Invalid("status must be requested, accepted, rejected or cancelled")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return u.repo.FindAll(filter, page, limit)
+}
+
+// decidable loads a transfer that the staff member's hospital can accept or
+// reject.
+func (u *PatientTransferUseCase) decidable(id uint, staffHospitalId uint) (*entities.PatientTransfer, error) {
+	transfer, err := u.FindById(id, staffHospitalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.DestinationHospitalID != staffHospitalId {
+		return nil, entities.Forbidden("not_transfer_destination", "only the destination hospital can accept or reject a transfer")
+	}
+	if transfer.Status != consts.TransferStatusRequested {
+		return nil, entities.ErrInvalidTransition
+	}
+
+	return transfer, nil
+}
+
+func matchingIdentity(source *entities.Patient, matches []entities.Patient) *entities.Patient {
+	for i, match := range matches {
+		if source.NationalIDIdx != "" && match.NationalIDIdx == source.NationalIDIdx {
+			return &matches[i]
+		}
+		if source.PassportIDIdx != "" && match.PassportIDIdx == source.PassportIDIdx {
+			return &matches[i]
+		}
+	}
+	return nil
+}
+
+// copyPatient clones the source record and its demographics as a new,
+// unsaved patient of the destination hospital.
+func copyPatient(source *entities.Patient, hospitalId uint, hn string) *entities.Patient {
+	copied := *source
+	copied.ID = 0
+	copied.HospitalID = hospitalId
+	copied.Hospital = entities.Hospital{}
+	copied.PatientHN = hn
+	copied.Version = 0
+	copied.CreatedAt = time.Time{}
+	copied.UpdatedAt = time.Time{}
+
+	copied.Addresses = make([]entities.PatientAddress, len(source.Addresses))
+	for i, address := range source.Addresses {
+		address.ID = 0
+		address.PatientID = 0
+		address.CreatedAt = time.Time{}
+		address.UpdatedAt = time.Time{}
+		copied.Addresses[i] = address
+	}
+
+	copied.EmergencyContacts = make([]entities.PatientEmergencyContact, len(source.EmergencyContacts))
+	for i, contact := range source.EmergencyContacts {
+		contact.ID = 0
+		contact.PatientID = 0
+		contact.CreatedAt = time.Time{}
+		contact.UpdatedAt = time.Time{}
+		copied.EmergencyContacts[i] = contact
+	}
+
+	return &copied
+}
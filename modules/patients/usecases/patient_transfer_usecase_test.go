@@ -0,0 +1,249 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTransferUseCase() (entities.PatientTransferUseCase, *mocks.MockPatientTransferRepository, *mocks.MockPatientRepository, *mocks.MockHospitalRepository) {
+	mockRepo := mocks.NewMockPatientTransferRepository()
+	mockPatientRepo := mocks.NewMockPatientRepository()
+	mockHospitalRepo := mocks.NewMockHospitalRepository()
+	return usecases.NewPatientTransferUseCase(mockRepo, mockPatientRepo, mockHospitalRepo), mockRepo, mockPatientRepo, mockHospitalRepo
+}
+
+func pendingTransfer() *entities.PatientTransfer {
+	return &entities.PatientTransfer{
+		ID:                    1,
+		PatientID:             10,
+		SourceHospitalID:      1,
+		DestinationHospitalID: 2,
+		Status:                consts.TransferStatusRequested,
+		RequestedByID:         9,
+		Version:               1,
+	}
+}
+
+func transferSource() *entities.Patient {
+	return &entities.Patient{
+		ID:            10,
+		HospitalID:    1,
+		PatientHN:     "HN-001",
+		FirstNameTH:   "สมชาย",
+		NationalID:    "1234567890123",
+		NationalIDIdx: "idx-national",
+		Version:       4,
+		Addresses:     []entities.PatientAddress{{ID: 3, PatientID: 10, Province: "Bangkok"}},
+		EmergencyContacts: []entities.PatientEmergencyContact{
+			{ID: 5, PatientID: 10, FullName: "สมหญิง"},
+		},
+	}
+}
+
+func TestCreatePatientTransfer(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, mockHospitalRepo := newTransferUseCase()
+		mockPatientRepo.On("FindById", uint(10)).Return(transferSource(), nil)
+		mockHospitalRepo.On("FindById", uint(2)).Return(&entities.Hospital{ID: 2}, nil)
+		mockRepo.On("Create", mock.Anything).Return(&entities.PatientTransfer{ID: 1}, nil)
+
+		_, err := usecase.Create(&entities.PatientTransferCreateRequest{PatientID: 10, DestinationHospitalID: 2, Reason: "referral"}, 1, 9)
+		require.NoError(t, err)
+
+		created := mockRepo.Calls[0].Arguments.Get(0).(*entities.PatientTransfer)
+		assert.Equal(t, consts.TransferStatusRequested, created.Status)
+		assert.Equal(t, uint(1), created.SourceHospitalID)
+		assert.Equal(t, uint(9), created.RequestedByID)
+		assert.False(t, created.RequestedAt.IsZero())
+	})
+
+	t.Run("Patient Of Another Hospital", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newTransferUseCase()
+		mockPatientRepo.On("FindById", uint(10)).Return(transferSource(), nil)
+
+		_, err := usecase.Create(&entities.PatientTransferCreateRequest{PatientID: 10, DestinationHospitalID: 3, Reason: "referral"}, 2, 9)
+		assert.EqualError(t, err, "patient not found")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Same Hospital", func(t *testing.T) {
+		usecase, _, mockPatientRepo, _ := newTransferUseCase()
+		mockPatientRepo.On("FindById", uint(10)).Return(transferSource(), nil)
+
+		_, err := usecase.Create(&entities.PatientTransferCreateRequest{PatientID: 10, DestinationHospitalID: 1, Reason: "referral"}, 1, 9)
+		assert.EqualError(t, err, "destination must be a different hospital")
+	})
+
+	t.Run("Unknown Destination", func(t *testing.T) {
+		usecase, _, mockPatientRepo, mockHospitalRepo := newTransferUseCase()
+		mockPatientRepo.On("FindById", uint(10)).Return(transferSource(), nil)
+		mockHospitalRepo.On("FindById", uint(7)).Return((*entities.Hospital)(nil), errors.New("record not found"))
+
+		_, err := usecase.Create(&entities.PatientTransferCreateRequest{PatientID: 10, DestinationHospitalID: 7, Reason: "referral"}, 1, 9)
+		assert.EqualError(t, err, "destination hospital not found")
+	})
+}
+
+func TestAcceptPatientTransfer(t *testing.T) {
+	t.Run("Copies Patient With New HN", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+		mockPatientRepo.On("FindById", uint(10)).Return(transferSource(), nil)
+		mockRepo.On("FindDestinationMatches", uint(2), mock.Anything).Return([]entities.Patient{}, nil)
+		mockRepo.On("Accept", mock.Anything, mock.Anything).Return(&entities.PatientTransfer{ID: 1}, nil)
+
+		_, err := usecase.Accept(1, &entities.PatientTransferAcceptRequest{PatientHN: " HN-900 "}, 2, 20)
+		require.NoError(t, err)
+
+		transfer := mockRepo.Calls[2].Arguments.Get(0).(*entities.PatientTransfer)
+		copied := mockRepo.Calls[2].Arguments.Get(1).(*entities.Patient)
+		assert.Equal(t, consts.TransferStatusAccepted, transfer.Status)
+		assert.Equal(t, uint(20), *transfer.DecidedByID)
+		assert.NotNil(t, transfer.DecidedAt)
+		assert.Equal(t, "HN-900", transfer.DestinationHN)
+		assert.False(t, transfer.Linked)
+
+		assert.Zero(t, copied.ID)
+		assert.Equal(t, uint(2), copied.HospitalID)
+		assert.Equal(t, "HN-900", copied.PatientHN)
+		assert.Equal(t, "1234567890123", copied.NationalID)
+		assert.Zero(t, copied.Version)
+		require.Len(t, copied.Addresses, 1)
+		assert.Zero(t, copied.Addresses[0].ID)
+		assert.Zero(t, copied.Addresses[0].PatientID)
+		require.Len(t, copied.EmergencyContacts, 1)
+		assert.Zero(t, copied.EmergencyContacts[0].ID)
+	})
+
+	t.Run("Links Existing Patient", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+		mockPatientRepo.On("FindById", uint(10)).Return(transferSource(), nil)
+		mockRepo.On("FindDestinationMatches", uint(2), mock.Anything).Return([]entities.Patient{
+			{ID: 77, HospitalID: 2, PatientHN: "B-77", NationalIDIdx: "idx-national"},
+		}, nil)
+		mockRepo.On("Accept", mock.Anything, (*entities.Patient)(nil)).Return(&entities.PatientTransfer{ID: 1}, nil)
+
+		_, err := usecase.Accept(1, &entities.PatientTransferAcceptRequest{}, 2, 20)
+		require.NoError(t, err)
+
+		transfer := mockRepo.Calls[2].Arguments.Get(0).(*entities.PatientTransfer)
+		assert.True(t, transfer.Linked)
+		assert.Equal(t, uint(77), *transfer.DestinationPatientID)
+		assert.Equal(t, "B-77", transfer.DestinationHN)
+	})
+
+	t.Run("HN Required To Copy", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+		mockPatientRepo.On("FindById", uint(10)).Return(transferSource(), nil)
+		mockRepo.On("FindDestinationMatches", uint(2), mock.Anything).Return([]entities.Patient{}, nil)
+
+		_, err := usecase.Accept(1, &entities.PatientTransferAcceptRequest{}, 2, 20)
+		assert.EqualError(t, err, "patient_hn is required")
+		mockRepo.AssertNotCalled(t, "Accept", mock.Anything, mock.Anything)
+	})
+
+	t.Run("HN Taken At Destination", func(t *testing.T) {
+		usecase, mockRepo, mockPatientRepo, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+		mockPatientRepo.On("FindById", uint(10)).Return(transferSource(), nil)
+		mockRepo.On("FindDestinationMatches", uint(2), mock.Anything).Return([]entities.Patient{
+			{ID: 78, HospitalID: 2, PatientHN: "HN-900", NationalIDIdx: "idx-other"},
+		}, nil)
+
+		_, err := usecase.Accept(1, &entities.PatientTransferAcceptRequest{PatientHN: "HN-900"}, 2, 20)
+		assert.EqualError(t, err, "patient_hn is already used at the destination hospital")
+	})
+
+	t.Run("Source Hospital Cannot Accept", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+
+		_, err := usecase.Accept(1, &entities.PatientTransferAcceptRequest{PatientHN: "HN-900"}, 1, 9)
+		assert.EqualError(t, err, "only the destination hospital can accept or reject a transfer")
+	})
+
+	t.Run("Already Decided", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newTransferUseCase()
+		transfer := pendingTransfer()
+		transfer.Status = consts.TransferStatusRejected
+		mockRepo.On("FindById", uint(1)).Return(transfer, nil)
+
+		_, err := usecase.Accept(1, &entities.PatientTransferAcceptRequest{PatientHN: "HN-900"}, 2, 20)
+		assert.ErrorIs(t, err, entities.ErrInvalidTransition)
+	})
+}
+
+func TestRejectAndCancelPatientTransfer(t *testing.T) {
+	t.Run("Reject", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+		mockRepo.On("Update", mock.Anything).Return(&entities.PatientTransfer{ID: 1}, nil)
+
+		_, err := usecase.Reject(1, &entities.PatientTransferRejectRequest{Reason: "no beds"}, 2, 20)
+		require.NoError(t, err)
+
+		updated := mockRepo.Calls[1].Arguments.Get(0).(*entities.PatientTransfer)
+		assert.Equal(t, consts.TransferStatusRejected, updated.Status)
+		assert.Equal(t, "no beds", updated.RejectReason)
+		assert.Equal(t, uint(20), *updated.DecidedByID)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+		mockRepo.On("Update", mock.Anything).Return(&entities.PatientTransfer{ID: 1}, nil)
+
+		_, err := usecase.Cancel(1, 1, 9)
+		require.NoError(t, err)
+
+		updated := mockRepo.Calls[1].Arguments.Get(0).(*entities.PatientTransfer)
+		assert.Equal(t, consts.TransferStatusCancelled, updated.Status)
+		assert.Equal(t, uint(9), *updated.CancelledByID)
+		assert.NotNil(t, updated.CancelledAt)
+	})
+
+	t.Run("Destination Cannot Cancel", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+
+		_, err := usecase.Cancel(1, 2, 20)
+		assert.EqualError(t, err, "only the source hospital can cancel a transfer")
+	})
+
+	t.Run("Unrelated Hospital", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newTransferUseCase()
+		mockRepo.On("FindById", uint(1)).Return(pendingTransfer(), nil)
+
+		_, err := usecase.Cancel(1, 3, 30)
+		assert.EqualError(t, err, "transfer not found")
+	})
+}
+
+func TestFindAllPatientTransfers(t *testing.T) {
+	t.Run("Clamps Paging", func(t *testing.T) {
+		usecase, mockRepo, _, _ := newTransferUseCase()
+		filter := entities.PatientTransferFilter{HospitalID: 1, Direction: "incoming"}
+		mockRepo.On("FindAll", filter, 1, 20).Return([]entities.PatientTransfer{}, 0, nil)
+
+		_, _, err := usecase.FindAll(filter, 0, 500)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Direction", func(t *testing.T) {
+		usecase, _, _, _ := newTransferUseCase()
+
+		_, _, err := usecase.FindAll(entities.PatientTransferFilter{HospitalID: 1, Direction: "sideways"}, 1, 20)
+		assert.EqualError(t, err, "direction must be incoming or outgoing")
+	})
+}
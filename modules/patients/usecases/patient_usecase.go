@@ -1,9 +1,8 @@
 package usecases
 
 import (
-	"errors"
-
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 )
 
 type PatientUseCase struct {
@@ -14,11 +13,15 @@ func NewPatientUseCase(repo entities.PatientRepository) entities.PatientUseCase
 	return &PatientUseCase{repo: repo}
 }
 
+// Create saves a new patient unless it duplicates one already at its
+// hospital, by the same rule the import applies (see duplicateKeys).
 func (u *PatientUseCase) Create(patient *entities.Patient) (*entities.Patient, error) {
-	if exist, err := u.repo.FindByName(patient.FirstNameTH, patient.LastNameTH); err != nil {
+	existing, err := u.repo.FindDuplicates(patient.HospitalID, []entities.Patient{*patient})
+	if err != nil {
 		return nil, err
-	} else if len(exist) > 0 {
-		return nil, errors.New("patient already exists")
+	}
+	if field, _ := findDuplicate(duplicateKeys(patient), knownDuplicates(existing), nil); field != "" {
+		return nil, entities.Conflict("patient_exists", "patient with this "+field+" already exists")
 	}
 
 	createdPatient, err := u.repo.Create(patient)
@@ -29,11 +32,20 @@ func (u *PatientUseCase) Create(patient *entities.Patient) (*entities.Patient, e
 	return createdPatient, nil
 }
 
+// Update saves the patient if the stored row belongs to the staff member's
+// hospital. The hospital in the request is ignored, so an update cannot move
+// a patient to another hospital; transfers do that.
 func (u *PatientUseCase) Update(patient *entities.Patient, staffHospitalId uint) (*entities.Patient, error) {
-	if patient.HospitalID != staffHospitalId {
-		return nil, errors.New("patient not found")
+	exist, err := u.repo.FindById(patient.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Patients archived with their hospital can no longer be changed.
+	if exist == nil || exist.HospitalID != staffHospitalId || exist.ArchivedAt != nil {
+		return nil, entities.NotFound("patient")
 	}
 
+	patient.HospitalID = exist.HospitalID
 	return u.repo.Update(patient)
 }
 
@@ -43,32 +55,55 @@ func (u *PatientUseCase) Delete(id uint, staffHospitalId uint) (*entities.Patien
 		return nil, err
 	}
 	if exist == nil {
-		return nil, errors.New("patient not found")
+		return nil, entities.NotFound("patient")
 	}
 
 	if exist.HospitalID != staffHospitalId {
-		return nil, errors.New("patient not found")
+		return nil, entities.NotFound("patient")
 	}
 
 	return u.repo.Delete(id)
 }
 
+// FindById finds a patient of the staff member's hospital by its id.
+func (u *PatientUseCase) FindById(id uint, staffHospitalId uint) (*entities.Patient, error) {
+	exist, err := u.repo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if exist == nil || exist.HospitalID != staffHospitalId {
+		return nil, entities.NotFound("patient")
+	}
+
+	return exist, nil
+}
+
 func (u *PatientUseCase) FindByIdNationalOrPassport(id string, staffHospitalId uint) (*entities.Patient, error) {
-	exist, err := u.repo.FindByIdNationalOrPassport(id)
+	exist, err := u.repo.FindByIdNationalOrPassport(id, staffHospitalId)
 	if err != nil {
 		return nil, err
 	}
 	if exist == nil {
-		return nil, errors.New("patient not found")
+		return nil, entities.NotFound("patient")
 	}
 
 	if exist.HospitalID != staffHospitalId {
-		return nil, errors.New("patient not found")
+		return nil, entities.NotFound("patient")
 	}
 
 	return exist, nil
 }
 
+// FindAll lists the patients of the staff member's hospital.
+func (u *PatientUseCase) FindAll(spec *query.Spec, staffHospitalId uint) ([]entities.Patient, int, error) {
+	patients, totalCount, err := u.repo.FindAll(staffHospitalId, spec)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return patients, spec.TotalPages(int64(totalCount)), nil
+}
+
 func (u *PatientUseCase) FindByAdvanceSearch(input entities.PatientSearchInput, page int, limit int) ([]entities.Patient, int, error) {
 	patients, totalPage, err := u.repo.FindByAdvanceSearch(input, page, limit)
 	if err != nil {
@@ -8,16 +8,20 @@ import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
 	"github.com/Teemo4621/Hospital-Api/modules/mocks"
 	"github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCreate(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		input := &entities.Patient{FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1}
+		input := &entities.Patient{FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1}
 		exist := []entities.Patient{}
-		mockRepo.On("FindByName", "Test", "A").Return(exist, nil)
+		mockRepo.On("FindDuplicates", uint(1), []entities.Patient{*input}).Return(exist, nil)
 		mockRepo.On("Create", input).Return(input, nil)
 
 		result, err := usecase.Create(input)
@@ -26,17 +30,111 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, "A", result.LastNameTH)
 		assert.Equal(t, "Test", result.FirstNameEN)
 		assert.Equal(t, "A", result.LastNameEN)
-		assert.Equal(t, "M", result.Gender)
+		assert.Equal(t, consts.GenderMale, result.Gender)
 		assert.Equal(t, uint(1), result.HospitalID)
 	})
+
+	t.Run("Duplicate Name", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		input := &entities.Patient{FirstNameTH: "Test", LastNameTH: "A", NationalID: "1234567890123", HospitalID: 1}
+		mockRepo.On("FindDuplicates", uint(1), mock.Anything).Return([]entities.Patient{{ID: 5, FirstNameTH: "Test", LastNameTH: "A", HospitalID: 1}}, nil)
+
+		_, err := usecase.Create(input)
+		assert.ErrorIs(t, err, entities.ErrConflict)
+		assert.EqualError(t, err, "patient with this name already exists")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Duplicate National ID", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		input := &entities.Patient{FirstNameTH: "Test", LastNameTH: "A", NationalID: "1-2345-67890-12-3", HospitalID: 1}
+		mockRepo.On("FindDuplicates", uint(1), mock.Anything).Return([]entities.Patient{{ID: 5, FirstNameTH: "Other", LastNameTH: "B", NationalID: "1234567890123", HospitalID: 1}}, nil)
+
+		_, err := usecase.Create(input)
+		assert.EqualError(t, err, "patient with this national_id already exists")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestUpdatePatientUseCase(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		input := &entities.Patient{ID: 7, FirstNameTH: "Test", HospitalID: 1, Version: 3}
+		mockRepo.On("FindById", uint(7)).Return(&entities.Patient{ID: 7, HospitalID: 1, Version: 3}, nil)
+		mockRepo.On("Update", input).Return(input, nil)
+
+		result, err := usecase.Update(input, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, input, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Patient Of Another Hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		input := &entities.Patient{ID: 7, FirstNameTH: "Test", HospitalID: 1, Version: 3}
+		mockRepo.On("FindById", uint(7)).Return(&entities.Patient{ID: 7, HospitalID: 2, Version: 3}, nil)
+
+		result, err := usecase.Update(input, 1)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, entities.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Archived Patient", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		input := &entities.Patient{ID: 7, FirstNameTH: "Test", HospitalID: 1, Version: 3}
+		archivedAt := time.Now()
+		mockRepo.On("FindById", uint(7)).Return(&entities.Patient{ID: 7, HospitalID: 1, Version: 3, ArchivedAt: &archivedAt}, nil)
+
+		result, err := usecase.Update(input, 1)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, entities.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Keeps The Stored Hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		input := &entities.Patient{ID: 7, FirstNameTH: "Test", HospitalID: 2, Version: 3}
+		mockRepo.On("FindById", uint(7)).Return(&entities.Patient{ID: 7, HospitalID: 1, Version: 3}, nil)
+		mockRepo.On("Update", mock.MatchedBy(func(p *entities.Patient) bool {
+			return p.HospitalID == 1
+		})).Return(input, nil)
+
+		_, err := usecase.Update(input, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestFindAllPatientUseCase(t *testing.T) {
+	t.Run("Lists The Staff Hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		spec := &query.Spec{Page: 1, Limit: 10}
+		patients := []entities.Patient{{ID: 1, HospitalID: 2}}
+		mockRepo.On("FindAll", uint(2), spec).Return(patients, 11, nil)
+
+		result, totalPage, err := usecase.FindAll(spec, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, patients, result)
+		assert.Equal(t, 2, totalPage)
+	})
+
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
 		patients := []entities.Patient{
-			{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1},
+			{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1},
 		}
 		mockRepo.On("FindByAdvanceSearch", entities.PatientSearchInput{}, 1, 10).Return(patients, 1, nil)
 
@@ -66,7 +164,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByIdNational", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231"}
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231"}
 		input := entities.PatientSearchInput{NationalID: "11231231241231"}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{*patient}, 1, nil)
 
@@ -76,7 +174,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
@@ -95,7 +193,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByIdPassport", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, PassportID: "11231231241231"}
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, PassportID: "11231231241231"}
 		input := entities.PatientSearchInput{PassportID: "11231231241231"}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{*patient}, 1, nil)
 
@@ -106,7 +204,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
@@ -125,7 +223,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByFirstName", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231"}
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231"}
 		input := entities.PatientSearchInput{FirstName: "Test"}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{*patient}, 1, nil)
 
@@ -136,7 +234,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
@@ -155,7 +253,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByMiddleName", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", MiddleNameTH: "TestMid", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231"}
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", MiddleNameTH: "TestMid", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231"}
 		input := entities.PatientSearchInput{MiddleName: "TestMid"}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{*patient}, 1, nil)
 
@@ -166,7 +264,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
@@ -185,7 +283,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByLastName", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231"}
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231"}
 		input := entities.PatientSearchInput{LastName: "A"}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{*patient}, 1, nil)
 
@@ -196,7 +294,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
@@ -215,8 +313,8 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByBirthDate", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		date := time.Now()
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231", DateOfBirth: &date}
+		date := civil.DateOf(time.Now())
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231", DateOfBirth: &date}
 		input := entities.PatientSearchInput{DateOfBirth: &date}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{*patient}, 1, nil)
 
@@ -227,14 +325,14 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
 	t.Run("FindByBirthDateFailed", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		date := time.Now()
+		date := civil.DateOf(time.Now())
 		input := entities.PatientSearchInput{DateOfBirth: &date}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{}, 0, errors.New("failed to find patients"))
 
@@ -247,7 +345,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByPhoneNumber", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231", PhoneNumber: "0812345678"}
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231", PhoneNumber: "0812345678"}
 		input := entities.PatientSearchInput{PhoneNumber: "0812345678"}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{*patient}, 1, nil)
 
@@ -258,7 +356,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
@@ -277,7 +375,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByEmail", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231", Email: "test@gmail.com"}
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231", Email: "test@gmail.com"}
 		input := entities.PatientSearchInput{Email: "test@gmail.com"}
 		mockRepo.On("FindByAdvanceSearch", input, 1, 10).Return([]entities.Patient{*patient}, 1, nil)
 
@@ -288,7 +386,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
@@ -307,8 +405,8 @@ func TestFindAllPatientUseCase(t *testing.T) {
 	t.Run("FindByAllData", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		date := time.Now()
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231", Email: "test@gmail.com", PhoneNumber: "0812345678", DateOfBirth: &date}
+		date := civil.DateOf(time.Now())
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231", Email: "test@gmail.com", PhoneNumber: "0812345678", DateOfBirth: &date}
 		input := entities.PatientSearchInput{}
 		input.NationalID = patient.NationalID
 		input.PassportID = patient.PassportID
@@ -327,7 +425,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		assert.Equal(t, "A", result[0].LastNameTH)
 		assert.Equal(t, "Test", result[0].FirstNameEN)
 		assert.Equal(t, "A", result[0].LastNameEN)
-		assert.Equal(t, "M", result[0].Gender)
+		assert.Equal(t, consts.GenderMale, result[0].Gender)
 		assert.Equal(t, uint(1), result[0].HospitalID)
 	})
 
@@ -335,7 +433,7 @@ func TestFindAllPatientUseCase(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
 		input := entities.PatientSearchInput{}
-		date := time.Now()
+		date := civil.DateOf(time.Now())
 		input.NationalID = "11231231241231"
 		input.PassportID = "11231231241231"
 		input.FirstName = "Test"
@@ -359,8 +457,8 @@ func TestFindByIdNationalOrPassport(t *testing.T) {
 		mockHospitalRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
 		mockHospitalRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, NationalID: "11231231241231"}
-		mockRepo.On("FindByIdNationalOrPassport", "11231231241231").Return(patient, nil)
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, NationalID: "11231231241231"}
+		mockRepo.On("FindByIdNationalOrPassport", "11231231241231", uint(1)).Return(patient, nil)
 
 		patient, err := usecase.FindByIdNationalOrPassport("11231231241231", uint(1))
 
@@ -369,7 +467,7 @@ func TestFindByIdNationalOrPassport(t *testing.T) {
 		assert.Equal(t, "A", patient.LastNameTH)
 		assert.Equal(t, "Test", patient.FirstNameEN)
 		assert.Equal(t, "A", patient.LastNameEN)
-		assert.Equal(t, "M", patient.Gender)
+		assert.Equal(t, consts.GenderMale, patient.Gender)
 		assert.Equal(t, uint(1), patient.HospitalID)
 		assert.Equal(t, "11231231241231", patient.NationalID)
 		mockRepo.AssertExpectations(t)
@@ -378,7 +476,7 @@ func TestFindByIdNationalOrPassport(t *testing.T) {
 	t.Run("FindByNationalIdFailed", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		mockRepo.On("FindByIdNationalOrPassport", "11231231241231").Return((*entities.Patient)(nil), errors.New("failed to find patient"))
+		mockRepo.On("FindByIdNationalOrPassport", "11231231241231", uint(1)).Return((*entities.Patient)(nil), errors.New("failed to find patient"))
 
 		patient, err := usecase.FindByIdNationalOrPassport("11231231241231", uint(1))
 
@@ -392,8 +490,8 @@ func TestFindByIdNationalOrPassport(t *testing.T) {
 		mockHospitalRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
 		mockHospitalRepo.On("FindById", uint(1)).Return(&entities.Hospital{ID: 1}, nil)
-		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1, PassportID: "DB11241231"}
-		mockRepo.On("FindByIdNationalOrPassport", "DB11241231").Return(patient, nil)
+		patient := &entities.Patient{ID: 1, FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1, PassportID: "DB11241231"}
+		mockRepo.On("FindByIdNationalOrPassport", "DB11241231", uint(1)).Return(patient, nil)
 
 		patient, err := usecase.FindByIdNationalOrPassport("DB11241231", uint(1))
 
@@ -402,7 +500,7 @@ func TestFindByIdNationalOrPassport(t *testing.T) {
 		assert.Equal(t, "A", patient.LastNameTH)
 		assert.Equal(t, "Test", patient.FirstNameEN)
 		assert.Equal(t, "A", patient.LastNameEN)
-		assert.Equal(t, "M", patient.Gender)
+		assert.Equal(t, consts.GenderMale, patient.Gender)
 		assert.Equal(t, uint(1), patient.HospitalID)
 		assert.Equal(t, "DB11241231", patient.PassportID)
 		mockRepo.AssertExpectations(t)
@@ -411,7 +509,7 @@ func TestFindByIdNationalOrPassport(t *testing.T) {
 	t.Run("FindByPassportIdFailed", func(t *testing.T) {
 		mockRepo := mocks.NewMockPatientRepository()
 		usecase := usecases.NewPatientUseCase(mockRepo)
-		mockRepo.On("FindByIdNationalOrPassport", "DB11241231").Return((*entities.Patient)(nil), errors.New("failed to find patient"))
+		mockRepo.On("FindByIdNationalOrPassport", "DB11241231", uint(1)).Return((*entities.Patient)(nil), errors.New("failed to find patient"))
 
 		patient, err := usecase.FindByIdNationalOrPassport("DB11241231", uint(1))
 
@@ -421,3 +519,30 @@ func TestFindByIdNationalOrPassport(t *testing.T) {
 	})
 
 }
+
+func TestFindByIdPatientUseCase(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		patient := &entities.Patient{ID: 7, HospitalID: 1}
+		mockRepo.On("FindById", uint(7)).Return(patient, nil)
+
+		result, err := usecase.FindById(7, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, patient, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Another Hospital", func(t *testing.T) {
+		mockRepo := mocks.NewMockPatientRepository()
+		usecase := usecases.NewPatientUseCase(mockRepo)
+		mockRepo.On("FindById", uint(7)).Return(&entities.Patient{ID: 7, HospitalID: 2}, nil)
+
+		result, err := usecase.FindById(7, 1)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, entities.ErrNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -1,43 +1,160 @@
 package servers
 
 import (
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	_appointmentHttp "github.com/Teemo4621/Hospital-Api/modules/appointments/controllers"
+	_appointmentRepo "github.com/Teemo4621/Hospital-Api/modules/appointments/repositories"
+	_appointmentUseCase "github.com/Teemo4621/Hospital-Api/modules/appointments/usecases"
+	_auditHttp "github.com/Teemo4621/Hospital-Api/modules/audits/controllers"
+	_auditRepo "github.com/Teemo4621/Hospital-Api/modules/audits/repositories"
+	_auditUseCase "github.com/Teemo4621/Hospital-Api/modules/audits/usecases"
+	_bedHttp "github.com/Teemo4621/Hospital-Api/modules/beds/controllers"
+	_bedRepo "github.com/Teemo4621/Hospital-Api/modules/beds/repositories"
+	_bedUseCase "github.com/Teemo4621/Hospital-Api/modules/beds/usecases"
+	_departmentHttp "github.com/Teemo4621/Hospital-Api/modules/departments/controllers"
+	_departmentRepo "github.com/Teemo4621/Hospital-Api/modules/departments/repositories"
+	_departmentUseCase "github.com/Teemo4621/Hospital-Api/modules/departments/usecases"
+	_encounterHttp "github.com/Teemo4621/Hospital-Api/modules/encounters/controllers"
+	_encounterRepo "github.com/Teemo4621/Hospital-Api/modules/encounters/repositories"
+	_encounterUseCase "github.com/Teemo4621/Hospital-Api/modules/encounters/usecases"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
 	_hospitalHttp "github.com/Teemo4621/Hospital-Api/modules/hospitals/controllers"
 	_hospitalRepo "github.com/Teemo4621/Hospital-Api/modules/hospitals/repositories"
 	_hospitalUseCase "github.com/Teemo4621/Hospital-Api/modules/hospitals/usecases"
 	_patientHttp "github.com/Teemo4621/Hospital-Api/modules/patients/controllers"
 	_patientRepo "github.com/Teemo4621/Hospital-Api/modules/patients/repositories"
 	_patientUseCase "github.com/Teemo4621/Hospital-Api/modules/patients/usecases"
+	_settingsHttp "github.com/Teemo4621/Hospital-Api/modules/settings/controllers"
+	_settingsRepo "github.com/Teemo4621/Hospital-Api/modules/settings/repositories"
+	_settingsUseCase "github.com/Teemo4621/Hospital-Api/modules/settings/usecases"
 	_staffHttp "github.com/Teemo4621/Hospital-Api/modules/staffs/controllers"
 	_staffRepo "github.com/Teemo4621/Hospital-Api/modules/staffs/repositories"
 	_staffUseCase "github.com/Teemo4621/Hospital-Api/modules/staffs/usecases"
+	_statsHttp "github.com/Teemo4621/Hospital-Api/modules/stats/controllers"
+	_statsRepo "github.com/Teemo4621/Hospital-Api/modules/stats/repositories"
+	_statsUseCase "github.com/Teemo4621/Hospital-Api/modules/stats/usecases"
 	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
-	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/Teemo4621/Hospital-Api/pkgs/openapi"
+	"github.com/Teemo4621/Hospital-Api/pkgs/storages"
 	"github.com/gin-gonic/gin"
 )
 
+// v1 is kept until v1Sunset for clients that have not moved to v2 yet.
+var (
+	v1DeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	v1Sunset       = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
 func (s *Server) MapHandlers() error {
+	if mode := s.Cfg.App.Mode; mode != "" && mode != "production" {
+		doc, err := openapi.Load()
+		if err != nil {
+			return err
+		}
+		// Registered before the error middleware so that it also sees the
+		// problems that middleware writes.
+		s.App.Use(middlewares.OpenAPIValidator(doc))
+	}
+	s.App.Use(middlewares.ErrorMiddleware())
+	s.App.HandleMethodNotAllowed = true
+	s.App.NoRoute(middlewares.RouteNotFound)
+	s.App.NoMethod(middlewares.MethodNotAllowed)
+
 	apiGroup := s.App.Group("/api")
-	v1 := apiGroup.Group("/v1")
+	apiGroup.GET("/openapi.json", openapi.ServeJSON)
+	apiGroup.GET("/docs", openapi.ServeUI)
+	apiGroup.GET("/docs/assets/*filepath", openapi.ServeAssets)
 	authMiddleware := middlewares.NewAuthMiddleware(s.Cfg)
-	hospitalGroup := v1.Group("/hospitals")
 
 	hospitalRepository := _hospitalRepo.NewHospitalRepository(s.Db)
 	hospitalUseCase := _hospitalUseCase.NewHospitalUseCase(hospitalRepository)
-	_hospitalHttp.NewHospitalController(hospitalGroup, *s.Cfg, hospitalUseCase)
 
-	staffGroup := v1.Group("/staff")
+	hospitalCodeRepository := _hospitalRepo.NewHospitalCodeRepository(s.Db)
+	hospitalCodeUseCase := _hospitalUseCase.NewHospitalCodeUseCase(hospitalCodeRepository, hospitalRepository)
+
+	settingsRepository := _settingsRepo.NewHospitalSettingsRepository(s.Db)
+	settingsUseCase := _settingsUseCase.NewHospitalSettingsUseCase(settingsRepository)
+
+	statsRepository := _statsRepo.NewHospitalStatsRepository(s.Db)
+	statsUseCase := _statsUseCase.NewHospitalStatsUseCase(statsRepository, settingsUseCase)
+	s.jobs = append(s.jobs, func() { statsUseCase.StartRefresh(_statsUseCase.DefaultRefreshInterval) })
+
 	staffRepository := _staffRepo.NewStaffRepository(s.Db)
 	staffUseCase := _staffUseCase.NewStaffUseCase(staffRepository, hospitalRepository)
-	_staffHttp.NewStaffController(staffGroup, *s.Cfg, staffUseCase, *authMiddleware)
 
-	patientGroup := v1.Group("/patient")
+	departmentRepository := _departmentRepo.NewDepartmentRepository(s.Db)
+	departmentUseCase := _departmentUseCase.NewDepartmentUseCase(departmentRepository, staffRepository)
+
+	auditRepository := _auditRepo.NewAuditLogRepository(s.Db)
+	auditUseCase := _auditUseCase.NewAuditLogUseCase(auditRepository)
+
 	patientRepository := _patientRepo.NewPatientRepository(s.Db)
 	patientUseCase := _patientUseCase.NewPatientUseCase(patientRepository)
-	_patientHttp.NewPatientController(patientGroup, *s.Cfg, patientUseCase, *authMiddleware)
 
-	s.App.Use(func(c *gin.Context) {
-		utils.ErrorResponse(c, "end point not found")
-	})
+	patientDemographicRepository := _patientRepo.NewPatientDemographicRepository(s.Db)
+	patientDemographicUseCase := _patientUseCase.NewPatientDemographicUseCase(patientDemographicRepository, patientRepository)
+
+	storage, err := storages.NewStorage(s.Cfg.Storage)
+	if err != nil {
+		return err
+	}
+	patientAttachmentRepository := _patientRepo.NewPatientAttachmentRepository(s.Db)
+	patientAttachmentUseCase := _patientUseCase.NewPatientAttachmentUseCase(patientAttachmentRepository, patientRepository, storage, s.Cfg.Storage.MaxUploadSize)
+
+	patientImportRepository := _patientRepo.NewPatientImportRepository(s.Db)
+	patientImportUseCase := _patientUseCase.NewPatientImportUseCase(patientImportRepository, storage)
+
+	patientExportRepository := _patientRepo.NewPatientExportRepository(s.Db)
+	patientExportUseCase := _patientUseCase.NewPatientExportUseCase(patientExportRepository, patientRepository, storage)
+
+	patientTransferRepository := _patientRepo.NewPatientTransferRepository(s.Db)
+	patientTransferUseCase := _patientUseCase.NewPatientTransferUseCase(patientTransferRepository, patientRepository, hospitalRepository)
+
+	encounterRepository := _encounterRepo.NewEncounterRepository(s.Db)
+	encounterUseCase := _encounterUseCase.NewEncounterUseCase(encounterRepository, patientRepository, staffRepository)
+
+	appointmentRepository := _appointmentRepo.NewAppointmentRepository(s.Db)
+	appointmentUseCase := _appointmentUseCase.NewAppointmentUseCase(appointmentRepository, patientRepository, staffRepository, settingsUseCase)
+
+	bedRepository := _bedRepo.NewBedRepository(s.Db)
+	bedUseCase := _bedUseCase.NewBedUseCase(bedRepository, encounterRepository)
+
+	// v1 and v2 share the use cases and differ only in the patient routes:
+	// v2 maps them as resources under /patients.
+	versions := []struct {
+		group    *gin.RouterGroup
+		patients string
+		mapCore  func(c *gin.RouterGroup, cfg configs.Config, patientUsecase entities.PatientUseCase, auditUsecase entities.AuditLogUseCase, authMiddleware middlewares.AuthMiddleware)
+	}{
+		{apiGroup.Group("/v1", middlewares.Deprecated(v1DeprecatedAt, v1Sunset, "/api/v2")), "/patient", _patientHttp.NewPatientController},
+		{apiGroup.Group("/v2"), "/patients", _patientHttp.NewPatientV2Controller},
+	}
+
+	for _, version := range versions {
+		hospitalGroup := version.group.Group("/hospitals")
+		_hospitalHttp.NewHospitalController(hospitalGroup, *s.Cfg, hospitalUseCase, *authMiddleware)
+		_hospitalHttp.NewHospitalCodeController(hospitalGroup, *s.Cfg, hospitalCodeUseCase, *authMiddleware)
+		_settingsHttp.NewHospitalSettingsController(hospitalGroup.Group("/:id/settings"), *s.Cfg, settingsUseCase, *authMiddleware)
+		_statsHttp.NewHospitalStatsController(hospitalGroup.Group("/:id/stats"), *s.Cfg, statsUseCase, *authMiddleware)
+		_departmentHttp.NewDepartmentController(hospitalGroup.Group("/:id/departments"), *s.Cfg, departmentUseCase, *authMiddleware)
+
+		_staffHttp.NewStaffController(version.group.Group("/staff"), *s.Cfg, staffUseCase, *authMiddleware)
+		_auditHttp.NewAuditLogController(version.group.Group("/audits"), *s.Cfg, auditUseCase, *authMiddleware)
+
+		patientGroup := version.group.Group(version.patients)
+		version.mapCore(patientGroup, *s.Cfg, patientUseCase, auditUseCase, *authMiddleware)
+		_patientHttp.NewPatientDemographicController(patientGroup, *s.Cfg, patientDemographicUseCase, *authMiddleware)
+		_patientHttp.NewPatientAttachmentController(patientGroup, *s.Cfg, patientAttachmentUseCase, *authMiddleware)
+		_patientHttp.NewPatientImportController(patientGroup, *s.Cfg, patientImportUseCase, *authMiddleware)
+		_patientHttp.NewPatientExportController(patientGroup, *s.Cfg, patientExportUseCase, auditUseCase, *authMiddleware)
+		_patientHttp.NewPatientTransferController(patientGroup, *s.Cfg, patientTransferUseCase, *authMiddleware)
+
+		_encounterHttp.NewEncounterController(version.group.Group("/encounters"), *s.Cfg, encounterUseCase, *authMiddleware)
+		_appointmentHttp.NewAppointmentController(version.group.Group("/appointments"), *s.Cfg, appointmentUseCase, *authMiddleware)
+		_bedHttp.NewBedController(version.group.Group("/beds"), *s.Cfg, bedUseCase, *authMiddleware)
+	}
 
 	return nil
 }
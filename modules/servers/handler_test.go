@@ -0,0 +1,86 @@
+package servers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/servers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutesAreDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := servers.NewServer(&configs.Config{}, nil)
+	require.NoError(t, server.MapHandlers())
+
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	mapped := make(map[string]bool)
+	for _, route := range server.App.Routes() {
+		mapped[strings.ToLower(route.Method)+" "+openapi.PathTemplate(route.Path)] = true
+
+		_, ok := doc.Operation(route.Method, route.Path)
+		assert.True(t, ok, "%s %s is not in pkgs/openapi/openapi.json", route.Method, route.Path)
+	}
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			assert.True(t, mapped[method+" "+path], "%s %s is documented but not mapped", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestV1IsDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := servers.NewServer(&configs.Config{}, nil)
+	require.NoError(t, server.MapHandlers())
+
+	tests := []struct {
+		path       string
+		deprecated bool
+	}{
+		{"/api/v1/staff/me", true},
+		{"/api/v2/staff/me", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			resp := httptest.NewRecorder()
+
+			server.App.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusUnauthorized, resp.Code)
+			assert.Equal(t, tt.deprecated, resp.Header().Get("Deprecation") != "")
+			assert.Equal(t, tt.deprecated, resp.Header().Get("Sunset") != "")
+		})
+	}
+}
+
+func TestDocsLoadNothingFromACDN(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := servers.NewServer(&configs.Config{}, nil)
+	require.NoError(t, server.MapHandlers())
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/docs", nil)
+	resp := httptest.NewRecorder()
+	server.App.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `src="/api/docs/assets/swagger-ui-bundle.js"`)
+	assert.NotContains(t, resp.Body.String(), "https://")
+
+	for _, path := range []string{"/api/docs/assets/README.md", "/api/docs/assets/missing.js"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		resp := httptest.NewRecorder()
+		server.App.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code, path)
+	}
+}
@@ -13,6 +13,9 @@ type Server struct {
 	App *gin.Engine
 	Cfg *configs.Config
 	Db  *gorm.DB
+	// jobs are the background tasks MapHandlers sets up. Start runs them,
+	// so routes can be mapped without a database, as the route test does.
+	jobs []func()
 }
 
 func NewServer(cfg *configs.Config, db *gorm.DB) *Server {
@@ -28,6 +31,9 @@ func (s *Server) Start() {
 		log.Fatalln(err.Error())
 		panic(err)
 	}
+	for _, job := range s.jobs {
+		job()
+	}
 
 	ginConnURL, err := utils.BuildConnectionUrl("gin", *s.Cfg)
 	if err != nil {
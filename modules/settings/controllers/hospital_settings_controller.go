@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type HospitalSettingsCon struct {
+	Cfg                     configs.Config
+	HospitalSettingsUsecase entities.HospitalSettingsUseCase
+	AuthMiddleware          middlewares.AuthMiddleware
+}
+
+// NewHospitalSettingsController registers the routes on a group mounted at
+// /hospitals/:id/settings. Only administrators of the hospital and system
+// administrators may use them.
+func NewHospitalSettingsController(c *gin.RouterGroup, cfg configs.Config, hospitalSettingsUsecase entities.HospitalSettingsUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &HospitalSettingsCon{
+		Cfg:                     cfg,
+		HospitalSettingsUsecase: hospitalSettingsUsecase,
+		AuthMiddleware:          authMiddleware,
+	}
+
+	c.Use(
+		controller.AuthMiddleware.JwtAuthentication(),
+		controller.AuthMiddleware.RequireScope(consts.ScopeSettingsManage),
+		controller.AuthMiddleware.RequireHospital("id"),
+	)
+
+	c.GET("/", controller.Get)
+	c.PUT("/", controller.Update)
+	c.GET("/history", controller.FindHistory)
+}
+
+func (a *HospitalSettingsCon) Get(c *gin.Context) {
+	hospitalId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	settings, err := a.HospitalSettingsUsecase.Get(uint(hospitalId))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, settings.Version)
+	utils.OkResponse(c, settings)
+}
+
+// Update replaces the settings. It needs the version from the settings'
+// ETag in If-Match, which is "0" while the hospital is on the defaults.
+func (a *HospitalSettingsCon) Update(c *gin.Context) {
+	userData, exists := c.Get("user_data")
+	if !exists {
+		utils.UnauthorizedResponse(c, "Unauthorized")
+		return
+	}
+
+	hospitalId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	var req entities.HospitalSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	version, ok := utils.IfMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	settings, err := a.HospitalSettingsUsecase.Update(uint(hospitalId), version, &req, userData.(*entities.JwtClaim).Id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SetETag(c, settings.Version)
+	utils.OkResponse(c, settings)
+}
+
+func (a *HospitalSettingsCon) FindHistory(c *gin.Context) {
+	hospitalId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	page, limit, err := query.ParsePage(c.Request.URL.Query(), 20, 100)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	changes, totalPage, err := a.HospitalSettingsUsecase.FindHistory(uint(hospitalId), page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if len(changes) == 0 {
+		changes = []entities.HospitalSettingsChange{}
+	}
+
+	utils.OkResponse(c, gin.H{
+		"changes": changes,
+		"meta": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"page_total": totalPage,
+		},
+	})
+}
@@ -0,0 +1,157 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/settings/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func testConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	return cfg
+}
+
+func setupRouter(usecase entities.HospitalSettingsUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := testConfig()
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/hospitals/:id/settings")
+	controllers.NewHospitalSettingsController(group, *cfg, usecase, *authMiddleware)
+	return r
+}
+
+func withRole(req *http.Request, role consts.StaffRole) *http.Request {
+	token, _ := utils.GenerateAccessToken(testConfig(), &entities.Jwtpassport{Id: 7, HospitalID: 1, Role: string(role), Scopes: role.Scopes()})
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	return req
+}
+
+const settingsBody = `{"timezone":"Asia/Bangkok","default_locale":"th","password_policy":{"min_length":10},"session_timeout_minutes":30}`
+
+// ----------- Tests ----------- //
+
+func TestGetHospitalSettingsHandler(t *testing.T) {
+	t.Run("Admin Of Hospital", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalSettingsUseCase()
+		mockUsecase.On("Get", uint(1)).Return(entities.DefaultHospitalSettings(1), nil)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/settings/", nil)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"0"`, resp.Header().Get("ETag"))
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Doctor Is Forbidden", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalSettingsUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/settings/", nil)
+		withRole(req, consts.StaffRoleDoctor)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockUsecase.AssertNotCalled(t, "Get", mock.Anything)
+	})
+
+	t.Run("Admin Of Another Hospital", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalSettingsUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/2/settings/", nil)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		mockUsecase.AssertNotCalled(t, "Get", mock.Anything)
+	})
+}
+
+func TestUpdateHospitalSettingsHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalSettingsUseCase()
+		mockUsecase.On("Update", uint(1), uint(0), mock.MatchedBy(func(req *entities.HospitalSettingsRequest) bool {
+			return req.PasswordPolicy.MinLength == 10 && req.SessionTimeoutMinutes == 30
+		}), uint(7)).Return(&entities.HospitalSettings{HospitalID: 1, Version: 1}, nil)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1/settings/", bytes.NewBufferString(settingsBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"0"`)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, `"1"`, resp.Header().Get("ETag"))
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Setting", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalSettingsUseCase()
+		mockUsecase.On("Update", uint(1), uint(0), mock.Anything, uint(7)).
+			Return((*entities.HospitalSettings)(nil), &entities.FieldError{Field: "timezone", Message: "timezone must be an IANA time zone such as Asia/Bangkok"})
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1/settings/", bytes.NewBufferString(settingsBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"0"`)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Missing If-Match", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalSettingsUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodPut, "/hospitals/1/settings/", bytes.NewBufferString(settingsBody))
+		req.Header.Set("Content-Type", "application/json")
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+	})
+}
+
+func TestHospitalSettingsHistoryHandler(t *testing.T) {
+	mockUsecase := mocks.NewMockHospitalSettingsUseCase()
+	mockUsecase.On("FindHistory", uint(1), 1, 20).Return([]entities.HospitalSettingsChange{{ID: 1, Field: "mfa_required"}}, 1, nil)
+
+	r := setupRouter(mockUsecase)
+	req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/settings/history", nil)
+	withRole(req, consts.StaffRoleSysAdmin)
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "mfa_required")
+	mockUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"gorm.io/gorm"
+)
+
+type HospitalSettingsRepo struct {
+	Db *gorm.DB
+}
+
+func NewHospitalSettingsRepository(db *gorm.DB) entities.HospitalSettingsRepository {
+	return &HospitalSettingsRepo{Db: db}
+}
+
+func (r *HospitalSettingsRepo) FindByHospital(hospitalId uint) (*entities.HospitalSettings, error) {
+	var settings entities.HospitalSettings
+	if err := r.Db.First(&settings, "hospital_id = ?", hospitalId).Error; err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// Save writes the settings and their change history in one transaction.
+// Version 0 means the hospital is still on the defaults, so the row is
+// inserted; a concurrent first save then fails on the primary key and is
+// reported as a version conflict like any other lost update.
+func (r *HospitalSettingsRepo) Save(settings *entities.HospitalSettings, changes []entities.HospitalSettingsChange) (*entities.HospitalSettings, error) {
+	version := settings.Version
+	settings.Version = version + 1
+
+	err := r.Db.Transaction(func(tx *gorm.DB) error {
+		if version == 0 {
+			if err := tx.Create(settings).Error; err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return entities.ErrVersionConflict
+				}
+				return err
+			}
+		} else {
+			result := tx.Model(settings).
+				Where("version = ?", version).
+				Select("*").
+				Omit("hospital_id").
+				Updates(settings)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return entities.ErrVersionConflict
+			}
+		}
+
+		for i := range changes {
+			changes[i].Version = settings.Version
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return tx.Create(&changes).Error
+	})
+	if err != nil {
+		settings.Version = version
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (r *HospitalSettingsRepo) FindHistory(hospitalId uint, page int, limit int) ([]entities.HospitalSettingsChange, int64, error) {
+	var changes []entities.HospitalSettingsChange
+	var totalCount int64
+
+	query := r.Db.Model(&entities.HospitalSettingsChange{}).Where("hospital_id = ?", hospitalId)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("changed_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&changes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return changes, totalCount, nil
+}
@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"gorm.io/gorm"
+)
+
+// settingsFields lists the settings recorded in the change history, with
+// how each value is written there.
+var settingsFields = []struct {
+	name  string
+	value func(s *entities.HospitalSettings) string
+}{
+	{"timezone", func(s *entities.HospitalSettings) string { return s.Timezone }},
+	{"default_locale", func(s *entities.HospitalSettings) string { return string(s.DefaultLocale) }},
+	{"hn_format", func(s *entities.HospitalSettings) string { return s.HNFormat }},
+	{"password_policy.min_length", func(s *entities.HospitalSettings) string { return strconv.Itoa(s.PasswordPolicy.MinLength) }},
+	{"password_policy.require_upper", func(s *entities.HospitalSettings) string { return strconv.FormatBool(s.PasswordPolicy.RequireUpper) }},
+	{"password_policy.require_digit", func(s *entities.HospitalSettings) string { return strconv.FormatBool(s.PasswordPolicy.RequireDigit) }},
+	{"password_policy.require_symbol", func(s *entities.HospitalSettings) string { return strconv.FormatBool(s.PasswordPolicy.RequireSymbol) }},
+	{"password_policy.max_age_days", func(s *entities.HospitalSettings) string { return strconv.Itoa(s.PasswordPolicy.MaxAgeDays) }},
+	{"password_policy.history_to_check", func(s *entities.HospitalSettings) string { return strconv.Itoa(s.PasswordPolicy.HistoryToCheck) }},
+	{"mfa_required", func(s *entities.HospitalSettings) string { return strconv.FormatBool(s.MFARequired) }},
+	{"session_timeout_minutes", func(s *entities.HospitalSettings) string { return strconv.Itoa(s.SessionTimeoutMinutes) }},
+	{"retention_days", func(s *entities.HospitalSettings) string { return strconv.Itoa(s.RetentionDays) }},
+	{"enabled_modules", func(s *entities.HospitalSettings) string {
+		modules := make([]string, len(s.EnabledModules))
+		for i, module := range s.EnabledModules {
+			modules[i] = string(module)
+		}
+		return strings.Join(modules, ",")
+	}},
+}
+
+// HospitalSettingsUseCase keeps the settings it has read in memory. The
+// entry of a hospital is dropped whenever its settings are saved through
+// this use case.
+type HospitalSettingsUseCase struct {
+	repo entities.HospitalSettingsRepository
+
+	mu    sync.RWMutex
+	cache map[uint]entities.HospitalSettings
+}
+
+func NewHospitalSettingsUseCase(repo entities.HospitalSettingsRepository) entities.HospitalSettingsUseCase {
+	return &HospitalSettingsUseCase{repo: repo, cache: make(map[uint]entities.HospitalSettings)}
+}
+
+// Get returns the hospital's settings, or the defaults when it has never
+// saved any. Callers get their own copy and may modify it.
+func (u *HospitalSettingsUseCase) Get(hospitalId uint) (*entities.HospitalSettings, error) {
+	u.mu.RLock()
+	cached, ok := u.cache[hospitalId]
+	u.mu.RUnlock()
+	if ok {
+		return copySettings(&cached), nil
+	}
+
+	settings, err := u.repo.FindByHospital(hospitalId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		settings, err = entities.DefaultHospitalSettings(hospitalId), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	u.cache[hospitalId] = *copySettings(settings)
+	u.mu.Unlock()
+
+	return settings, nil
+}
+
+// Update replaces the settings and records every field that changed. An
+// update that changes nothing is not saved.
+func (u *HospitalSettingsUseCase) Update(hospitalId uint, version uint, req *entities.HospitalSettingsRequest, staffId uint) (*entities.HospitalSettings, error) {
+	current, err := u.Get(hospitalId)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version != version {
+		return nil, entities.ErrVersionConflict
+	}
+
+	next := copySettings(current)
+	next.Timezone = strings.TrimSpace(req.Timezone)
+	next.DefaultLocale = req.DefaultLocale
+	next.HNFormat = strings.TrimSpace(req.HNFormat)
+	next.PasswordPolicy = req.PasswordPolicy
+	next.MFARequired = req.MFARequired
+	next.SessionTimeoutMinutes = req.SessionTimeoutMinutes
+	next.RetentionDays = req.RetentionDays
+	next.EnabledModules = append([]consts.HospitalModule{}, req.EnabledModules...)
+	next.UpdatedByID = staffId
+
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var changes []entities.HospitalSettingsChange
+	for _, field := range settingsFields {
+		oldValue, newValue := field.value(current), field.value(next)
+		if oldValue != newValue {
+			changes = append(changes, entities.HospitalSettingsChange{
+				HospitalID:  hospitalId,
+				Field:       field.name,
+				OldValue:    oldValue,
+				NewValue:    newValue,
+				ChangedByID: staffId,
+				ChangedAt:   now,
+			})
+		}
+	}
+	if len(changes) == 0 {
+		return current, nil
+	}
+
+	saved, err := u.repo.Save(next, changes)
+
+	// Drop the entry even when saving failed: a version conflict means the
+	// cached copy is stale.
+	u.mu.Lock()
+	delete(u.cache, hospitalId)
+	u.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (u *HospitalSettingsUseCase) FindHistory(hospitalId uint, page int, limit int) ([]entities.HospitalSettingsChange, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	changes, totalCount, err := u.repo.FindHistory(hospitalId, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	totalPage := int((totalCount + int64(limit) - 1) / int64(limit))
+	return changes, totalPage, nil
+}
+
+func copySettings(settings *entities.HospitalSettings) *entities.HospitalSettings {
+	copied := *settings
+	copied.EnabledModules = append([]consts.HospitalModule(nil), settings.EnabledModules...)
+	return &copied
+}
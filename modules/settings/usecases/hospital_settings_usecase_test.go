@@ -0,0 +1,144 @@
+package usecases_test
+
+import (
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/settings/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func settingsRequest() *entities.HospitalSettingsRequest {
+	return &entities.HospitalSettingsRequest{
+		Timezone:              "Asia/Bangkok",
+		DefaultLocale:         consts.LocaleTH,
+		PasswordPolicy:        entities.PasswordPolicy{MinLength: 8, RequireDigit: true},
+		SessionTimeoutMinutes: 60,
+		EnabledModules:        consts.HospitalModules,
+	}
+}
+
+func TestGetHospitalSettings(t *testing.T) {
+	t.Run("Defaults When Never Saved", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalSettingsRepository()
+		usecase := usecases.NewHospitalSettingsUseCase(mockRepo)
+
+		mockRepo.On("FindByHospital", uint(1)).Return((*entities.HospitalSettings)(nil), gorm.ErrRecordNotFound)
+
+		settings, err := usecase.Get(1)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(0), settings.Version)
+		assert.Equal(t, "Asia/Bangkok", settings.Timezone)
+		assert.Equal(t, consts.HospitalModules, settings.EnabledModules)
+	})
+
+	t.Run("Cached After First Read", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalSettingsRepository()
+		usecase := usecases.NewHospitalSettingsUseCase(mockRepo)
+
+		stored := entities.DefaultHospitalSettings(1)
+		stored.Version = 2
+		mockRepo.On("FindByHospital", uint(1)).Return(stored, nil).Once()
+
+		first, err := usecase.Get(1)
+		assert.NoError(t, err)
+		first.EnabledModules[0] = "changed"
+
+		second, err := usecase.Get(1)
+		assert.NoError(t, err)
+		assert.Equal(t, consts.HospitalModulePatients, second.EnabledModules[0])
+		mockRepo.AssertNumberOfCalls(t, "FindByHospital", 1)
+	})
+}
+
+func TestUpdateHospitalSettings(t *testing.T) {
+	t.Run("Records Changed Fields And Invalidates Cache", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalSettingsRepository()
+		usecase := usecases.NewHospitalSettingsUseCase(mockRepo)
+
+		mockRepo.On("FindByHospital", uint(1)).Return((*entities.HospitalSettings)(nil), gorm.ErrRecordNotFound).Once()
+		mockRepo.On("Save", mock.AnythingOfType("*entities.HospitalSettings"), mock.MatchedBy(func(changes []entities.HospitalSettingsChange) bool {
+			return len(changes) == 2 &&
+				changes[0].Field == "mfa_required" && changes[0].OldValue == "false" && changes[0].NewValue == "true" &&
+				changes[1].Field == "session_timeout_minutes" && changes[1].NewValue == "30" &&
+				changes[0].ChangedByID == 7
+		})).Return(&entities.HospitalSettings{HospitalID: 1, Version: 1}, nil)
+
+		req := settingsRequest()
+		req.MFARequired = true
+		req.SessionTimeoutMinutes = 30
+
+		settings, err := usecase.Update(1, 0, req, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), settings.Version)
+
+		saved := entities.DefaultHospitalSettings(1)
+		saved.Version = 1
+		mockRepo.On("FindByHospital", uint(1)).Return(saved, nil).Once()
+
+		reloaded, err := usecase.Get(1)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), reloaded.Version)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("No Change Is Not Saved", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalSettingsRepository()
+		usecase := usecases.NewHospitalSettingsUseCase(mockRepo)
+
+		mockRepo.On("FindByHospital", uint(1)).Return((*entities.HospitalSettings)(nil), gorm.ErrRecordNotFound)
+
+		_, err := usecase.Update(1, 0, settingsRequest(), 7)
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Stale Version", func(t *testing.T) {
+		mockRepo := mocks.NewMockHospitalSettingsRepository()
+		usecase := usecases.NewHospitalSettingsUseCase(mockRepo)
+
+		stored := entities.DefaultHospitalSettings(1)
+		stored.Version = 4
+		mockRepo.On("FindByHospital", uint(1)).Return(stored, nil)
+
+		_, err := usecase.Update(1, 3, settingsRequest(), 7)
+		assert.ErrorIs(t, err, entities.ErrVersionConflict)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		cases := map[string]struct {
+			change  func(req *entities.HospitalSettingsRequest)
+			message string
+		}{
+			"Unknown Timezone": {func(req *entities.HospitalSettingsRequest) { req.Timezone = "Mars/Olympus" }, "timezone must be an IANA time zone such as Asia/Bangkok"},
+			"Locale":           {func(req *entities.HospitalSettingsRequest) { req.DefaultLocale = "jp" }, "default_locale must be th or en"},
+			"HN Format":        {func(req *entities.HospitalSettingsRequest) { req.HNFormat = "[0-9" }, "hn_format must be a valid regular expression"},
+			"Short Password":   {func(req *entities.HospitalSettingsRequest) { req.PasswordPolicy.MinLength = 4 }, "password_policy.min_length must be between 8 and 128"},
+			"Session Timeout":  {func(req *entities.HospitalSettingsRequest) { req.SessionTimeoutMinutes = 2 }, "session_timeout_minutes must be between 5 and 1440"},
+			"Retention":        {func(req *entities.HospitalSettingsRequest) { req.RetentionDays = 30 }, "retention_days must be 0 or at least 3650"},
+			"Unknown Module": {func(req *entities.HospitalSettingsRequest) {
+				req.EnabledModules = []consts.HospitalModule{"billing"}
+			}, `unknown module "billing"`},
+		}
+
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				mockRepo := mocks.NewMockHospitalSettingsRepository()
+				usecase := usecases.NewHospitalSettingsUseCase(mockRepo)
+				mockRepo.On("FindByHospital", uint(1)).Return((*entities.HospitalSettings)(nil), gorm.ErrRecordNotFound)
+
+				req := settingsRequest()
+				tc.change(req)
+
+				_, err := usecase.Update(1, 0, req, 7)
+				var fieldErr *entities.FieldError
+				assert.ErrorAs(t, err, &fieldErr)
+				assert.EqualError(t, err, tc.message)
+			})
+		}
+	})
+}
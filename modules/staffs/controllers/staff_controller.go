@@ -1,6 +1,9 @@
 package controllers
 
 import (
+	"errors"
+	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/Teemo4621/Hospital-Api/configs"
@@ -30,47 +33,26 @@ func NewStaffController(c *gin.RouterGroup, cfg configs.Config, staffUsecase ent
 	c.GET("/me", controller.AuthMiddleware.JwtAuthentication(), controller.Me)
 }
 
+// FindAll lists staff. It accepts the StaffQuery parameters, for example
+// filter[role][in]=doctor,nurse&sort=last_name_en,-created_at&include=hospital.
 func (a *StaffCon) FindAll(c *gin.Context) {
-	page := c.Query("page")
-	limit := c.Query("limit")
-
-	if page == "" {
-		page = "1"
-	}
-
-	if limit == "" {
-		limit = "10"
-	}
-
-	limitInt, err := strconv.Atoi(limit)
+	spec, err := entities.StaffQuery.Parse(c.Request.URL.Query())
 	if err != nil {
-		utils.BadRequestResponse(c, "limit is required and must be an integer")
+		c.Error(err)
 		return
 	}
 
-	pageInt, err := strconv.Atoi(page)
+	staffs, totalPage, err := a.StaffUsecase.FindAll(spec)
 	if err != nil {
-		utils.BadRequestResponse(c, "page is required and must be an integer")
+		c.Error(err)
 		return
 	}
 
-	if pageInt < 1 {
-		pageInt = 1
-	}
-
-	if limitInt < 1 {
-		limitInt = 10
-	}
-
-	staffs, totalPage, err := a.StaffUsecase.FindAll(pageInt, limitInt)
-	if err != nil {
-		utils.ErrorResponse(c, err.Error())
-		return
-	}
-
-	var staffFindResponse []entities.StaffResponse
+	locale := utils.RequestLocale(c)
+	withHospital := slices.Contains(spec.Includes, "hospital")
+	staffFindResponse := []entities.StaffResponse{}
 	for _, staff := range staffs {
-		staffFindResponse = append(staffFindResponse, entities.StaffResponse{
+		response := entities.StaffResponse{
 			ID:           staff.ID,
 			FirstNameTH:  staff.FirstNameTH,
 			MiddleNameTH: staff.MiddleNameTH,
@@ -79,19 +61,27 @@ func (a *StaffCon) FindAll(c *gin.Context) {
 			MiddleNameEN: staff.MiddleNameEN,
 			LastNameEN:   staff.LastNameEN,
 			Gender:       staff.Gender,
-			Hospital:     staff.Hospital,
-		})
+			GenderLabel:  staff.Gender.Label(locale),
+			Role:         staff.Role,
+			Version:      staff.Version,
+		}
+		if withHospital {
+			response.Hospital = &staff.Hospital
+		}
+		staffFindResponse = append(staffFindResponse, response)
 	}
 
-	if len(staffFindResponse) == 0 {
-		staffFindResponse = []entities.StaffResponse{}
+	picked, err := spec.Pick(staffFindResponse)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
 	response := gin.H{
-		"staffs": staffFindResponse,
+		"staffs": picked,
 		"meta": gin.H{
-			"page":       pageInt,
-			"limit":      limitInt,
+			"page":       spec.Page,
+			"limit":      spec.Limit,
 			"page_total": totalPage,
 		},
 	}
@@ -110,7 +100,7 @@ func (a *StaffCon) FindById(c *gin.Context) {
 
 	staff, err := a.StaffUsecase.FindById(uint(staffID))
 	if err != nil {
-		utils.NotFoundResponse(c, "staff not found")
+		c.Error(err)
 		return
 	}
 
@@ -123,24 +113,29 @@ func (a *StaffCon) FindById(c *gin.Context) {
 		MiddleNameEN: staff.MiddleNameEN,
 		LastNameEN:   staff.LastNameEN,
 		Gender:       staff.Gender,
-		Hospital:     staff.Hospital,
+		GenderLabel:  staff.Gender.Label(utils.RequestLocale(c)),
+		Role:         staff.Role,
+		Version:      staff.Version,
+		Hospital:     &staff.Hospital,
 	}
 
+	utils.SetETag(c, staff.Version)
 	utils.OkResponse(c, staffFindResponse)
 }
 
 func (a *StaffCon) Create(c *gin.Context) {
 	var staffCreateRequest entities.StaffCreateRequest
 	if err := c.ShouldBindJSON(&staffCreateRequest); err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		utils.BindErrorResponse(c, err)
 		return
 	}
 
 	staff, err := a.StaffUsecase.Create(&staffCreateRequest)
 	if err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
+	staff.GenderLabel = staff.Gender.Label(utils.RequestLocale(c))
 
 	utils.OkResponse(c, staff)
 }
@@ -148,16 +143,26 @@ func (a *StaffCon) Create(c *gin.Context) {
 func (a *StaffCon) Login(c *gin.Context) {
 	var loginRequest entities.StaffLoginRequest
 	if err := c.ShouldBindJSON(&loginRequest); err != nil {
-		utils.BadRequestResponse(c, err.Error())
+		utils.BindErrorResponse(c, err)
 		return
 	}
 
+	// Every rejected login gets the same answer, so it does not reveal
+	// which of the username, password or hospital was wrong.
 	staff, err := a.StaffUsecase.Login(&a.Cfg, &loginRequest)
+	if errors.Is(err, entities.ErrNotFound) || errors.Is(err, entities.ErrValidation) {
+		utils.WriteProblem(c, utils.NewProblem(http.StatusNotFound, "invalid_credentials", "username, password or hospital is invalid"))
+		return
+	}
 	if err != nil {
-		utils.NotFoundResponse(c, "username, password or hospital is invalid")
+		c.Error(err)
 		return
 	}
 
+	if staff.Staff != nil {
+		staff.Staff.GenderLabel = staff.Staff.Gender.Label(utils.RequestLocale(c))
+	}
+
 	c.SetCookie("access_token", staff.AccessToken, 60*60, "/", "localhost", false, true)
 
 	utils.OkResponse(c, staff)
@@ -172,16 +177,22 @@ func (a *StaffCon) Update(c *gin.Context) {
 
 	var staffReq entities.StaffUpdateRequest
 	if err := c.ShouldBindJSON(&staffReq); err != nil {
-		utils.BadRequestResponse(c, "bad request")
+		utils.BindErrorResponse(c, err)
+		return
+	}
+
+	version, ok := utils.IfMatchVersion(c)
+	if !ok {
 		return
 	}
 
 	staffID := userData.(*entities.JwtClaim).Id
 	staffReq.ID = staffID
+	staffReq.Version = version
 
 	updatedStaff, err := a.StaffUsecase.Update(&staffReq)
 	if err != nil {
-		utils.NotFoundResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
@@ -195,9 +206,13 @@ func (a *StaffCon) Update(c *gin.Context) {
 		MiddleNameEN: updatedStaff.MiddleNameEN,
 		LastNameEN:   updatedStaff.LastNameEN,
 		Gender:       updatedStaff.Gender,
+		GenderLabel:  updatedStaff.Gender.Label(utils.RequestLocale(c)),
+		Role:         updatedStaff.Role,
+		Version:      updatedStaff.Version,
 		Hospital:     updatedStaff.Hospital,
 	}
 
+	utils.SetETag(c, updatedStaff.Version)
 	utils.OkResponse(c, updatedStaffFindResponse)
 }
 
@@ -213,7 +228,7 @@ func (a *StaffCon) Me(c *gin.Context) {
 
 	staff, err := a.StaffUsecase.FindById(staffID)
 	if err != nil {
-		utils.NotFoundResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
@@ -227,8 +242,12 @@ func (a *StaffCon) Me(c *gin.Context) {
 		MiddleNameEN: staff.MiddleNameEN,
 		LastNameEN:   staff.LastNameEN,
 		Gender:       staff.Gender,
+		GenderLabel:  staff.Gender.Label(utils.RequestLocale(c)),
+		Role:         staff.Role,
+		Version:      staff.Version,
 		Hospital:     staff.Hospital,
 	}
 
+	utils.SetETag(c, staff.Version)
 	utils.OkResponse(c, staffFindResponse)
 }
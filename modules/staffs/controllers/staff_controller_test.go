@@ -3,7 +3,6 @@ package controllers_test
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,7 +11,9 @@ import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
 	"github.com/Teemo4621/Hospital-Api/modules/mocks"
 	"github.com/Teemo4621/Hospital-Api/modules/staffs/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
 	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -23,6 +24,7 @@ import (
 func setupRouter(usecase entities.StaffUseCase) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
 	Cfg := &configs.Config{}
 	Cfg.JWT.Secret = "test"
 	Cfg.JWT.Expire = 1
@@ -35,14 +37,26 @@ func setupRouter(usecase entities.StaffUseCase) *gin.Engine {
 
 // ----------- Tests ----------- //
 
+// staffSpec is the spec of a staff listing with the default hospital
+// include.
+func staffSpec(page int, filters ...query.Filter) *query.Spec {
+	return &query.Spec{
+		Filters:  filters,
+		Includes: []string{"hospital"},
+		Preloads: []string{"Hospital"},
+		Page:     page,
+		Limit:    10,
+	}
+}
+
 func TestFindAllStaffHandler(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockUsecase := mocks.NewMockStaffUseCase()
 		r := setupRouter(mockUsecase)
 		staffs := []entities.Staff{
-			{ID: 1, Username: "Test A", Password: "password", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1},
+			{ID: 1, Username: "Test A", Password: "password", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1},
 		}
-		mockUsecase.On("FindAll", 1, 10).Return(staffs, 1, nil)
+		mockUsecase.On("FindAll", staffSpec(1)).Return(staffs, 1, nil)
 		req, _ := http.NewRequest(http.MethodGet, "/staff/", nil)
 		resp := httptest.NewRecorder()
 		r.ServeHTTP(resp, req)
@@ -51,13 +65,83 @@ func TestFindAllStaffHandler(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 
+	t.Run("Department Filter", func(t *testing.T) {
+		mockUsecase := mocks.NewMockStaffUseCase()
+		r := setupRouter(mockUsecase)
+		mockUsecase.On("FindAll", staffSpec(1, query.Filter{Field: "department_id", Op: query.OpEq, Value: int64(4)})).Return([]entities.Staff{}, 0, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/staff/?department_id=4", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Filter And Sort", func(t *testing.T) {
+		mockUsecase := mocks.NewMockStaffUseCase()
+		r := setupRouter(mockUsecase)
+		spec := staffSpec(1,
+			query.Filter{Field: "active", Op: query.OpEq, Value: true},
+			query.Filter{Field: "gender", Column: "gender", Op: query.OpEq, Value: "male"},
+			query.Filter{Field: "hospital_id", Column: "hospital_id", Op: query.OpEq, Value: int64(2)},
+			query.Filter{Field: "name", Op: query.OpLike, Value: "somchai"},
+			query.Filter{Field: "role", Column: "role", Op: query.OpEq, Value: "doctor"},
+		)
+		spec.Sort = []query.SortField{{Column: "last_name_en", Desc: true}, {Column: "id"}}
+		mockUsecase.On("FindAll", spec).Return([]entities.Staff{}, 0, nil)
+		req, _ := http.NewRequest(http.MethodGet, "/staff/?hospital_id=2&name=somchai&role=doctor&gender=male&active=true&sort=-last_name_en,id", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Filters", func(t *testing.T) {
+		for _, params := range []string{"role=janitor", "gender=x", "active=maybe", "sort=password", "sort=id:up", "sort=id,-id", "filter[password][eq]=x", "fields=password", "include=departments"} {
+			mockUsecase := mocks.NewMockStaffUseCase()
+			r := setupRouter(mockUsecase)
+			req, _ := http.NewRequest(http.MethodGet, "/staff/?"+params, nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code, params)
+		}
+	})
+
+	t.Run("Sparse Fields Without Hospital", func(t *testing.T) {
+		mockUsecase := mocks.NewMockStaffUseCase()
+		r := setupRouter(mockUsecase)
+		staffs := []entities.Staff{
+			{ID: 1, FirstNameEN: "Test", LastNameEN: "A", Role: consts.StaffRoleDoctor, HospitalID: 1},
+		}
+		mockUsecase.On("FindAll", &query.Spec{
+			Fields:  []string{"first_name_en", "role"},
+			Columns: []string{"id", "hospital_id", "first_name_en", "role"},
+			Page:    1,
+			Limit:   10,
+		}).Return(staffs, 1, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/staff/?fields=first_name_en,role&include=", nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		items := body["data"].(map[string]interface{})["staffs"].([]interface{})
+		assert.Equal(t, map[string]interface{}{"first_name_en": "Test", "role": "doctor"}, items[0])
+		mockUsecase.AssertExpectations(t)
+	})
+
 	t.Run("Pagination Page > Page Total", func(t *testing.T) {
 		mockUsecase := mocks.NewMockStaffUseCase()
 		r := setupRouter(mockUsecase)
 		staffs := []entities.Staff{
-			{ID: 1, Username: "", Password: "", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1},
+			{ID: 1, Username: "", Password: "", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1},
 		}
-		mockUsecase.On("FindAll", 2, 10).Return(staffs, 1, nil)
+		mockUsecase.On("FindAll", staffSpec(2)).Return(staffs, 1, nil)
 
 		req, _ := http.NewRequest(http.MethodGet, "/staff/?page=2", nil)
 		resp := httptest.NewRecorder()
@@ -112,7 +196,7 @@ func TestFindAllStaffHandler(t *testing.T) {
 		mockUsecase := mocks.NewMockStaffUseCase()
 		r := setupRouter(mockUsecase)
 		staffs := []entities.Staff{}
-		mockUsecase.On("FindAll", 2, 10).Return(staffs, 0, nil)
+		mockUsecase.On("FindAll", staffSpec(2)).Return(staffs, 0, nil)
 
 		req, _ := http.NewRequest(http.MethodGet, "/staff/?page=2", nil)
 		resp := httptest.NewRecorder()
@@ -145,7 +229,7 @@ func TestFindById(t *testing.T) {
 		mockUsecase := mocks.NewMockStaffUseCase()
 		r := setupRouter(mockUsecase)
 
-		staff := &entities.Staff{ID: 1, Username: "Test A", Password: "password", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1}
+		staff := &entities.Staff{ID: 1, Username: "Test A", Password: "password", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1}
 		mockUsecase.On("FindById", uint(1)).Return(staff, nil)
 
 		req, _ := http.NewRequest(http.MethodGet, "/staff/1", nil)
@@ -156,9 +240,31 @@ func TestFindById(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 
+	t.Run("English Gender Label", func(t *testing.T) {
+		mockUsecase := mocks.NewMockStaffUseCase()
+		r := setupRouter(mockUsecase)
+
+		staff := &entities.Staff{ID: 1, Username: "Test A", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1}
+		mockUsecase.On("FindById", uint(1)).Return(staff, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/staff/1", nil)
+		req.Header.Set("Accept-Language", "en")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		var body struct {
+			Data entities.StaffResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, "Male", body.Data.GenderLabel)
+		mockUsecase.AssertExpectations(t)
+	})
+
 	t.Run("Not Found", func(t *testing.T) {
 		mockUsecase := mocks.NewMockStaffUseCase()
-		mockUsecase.On("FindById", uint(99)).Return((*entities.Staff)(nil), errors.New("staff not found"))
+		mockUsecase.On("FindById", uint(99)).Return((*entities.Staff)(nil), entities.NotFound("staff"))
 
 		r := setupRouter(mockUsecase)
 		req, _ := http.NewRequest(http.MethodGet, "/staff/99", nil)
@@ -182,7 +288,7 @@ func TestFindById(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
-		assert.Equal(t, "id is required and must be an integer", body["message"])
+		assert.Equal(t, "id is required and must be an integer", body["detail"])
 		mockUsecase.AssertExpectations(t)
 	})
 }
@@ -192,7 +298,7 @@ func TestMe(t *testing.T) {
 		mockUsecase := mocks.NewMockStaffUseCase()
 		r := setupRouter(mockUsecase)
 
-		staff := &entities.Staff{ID: 1, Username: "Test A", Password: "password", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1}
+		staff := &entities.Staff{ID: 1, Username: "Test A", Password: "password", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1}
 		mockUsecase.On("FindById", uint(1)).Return(staff, nil)
 
 		Cfg := &configs.Config{}
@@ -239,7 +345,7 @@ func TestMe(t *testing.T) {
 		assert.Equal(t, staff.FirstNameEN, data["first_name_en"])
 		assert.Equal(t, staff.MiddleNameEN, data["middle_name_en"])
 		assert.Equal(t, staff.LastNameEN, data["last_name_en"])
-		assert.Equal(t, staff.Gender, data["gender"])
+		assert.Equal(t, string(staff.Gender), data["gender"])
 		assert.Equal(t, staff.Hospital.HospitalName, data["hospital"].(map[string]interface{})["hospital_name"])
 		mockUsecase.AssertExpectations(t)
 	})
@@ -262,7 +368,7 @@ func TestCreate(t *testing.T) {
 		mockUsecase := mocks.NewMockStaffUseCase()
 		r := setupRouter(mockUsecase)
 
-		staff := &entities.Staff{ID: 1, Username: "Test A", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1}
+		staff := &entities.Staff{ID: 1, Username: "Test A", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1}
 
 		newStaff := &entities.StaffCreateResponse{
 			ID:          staff.ID,
@@ -306,7 +412,7 @@ func TestCreate(t *testing.T) {
 			Username: "Test A",
 			Password: "password",
 			Hospital: "Hospital A",
-		}).Return((*entities.StaffCreateResponse)(nil), errors.New("username is already exists"))
+		}).Return((*entities.StaffCreateResponse)(nil), entities.Conflict("username_taken", "staff name already exists"))
 
 		req, _ := http.NewRequest(http.MethodPost, "/staff/create", bytes.NewBufferString(`{
 			"username": "Test A",
@@ -321,8 +427,9 @@ func TestCreate(t *testing.T) {
 		err := json.Unmarshal(resp.Body.Bytes(), &body)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "username is already exists", body["message"])
-		assert.Equal(t, "error", body["status"])
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		assert.Equal(t, "staff name already exists", body["detail"])
+		assert.Equal(t, "username_taken", body["code"])
 		mockUsecase.AssertExpectations(t)
 	})
 
@@ -382,7 +489,7 @@ func TestCreate(t *testing.T) {
 			Username: "Test A",
 			Password: "password",
 			Hospital: "Hospital A",
-		}).Return((*entities.StaffCreateResponse)(nil), errors.New("hospital not found"))
+		}).Return((*entities.StaffCreateResponse)(nil), entities.NotFound("hospital"))
 
 		req, _ := http.NewRequest(http.MethodPost, "/staff/create", bytes.NewBufferString(`{
 			"username": "Test A",
@@ -397,8 +504,9 @@ func TestCreate(t *testing.T) {
 		err := json.Unmarshal(resp.Body.Bytes(), &body)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "hospital not found", body["message"])
-		assert.Equal(t, "error", body["status"])
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Equal(t, "hospital not found", body["detail"])
+		assert.Equal(t, "hospital_not_found", body["code"])
 		mockUsecase.AssertExpectations(t)
 	})
 }
@@ -411,7 +519,7 @@ func TestLogin(t *testing.T) {
 		Cfg.JWT.Expire = 1
 		r := setupRouter(mockUsecase)
 
-		staff := &entities.Staff{ID: 1, Username: "Test A", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: "M", HospitalID: 1}
+		staff := &entities.Staff{ID: 1, Username: "Test A", FirstNameTH: "Test", LastNameTH: "A", FirstNameEN: "Test", LastNameEN: "A", Gender: consts.GenderMale, HospitalID: 1}
 
 		mockUsecase.On("Login", Cfg, &entities.StaffLoginRequest{
 			Username: "test",
@@ -498,6 +606,33 @@ func TestLogin(t *testing.T) {
 		mockUsecase.AssertExpectations(t)
 	})
 
+	t.Run("Hospital Code Instead Of Name", func(t *testing.T) {
+		mockUsecase := mocks.NewMockStaffUseCase()
+		r := setupRouter(mockUsecase)
+
+		Cfg := &configs.Config{}
+		Cfg.JWT.Secret = "test"
+		Cfg.JWT.Expire = 1
+
+		mockUsecase.On("Login", Cfg, &entities.StaffLoginRequest{
+			Username:     "test",
+			Password:     "password",
+			HospitalCode: "13781",
+		}).Return(&entities.StaffLoginResponse{Staff: &entities.StaffMeResponse{ID: 1}, AccessToken: "test"}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/staff/login", bytes.NewBufferString(`{
+			"username": "test",
+			"password": "password",
+			"hospital_code": "13781"
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
 	t.Run("Hospital not found", func(t *testing.T) {
 		mockUsecase := mocks.NewMockStaffUseCase()
 		r := setupRouter(mockUsecase)
@@ -510,7 +645,7 @@ func TestLogin(t *testing.T) {
 			Username: "test",
 			Password: "password",
 			Hospital: "Hospital A",
-		}).Return((*entities.StaffLoginResponse)(nil), errors.New("hospital not found"))
+		}).Return((*entities.StaffLoginResponse)(nil), entities.NotFound("hospital"))
 
 		req, _ := http.NewRequest(http.MethodPost, "/staff/login", bytes.NewBufferString(`{
 			"username": "test",
@@ -525,8 +660,9 @@ func TestLogin(t *testing.T) {
 		err := json.Unmarshal(resp.Body.Bytes(), &body)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "username, password or hospital is invalid", body["message"])
-		assert.Equal(t, "error", body["status"])
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Equal(t, "username, password or hospital is invalid", body["detail"])
+		assert.Equal(t, "invalid_credentials", body["code"])
 		mockUsecase.AssertExpectations(t)
 	})
 }
@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type StaffRepo struct {
@@ -22,8 +24,25 @@ func (r *StaffRepo) Create(staff *entities.Staff) (*entities.Staff, error) {
 }
 
 func (r *StaffRepo) Update(staff *entities.Staff) (*entities.Staff, error) {
-	if err := r.Db.Save(&staff).Error; err != nil {
-		return nil, err
+	version := staff.Version
+	staff.Version = version + 1
+
+	result := r.Db.Model(staff).
+		Where("version = ?", version).
+		Select("*").
+		Omit("id", "created_at", "archived_at", clause.Associations).
+		Updates(staff)
+	if result.Error != nil {
+		staff.Version = version
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		staff.Version = version
+		if _, err := r.FindById(staff.ID); err != nil {
+			return nil, err
+		}
+		return nil, entities.ErrVersionConflict
 	}
 
 	return staff, nil
@@ -33,17 +52,17 @@ func (r *StaffRepo) Delete(id uint) error {
 	return r.Db.Delete(&entities.Staff{}, id).Error
 }
 
-func (r *StaffRepo) FindStaffCount() (int64, error) {
+func (r *StaffRepo) FindStaffCount(spec *query.Spec) (int64, error) {
 	var count int64
-	if err := r.Db.Model(&entities.Staff{}).Count(&count).Error; err != nil {
+	if err := r.Db.Model(&entities.Staff{}).Scopes(spec.Where(r.filters())).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (r *StaffRepo) FindAll(page int, limit int) ([]entities.Staff, error) {
+func (r *StaffRepo) FindAll(spec *query.Spec) ([]entities.Staff, error) {
 	var staffs []entities.Staff
-	if err := r.Db.Preload("Hospital").Offset((page - 1) * limit).Limit(limit).Find(&staffs).Error; err != nil {
+	if err := r.Db.Scopes(spec.Where(r.filters()), spec.List).Find(&staffs).Error; err != nil {
 		return nil, err
 	}
 	return staffs, nil
@@ -59,8 +78,29 @@ func (r *StaffRepo) FindById(id uint) (*entities.Staff, error) {
 
 func (r *StaffRepo) FindByUsername(username string) (*entities.Staff, error) {
 	var staff entities.Staff
-	if err := r.Db.Preload("Hospital").Where("username = ?", username).First(&staff).Error; err != nil {
+	if err := r.Db.Preload("Hospital.FormerNames").Where("username = ?", username).First(&staff).Error; err != nil {
 		return nil, err
 	}
 	return &staff, nil
 }
+
+// filters applies the StaffQuery filters that have no single column.
+func (r *StaffRepo) filters() map[string]query.FilterFunc {
+	return map[string]query.FilterFunc{
+		"department_id": func(db *gorm.DB, filter query.Filter) *gorm.DB {
+			subtree := r.Db.Model(&entities.Department{}).Select("path || '%'").Where("id = ?", filter.Value)
+			departments := r.Db.Model(&entities.Department{}).Select("id").Where("path LIKE (?)", subtree)
+			return db.Where("id IN (?)", r.Db.Model(&entities.StaffDepartment{}).Select("staff_id").Where("department_id IN (?)", departments))
+		},
+		"name": func(db *gorm.DB, filter query.Filter) *gorm.DB {
+			pattern := query.ContainsPattern(filter.Value.(string))
+			return db.Where("((first_name_th || ' ' || last_name_th) ILIKE ? OR (first_name_en || ' ' || last_name_en) ILIKE ?)", pattern, pattern)
+		},
+		"active": func(db *gorm.DB, filter query.Filter) *gorm.DB {
+			if filter.Value.(bool) {
+				return db.Where("archived_at IS NULL")
+			}
+			return db.Where("archived_at IS NOT NULL")
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"gorm.io/gorm"
+)
+
+// SetStaffRole changes a staff member's role. Roles are granted out of band
+// because self-registration must never pick its own. The new role applies
+// from the staff's next login.
+func SetStaffRole(db *gorm.DB, username string, role consts.StaffRole) error {
+	if !role.IsValid() {
+		return entities.Invalid("role must be sysadmin, admin, doctor, nurse, clerk or auditor")
+	}
+
+	result := db.Model(&entities.Staff{}).
+		Where("username = ?", username).
+		Updates(map[string]interface{}{
+			"role":    role,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.NotFound("staff")
+	}
+
+	return nil
+}
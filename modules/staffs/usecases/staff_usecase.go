@@ -2,10 +2,13 @@ package usecases
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/Teemo4621/Hospital-Api/configs"
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"gorm.io/gorm"
 )
 
 type StaffUseCase struct {
@@ -21,7 +24,7 @@ func (u *StaffUseCase) Create(staff *entities.StaffCreateRequest) (*entities.Sta
 	exist, _ := u.repo.FindByUsername(staff.Username)
 
 	if exist != nil {
-		return nil, errors.New("staff name already exists")
+		return nil, entities.Conflict("username_taken", "staff name already exists")
 	}
 
 	hashedPassword, err := utils.HashPassword(staff.Password)
@@ -32,7 +35,7 @@ func (u *StaffUseCase) Create(staff *entities.StaffCreateRequest) (*entities.Sta
 
 	hospital, _ := u.hospitalRepo.FindByName(staff.Hospital)
 	if hospital == nil {
-		return nil, errors.New("hospital not found")
+		return nil, entities.NotFound("hospital")
 	}
 
 	createdStaff := entities.Staff{
@@ -65,7 +68,13 @@ func (u *StaffUseCase) Update(staff *entities.StaffUpdateRequest) (*entities.Sta
 		return nil, err
 	}
 	if exist == nil {
-		return nil, errors.New("staff not found")
+		return nil, entities.NotFound("staff")
+	}
+	if exist.Version != staff.Version {
+		return nil, entities.ErrVersionConflict
+	}
+	if staff.Gender != "" && !staff.Gender.IsValid() {
+		return nil, entities.Invalid("gender must be male, female, other or unknown")
 	}
 
 	exist.FirstNameTH = staff.FirstNameTH
@@ -74,7 +83,9 @@ func (u *StaffUseCase) Update(staff *entities.StaffUpdateRequest) (*entities.Sta
 	exist.FirstNameEN = staff.FirstNameEN
 	exist.MiddleNameEN = staff.MiddleNameEN
 	exist.LastNameEN = staff.LastNameEN
-	exist.Gender = staff.Gender
+	if staff.Gender != "" {
+		exist.Gender = staff.Gender
+	}
 
 	data, err := u.repo.Update(exist)
 	if err != nil {
@@ -90,21 +101,21 @@ func (u *StaffUseCase) Delete(id uint) error {
 		return err
 	}
 	if exist == nil {
-		return errors.New("staff not found")
+		return entities.NotFound("staff")
 	}
 
 	return u.repo.Delete(id)
 }
 
-func (u *StaffUseCase) FindAll(page int, limit int) ([]entities.Staff, int, error) {
-	totalCount, err := u.repo.FindStaffCount()
+func (u *StaffUseCase) FindAll(spec *query.Spec) ([]entities.Staff, int, error) {
+	totalCount, err := u.repo.FindStaffCount(spec)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	totalPage := int((totalCount + int64(limit) - 1) / int64(limit))
+	totalPage := spec.TotalPages(totalCount)
 
-	staffs, err := u.repo.FindAll(page, limit)
+	staffs, err := u.repo.FindAll(spec)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -118,7 +129,7 @@ func (u *StaffUseCase) FindById(id uint) (*entities.Staff, error) {
 		return nil, err
 	}
 	if exist == nil {
-		return nil, errors.New("staff not found")
+		return nil, entities.NotFound("staff")
 	}
 
 	return exist, nil
@@ -130,7 +141,7 @@ func (u *StaffUseCase) FindByUsername(username string) (*entities.Staff, error)
 		return nil, err
 	}
 	if exist == nil {
-		return nil, errors.New("staff not found")
+		return nil, entities.NotFound("staff")
 	}
 
 	return exist, nil
@@ -138,20 +149,25 @@ func (u *StaffUseCase) FindByUsername(username string) (*entities.Staff, error)
 
 func (u *StaffUseCase) Login(cfg *configs.Config, loginRequest *entities.StaffLoginRequest) (*entities.StaffLoginResponse, error) {
 	exist, err := u.repo.FindByUsername(loginRequest.Username)
-	if err != nil {
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
 
-	if exist == nil {
-		return nil, errors.New("staff not found")
+	// Staff archived with their hospital can no longer log in.
+	if exist == nil || exist.ArchivedAt != nil {
+		return nil, entities.NotFound("staff")
 	}
 
 	if !utils.CheckPassword(loginRequest.Password, exist.Password) {
-		return nil, errors.New("invalid password")
+		return nil, entities.Invalid("invalid password")
 	}
 
-	if exist.Hospital.HospitalName != loginRequest.Hospital {
-		return nil, errors.New("hospital name not match")
+	if code := strings.TrimSpace(loginRequest.HospitalCode); code != "" {
+		if exist.Hospital.Code != code {
+			return nil, entities.Invalid("hospital code not match")
+		}
+	} else if !exist.Hospital.HasName(loginRequest.Hospital) {
+		return nil, entities.Invalid("hospital name not match")
 	}
 
 	accessToken, err := utils.GenerateAccessToken(cfg, &entities.Jwtpassport{
@@ -159,6 +175,8 @@ func (u *StaffUseCase) Login(cfg *configs.Config, loginRequest *entities.StaffLo
 		Username:   exist.Username,
 		Hospital:   exist.Hospital.HospitalName,
 		HospitalID: exist.Hospital.ID,
+		Role:       string(exist.Role),
+		Scopes:     exist.Role.Scopes(),
 	})
 
 	if err != nil {
@@ -176,6 +194,7 @@ func (u *StaffUseCase) Login(cfg *configs.Config, loginRequest *entities.StaffLo
 			MiddleNameEN: exist.MiddleNameEN,
 			LastNameEN:   exist.LastNameEN,
 			Gender:       exist.Gender,
+			Role:         exist.Role,
 			Hospital:     exist.Hospital,
 		},
 		AccessToken: accessToken,
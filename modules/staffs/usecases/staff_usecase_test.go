@@ -9,6 +9,8 @@ import (
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
 	"github.com/Teemo4621/Hospital-Api/modules/mocks"
 	"github.com/Teemo4621/Hospital-Api/modules/staffs/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
 	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -33,7 +35,7 @@ func TestCreateStaff(t *testing.T) {
 			FirstNameEN:  "test",
 			MiddleNameEN: "test",
 			LastNameEN:   "test",
-			Gender:       "M",
+			Gender:       consts.GenderMale,
 		}, nil)
 
 		result, err := usecase.Create(input)
@@ -77,7 +79,7 @@ func TestUpdateStaff(t *testing.T) {
 		mockRepo := mocks.NewMockStaffRepository()
 		mockHospitalRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewStaffUseCase(mockRepo, mockHospitalRepo)
-		input := &entities.StaffUpdateRequest{ID: uint(1), FirstNameTH: "test11", FirstNameEN: "test11", Gender: "M"}
+		input := &entities.StaffUpdateRequest{ID: uint(1), FirstNameTH: "test11", FirstNameEN: "test11", Gender: consts.GenderMale}
 
 		OldStaff := &entities.Staff{
 			ID:           uint(1),
@@ -88,7 +90,7 @@ func TestUpdateStaff(t *testing.T) {
 			FirstNameEN:  "test",
 			MiddleNameEN: "test",
 			LastNameEN:   "test",
-			Gender:       "M",
+			Gender:       consts.GenderMale,
 		}
 
 		mockRepo.On("FindById", input.ID).Return(OldStaff, nil)
@@ -107,7 +109,7 @@ func TestUpdateStaff(t *testing.T) {
 		assert.Equal(t, "", result.MiddleNameEN)
 		assert.Equal(t, "", result.LastNameTH)
 		assert.Equal(t, "", result.LastNameEN)
-		assert.Equal(t, "M", result.Gender)
+		assert.Equal(t, consts.GenderMale, result.Gender)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -115,15 +117,29 @@ func TestUpdateStaff(t *testing.T) {
 		mockRepo := mocks.NewMockStaffRepository()
 		mockHospitalRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewStaffUseCase(mockRepo, mockHospitalRepo)
-		input := &entities.StaffUpdateRequest{ID: uint(1), FirstNameTH: "test", FirstNameEN: "test", Gender: "M"}
+		input := &entities.StaffUpdateRequest{ID: uint(1), FirstNameTH: "test", FirstNameEN: "test", Gender: consts.GenderMale}
 		mockRepo.On("FindById", input.ID).Return((*entities.Staff)(nil), nil)
 
 		_, err := usecase.Update(input)
 		assert.EqualError(t, err, "staff not found")
 	})
+
+	t.Run("Version conflict", func(t *testing.T) {
+		mockRepo := mocks.NewMockStaffRepository()
+		mockHospitalRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewStaffUseCase(mockRepo, mockHospitalRepo)
+		input := &entities.StaffUpdateRequest{ID: uint(1), FirstNameTH: "test", Version: 1}
+		mockRepo.On("FindById", input.ID).Return(&entities.Staff{ID: 1, Version: 2}, nil)
+
+		_, err := usecase.Update(input)
+		assert.ErrorIs(t, err, entities.ErrVersionConflict)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
 }
 
 func TestFindAllStaffs(t *testing.T) {
+	spec := &query.Spec{Page: 1, Limit: 10}
+
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := mocks.NewMockStaffRepository()
 		mockHospitalRepo := mocks.NewMockHospitalRepository()
@@ -131,10 +147,10 @@ func TestFindAllStaffs(t *testing.T) {
 
 		staffs := []entities.Staff{{ID: 1}, {ID: 2}}
 
-		mockRepo.On("FindStaffCount").Return(int64(1), nil)
-		mockRepo.On("FindAll", 1, 10).Return(staffs, nil)
+		mockRepo.On("FindStaffCount", spec).Return(int64(1), nil)
+		mockRepo.On("FindAll", spec).Return(staffs, nil)
 
-		result, _, err := usecase.FindAll(1, 10)
+		result, _, err := usecase.FindAll(spec)
 		assert.NoError(t, err)
 		assert.Len(t, result, 2)
 	})
@@ -144,10 +160,10 @@ func TestFindAllStaffs(t *testing.T) {
 		mockHospitalRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewStaffUseCase(mockRepo, mockHospitalRepo)
 
-		mockRepo.On("FindStaffCount").Return(int64(1), errors.New("failed to find staffs"))
-		mockRepo.On("FindAll", 1, 10).Return(nil, errors.New("record not found"))
+		mockRepo.On("FindStaffCount", spec).Return(int64(1), errors.New("failed to find staffs"))
+		mockRepo.On("FindAll", spec).Return(nil, errors.New("record not found"))
 
-		_, _, err := usecase.FindAll(1, 10)
+		_, _, err := usecase.FindAll(spec)
 		assert.EqualError(t, err, "failed to find staffs")
 	})
 
@@ -156,10 +172,10 @@ func TestFindAllStaffs(t *testing.T) {
 		mockHospitalRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewStaffUseCase(mockRepo, mockHospitalRepo)
 
-		mockRepo.On("FindStaffCount").Return(int64(0), nil)
-		mockRepo.On("FindAll", 1, 10).Return([]entities.Staff{}, nil)
+		mockRepo.On("FindStaffCount", spec).Return(int64(0), nil)
+		mockRepo.On("FindAll", spec).Return([]entities.Staff{}, nil)
 
-		staffs, total, _ := usecase.FindAll(1, 10)
+		staffs, total, _ := usecase.FindAll(spec)
 		assert.Equal(t, []entities.Staff{}, staffs)
 		assert.Equal(t, 0, total)
 	})
@@ -169,10 +185,11 @@ func TestFindAllStaffs(t *testing.T) {
 		mockHospitalRepo := mocks.NewMockHospitalRepository()
 		usecase := usecases.NewStaffUseCase(mockRepo, mockHospitalRepo)
 
-		mockRepo.On("FindStaffCount").Return(int64(0), nil)
-		mockRepo.On("FindAll", 2, 10).Return([]entities.Staff{}, nil)
+		spec := &query.Spec{Page: 2, Limit: 10}
+		mockRepo.On("FindStaffCount", spec).Return(int64(0), nil)
+		mockRepo.On("FindAll", spec).Return([]entities.Staff{}, nil)
 
-		staffs, total, _ := usecase.FindAll(2, 10)
+		staffs, total, _ := usecase.FindAll(spec)
 		assert.Equal(t, []entities.Staff{}, staffs)
 		assert.Equal(t, 0, total)
 		mockRepo.AssertExpectations(t)
@@ -235,6 +252,21 @@ func TestFindByUsername(t *testing.T) {
 }
 
 func TestLogin(t *testing.T) {
+	t.Run("Archived Staff", func(t *testing.T) {
+		cfg := &configs.Config{}
+		cfg.JWT.Secret = "test"
+		mockRepo := mocks.NewMockStaffRepository()
+		usecase := usecases.NewStaffUseCase(mockRepo, mocks.NewMockHospitalRepository())
+
+		hashedPassword, _ := utils.HashPassword("test")
+		archivedAt := time.Now()
+		staff := &entities.Staff{ID: 1, Username: "test", Password: hashedPassword, ArchivedAt: &archivedAt, Hospital: entities.Hospital{ID: 1, HospitalName: "test"}}
+		mockRepo.On("FindByUsername", "test").Return(staff, nil)
+
+		_, err := usecase.Login(cfg, &entities.StaffLoginRequest{Username: "test", Password: "test", Hospital: "test"})
+		assert.EqualError(t, err, "staff not found")
+	})
+
 	t.Run("Success", func(t *testing.T) {
 		cfg := &configs.Config{}
 		cfg.JWT.Secret = "test"
@@ -269,6 +301,56 @@ func TestLogin(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("Former Hospital Name", func(t *testing.T) {
+		cfg := &configs.Config{}
+		cfg.JWT.Secret = "test"
+		cfg.JWT.Expire = 1
+		mockRepo := mocks.NewMockStaffRepository()
+		mockHospitalRepo := mocks.NewMockHospitalRepository()
+		usecase := usecases.NewStaffUseCase(mockRepo, mockHospitalRepo)
+
+		hashedPassword, _ := utils.HashPassword("test")
+		staff := &entities.Staff{
+			ID:       1,
+			Username: "test",
+			Password: hashedPassword,
+			Hospital: entities.Hospital{
+				ID:           1,
+				HospitalName: "renamed",
+				FormerNames:  []entities.HospitalNameAlias{{HospitalID: 1, Name: "test"}},
+			},
+		}
+		mockRepo.On("FindByUsername", "test").Return(staff, nil)
+
+		result, err := usecase.Login(cfg, &entities.StaffLoginRequest{
+			Username: "test",
+			Password: "test",
+			Hospital: "test",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "renamed", result.Staff.Hospital.HospitalName)
+	})
+
+	t.Run("Hospital Code", func(t *testing.T) {
+		cfg := &configs.Config{}
+		cfg.JWT.Secret = "test"
+		cfg.JWT.Expire = 1
+		mockRepo := mocks.NewMockStaffRepository()
+		usecase := usecases.NewStaffUseCase(mockRepo, mocks.NewMockHospitalRepository())
+
+		hashedPassword, _ := utils.HashPassword("test")
+		staff := &entities.Staff{ID: 1, Username: "test", Password: hashedPassword, Hospital: entities.Hospital{ID: 1, HospitalName: "Siriraj", Code: "13781"}}
+		mockRepo.On("FindByUsername", "test").Return(staff, nil)
+
+		result, err := usecase.Login(cfg, &entities.StaffLoginRequest{Username: "test", Password: "test", HospitalCode: "13781"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Siriraj", result.Staff.Hospital.HospitalName)
+
+		_, err = usecase.Login(cfg, &entities.StaffLoginRequest{Username: "test", Password: "test", Hospital: "Siriraj", HospitalCode: "10661"})
+		assert.EqualError(t, err, "hospital code not match")
+	})
+
 	t.Run("Staff not found", func(t *testing.T) {
 		cfg := &configs.Config{}
 		cfg.JWT.Secret = "test"
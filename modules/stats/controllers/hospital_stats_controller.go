@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type HospitalStatsCon struct {
+	Cfg                  configs.Config
+	HospitalStatsUsecase entities.HospitalStatsUseCase
+	AuthMiddleware       middlewares.AuthMiddleware
+}
+
+// NewHospitalStatsController registers the routes on a group mounted at
+// /hospitals/:id/stats. Administrators see their own hospital and system
+// administrators any hospital.
+func NewHospitalStatsController(c *gin.RouterGroup, cfg configs.Config, hospitalStatsUsecase entities.HospitalStatsUseCase, authMiddleware middlewares.AuthMiddleware) {
+	controller := &HospitalStatsCon{
+		Cfg:                  cfg,
+		HospitalStatsUsecase: hospitalStatsUsecase,
+		AuthMiddleware:       authMiddleware,
+	}
+
+	c.Use(
+		controller.AuthMiddleware.JwtAuthentication(),
+		controller.AuthMiddleware.RequireScope(consts.ScopeStatsRead),
+		controller.AuthMiddleware.RequireHospital("id"),
+	)
+
+	c.GET("/", controller.Find)
+}
+
+// Find takes the trend range from ?from= and ?to= (YYYY-MM-DD) and its
+// granularity from ?interval=day|week|month.
+func (a *HospitalStatsCon) Find(c *gin.Context) {
+	hospitalId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.BadRequestResponse(c, "id is not a number")
+		return
+	}
+
+	req := entities.HospitalStatsRequest{Interval: consts.StatsInterval(c.Query("interval"))}
+	if value := c.Query("from"); value != "" {
+		if req.From, err = civil.Parse(value); err != nil {
+			utils.BadRequestResponse(c, "from must be YYYY-MM-DD")
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if req.To, err = civil.Parse(value); err != nil {
+			utils.BadRequestResponse(c, "to must be YYYY-MM-DD")
+			return
+		}
+	}
+
+	stats, err := a.HospitalStatsUsecase.Find(uint(hospitalId), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.OkResponse(c, stats)
+}
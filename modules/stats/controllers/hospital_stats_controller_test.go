@@ -0,0 +1,120 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/stats/controllers"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/Teemo4621/Hospital-Api/pkgs/middlewares"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------- Test Setup ----------- //
+
+func testConfig() *configs.Config {
+	cfg := &configs.Config{}
+	cfg.JWT.Secret = "test"
+	cfg.JWT.Expire = 1
+	return cfg
+}
+
+func setupRouter(usecase entities.HospitalStatsUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	r.Use(middlewares.ErrorMiddleware())
+	cfg := testConfig()
+	authMiddleware := middlewares.NewAuthMiddleware(cfg)
+	group := r.Group("/hospitals/:id/stats")
+	controllers.NewHospitalStatsController(group, *cfg, usecase, *authMiddleware)
+	return r
+}
+
+func withRole(req *http.Request, role consts.StaffRole) *http.Request {
+	token, _ := utils.GenerateAccessToken(testConfig(), &entities.Jwtpassport{Id: 7, HospitalID: 1, Role: string(role), Scopes: role.Scopes()})
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	return req
+}
+
+// ----------- Tests ----------- //
+
+func TestFindHospitalStatsHandler(t *testing.T) {
+	t.Run("Admin Of Hospital", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalStatsUseCase()
+		mockUsecase.On("Find", uint(1), &entities.HospitalStatsRequest{
+			From:     civil.Date{Year: 2024, Month: 1, Day: 1},
+			To:       civil.Date{Year: 2024, Month: 3, Day: 31},
+			Interval: consts.StatsIntervalMonth,
+		}).Return(&entities.HospitalStats{HospitalID: 1, TotalPatients: 12}, nil)
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/stats/?from=2024-01-01&to=2024-03-31&interval=month", nil)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"total_patients":12`)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Doctor Is Forbidden", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalStatsUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/stats/", nil)
+		withRole(req, consts.StaffRoleDoctor)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockUsecase.AssertNotCalled(t, "Find", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Admin Of Another Hospital", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalStatsUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/2/stats/", nil)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		mockUsecase.AssertNotCalled(t, "Find", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Date", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalStatsUseCase()
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/stats/?from=01/01/2024", nil)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("Invalid Range", func(t *testing.T) {
+		mockUsecase := mocks.NewMockHospitalStatsUseCase()
+		mockUsecase.On("Find", uint(1), mock.Anything).
+			Return((*entities.HospitalStats)(nil), &entities.FieldError{Field: "from", Message: "from must not be after to"})
+
+		r := setupRouter(mockUsecase)
+		req, _ := http.NewRequest(http.MethodGet, "/hospitals/1/stats/?from=2024-02-01&to=2024-01-01", nil)
+		withRole(req, consts.StaffRoleAdmin)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HospitalStatsRepo struct {
+	Db *gorm.DB
+}
+
+func NewHospitalStatsRepository(db *gorm.DB) entities.HospitalStatsRepository {
+	return &HospitalStatsRepo{Db: db}
+}
+
+// Refresh recomputes the statistics views. Reads keep seeing the previous
+// contents until each refresh completes.
+func (r *HospitalStatsRepo) Refresh() error {
+	views := []string{
+		(entities.HospitalRegistrationStat{}).TableName(),
+		(entities.HospitalDemographicStat{}).TableName(),
+	}
+
+	for _, view := range views {
+		if err := r.Db.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY ?", clause.Table{Name: view}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *HospitalStatsRepo) FindDemographics(hospitalId uint) ([]entities.HospitalDemographicStat, error) {
+	var stats []entities.HospitalDemographicStat
+	if err := r.Db.Where("hospital_id = ?", hospitalId).Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// CountRegistrations counts the patients registered from from to to
+// inclusive.
+func (r *HospitalStatsRepo) CountRegistrations(hospitalId uint, from civil.Date, to civil.Date) (int64, error) {
+	var count int64
+	err := r.Db.Model(&entities.HospitalRegistrationStat{}).
+		Select("COALESCE(SUM(registrations), 0)").
+		Where("hospital_id = ? AND day BETWEEN ? AND ?", hospitalId, from, to).
+		Scan(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *HospitalStatsRepo) CountRegistrationsBefore(hospitalId uint, day civil.Date) (int64, error) {
+	var count int64
+	err := r.Db.Model(&entities.HospitalRegistrationStat{}).
+		Select("COALESCE(SUM(registrations), 0)").
+		Where("hospital_id = ? AND day < ?", hospitalId, day).
+		Scan(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindRegistrationTrend sums the registrations from from to to by interval.
+// Periods start on the first day of the interval, Monday for weeks, and
+// periods without registrations are left out.
+func (r *HospitalStatsRepo) FindRegistrationTrend(hospitalId uint, from civil.Date, to civil.Date, interval consts.StatsInterval) ([]entities.HospitalStatsPoint, error) {
+	var points []entities.HospitalStatsPoint
+	err := r.Db.Model(&entities.HospitalRegistrationStat{}).
+		Select("date_trunc(?, day::timestamp)::date AS period, SUM(registrations) AS registrations", string(interval)).
+		Where("hospital_id = ? AND day BETWEEN ? AND ?", hospitalId, from, to).
+		Group("period").
+		Order("period").
+		Scan(&points).Error
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func (r *HospitalStatsRepo) CountActiveStaff(hospitalId uint) (int64, error) {
+	var count int64
+	if err := r.Db.Model(&entities.Staff{}).Where("hospital_id = ? AND archived_at IS NULL", hospitalId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+// DefaultRefreshInterval is how often the server refreshes the statistics
+// views. Statistics may lag the patient and staff tables by this much.
+const DefaultRefreshInterval = 15 * time.Minute
+
+// defaultStatsRangeDays is the length of the trend when no range is given.
+const defaultStatsRangeDays = 30
+
+type HospitalStatsUseCase struct {
+	repo            entities.HospitalStatsRepository
+	settingsUsecase entities.HospitalSettingsUseCase
+}
+
+func NewHospitalStatsUseCase(repo entities.HospitalStatsRepository, settingsUsecase entities.HospitalSettingsUseCase) entities.HospitalStatsUseCase {
+	return &HospitalStatsUseCase{repo: repo, settingsUsecase: settingsUsecase}
+}
+
+// Find reports the statistics of a hospital. Days are counted in the
+// hospital's time zone, so "today" matches the registration days in the
+// views.
+func (u *HospitalStatsUseCase) Find(hospitalId uint, req *entities.HospitalStatsRequest) (*entities.HospitalStats, error) {
+	settings, err := u.settingsUsecase.Get(hospitalId)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	today := civil.Today(loc)
+
+	from, to, interval := req.From, req.To, req.Interval
+	if to.IsZero() {
+		to = today
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, 1-defaultStatsRangeDays)
+	}
+	if interval == "" {
+		interval = consts.StatsIntervalDay
+	}
+
+	if !interval.IsValid() {
+		return nil, &entities.FieldError{Field: "interval", Message: "interval must be day, week or month"}
+	}
+	if from.After(to) {
+		return nil, &entities.FieldError{Field: "from", Message: "from must not be after to"}
+	}
+	if from.AddDate(0, 0, entities.MaxStatsRangeDays).Before(to) {
+		return nil, &entities.FieldError{Field: "to", Message: fmt.Sprintf("the range must not be longer than %d days", entities.MaxStatsRangeDays)}
+	}
+
+	stats := &entities.HospitalStats{
+		HospitalID: hospitalId,
+		Genders:    make(map[consts.Gender]int64),
+		AgeBands:   make(map[consts.AgeBand]int64),
+		From:       from,
+		To:         to,
+		Interval:   interval,
+	}
+	for _, gender := range []consts.Gender{consts.GenderMale, consts.GenderFemale, consts.GenderOther, consts.GenderUnknown} {
+		stats.Genders[gender] = 0
+	}
+	for _, band := range consts.AgeBands {
+		stats.AgeBands[band] = 0
+	}
+
+	demographics, err := u.repo.FindDemographics(hospitalId)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range demographics {
+		stats.TotalPatients += row.Patients
+		stats.Genders[row.Gender] += row.Patients
+		stats.AgeBands[row.AgeBand] += row.Patients
+		if stats.RefreshedAt == nil {
+			refreshedAt := row.RefreshedAt
+			stats.RefreshedAt = &refreshedAt
+		}
+	}
+
+	if stats.ActiveStaff, err = u.repo.CountActiveStaff(hospitalId); err != nil {
+		return nil, err
+	}
+
+	if stats.NewPatients.Day, err = u.repo.CountRegistrations(hospitalId, today, today); err != nil {
+		return nil, err
+	}
+	if stats.NewPatients.Week, err = u.repo.CountRegistrations(hospitalId, today.AddDate(0, 0, -6), today); err != nil {
+		return nil, err
+	}
+	if stats.NewPatients.Month, err = u.repo.CountRegistrations(hospitalId, today.AddDate(0, 0, -29), today); err != nil {
+		return nil, err
+	}
+
+	if stats.Trend, err = u.trend(hospitalId, from, to, interval); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// trend returns one point for every period from from to to, including the
+// periods without registrations, with the running total of patients.
+func (u *HospitalStatsUseCase) trend(hospitalId uint, from civil.Date, to civil.Date, interval consts.StatsInterval) ([]entities.HospitalStatsPoint, error) {
+	total, err := u.repo.CountRegistrationsBefore(hospitalId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := u.repo.FindRegistrationTrend(hospitalId, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+	registrations := make(map[civil.Date]int64, len(points))
+	for _, point := range points {
+		registrations[point.Period] = point.Registrations
+	}
+
+	trend := []entities.HospitalStatsPoint{}
+	for period := periodStart(from, interval); !period.After(to); period = nextPeriod(period, interval) {
+		total += registrations[period]
+		trend = append(trend, entities.HospitalStatsPoint{
+			Period:        period,
+			Registrations: registrations[period],
+			TotalPatients: total,
+		})
+	}
+
+	return trend, nil
+}
+
+// periodStart truncates day to the start of its period the way Postgres'
+// date_trunc does.
+func periodStart(day civil.Date, interval consts.StatsInterval) civil.Date {
+	switch interval {
+	case consts.StatsIntervalWeek:
+		weekday := int(day.Time(time.UTC).Weekday()+6) % 7
+		return day.AddDate(0, 0, -weekday)
+	case consts.StatsIntervalMonth:
+		return civil.Date{Year: day.Year, Month: day.Month, Day: 1}
+	}
+	return day
+}
+
+func nextPeriod(period civil.Date, interval consts.StatsInterval) civil.Date {
+	switch interval {
+	case consts.StatsIntervalWeek:
+		return period.AddDate(0, 0, 7)
+	case consts.StatsIntervalMonth:
+		return period.AddDate(0, 1, 0)
+	}
+	return period.AddDate(0, 0, 1)
+}
+
+func (u *HospitalStatsUseCase) Refresh() error {
+	return u.repo.Refresh()
+}
+
+// StartRefresh refreshes the views now and then every interval, for the
+// life of the process.
+func (u *HospitalStatsUseCase) StartRefresh(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := u.Refresh(); err != nil {
+				log.Printf("hospital stats refresh failed: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
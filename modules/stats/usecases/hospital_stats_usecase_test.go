@@ -0,0 +1,101 @@
+package usecases_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/modules/mocks"
+	"github.com/Teemo4621/Hospital-Api/modules/stats/usecases"
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func date(year int, month time.Month, day int) civil.Date {
+	return civil.Date{Year: year, Month: month, Day: day}
+}
+
+func setupStats() (*mocks.MockHospitalStatsRepository, entities.HospitalStatsUseCase) {
+	mockRepo := mocks.NewMockHospitalStatsRepository()
+	mockSettings := mocks.NewMockHospitalSettingsUseCase()
+	mockSettings.On("Get", uint(1)).Return(entities.DefaultHospitalSettings(1), nil)
+	return mockRepo, usecases.NewHospitalStatsUseCase(mockRepo, mockSettings)
+}
+
+func TestFindHospitalStats(t *testing.T) {
+	t.Run("Weekly Trend With Running Total", func(t *testing.T) {
+		mockRepo, usecase := setupStats()
+		refreshedAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+		mockRepo.On("FindDemographics", uint(1)).Return([]entities.HospitalDemographicStat{
+			{HospitalID: 1, Gender: consts.GenderFemale, AgeBand: consts.AgeBandAdult, Patients: 7, RefreshedAt: refreshedAt},
+			{HospitalID: 1, Gender: consts.GenderMale, AgeBand: consts.AgeBandAdult, Patients: 2, RefreshedAt: refreshedAt},
+			{HospitalID: 1, Gender: consts.GenderMale, AgeBand: consts.AgeBandSenior, Patients: 1, RefreshedAt: refreshedAt},
+		}, nil)
+		mockRepo.On("CountActiveStaff", uint(1)).Return(int64(4), nil)
+		mockRepo.On("CountRegistrations", uint(1), mock.Anything, mock.Anything).Return(int64(1), nil)
+		mockRepo.On("CountRegistrationsBefore", uint(1), date(2024, 1, 3)).Return(int64(5), nil)
+		// 2024-01-03 is a Wednesday, so the first week starts on Monday 2024-01-01.
+		mockRepo.On("FindRegistrationTrend", uint(1), date(2024, 1, 3), date(2024, 1, 20), consts.StatsIntervalWeek).Return([]entities.HospitalStatsPoint{
+			{Period: date(2024, 1, 1), Registrations: 2},
+			{Period: date(2024, 1, 15), Registrations: 3},
+		}, nil)
+
+		stats, err := usecase.Find(1, &entities.HospitalStatsRequest{From: date(2024, 1, 3), To: date(2024, 1, 20), Interval: consts.StatsIntervalWeek})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), stats.TotalPatients)
+		assert.Equal(t, int64(4), stats.ActiveStaff)
+		assert.Equal(t, int64(3), stats.Genders[consts.GenderMale])
+		assert.Equal(t, int64(0), stats.Genders[consts.GenderOther])
+		assert.Equal(t, int64(9), stats.AgeBands[consts.AgeBandAdult])
+		assert.Contains(t, stats.AgeBands, consts.AgeBandChild)
+		assert.Equal(t, &refreshedAt, stats.RefreshedAt)
+		assert.Equal(t, []entities.HospitalStatsPoint{
+			{Period: date(2024, 1, 1), Registrations: 2, TotalPatients: 7},
+			{Period: date(2024, 1, 8), Registrations: 0, TotalPatients: 7},
+			{Period: date(2024, 1, 15), Registrations: 3, TotalPatients: 10},
+		}, stats.Trend)
+	})
+
+	t.Run("Defaults To The Last 30 Days", func(t *testing.T) {
+		mockRepo, usecase := setupStats()
+		loc, _ := time.LoadLocation("Asia/Bangkok")
+		today := civil.Today(loc)
+
+		mockRepo.On("FindDemographics", uint(1)).Return([]entities.HospitalDemographicStat{}, nil)
+		mockRepo.On("CountActiveStaff", uint(1)).Return(int64(0), nil)
+		mockRepo.On("CountRegistrations", uint(1), today, today).Return(int64(1), nil)
+		mockRepo.On("CountRegistrations", uint(1), today.AddDate(0, 0, -6), today).Return(int64(3), nil)
+		mockRepo.On("CountRegistrations", uint(1), today.AddDate(0, 0, -29), today).Return(int64(8), nil)
+		mockRepo.On("CountRegistrationsBefore", uint(1), today.AddDate(0, 0, -29)).Return(int64(0), nil)
+		mockRepo.On("FindRegistrationTrend", uint(1), today.AddDate(0, 0, -29), today, consts.StatsIntervalDay).Return([]entities.HospitalStatsPoint{}, nil)
+
+		stats, err := usecase.Find(1, &entities.HospitalStatsRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, entities.HospitalNewPatients{Day: 1, Week: 3, Month: 8}, stats.NewPatients)
+		assert.Len(t, stats.Trend, 30)
+		assert.Nil(t, stats.RefreshedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Range", func(t *testing.T) {
+		cases := map[string]entities.HospitalStatsRequest{
+			"From After To":    {From: date(2024, 2, 1), To: date(2024, 1, 1)},
+			"Too Long":         {From: date(2020, 1, 1), To: date(2024, 1, 1)},
+			"Unknown Interval": {From: date(2024, 1, 1), To: date(2024, 2, 1), Interval: "year"},
+		}
+
+		for name, req := range cases {
+			t.Run(name, func(t *testing.T) {
+				mockRepo, usecase := setupStats()
+
+				_, err := usecase.Find(1, &req)
+				var fieldErr *entities.FieldError
+				assert.ErrorAs(t, err, &fieldErr)
+				mockRepo.AssertNotCalled(t, "FindDemographics", mock.Anything)
+			})
+		}
+	})
+}
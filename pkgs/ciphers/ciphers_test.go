@@ -0,0 +1,110 @@
+package ciphers
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune(b)), keySize)))
+}
+
+func newTestKeyring(t *testing.T, keys string, active uint32) *Keyring {
+	keyring, err := NewKeyring(configs.Encryption{Keys: keys, ActiveVersion: active, IndexKey: testKey('i')})
+	assert.NoError(t, err)
+	return keyring
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	keyring := newTestKeyring(t, "1:"+testKey('a'), 1)
+
+	sealed, err := keyring.Encrypt("1103700012345")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, "enc:v1:"))
+	assert.NotContains(t, sealed, "1103700012345")
+
+	again, _ := keyring.Encrypt("1103700012345")
+	assert.NotEqual(t, sealed, again, "each value must get its own data key and nonce")
+
+	plaintext, err := keyring.Decrypt(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "1103700012345", plaintext)
+}
+
+func TestDecryptLegacyPlaintext(t *testing.T) {
+	keyring := newTestKeyring(t, "1:"+testKey('a'), 1)
+
+	plaintext, err := keyring.Decrypt("AA1234567")
+	assert.NoError(t, err)
+	assert.Equal(t, "AA1234567", plaintext)
+	assert.True(t, keyring.NeedsRotation("AA1234567"))
+}
+
+func TestKeyRotation(t *testing.T) {
+	old := newTestKeyring(t, "1:"+testKey('a'), 1)
+	sealed, _ := old.Encrypt("1103700012345")
+
+	rotated := newTestKeyring(t, "1:"+testKey('a')+",2:"+testKey('b'), 2)
+	assert.True(t, rotated.NeedsRotation(sealed))
+
+	plaintext, err := rotated.Decrypt(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "1103700012345", plaintext)
+
+	resealed, _ := rotated.Encrypt(plaintext)
+	assert.True(t, strings.HasPrefix(resealed, "enc:v2:"))
+	assert.False(t, rotated.NeedsRotation(resealed))
+
+	retired := newTestKeyring(t, "2:"+testKey('b'), 2)
+	_, err = retired.Decrypt(sealed)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestDecryptTampered(t *testing.T) {
+	keyring := newTestKeyring(t, "1:"+testKey('a'), 1)
+	sealed, _ := keyring.Encrypt("1103700012345")
+
+	parts := strings.Split(sealed, ":")
+	value := []byte(parts[3])
+	value[len(value)-2] ^= 1
+	parts[3] = string(value)
+
+	_, err := keyring.Decrypt(strings.Join(parts, ":"))
+	assert.Error(t, err)
+}
+
+func TestBlindIndex(t *testing.T) {
+	keyring := newTestKeyring(t, "1:"+testKey('a'), 1)
+
+	assert.Equal(t,
+		keyring.BlindIndex("national_id", "1103700012345"),
+		keyring.BlindIndex("national_id", "1-1037-00012-34-5"))
+	assert.Equal(t,
+		keyring.BlindIndex("passport_id", "aa1234567"),
+		keyring.BlindIndex("passport_id", "AA1234567"))
+	assert.NotEqual(t,
+		keyring.BlindIndex("national_id", "AA1234567"),
+		keyring.BlindIndex("passport_id", "AA1234567"))
+	assert.Empty(t, keyring.BlindIndex("national_id", " "))
+
+	rotated := newTestKeyring(t, "2:"+testKey('b'), 2)
+	assert.Equal(t,
+		keyring.BlindIndex("national_id", "1103700012345"),
+		rotated.BlindIndex("national_id", "1103700012345"),
+		"rotating encryption keys must not change blind indexes")
+}
+
+func TestNewKeyringValidation(t *testing.T) {
+	_, err := NewKeyring(configs.Encryption{})
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	_, err = NewKeyring(configs.Encryption{Keys: "1:" + testKey('a'), ActiveVersion: 2, IndexKey: testKey('i')})
+	assert.Error(t, err)
+
+	_, err = NewKeyring(configs.Encryption{Keys: "1:c2hvcnQ=", ActiveVersion: 1, IndexKey: testKey('i')})
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package ciphers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+)
+
+const keySize = 32
+
+var (
+	ErrNotConfigured = errors.New("field encryption keys are not configured")
+	ErrUnknownKey    = errors.New("ciphertext was written with an unknown key version")
+	ErrMalformed     = errors.New("ciphertext is malformed")
+)
+
+// Keyring holds the versioned key-encryption keys used to wrap per-value data
+// keys, plus the separate HMAC key behind the blind indexes. New values are
+// always sealed with the active version; older versions stay available for
+// decryption until every row has been re-encrypted.
+type Keyring struct {
+	active   uint32
+	keys     map[uint32][]byte
+	indexKey []byte
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultKeyring *Keyring
+)
+
+func SetDefault(keyring *Keyring) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultKeyring = keyring
+}
+
+func Default() (*Keyring, error) {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultKeyring == nil {
+		return nil, ErrNotConfigured
+	}
+	return defaultKeyring, nil
+}
+
+// NewKeyring builds a keyring from configuration. Keys is a comma separated
+// list of version:base64key pairs, e.g. "1:AAAA...,2:BBBB...".
+func NewKeyring(cfg configs.Encryption) (*Keyring, error) {
+	if cfg.Keys == "" || cfg.IndexKey == "" {
+		return nil, ErrNotConfigured
+	}
+
+	keys := map[uint32][]byte{}
+	for _, pair := range strings.Split(cfg.Keys, ",") {
+		versionText, encoded, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("encryption key %q must be in version:base64 form", pair)
+		}
+
+		version, err := strconv.ParseUint(versionText, 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("encryption key version %q must be a positive integer", versionText)
+		}
+
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key version %d: %w", version, err)
+		}
+		keys[uint32(version)] = key
+	}
+
+	indexKey, err := decodeKey(cfg.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+
+	if _, ok := keys[cfg.ActiveVersion]; !ok {
+		return nil, fmt.Errorf("active encryption key version %d is not in the keyring", cfg.ActiveVersion)
+	}
+
+	return &Keyring{active: cfg.ActiveVersion, keys: keys, indexKey: indexKey}, nil
+}
+
+func (k *Keyring) ActiveVersion() uint32 {
+	return k.active
+}
+
+// BlindIndex returns a keyed hash of value for exact-match lookups. The
+// column name separates the index spaces so equal values in different
+// columns do not produce equal hashes. Empty input yields an empty index.
+func (k *Keyring) BlindIndex(column string, value string) string {
+	normalized := normalize(value)
+	if normalized == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(column))
+	mac.Write([]byte{0})
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// normalize drops separators and case so "1-2345-67890-12-3" and
+// "1234567890123" index the same way.
+func normalize(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if unicode.IsSpace(r) || r == '-' {
+			continue
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.New("key is not valid base64")
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+	return key, nil
+}
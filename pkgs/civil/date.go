@@ -0,0 +1,172 @@
+package civil
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Era string
+
+const (
+	EraCE Era = "ce"
+	EraBE Era = "be"
+
+	// BuddhistEraOffset is the number of years the Thai solar calendar (พ.ศ.)
+	// runs ahead of the Gregorian one.
+	BuddhistEraOffset = 543
+)
+
+var ErrInvalidDate = errors.New("date must be YYYY-MM-DD")
+
+// ParseEra reads an `?era=` value; an empty value means CE.
+func ParseEra(value string) (Era, error) {
+	switch Era(strings.ToLower(strings.TrimSpace(value))) {
+	case "", EraCE:
+		return EraCE, nil
+	case EraBE:
+		return EraBE, nil
+	}
+	return "", errors.New("era must be ce or be")
+}
+
+// Date is a calendar day without a time or time zone, stored as a Postgres
+// `date`. The year is always held in CE; era only changes how the date is
+// written out as JSON, see In.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+
+	era Era
+}
+
+// DateOf returns the day t falls on in its own location.
+func DateOf(t time.Time) Date {
+	return Date{Year: t.Year(), Month: t.Month(), Day: t.Day()}
+}
+
+// Today returns the current day in loc.
+func Today(loc *time.Location) Date {
+	return DateOf(time.Now().In(loc))
+}
+
+// Parse accepts YYYY-MM-DD or an RFC 3339 timestamp. A timestamp keeps the
+// day written in its own offset, so midnight in Bangkok is not moved to the
+// previous day in UTC.
+func Parse(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return DateOf(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return DateOf(t), nil
+	}
+	return Date{}, ErrInvalidDate
+}
+
+func (d Date) IsZero() bool {
+	return d.Year == 0 && d.Month == 0 && d.Day == 0
+}
+
+// Time returns midnight of the day in loc.
+func (d Date) Time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// AddDate works like time.Time.AddDate, normalising overflow the same way.
+func (d Date) AddDate(years int, months int, days int) Date {
+	return DateOf(d.Time(time.UTC).AddDate(years, months, days))
+}
+
+func (d Date) Before(other Date) bool {
+	return d.Time(time.UTC).Before(other.Time(time.UTC))
+}
+
+func (d Date) After(other Date) bool {
+	return other.Before(d)
+}
+
+// In returns the same day flagged to be written in era.
+func (d Date) In(era Era) Date {
+	d.era = era
+	return d
+}
+
+// FromEra converts a date whose year was given in era back to CE.
+func (d Date) FromEra(era Era) Date {
+	if era == EraBE {
+		d.Year -= BuddhistEraOffset
+	}
+	d.era = ""
+	return d
+}
+
+// String formats the date as YYYY-MM-DD in CE.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	year := d.Year
+	if d.era == EraBE {
+		year += BuddhistEraOffset
+	}
+	return json.Marshal(fmt.Sprintf("%04d-%02d-%02d", year, int(d.Month), d.Day))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ErrInvalidDate
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d *Date) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = DateOf(v)
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		*d = parsed
+		return err
+	case []byte:
+		parsed, err := Parse(string(v))
+		*d = parsed
+		return err
+	}
+	return fmt.Errorf("cannot scan %T into civil.Date", src)
+}
+
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+func (Date) GormDataType() string {
+	return "date"
+}
@@ -0,0 +1,89 @@
+package civil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Date Only", func(t *testing.T) {
+		date, err := Parse("1990-05-01")
+		require.NoError(t, err)
+		assert.Equal(t, Date{Year: 1990, Month: time.May, Day: 1}, date)
+	})
+
+	t.Run("Timestamp Keeps Its Own Day", func(t *testing.T) {
+		date, err := Parse("1990-05-01T00:00:00+07:00")
+		require.NoError(t, err)
+		assert.Equal(t, "1990-05-01", date.String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := Parse("01/05/1990")
+		assert.ErrorIs(t, err, ErrInvalidDate)
+	})
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		var date Date
+		require.NoError(t, json.Unmarshal([]byte(`"1990-05-01"`), &date))
+
+		out, err := json.Marshal(date)
+		require.NoError(t, err)
+		assert.Equal(t, `"1990-05-01"`, string(out))
+	})
+
+	t.Run("Buddhist Era", func(t *testing.T) {
+		var date Date
+		require.NoError(t, json.Unmarshal([]byte(`"2533-05-01"`), &date))
+		date = date.FromEra(EraBE)
+		assert.Equal(t, 1990, date.Year)
+
+		out, err := json.Marshal(date.In(EraBE))
+		require.NoError(t, err)
+		assert.Equal(t, `"2533-05-01"`, string(out))
+	})
+
+	t.Run("Null", func(t *testing.T) {
+		var value struct {
+			Date *Date `json:"date"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"date":null}`), &value))
+		assert.Nil(t, value.Date)
+	})
+}
+
+func TestScanAndValue(t *testing.T) {
+	var date Date
+	require.NoError(t, date.Scan(time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "1990-05-01", date.String())
+
+	require.NoError(t, date.Scan([]byte("2000-02-29")))
+	value, err := date.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "2000-02-29", value)
+
+	value, err = Date{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	assert.Error(t, date.Scan(42))
+}
+
+func TestParseEra(t *testing.T) {
+	era, err := ParseEra("")
+	require.NoError(t, err)
+	assert.Equal(t, EraCE, era)
+
+	era, err = ParseEra("BE")
+	require.NoError(t, err)
+	assert.Equal(t, EraBE, era)
+
+	_, err = ParseEra("jp")
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+package consts
+
+type AddressType string
+
+const (
+	AddressTypeHome       AddressType = "home"
+	AddressTypeWork       AddressType = "work"
+	AddressTypeRegistered AddressType = "registered"
+)
+
+func (a AddressType) IsValid() bool {
+	switch a {
+	case AddressTypeHome, AddressTypeWork, AddressTypeRegistered:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+package consts
+
+type AppointmentStatus string
+
+const (
+	AppointmentStatusBooked      AppointmentStatus = "booked"
+	AppointmentStatusCancelled   AppointmentStatus = "cancelled"
+	AppointmentStatusRescheduled AppointmentStatus = "rescheduled"
+	AppointmentStatusCompleted   AppointmentStatus = "completed"
+)
@@ -0,0 +1,18 @@
+package consts
+
+type AttachmentCategory string
+
+const (
+	AttachmentCategoryIDCard         AttachmentCategory = "id_card"
+	AttachmentCategoryConsentForm    AttachmentCategory = "consent_form"
+	AttachmentCategoryReferralLetter AttachmentCategory = "referral_letter"
+	AttachmentCategoryOther          AttachmentCategory = "other"
+)
+
+func (c AttachmentCategory) IsValid() bool {
+	switch c {
+	case AttachmentCategoryIDCard, AttachmentCategoryConsentForm, AttachmentCategoryReferralLetter, AttachmentCategoryOther:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,9 @@
+package consts
+
+const (
+	AuditActionRevealPII      = "pii.reveal"
+	AuditActionExportPatients = "patient.export"
+
+	AuditResourcePatient       = "patient"
+	AuditResourcePatientExport = "patient_export"
+)
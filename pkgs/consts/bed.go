@@ -0,0 +1,18 @@
+package consts
+
+type BedStatus string
+
+const (
+	BedStatusAvailable BedStatus = "available"
+	BedStatusOccupied  BedStatus = "occupied"
+	BedStatusCleaning  BedStatus = "cleaning"
+	BedStatusBlocked   BedStatus = "blocked"
+)
+
+func (s BedStatus) IsValid() bool {
+	switch s {
+	case BedStatusAvailable, BedStatusOccupied, BedStatusCleaning, BedStatusBlocked:
+		return true
+	}
+	return false
+}
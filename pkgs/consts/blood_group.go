@@ -0,0 +1,28 @@
+package consts
+
+type (
+	BloodGroup string
+	RhFactor   string
+)
+
+const (
+	BloodGroupA  BloodGroup = "A"
+	BloodGroupB  BloodGroup = "B"
+	BloodGroupAB BloodGroup = "AB"
+	BloodGroupO  BloodGroup = "O"
+
+	RhPositive RhFactor = "+"
+	RhNegative RhFactor = "-"
+)
+
+func (b BloodGroup) IsValid() bool {
+	switch b {
+	case BloodGroupA, BloodGroupB, BloodGroupAB, BloodGroupO:
+		return true
+	}
+	return false
+}
+
+func (r RhFactor) IsValid() bool {
+	return r == RhPositive || r == RhNegative
+}
@@ -0,0 +1,29 @@
+package consts
+
+// DepartmentKind is the level of a department in a hospital's hierarchy:
+// buildings contain departments and departments contain units.
+type DepartmentKind string
+
+const (
+	DepartmentKindBuilding   DepartmentKind = "building"
+	DepartmentKindDepartment DepartmentKind = "department"
+	DepartmentKindUnit       DepartmentKind = "unit"
+)
+
+func (k DepartmentKind) IsValid() bool {
+	return k.Level() > 0
+}
+
+// Level is 1 for buildings, 2 for departments and 3 for units, or 0 for an
+// unknown kind. A parent must have a lower level than its children.
+func (k DepartmentKind) Level() int {
+	switch k {
+	case DepartmentKindBuilding:
+		return 1
+	case DepartmentKindDepartment:
+		return 2
+	case DepartmentKindUnit:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package consts
+
+type (
+	EncounterType        string
+	EncounterStatus      string
+	DischargeDisposition string
+)
+
+const (
+	EncounterTypeOutpatient EncounterType = "outpatient"
+	EncounterTypeInpatient  EncounterType = "inpatient"
+	EncounterTypeEmergency  EncounterType = "emergency"
+
+	EncounterStatusInProgress EncounterStatus = "in_progress"
+	EncounterStatusAdmitted   EncounterStatus = "admitted"
+	EncounterStatusDischarged EncounterStatus = "discharged"
+	EncounterStatusCancelled  EncounterStatus = "cancelled"
+
+	DispositionHome          DischargeDisposition = "home"
+	DispositionReferred      DischargeDisposition = "referred"
+	DispositionAgainstAdvice DischargeDisposition = "against_advice"
+	DispositionDeceased      DischargeDisposition = "deceased"
+	DispositionOther         DischargeDisposition = "other"
+)
+
+// encounterTransitions lists the statuses an encounter may move to from
+// each status. Discharged and cancelled encounters are final.
+var encounterTransitions = map[EncounterStatus][]EncounterStatus{
+	EncounterStatusInProgress: {EncounterStatusAdmitted, EncounterStatusDischarged, EncounterStatusCancelled},
+	EncounterStatusAdmitted:   {EncounterStatusDischarged},
+}
+
+func (t EncounterType) IsValid() bool {
+	switch t {
+	case EncounterTypeOutpatient, EncounterTypeInpatient, EncounterTypeEmergency:
+		return true
+	}
+	return false
+}
+
+func (s EncounterStatus) IsValid() bool {
+	switch s {
+	case EncounterStatusInProgress, EncounterStatusAdmitted, EncounterStatusDischarged, EncounterStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (s EncounterStatus) CanTransitionTo(next EncounterStatus) bool {
+	for _, allowed := range encounterTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the patient is still under care in the encounter,
+// which is when ward and department transfers are allowed.
+func (s EncounterStatus) IsActive() bool {
+	return s == EncounterStatusInProgress || s == EncounterStatusAdmitted
+}
+
+func (d DischargeDisposition) IsValid() bool {
+	switch d {
+	case DispositionHome, DispositionReferred, DispositionAgainstAdvice, DispositionDeceased, DispositionOther:
+		return true
+	}
+	return false
+}
@@ -1,19 +1,100 @@
 package consts
 
-import "database/sql/driver"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 type Gender string
 
 const (
-	GenderMale   Gender = "M"
-	GenderFemale Gender = "F"
+	GenderMale    Gender = "male"
+	GenderFemale  Gender = "female"
+	GenderOther   Gender = "other"
+	GenderUnknown Gender = "unknown"
 )
 
-func (ct *Gender) Scan(value interface{}) error {
-	*ct = Gender(value.([]byte))
+var genderLabels = map[Gender][2]string{
+	GenderMale:    {"ชาย", "Male"},
+	GenderFemale:  {"หญิง", "Female"},
+	GenderOther:   {"อื่นๆ", "Other"},
+	GenderUnknown: {"ไม่ระบุ", "Unknown"},
+}
+
+// ParseGender reads a gender value, also accepting the legacy one letter
+// codes M, F, O and U.
+func ParseGender(value string) Gender {
+	switch v := strings.ToLower(strings.TrimSpace(value)); v {
+	case "m":
+		return GenderMale
+	case "f":
+		return GenderFemale
+	case "o":
+		return GenderOther
+	case "u":
+		return GenderUnknown
+	default:
+		return Gender(v)
+	}
+}
+
+func (g Gender) IsValid() bool {
+	switch g {
+	case GenderMale, GenderFemale, GenderOther, GenderUnknown:
+		return true
+	}
+	return false
+}
+
+// LabelTH returns the Thai display label, or the raw value when unknown.
+func (g Gender) LabelTH() string {
+	if labels, ok := genderLabels[g]; ok {
+		return labels[0]
+	}
+	return string(g)
+}
+
+// LabelEN returns the English display label, or the raw value when unknown.
+func (g Gender) LabelEN() string {
+	if labels, ok := genderLabels[g]; ok {
+		return labels[1]
+	}
+	return string(g)
+}
+
+// Label returns the display label in locale, falling back to Thai.
+func (g Gender) Label(locale Locale) string {
+	if locale == LocaleEN {
+		return g.LabelEN()
+	}
+	return g.LabelTH()
+}
+
+func (g *Gender) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*g = ParseGender(value)
+	return nil
+}
+
+func (g *Gender) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*g = ""
+	case string:
+		*g = Gender(strings.TrimSpace(v))
+	case []byte:
+		*g = Gender(strings.TrimSpace(string(v)))
+	default:
+		return fmt.Errorf("cannot scan %T into consts.Gender", value)
+	}
 	return nil
 }
 
-func (ct Gender) Value() (driver.Value, error) {
-	return string(ct), nil
+func (g Gender) Value() (driver.Value, error) {
+	return string(g), nil
 }
@@ -0,0 +1,26 @@
+package consts
+
+// HospitalDeletePolicy says what happens to a hospital's active staff and
+// patients when the hospital is deleted.
+type HospitalDeletePolicy string
+
+const (
+	// HospitalDeleteRefuse blocks the deletion while anyone is still active.
+	HospitalDeleteRefuse HospitalDeletePolicy = "refuse"
+	// HospitalDeleteArchiveAll archives the staff and patients together
+	// with the hospital.
+	HospitalDeleteArchiveAll HospitalDeletePolicy = "archive-all"
+	// HospitalDeleteReassign moves the staff and patients, with the
+	// patients' encounters, appointments and bed assignments, to another
+	// hospital first. It is refused while any patient HN is already used
+	// there.
+	HospitalDeleteReassign HospitalDeletePolicy = "reassign-to-hospital"
+)
+
+func (p HospitalDeletePolicy) IsValid() bool {
+	switch p {
+	case HospitalDeleteRefuse, HospitalDeleteArchiveAll, HospitalDeleteReassign:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package consts
+
+type (
+	ImportStatus    string
+	ImportIssueKind string
+)
+
+const (
+	ImportStatusPending   ImportStatus = "pending"
+	ImportStatusRunning   ImportStatus = "running"
+	ImportStatusCompleted ImportStatus = "completed"
+	ImportStatusFailed    ImportStatus = "failed"
+
+	ImportIssueInvalid   ImportIssueKind = "invalid"
+	ImportIssueDuplicate ImportIssueKind = "duplicate"
+)
@@ -0,0 +1,19 @@
+package consts
+
+type MaritalStatus string
+
+const (
+	MaritalStatusSingle    MaritalStatus = "single"
+	MaritalStatusMarried   MaritalStatus = "married"
+	MaritalStatusDivorced  MaritalStatus = "divorced"
+	MaritalStatusWidowed   MaritalStatus = "widowed"
+	MaritalStatusSeparated MaritalStatus = "separated"
+)
+
+func (m MaritalStatus) IsValid() bool {
+	switch m {
+	case MaritalStatusSingle, MaritalStatusMarried, MaritalStatusDivorced, MaritalStatusWidowed, MaritalStatusSeparated:
+		return true
+	}
+	return false
+}
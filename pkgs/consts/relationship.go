@@ -0,0 +1,23 @@
+package consts
+
+type Relationship string
+
+const (
+	RelationshipSpouse   Relationship = "spouse"
+	RelationshipParent   Relationship = "parent"
+	RelationshipChild    Relationship = "child"
+	RelationshipSibling  Relationship = "sibling"
+	RelationshipRelative Relationship = "relative"
+	RelationshipGuardian Relationship = "guardian"
+	RelationshipFriend   Relationship = "friend"
+	RelationshipOther    Relationship = "other"
+)
+
+func (r Relationship) IsValid() bool {
+	switch r {
+	case RelationshipSpouse, RelationshipParent, RelationshipChild, RelationshipSibling,
+		RelationshipRelative, RelationshipGuardian, RelationshipFriend, RelationshipOther:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package consts
+
+type StaffRole string
+
+const (
+	StaffRoleSysAdmin StaffRole = "sysadmin"
+	StaffRoleAdmin    StaffRole = "admin"
+	StaffRoleDoctor   StaffRole = "doctor"
+	StaffRoleNurse    StaffRole = "nurse"
+	StaffRoleClerk    StaffRole = "clerk"
+	StaffRoleAuditor  StaffRole = "auditor"
+)
+
+// Scopes granted to a role on top of its default field visibility.
+const (
+	ScopePIIReveal        = "pii:reveal"
+	ScopeAuditRead        = "audit:read"
+	ScopeHospitalManage   = "hospital:manage"
+	ScopeDepartmentManage = "department:manage"
+	ScopeSettingsManage   = "settings:manage"
+	ScopeStatsRead        = "stats:read"
+)
+
+func (r StaffRole) IsValid() bool {
+	switch r {
+	case StaffRoleSysAdmin, StaffRoleAdmin, StaffRoleDoctor, StaffRoleNurse, StaffRoleClerk, StaffRoleAuditor:
+		return true
+	}
+	return false
+}
+
+// Scopes returns the scopes issued in the access token for the role.
+func (r StaffRole) Scopes() []string {
+	switch r {
+	case StaffRoleSysAdmin:
+		return []string{ScopeAuditRead, ScopeHospitalManage, ScopeDepartmentManage, ScopeSettingsManage, ScopeStatsRead}
+	case StaffRoleAdmin:
+		return []string{ScopePIIReveal, ScopeAuditRead, ScopeDepartmentManage, ScopeSettingsManage, ScopeStatsRead}
+	case StaffRoleDoctor, StaffRoleNurse, StaffRoleClerk:
+		return []string{ScopePIIReveal}
+	case StaffRoleAuditor:
+		return []string{ScopeAuditRead}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package consts
+
+type Locale string
+
+const (
+	LocaleTH Locale = "th"
+	LocaleEN Locale = "en"
+)
+
+func (l Locale) IsValid() bool {
+	return l == LocaleTH || l == LocaleEN
+}
+
+// HospitalModule names a feature area a hospital can switch on or off in
+// its settings.
+type HospitalModule string
+
+const (
+	HospitalModulePatients     HospitalModule = "patients"
+	HospitalModuleEncounters   HospitalModule = "encounters"
+	HospitalModuleAppointments HospitalModule = "appointments"
+	HospitalModuleBeds         HospitalModule = "beds"
+	HospitalModuleTransfers    HospitalModule = "transfers"
+	HospitalModuleImports      HospitalModule = "imports"
+	HospitalModuleExports      HospitalModule = "exports"
+	HospitalModuleAttachments  HospitalModule = "attachments"
+)
+
+// HospitalModules lists every module, in the order new hospitals get them.
+var HospitalModules = []HospitalModule{
+	HospitalModulePatients,
+	HospitalModuleEncounters,
+	HospitalModuleAppointments,
+	HospitalModuleBeds,
+	HospitalModuleTransfers,
+	HospitalModuleImports,
+	HospitalModuleExports,
+	HospitalModuleAttachments,
+}
+
+func (m HospitalModule) IsValid() bool {
+	for _, module := range HospitalModules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package consts
+
+// StatsInterval is the period each point of a statistics trend covers.
+type StatsInterval string
+
+const (
+	StatsIntervalDay   StatsInterval = "day"
+	StatsIntervalWeek  StatsInterval = "week"
+	StatsIntervalMonth StatsInterval = "month"
+)
+
+func (i StatsInterval) IsValid() bool {
+	switch i {
+	case StatsIntervalDay, StatsIntervalWeek, StatsIntervalMonth:
+		return true
+	}
+	return false
+}
+
+// AgeBand groups patients by age in completed years.
+type AgeBand string
+
+const (
+	AgeBandChild      AgeBand = "0-14"
+	AgeBandYouth      AgeBand = "15-24"
+	AgeBandAdult      AgeBand = "25-44"
+	AgeBandMiddleAged AgeBand = "45-64"
+	AgeBandSenior     AgeBand = "65+"
+)
+
+// AgeBands lists every band from youngest to oldest.
+var AgeBands = []AgeBand{AgeBandChild, AgeBandYouth, AgeBandAdult, AgeBandMiddleAged, AgeBandSenior}
@@ -0,0 +1,18 @@
+package consts
+
+type TransferStatus string
+
+const (
+	TransferStatusRequested TransferStatus = "requested"
+	TransferStatusAccepted  TransferStatus = "accepted"
+	TransferStatusRejected  TransferStatus = "rejected"
+	TransferStatusCancelled TransferStatus = "cancelled"
+)
+
+func (s TransferStatus) IsValid() bool {
+	switch s {
+	case TransferStatusRequested, TransferStatusAccepted, TransferStatusRejected, TransferStatusCancelled:
+		return true
+	}
+	return false
+}
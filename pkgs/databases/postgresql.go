@@ -1,13 +1,16 @@
 package databases
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/Teemo4621/Hospital-Api/configs"
 	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
 	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func NewPostgresConnection(config configs.Config) (*gorm.DB, error) {
@@ -16,7 +19,9 @@ func NewPostgresConnection(config configs.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{
+		TranslateError: true,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -27,5 +32,157 @@ func NewPostgresConnection(config configs.Config) (*gorm.DB, error) {
 }
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(&entities.Staff{}, &entities.Patient{}, &entities.Hospital{})
+	if err := migrateGenders(db); err != nil {
+		return err
+	}
+
+	if err := migrateDatesOfBirth(db); err != nil {
+		return err
+	}
+
+	err := db.AutoMigrate(
+		&entities.Staff{},
+		&entities.Patient{},
+		&entities.Hospital{},
+		&entities.HospitalNameAlias{},
+		&entities.HospitalCodeEntry{},
+		&entities.Department{},
+		&entities.StaffDepartment{},
+		&entities.HospitalSettings{},
+		&entities.HospitalSettingsChange{},
+		&entities.PatientAddress{},
+		&entities.PatientEmergencyContact{},
+		&entities.PatientAttachment{},
+		&entities.PatientImportJob{},
+		&entities.PatientImportIssue{},
+		&entities.PatientExportJob{},
+		&entities.PatientTransfer{},
+		&entities.Encounter{},
+		&entities.EncounterTransfer{},
+		&entities.Appointment{},
+		&entities.StaffAvailability{},
+		&entities.StaffAvailabilityException{},
+		&entities.Ward{},
+		&entities.Bed{},
+		&entities.BedAssignment{},
+		&entities.AuditLog{},
+	)
+	if err != nil {
+		return err
+	}
+
+	return migrateStatsViews(db)
+}
+
+// migrateGenders maps the legacy one letter gender codes to consts.Gender
+// before AutoMigrate adds the CHECK constraints that reject them. The column
+// is only altered while it is not varchar(8) yet, since the stats views
+// select it once they exist and Postgres refuses to retype it then. It is a
+// no-op once every row holds a valid value.
+func migrateGenders(db *gorm.DB) error {
+	valid := []consts.Gender{consts.GenderMale, consts.GenderFemale, consts.GenderOther, consts.GenderUnknown}
+
+	for _, table := range []string{"patients", "staffs"} {
+		var column struct {
+			DataType  string
+			MaxLength *int `gorm:"column:character_maximum_length"`
+		}
+		err := db.Raw("SELECT data_type, character_maximum_length FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = 'gender'", table).
+			Scan(&column).Error
+		if err != nil {
+			return err
+		}
+		if column.DataType == "" {
+			continue
+		}
+
+		if column.DataType != "character varying" || column.MaxLength == nil || *column.MaxLength != 8 {
+			if err := db.Exec("ALTER TABLE ? ALTER COLUMN gender TYPE varchar(8)", clause.Table{Name: table}).Error; err != nil {
+				return err
+			}
+		}
+
+		err = db.Table(table).
+			Where("gender IS NULL OR gender NOT IN ?", valid).
+			UpdateColumn("gender", gorm.Expr("CASE upper(trim(gender)) WHEN 'M' THEN 'male' WHEN 'F' THEN 'female' WHEN 'O' THEN 'other' ELSE 'unknown' END")).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateDatesOfBirth turns a date_of_birth still stored as a timestamp into
+// a date. AutoMigrate would cast it in the session's time zone, usually UTC,
+// which moves a birth date sent as local midnight to the day before, so it is
+// read in the default hospital time zone instead. A database at this point
+// has no hospital settings yet, so the default is every hospital's zone. It is
+// a no-op once the column is a date.
+func migrateDatesOfBirth(db *gorm.DB) error {
+	var dataType string
+	err := db.Raw("SELECT data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'patients' AND column_name = 'date_of_birth'").
+		Scan(&dataType).Error
+	if err != nil {
+		return err
+	}
+	if dataType != "timestamp with time zone" {
+		return nil
+	}
+
+	return db.Exec(fmt.Sprintf("ALTER TABLE patients ALTER COLUMN date_of_birth TYPE date USING (date_of_birth AT TIME ZONE '%s')::date",
+		entities.DefaultHospitalSettings(0).Timezone)).Error
+}
+
+// migrateStatsViews creates the materialized views behind the hospital
+// statistics. They are refreshed in the background by the stats use case;
+// the unique indexes let it refresh them concurrently, without blocking
+// readers. Registration days and ages use the hospital's time zone from its
+// settings, falling back to the default one.
+func migrateStatsViews(db *gorm.DB) error {
+	registrations := (entities.HospitalRegistrationStat{}).TableName()
+	demographics := (entities.HospitalDemographicStat{}).TableName()
+	timezone := fmt.Sprintf("COALESCE(s.timezone, '%s')", entities.DefaultHospitalSettings(0).Timezone)
+
+	statements := []string{
+		fmt.Sprintf(`CREATE MATERIALIZED VIEW IF NOT EXISTS %s AS
+			SELECT p.hospital_id,
+				(p.created_at AT TIME ZONE %s)::date AS day,
+				COUNT(*) AS registrations
+			FROM patients p
+			LEFT JOIN hospital_settings s ON s.hospital_id = p.hospital_id
+			WHERE p.archived_at IS NULL
+			GROUP BY 1, 2`, registrations, timezone),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_day ON %s (hospital_id, day)", registrations, registrations),
+
+		fmt.Sprintf(`CREATE MATERIALIZED VIEW IF NOT EXISTS %s AS
+			SELECT hospital_id, gender,
+				CASE
+					WHEN age < 15 THEN '%s'
+					WHEN age < 25 THEN '%s'
+					WHEN age < 45 THEN '%s'
+					WHEN age < 65 THEN '%s'
+					ELSE '%s'
+				END AS age_band,
+				COUNT(*) AS patients,
+				now() AS refreshed_at
+			FROM (
+				SELECT p.hospital_id, p.gender,
+					date_part('year', age((now() AT TIME ZONE %s)::date, p.date_of_birth))::int AS age
+				FROM patients p
+				LEFT JOIN hospital_settings s ON s.hospital_id = p.hospital_id
+				WHERE p.archived_at IS NULL
+			) ages
+			GROUP BY 1, 2, 3`, demographics,
+			consts.AgeBandChild, consts.AgeBandYouth, consts.AgeBandAdult, consts.AgeBandMiddleAged, consts.AgeBandSenior, timezone),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_band ON %s (hospital_id, gender, age_band)", demographics, demographics),
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,62 @@
+package databases
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/ciphers"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// EncryptedSerializer stores string fields tagged `serializer:encrypted`
+// sealed with the default keyring and opens them again on read.
+type EncryptedSerializer struct{}
+
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	plaintext := stored
+	if ciphers.IsEncrypted(stored) {
+		keyring, err := ciphers.Default()
+		if err != nil {
+			return err
+		}
+		if plaintext, err = keyring.Decrypt(stored); err != nil {
+			return fmt.Errorf("decrypt %s: %w", field.Name, err)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field %s must be a string", field.Name)
+	}
+
+	if plaintext == "" {
+		return "", nil
+	}
+
+	keyring, err := ciphers.Default()
+	if err != nil {
+		return nil, err
+	}
+	return keyring.Encrypt(plaintext)
+}
@@ -0,0 +1,75 @@
+package geo
+
+import (
+	"errors"
+	"math"
+)
+
+// EarthRadiusKm is the mean Earth radius used for great-circle distances.
+const EarthRadiusKm = 6371.0088
+
+var ErrInvalidPoint = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+func (p Point) Validate() error {
+	if math.IsNaN(p.Lat) || math.IsNaN(p.Lng) || p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+		return ErrInvalidPoint
+	}
+	return nil
+}
+
+// DistanceKm returns the haversine distance between two points.
+func DistanceKm(a Point, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Box is a latitude/longitude rectangle.
+type Box struct {
+	MinLat float64
+	MaxLat float64
+	MinLng float64
+	MaxLng float64
+}
+
+// BoundingBox returns a rectangle that contains every point within radiusKm
+// of center, for use as a cheap index-friendly prefilter before DistanceKm.
+// Near the poles and across the antimeridian it widens to all longitudes.
+func BoundingBox(center Point, radiusKm float64) Box {
+	dLat := degrees(radiusKm / EarthRadiusKm)
+	box := Box{
+		MinLat: math.Max(center.Lat-dLat, -90),
+		MaxLat: math.Min(center.Lat+dLat, 90),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		return box
+	}
+
+	dLng := degrees(math.Asin(math.Min(1, math.Sin(radiusKm/EarthRadiusKm)/math.Cos(radians(center.Lat)))))
+	if center.Lng-dLng < -180 || center.Lng+dLng > 180 {
+		return box
+	}
+
+	box.MinLng = center.Lng - dLng
+	box.MaxLng = center.Lng + dLng
+	return box
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
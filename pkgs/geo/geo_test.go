@@ -0,0 +1,41 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	bangkok   = Point{Lat: 13.7563, Lng: 100.5018}
+	chiangMai = Point{Lat: 18.7883, Lng: 98.9853}
+)
+
+func TestDistanceKm(t *testing.T) {
+	assert.InDelta(t, 583, DistanceKm(bangkok, chiangMai), 5)
+	assert.InDelta(t, DistanceKm(bangkok, chiangMai), DistanceKm(chiangMai, bangkok), 1e-9)
+	assert.Zero(t, DistanceKm(bangkok, bangkok))
+}
+
+func TestBoundingBox(t *testing.T) {
+	t.Run("Contains Points Within Radius", func(t *testing.T) {
+		box := BoundingBox(bangkok, 600)
+		assert.True(t, chiangMai.Lat >= box.MinLat && chiangMai.Lat <= box.MaxLat)
+		assert.True(t, chiangMai.Lng >= box.MinLng && chiangMai.Lng <= box.MaxLng)
+
+		box = BoundingBox(bangkok, 100)
+		assert.False(t, chiangMai.Lat >= box.MinLat && chiangMai.Lat <= box.MaxLat)
+	})
+
+	t.Run("Antimeridian Widens To All Longitudes", func(t *testing.T) {
+		box := BoundingBox(Point{Lat: 0, Lng: 179.9}, 50)
+		assert.Equal(t, -180.0, box.MinLng)
+		assert.Equal(t, 180.0, box.MaxLng)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, bangkok.Validate())
+	assert.ErrorIs(t, Point{Lat: 91}.Validate(), ErrInvalidPoint)
+	assert.ErrorIs(t, Point{Lng: -181}.Validate(), ErrInvalidPoint)
+}
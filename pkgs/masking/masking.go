@@ -0,0 +1,301 @@
+package masking
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+)
+
+type Field string
+
+const (
+	FieldNationalID  Field = "national_id"
+	FieldPassportID  Field = "passport_id"
+	FieldPhoneNumber Field = "phone_number"
+	FieldEmail       Field = "email"
+)
+
+type Visibility int
+
+const (
+	Hidden Visibility = iota
+	Masked
+	Full
+)
+
+var (
+	ErrUnknownField     = entities.Invalid("reveal accepts national_id, passport_id, phone_number or email")
+	ErrRevealNotAllowed = entities.Forbidden("reveal_not_allowed", "role is not allowed to reveal the requested field")
+)
+
+var (
+	fields      = []Field{FieldNationalID, FieldPassportID, FieldPhoneNumber, FieldEmail}
+	defaultRole = consts.StaffRoleClerk
+
+	// policies holds each role's default visibility per field.
+	policies = map[consts.StaffRole]map[Field]Visibility{
+		consts.StaffRoleSysAdmin: {},
+		consts.StaffRoleAdmin: {
+			FieldNationalID:  Masked,
+			FieldPassportID:  Masked,
+			FieldPhoneNumber: Masked,
+			FieldEmail:       Masked,
+		},
+		consts.StaffRoleDoctor: {
+			FieldNationalID:  Full,
+			FieldPassportID:  Full,
+			FieldPhoneNumber: Full,
+			FieldEmail:       Full,
+		},
+		consts.StaffRoleNurse: {
+			FieldNationalID:  Masked,
+			FieldPassportID:  Masked,
+			FieldPhoneNumber: Full,
+			FieldEmail:       Full,
+		},
+		consts.StaffRoleClerk: {
+			FieldNationalID:  Masked,
+			FieldPassportID:  Masked,
+			FieldPhoneNumber: Masked,
+			FieldEmail:       Masked,
+		},
+		consts.StaffRoleAuditor: {},
+	}
+)
+
+// Viewer shapes patient responses for one caller. Fields missing from the
+// role's policy are hidden, and tokens without a known role are treated as
+// clerks.
+type Viewer struct {
+	role   consts.StaffRole
+	scopes []string
+	reveal []Field
+}
+
+func NewViewer(claim *entities.JwtClaim) Viewer {
+	role := consts.StaffRole(claim.Role)
+	if !role.IsValid() {
+		role = defaultRole
+	}
+
+	return Viewer{role: role, scopes: claim.Scopes}
+}
+
+// ParseReveal reads `?reveal=` values, accepting both repeated parameters
+// and comma-separated lists.
+func ParseReveal(values []string) ([]Field, error) {
+	var parsed []Field
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			field := Field(strings.TrimSpace(name))
+			if field == "" {
+				continue
+			}
+			if !slices.Contains(fields, field) {
+				return nil, ErrUnknownField
+			}
+			if !slices.Contains(parsed, field) {
+				parsed = append(parsed, field)
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+func (v Viewer) policy(field Field) Visibility {
+	return policies[v.role][field]
+}
+
+// CanReveal reports whether the caller may unmask the field. Hidden fields
+// can never be revealed, and masked ones need the pii:reveal scope.
+func (v Viewer) CanReveal(field Field) bool {
+	switch v.policy(field) {
+	case Full:
+		return true
+	case Masked:
+		return slices.Contains(v.scopes, consts.ScopePIIReveal)
+	}
+	return false
+}
+
+// WithReveal returns a viewer that shows the given fields in full.
+func (v Viewer) WithReveal(reveal []Field) (Viewer, error) {
+	for _, field := range reveal {
+		if !v.CanReveal(field) {
+			return v, ErrRevealNotAllowed
+		}
+	}
+
+	v.reveal = reveal
+	return v, nil
+}
+
+// Revealed lists the fields this viewer unmasks beyond its role's default,
+// which are the ones an audit entry has to be written for.
+func (v Viewer) Revealed() []Field {
+	var revealed []Field
+	for _, field := range v.reveal {
+		if v.policy(field) == Masked {
+			revealed = append(revealed, field)
+		}
+	}
+	return revealed
+}
+
+func (v Viewer) Visibility(field Field) Visibility {
+	visibility := v.policy(field)
+	if visibility == Masked && slices.Contains(v.reveal, field) {
+		return Full
+	}
+	return visibility
+}
+
+func (v Viewer) apply(field Field, value string, mask func(string) string) string {
+	if value == "" {
+		return ""
+	}
+
+	switch v.Visibility(field) {
+	case Full:
+		return value
+	case Masked:
+		return mask(value)
+	}
+	return ""
+}
+
+// Patient returns a copy of the patient with identifiers and contact details
+// shaped for the viewer. Emergency contacts follow the phone and email rules.
+func (v Viewer) Patient(patient entities.Patient) entities.Patient {
+	patient.NationalID = v.apply(FieldNationalID, patient.NationalID, NationalID)
+	patient.PassportID = v.apply(FieldPassportID, patient.PassportID, PassportID)
+	patient.PhoneNumber = v.apply(FieldPhoneNumber, patient.PhoneNumber, PhoneNumber)
+	patient.Email = v.apply(FieldEmail, patient.Email, Email)
+
+	if patient.EmergencyContacts != nil {
+		contacts := make([]entities.PatientEmergencyContact, len(patient.EmergencyContacts))
+		for i, contact := range patient.EmergencyContacts {
+			contact.PhoneNumber = v.apply(FieldPhoneNumber, contact.PhoneNumber, PhoneNumber)
+			contact.Email = v.apply(FieldEmail, contact.Email, Email)
+			contacts[i] = contact
+		}
+		patient.EmergencyContacts = contacts
+	}
+
+	return patient
+}
+
+// Patch keeps a patch from writing back what the viewer was shown. A value
+// equal to the viewer's masked or blank copy of the stored one is dropped, so
+// a response sent back unchanged leaves the stored identifiers alone. Any
+// other value that looks masked is refused.
+func (v Viewer) Patch(stored entities.Patient, req *entities.PatientPatchRequest) error {
+	shown := v.Patient(stored)
+	changes := []struct {
+		field Field
+		value **string
+		shown string
+	}{
+		{FieldNationalID, &req.NationalID, shown.NationalID},
+		{FieldPassportID, &req.PassportID, shown.PassportID},
+		{FieldPhoneNumber, &req.PhoneNumber, shown.PhoneNumber},
+		{FieldEmail, &req.Email, shown.Email},
+	}
+	for _, change := range changes {
+		if *change.value == nil {
+			continue
+		}
+		if **change.value == change.shown {
+			*change.value = nil
+			continue
+		}
+		if IsMasked(change.field, **change.value) {
+			return &entities.FieldError{Field: string(change.field), Message: string(change.field) + " is masked; send the full value or leave it out"}
+		}
+	}
+	return nil
+}
+
+func (v Viewer) Patients(patients []entities.Patient) []entities.Patient {
+	shaped := make([]entities.Patient, len(patients))
+	for i, patient := range patients {
+		shaped[i] = v.Patient(patient)
+	}
+	return shaped
+}
+
+// IsMasked reports whether value looks like the output of the field's mask.
+// The masks write a lower case x, or *** before the domain of an email, where
+// a real value never has one.
+func IsMasked(field Field, value string) bool {
+	if field == FieldEmail {
+		return strings.Contains(value, "***@")
+	}
+	return strings.ContainsRune(value, 'x')
+}
+
+// NationalID masks a Thai national ID as 1-xxxx-xxxxx-12-3, keeping the
+// first digit and the last three.
+func NationalID(value string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+
+	if len(digits) != 13 {
+		return keep(value, 1, 2)
+	}
+
+	return digits[:1] + "-xxxx-xxxxx-" + digits[10:12] + "-" + digits[12:]
+}
+
+func PassportID(value string) string {
+	return keep(value, 2, 2)
+}
+
+// PhoneNumber masks every digit except the last four and keeps separators,
+// so 081-234-5678 becomes xxx-xxx-5678.
+func PhoneNumber(value string) string {
+	runes := []rune(value)
+	remaining := 4
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] < '0' || runes[i] > '9' {
+			continue
+		}
+		if remaining > 0 {
+			remaining--
+			continue
+		}
+		runes[i] = 'x'
+	}
+	return string(runes)
+}
+
+func Email(value string) string {
+	at := strings.LastIndex(value, "@")
+	if at <= 0 {
+		return keep(value, 1, 0)
+	}
+
+	local := []rune(value[:at])
+	return string(local[:1]) + "***" + value[at:]
+}
+
+// keep replaces everything but the first head and last tail runes with x.
+// Values too short to keep anything are masked entirely.
+func keep(value string, head int, tail int) string {
+	runes := []rune(value)
+	if len(runes) <= head+tail {
+		return strings.Repeat("x", len(runes))
+	}
+
+	for i := head; i < len(runes)-tail; i++ {
+		runes[i] = 'x'
+	}
+	return string(runes)
+}
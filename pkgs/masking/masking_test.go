@@ -0,0 +1,126 @@
+package masking
+
+import (
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func samplePatient() entities.Patient {
+	return entities.Patient{
+		ID:          1,
+		NationalID:  "1234567890123",
+		PassportID:  "AA1234567",
+		PhoneNumber: "081-234-5678",
+		Email:       "somchai@example.com",
+		EmergencyContacts: []entities.PatientEmergencyContact{
+			{PhoneNumber: "0899999999", Email: "kin@example.com"},
+		},
+	}
+}
+
+func viewerFor(role consts.StaffRole) Viewer {
+	return NewViewer(&entities.JwtClaim{Role: string(role), Scopes: role.Scopes()})
+}
+
+func TestMaskFormats(t *testing.T) {
+	assert.Equal(t, "1-xxxx-xxxxx-12-3", NationalID("1234567890123"))
+	assert.Equal(t, "1-xxxx-xxxxx-12-3", NationalID("1-2345-67890-12-3"))
+	assert.Equal(t, "1xxx45", NationalID("123445"))
+	assert.Equal(t, "AAxxxxx67", PassportID("AA1234567"))
+	assert.Equal(t, "xxx-xxx-5678", PhoneNumber("081-234-5678"))
+	assert.Equal(t, "+xx xx xxx 5678", PhoneNumber("+66 81 234 5678"))
+	assert.Equal(t, "s***@example.com", Email("somchai@example.com"))
+	assert.Equal(t, "xxx", keep("abc", 2, 2))
+}
+
+func TestIsMasked(t *testing.T) {
+	assert.True(t, IsMasked(FieldNationalID, NationalID("1234567890123")))
+	assert.True(t, IsMasked(FieldPassportID, PassportID("AA1234567")))
+	assert.True(t, IsMasked(FieldPhoneNumber, PhoneNumber("081-234-5678")))
+	assert.True(t, IsMasked(FieldEmail, Email("somchai@example.com")))
+	assert.False(t, IsMasked(FieldNationalID, "1234567890123"))
+	assert.False(t, IsMasked(FieldEmail, "maxx@example.com"))
+}
+
+func TestViewerByRole(t *testing.T) {
+	t.Run("Clerk sees masked values", func(t *testing.T) {
+		shaped := viewerFor(consts.StaffRoleClerk).Patient(samplePatient())
+
+		assert.Equal(t, "1-xxxx-xxxxx-12-3", shaped.NationalID)
+		assert.Equal(t, "AAxxxxx67", shaped.PassportID)
+		assert.Equal(t, "xxx-xxx-5678", shaped.PhoneNumber)
+		assert.Equal(t, "s***@example.com", shaped.Email)
+		assert.Equal(t, "xxxxxx9999", shaped.EmergencyContacts[0].PhoneNumber)
+	})
+
+	t.Run("Doctor sees full values", func(t *testing.T) {
+		shaped := viewerFor(consts.StaffRoleDoctor).Patient(samplePatient())
+
+		assert.Equal(t, samplePatient(), shaped)
+	})
+
+	t.Run("Auditor sees nothing", func(t *testing.T) {
+		shaped := viewerFor(consts.StaffRoleAuditor).Patient(samplePatient())
+
+		assert.Empty(t, shaped.NationalID)
+		assert.Empty(t, shaped.PassportID)
+		assert.Empty(t, shaped.PhoneNumber)
+		assert.Empty(t, shaped.Email)
+		assert.Empty(t, shaped.EmergencyContacts[0].PhoneNumber)
+	})
+
+	t.Run("Unknown role falls back to clerk", func(t *testing.T) {
+		shaped := NewViewer(&entities.JwtClaim{}).Patient(samplePatient())
+
+		assert.Equal(t, "1-xxxx-xxxxx-12-3", shaped.NationalID)
+	})
+
+	t.Run("Does not modify the input", func(t *testing.T) {
+		patient := samplePatient()
+		viewerFor(consts.StaffRoleClerk).Patient(patient)
+
+		assert.Equal(t, samplePatient(), patient)
+	})
+}
+
+func TestReveal(t *testing.T) {
+	t.Run("Clerk may reveal national id", func(t *testing.T) {
+		viewer, err := viewerFor(consts.StaffRoleClerk).WithReveal([]Field{FieldNationalID})
+		assert.NoError(t, err)
+
+		shaped := viewer.Patient(samplePatient())
+		assert.Equal(t, "1234567890123", shaped.NationalID)
+		assert.Equal(t, "xxx-xxx-5678", shaped.PhoneNumber)
+		assert.Equal(t, []Field{FieldNationalID}, viewer.Revealed())
+	})
+
+	t.Run("Reveal needs the scope", func(t *testing.T) {
+		viewer := NewViewer(&entities.JwtClaim{Role: string(consts.StaffRoleClerk)})
+
+		_, err := viewer.WithReveal([]Field{FieldNationalID})
+		assert.ErrorIs(t, err, ErrRevealNotAllowed)
+	})
+
+	t.Run("Auditor cannot reveal", func(t *testing.T) {
+		_, err := viewerFor(consts.StaffRoleAuditor).WithReveal([]Field{FieldNationalID})
+		assert.ErrorIs(t, err, ErrRevealNotAllowed)
+	})
+
+	t.Run("Fields already visible are not audited", func(t *testing.T) {
+		viewer, err := viewerFor(consts.StaffRoleNurse).WithReveal([]Field{FieldPhoneNumber, FieldNationalID})
+		assert.NoError(t, err)
+		assert.Equal(t, []Field{FieldNationalID}, viewer.Revealed())
+	})
+}
+
+func TestParseReveal(t *testing.T) {
+	fields, err := ParseReveal([]string{"national_id, phone_number", "national_id"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Field{FieldNationalID, FieldPhoneNumber}, fields)
+
+	_, err = ParseReveal([]string{"first_name_th"})
+	assert.ErrorIs(t, err, ErrUnknownField)
+}
@@ -1,7 +1,12 @@
 package middlewares
 
 import (
+	"slices"
+	"strconv"
+
 	"github.com/Teemo4621/Hospital-Api/configs"
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
 	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -34,3 +39,48 @@ func (a *AuthMiddleware) JwtAuthentication() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireScope rejects callers whose access token was not issued with the
+// given scope. It must run after JwtAuthentication.
+func (a *AuthMiddleware) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userData, exists := c.Get("user_data")
+		if !exists {
+			utils.UnauthorizedResponse(c, "Unauthorized")
+			c.Abort()
+			return
+		}
+
+		if !slices.Contains(userData.(*entities.JwtClaim).Scopes, scope) {
+			utils.ForbiddenResponse(c, "Forbidden")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireHospital limits a route with a hospital id path parameter to staff
+// of that hospital and to callers with hospital:manage. Others get a 404,
+// so the route does not reveal which hospitals exist. It must run after
+// JwtAuthentication.
+func (a *AuthMiddleware) RequireHospital(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userData, exists := c.Get("user_data")
+		if !exists {
+			utils.UnauthorizedResponse(c, "Unauthorized")
+			c.Abort()
+			return
+		}
+
+		claim := userData.(*entities.JwtClaim)
+		if c.Param(param) != strconv.FormatUint(uint64(claim.HospitalID), 10) && !slices.Contains(claim.Scopes, consts.ScopeHospitalManage) {
+			utils.NotFoundResponse(c, "hospital not found")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks every response of the routes it is used on as deprecated
+// since deprecatedAt, with the Deprecation header of RFC 9745, and as going
+// away at sunset, with the Sunset header of RFC 8594. successor, when set,
+// is linked as the version to move to.
+func Deprecated(deprecatedAt time.Time, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunsetAt := sunset.UTC().Format(http.TimeFormat)
+	link := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Deprecation", deprecation)
+		header.Set("Sunset", sunsetAt)
+		if successor != "" {
+			header.Add("Link", link)
+		}
+		c.Next()
+	}
+}
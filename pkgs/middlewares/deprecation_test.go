@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorMiddleware())
+	deprecatedAt := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+	old := r.Group("/v1", Deprecated(deprecatedAt, sunset, "/v2"))
+	old.GET("/things", func(c *gin.Context) {
+		utils.OkResponse(c, nil)
+	})
+	old.GET("/missing", func(c *gin.Context) {
+		utils.NotFoundResponse(c, "thing not found")
+	})
+
+	for _, path := range []string{"/v1/things", "/v1/missing"} {
+		t.Run(path, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			assert.Equal(t, "@1790812800", resp.Header().Get("Deprecation"))
+			assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", resp.Header().Get("Sunset"))
+			assert.Equal(t, `</v2>; rel="successor-version"`, resp.Header().Get("Link"))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorMiddleware writes the last error a handler attached with c.Error as
+// a problem response. Handlers that have already written a response are
+// left alone.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		problem := utils.ProblemFromError(err)
+		// A version conflict is a failed precondition only when the client
+		// sent one. Otherwise another request changed the row first.
+		if errors.Is(err, entities.ErrVersionConflict) && c.GetHeader("If-Match") == "" {
+			problem.Status = http.StatusConflict
+			problem.Title = http.StatusText(http.StatusConflict)
+		}
+		if problem.Status >= http.StatusInternalServerError {
+			log.Printf("❌ %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+		utils.WriteProblem(c, problem)
+	}
+}
+
+// RouteNotFound answers requests that match no route.
+func RouteNotFound(c *gin.Context) {
+	utils.WriteProblem(c, utils.NewProblem(http.StatusNotFound, "route_not_found", "end point not found"))
+}
+
+// MethodNotAllowed answers requests to a route that exists with another
+// method.
+func MethodNotAllowed(c *gin.Context) {
+	utils.WriteProblem(c, utils.NewProblem(http.StatusMethodNotAllowed, "method_not_allowed", "method is not allowed on this end point"))
+}
@@ -0,0 +1,119 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupErrorRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(RouteNotFound)
+	r.NoMethod(MethodNotAllowed)
+	r.Use(ErrorMiddleware())
+	r.PUT("/things/:id", handler)
+	return r
+}
+
+func serve(r *gin.Engine, method string, path string, header http.Header) (*httptest.ResponseRecorder, utils.Problem) {
+	req, _ := http.NewRequest(method, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, req)
+
+	var problem utils.Problem
+	json.Unmarshal(resp.Body.Bytes(), &problem)
+	return resp, problem
+}
+
+func TestErrorMiddleware(t *testing.T) {
+	t.Run("Translates The Handler Error", func(t *testing.T) {
+		r := setupErrorRouter(func(c *gin.Context) {
+			c.Error(entities.NotFound("thing"))
+		})
+
+		resp, problem := serve(r, http.MethodPut, "/things/1", nil)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Equal(t, utils.ProblemContentType, resp.Header().Get("Content-Type"))
+		assert.Equal(t, "thing_not_found", problem.Code)
+		assert.Equal(t, "thing not found", problem.Detail)
+		assert.Equal(t, "/things/1", problem.Instance)
+	})
+
+	t.Run("Hides Unknown Errors", func(t *testing.T) {
+		r := setupErrorRouter(func(c *gin.Context) {
+			c.Error(errors.New("pq: connection refused"))
+		})
+
+		resp, problem := serve(r, http.MethodPut, "/things/1", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Equal(t, "internal_error", problem.Code)
+		assert.NotContains(t, resp.Body.String(), "connection refused")
+	})
+
+	t.Run("Version Conflict With If-Match", func(t *testing.T) {
+		r := setupErrorRouter(func(c *gin.Context) {
+			c.Error(entities.ErrVersionConflict)
+		})
+
+		resp, problem := serve(r, http.MethodPut, "/things/1", http.Header{"If-Match": {`"3"`}})
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+		assert.Equal(t, "version_conflict", problem.Code)
+	})
+
+	t.Run("Version Conflict Without If-Match", func(t *testing.T) {
+		r := setupErrorRouter(func(c *gin.Context) {
+			c.Error(entities.ErrVersionConflict)
+		})
+
+		resp, problem := serve(r, http.MethodPut, "/things/1", nil)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		assert.Equal(t, http.StatusConflict, problem.Status)
+		assert.Equal(t, "version_conflict", problem.Code)
+	})
+
+	t.Run("Leaves Written Responses Alone", func(t *testing.T) {
+		r := setupErrorRouter(func(c *gin.Context) {
+			c.Error(errors.New("logged only"))
+			c.JSON(http.StatusAccepted, gin.H{"ok": true})
+		})
+
+		resp, _ := serve(r, http.MethodPut, "/things/1", nil)
+
+		assert.Equal(t, http.StatusAccepted, resp.Code)
+		assert.JSONEq(t, `{"ok":true}`, resp.Body.String())
+	})
+
+	t.Run("Unknown Route", func(t *testing.T) {
+		r := setupErrorRouter(func(c *gin.Context) {})
+
+		resp, problem := serve(r, http.MethodGet, "/nowhere", nil)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Equal(t, "route_not_found", problem.Code)
+	})
+
+	t.Run("Wrong Method", func(t *testing.T) {
+		r := setupErrorRouter(func(c *gin.Context) {})
+
+		resp, problem := serve(r, http.MethodDelete, "/things/1", nil)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+		assert.Equal(t, "method_not_allowed", problem.Code)
+	})
+}
@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/openapi"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxValidatedBody is the largest request or response body OpenAPIValidator
+// reads. Larger bodies are passed on unchecked.
+const maxValidatedBody = 1 << 20
+
+// OpenAPIValidator rejects requests that do not match the OpenAPI document
+// with a 400 problem, and logs JSON responses that do not match it. It is
+// meant for development and staging: routes missing from the document are
+// passed on, and mismatched responses are still sent.
+func OpenAPIValidator(doc *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op, ok := doc.Operation(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		body, complete, err := peekBody(c.Request)
+		if err != nil {
+			utils.BadRequestResponse(c, "request body could not be read")
+			return
+		}
+		if complete {
+			params := make(map[string]string, len(c.Params))
+			for _, param := range c.Params {
+				params[param.Key] = param.Value
+			}
+			if err := doc.ValidateRequest(op, c.Request, params, body); err != nil {
+				problem := utils.NewProblem(http.StatusBadRequest, "validation_failed", "request does not match the API document")
+				var validationErr *openapi.ValidationError
+				if errors.As(err, &validationErr) {
+					problem.Errors = []utils.ProblemField{{Field: validationErr.Location, Message: validationErr.Message}}
+				}
+				utils.WriteProblem(c, problem)
+				return
+			}
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if !recorder.recording() {
+			return
+		}
+		if err := doc.ValidateResponse(op, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			log.Printf("⚠️ %s %s answered %d outside the API document: %v", c.Request.Method, c.Request.URL.Path, recorder.Status(), err)
+		}
+	}
+}
+
+// peekBody reads a JSON request body for validation and puts it back for
+// the handler. complete is false when the body is too large to check.
+func peekBody(r *http.Request) ([]byte, bool, error) {
+	contentType := r.Header.Get("Content-Type")
+	if r.Body == nil || r.Body == http.NoBody || (contentType != "" && !strings.Contains(contentType, "json")) {
+		return nil, true, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxValidatedBody+1))
+	if err != nil {
+		return nil, false, err
+	}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+
+	return body, len(body) <= maxValidatedBody, nil
+}
+
+// responseRecorder keeps a copy of JSON responses up to maxValidatedBody.
+// Streamed exports and downloads are passed through without a copy.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	skipped  bool
+	decided  bool
+	tooLarge bool
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.keep(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *responseRecorder) keep(data []byte) {
+	if !w.decided {
+		w.decided = true
+		w.skipped = !strings.Contains(w.Header().Get("Content-Type"), "json")
+	}
+	if w.skipped || w.tooLarge {
+		return
+	}
+	if w.body.Len()+len(data) > maxValidatedBody {
+		w.tooLarge = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+// recording reports whether the whole response body was kept.
+func (w *responseRecorder) recording() bool {
+	return w.decided && !w.skipped && !w.tooLarge
+}
@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/openapi"
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupOpenAPIRouter(t *testing.T) *gin.Engine {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(OpenAPIValidator(doc), ErrorMiddleware())
+	r.POST("/api/v1/staff/login", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		utils.OkResponse(c, gin.H{"echo": string(body)})
+	})
+	r.GET("/undocumented", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func TestOpenAPIValidator(t *testing.T) {
+	r := setupOpenAPIRouter(t)
+
+	t.Run("Rejects A Request Outside The Document", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/staff/login", strings.NewReader(`{"username":"admin"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		var problem utils.Problem
+		json.Unmarshal(resp.Body.Bytes(), &problem)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, []utils.ProblemField{{Field: "body.password", Message: "is required"}}, problem.Errors)
+	})
+
+	t.Run("Passes The Body On To The Handler", func(t *testing.T) {
+		body := `{"username":"admin","password":"secret","hospital":"Siriraj"}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/staff/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		var response struct {
+			Data struct {
+				Echo string `json:"echo"`
+			} `json:"data"`
+		}
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, body, response.Data.Echo)
+	})
+
+	t.Run("Ignores Undocumented Routes", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/undocumented", nil)
+		resp := httptest.NewRecorder()
+
+		r.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "ok", resp.Body.String())
+	})
+}
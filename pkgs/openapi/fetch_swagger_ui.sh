@@ -0,0 +1,25 @@
+#!/bin/sh
+# Fetches the pinned swagger-ui-dist release into swagger-ui/, checking the
+# package against the integrity hash npm publishes for it.
+set -eu
+
+version=5.17.14
+dir=$(cd "$(dirname "$0")" && pwd)/swagger-ui
+registry=https://registry.npmjs.org/swagger-ui-dist
+
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+curl -fsSL "$registry/-/swagger-ui-dist-$version.tgz" -o "$tmp/package.tgz"
+
+expected=$(curl -fsSL "$registry/$version" | sed -n 's/.*"integrity":"\(sha512-[^"]*\)".*/\1/p')
+actual=sha512-$(openssl dgst -sha512 -binary "$tmp/package.tgz" | openssl base64 -A)
+if [ -z "$expected" ] || [ "$expected" != "$actual" ]; then
+	echo "swagger-ui-dist $version does not match its published integrity" >&2
+	exit 1
+fi
+
+tar -xzf "$tmp/package.tgz" -C "$tmp"
+for file in swagger-ui.css swagger-ui-bundle.js LICENSE; do
+	cp "$tmp/package/$file" "$dir/$file"
+done
@@ -0,0 +1,302 @@
+// Package openapi serves the OpenAPI 3.1 document of the API and checks
+// requests and responses against it.
+//
+// The document is kept by hand in openapi.json. Every route mapped by the
+// server must be documented there; the route test of the servers package
+// fails otherwise.
+//
+// Only v2 and the v1 patient routes are written out. Every other v1 route is
+// the v2 one marked deprecated, so its path item is derived when the
+// document is loaded; see expand.
+package openapi
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/utils"
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate ./fetch_swagger_ui.sh
+
+var (
+	//go:embed openapi.json
+	document []byte
+
+	//go:embed swagger.html
+	swaggerUI []byte
+
+	// swaggerAssets holds the vendored swagger-ui-dist files the page
+	// loads, so the docs do not depend on a CDN.
+	//go:embed swagger-ui
+	swaggerAssets embed.FS
+)
+
+type (
+	Document struct {
+		OpenAPI    string              `json:"openapi"`
+		Paths      map[string]PathItem `json:"paths"`
+		Components Components          `json:"components"`
+	}
+
+	// PathItem maps lower case HTTP methods to their operation.
+	PathItem map[string]*Operation
+
+	Operation struct {
+		OperationID string               `json:"operationId"`
+		Parameters  []*Parameter         `json:"parameters"`
+		RequestBody *RequestBody         `json:"requestBody"`
+		Responses   map[string]*Response `json:"responses"`
+	}
+
+	Parameter struct {
+		Ref      string  `json:"$ref"`
+		Name     string  `json:"name"`
+		In       string  `json:"in"`
+		Required bool    `json:"required"`
+		Style    string  `json:"style"`
+		Schema   *Schema `json:"schema"`
+	}
+
+	RequestBody struct {
+		Required bool                 `json:"required"`
+		Content  map[string]MediaType `json:"content"`
+	}
+
+	Response struct {
+		Ref     string               `json:"$ref"`
+		Content map[string]MediaType `json:"content"`
+	}
+
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+
+	Components struct {
+		Schemas    map[string]*Schema    `json:"schemas"`
+		Parameters map[string]*Parameter `json:"parameters"`
+		Responses  map[string]*Response  `json:"responses"`
+	}
+
+	// Schema holds the JSON Schema keywords the validator understands.
+	// Others, such as format, are only documentation.
+	Schema struct {
+		Ref                  string             `json:"$ref"`
+		Type                 Types              `json:"type"`
+		Properties           map[string]*Schema `json:"properties"`
+		Required             []string           `json:"required"`
+		AdditionalProperties *Schema            `json:"additionalProperties"`
+		Items                *Schema            `json:"items"`
+		Enum                 []any              `json:"enum"`
+		AnyOf                []*Schema          `json:"anyOf"`
+		Minimum              *float64           `json:"minimum"`
+	}
+
+	// Types is the type keyword, which OpenAPI 3.1 allows to be a list
+	// such as ["string", "null"].
+	Types []string
+)
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Load parses the embedded document, with the derived v1 routes.
+func Load() (*Document, error) {
+	data, err := expanded()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// ServeJSON answers with the document, with the derived v1 routes.
+func ServeJSON(c *gin.Context) {
+	data, err := expanded()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+const (
+	v1Prefix = "/api/v1/"
+	v2Prefix = "/api/v2/"
+
+	// v2OnlyPrefix holds the v2 routes whose v1 form differs, so the v1
+	// ones are written out in the document instead.
+	v2OnlyPrefix = v2Prefix + "patients"
+)
+
+var (
+	expandOnce   sync.Once
+	expandedDoc  []byte
+	errExpandDoc error
+)
+
+func expanded() ([]byte, error) {
+	expandOnce.Do(func() {
+		expandedDoc, errExpandDoc = expand(document)
+	})
+	return expandedDoc, errExpandDoc
+}
+
+// expand adds the v1 twin of every v2 path item outside v2OnlyPrefix. The
+// twin has the same operations with V1 appended to their ids, marked
+// deprecated, with the deprecation headers on every response and the
+// deprecated problem as the default one.
+func expand(source []byte) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(source, &doc); err != nil {
+		return nil, err
+	}
+
+	paths, ok := doc["paths"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("openapi: document has no paths")
+	}
+
+	v2 := make([]string, 0, len(paths))
+	for route := range paths {
+		if strings.HasPrefix(route, v2Prefix) && !strings.HasPrefix(route, v2OnlyPrefix) {
+			v2 = append(v2, route)
+		}
+	}
+
+	for _, route := range v2 {
+		v1 := v1Prefix + strings.TrimPrefix(route, v2Prefix)
+		if _, ok := paths[v1]; ok {
+			return nil, fmt.Errorf("openapi: %s is derived from %s and must not be written out", v1, route)
+		}
+
+		item, err := deprecated(paths[route])
+		if err != nil {
+			return nil, fmt.Errorf("openapi: %s: %w", route, err)
+		}
+		paths[v1] = item
+	}
+
+	return json.Marshal(doc)
+}
+
+// deprecated returns a copy of a v2 path item as its v1 twin.
+func deprecated(item any) (map[string]any, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations map[string]map[string]any
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]any, len(operations))
+	for method, op := range operations {
+		id, _ := op["operationId"].(string)
+		op["operationId"] = id + "V1"
+		op["deprecated"] = true
+
+		responses, _ := op["responses"].(map[string]any)
+		for status, response := range responses {
+			if status == "default" {
+				responses[status] = map[string]any{"$ref": "#/components/responses/DeprecatedProblem"}
+				continue
+			}
+
+			response, ok := response.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s response %s is not an object", method, status)
+			}
+			headers, _ := response["headers"].(map[string]any)
+			if headers == nil {
+				headers = make(map[string]any)
+			}
+			for _, name := range []string{"Deprecation", "Sunset", "Link"} {
+				headers[name] = map[string]any{"$ref": "#/components/headers/" + name}
+			}
+			response["headers"] = headers
+		}
+
+		out[method] = op
+	}
+	return out, nil
+}
+
+// ServeUI answers with a Swagger UI page that reads the document from
+// /api/openapi.json.
+func ServeUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", swaggerUI)
+}
+
+// ServeAssets answers with the stylesheet and scripts of the Swagger UI
+// page from the embedded copy.
+func ServeAssets(c *gin.Context) {
+	name := path.Join("swagger-ui", path.Clean("/"+c.Param("filepath")))
+	if ext := path.Ext(name); ext != ".css" && ext != ".js" {
+		utils.WriteProblem(c, utils.NewProblem(http.StatusNotFound, "asset_not_found", "asset not found"))
+		return
+	}
+	if _, err := fs.Stat(swaggerAssets, name); err != nil {
+		utils.WriteProblem(c, utils.NewProblem(http.StatusNotFound, "asset_not_found", "asset not found"))
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.FileFromFS(name, http.FS(swaggerAssets))
+}
+
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// PathTemplate turns a gin route such as /patient/:id into the OpenAPI
+// form /patient/{id}.
+func PathTemplate(route string) string {
+	return ginParam.ReplaceAllString(route, "{$1}")
+}
+
+// Operation finds the operation documented for a gin route.
+func (d *Document) Operation(method string, route string) (*Operation, bool) {
+	item, ok := d.Paths[PathTemplate(route)]
+	if !ok {
+		return nil, false
+	}
+	op, ok := item[strings.ToLower(method)]
+	return op, ok
+}
+
+func (d *Document) parameter(p *Parameter) *Parameter {
+	if name, ok := strings.CutPrefix(p.Ref, "#/components/parameters/"); ok {
+		if resolved, ok := d.Components.Parameters[name]; ok {
+			return resolved
+		}
+	}
+	return p
+}
+
+func (d *Document) response(r *Response) *Response {
+	if name, ok := strings.CutPrefix(r.Ref, "#/components/responses/"); ok {
+		if resolved, ok := d.Components.Responses[name]; ok {
+			return resolved
+		}
+	}
+	return r
+}
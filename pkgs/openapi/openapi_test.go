@@ -0,0 +1,72 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	t.Run("Derives Deprecated V1 Twins", func(t *testing.T) {
+		source := `{"paths": {
+			"/api/v2/staff/me": {"get": {"operationId": "getStaffMe", "responses": {
+				"200": {"description": "OK", "headers": {"ETag": {"$ref": "#/components/headers/ETag"}}},
+				"default": {"$ref": "#/components/responses/Problem"}
+			}}},
+			"/api/v2/patients": {"get": {"operationId": "getPatients", "responses": {}}},
+			"/api/v1/patient/": {"get": {"operationId": "getPatientV1", "responses": {}}}
+		}}`
+
+		data, err := expand([]byte(source))
+		require.NoError(t, err)
+
+		var doc struct {
+			Paths map[string]map[string]map[string]any `json:"paths"`
+		}
+		require.NoError(t, json.Unmarshal(data, &doc))
+
+		assert.Len(t, doc.Paths, 4)
+		assert.NotContains(t, doc.Paths, "/api/v1/patients")
+
+		v1 := doc.Paths["/api/v1/staff/me"]["get"]
+		assert.Equal(t, "getStaffMeV1", v1["operationId"])
+		assert.Equal(t, true, v1["deprecated"])
+		assert.Equal(t, map[string]any{
+			"200": map[string]any{"description": "OK", "headers": map[string]any{
+				"ETag":        map[string]any{"$ref": "#/components/headers/ETag"},
+				"Deprecation": map[string]any{"$ref": "#/components/headers/Deprecation"},
+				"Sunset":      map[string]any{"$ref": "#/components/headers/Sunset"},
+				"Link":        map[string]any{"$ref": "#/components/headers/Link"},
+			}},
+			"default": map[string]any{"$ref": "#/components/responses/DeprecatedProblem"},
+		}, v1["responses"])
+
+		v2 := doc.Paths["/api/v2/staff/me"]["get"]
+		assert.Equal(t, "getStaffMe", v2["operationId"])
+		assert.NotContains(t, v2, "deprecated")
+	})
+
+	t.Run("Refuses Written Out Twins", func(t *testing.T) {
+		source := `{"paths": {
+			"/api/v2/staff/me": {"get": {"operationId": "getStaffMe", "responses": {}}},
+			"/api/v1/staff/me": {"get": {"operationId": "getStaffMeV1", "responses": {}}}
+		}}`
+
+		_, err := expand([]byte(source))
+		assert.EqualError(t, err, "openapi: /api/v1/staff/me is derived from /api/v2/staff/me and must not be written out")
+	})
+}
+
+func TestLoadDerivesV1Routes(t *testing.T) {
+	doc := load(t)
+
+	op := operation(t, doc, "DELETE", "/api/v1/hospitals/:id")
+	assert.Equal(t, "deleteHospitalsIdV1", op.OperationID)
+	assert.Equal(t, "#/components/responses/DeprecatedProblem", op.Responses["default"].Ref)
+
+	op = operation(t, doc, "DELETE", "/api/v2/hospitals/:id")
+	assert.Equal(t, "deleteHospitalsId", op.OperationID)
+	assert.Equal(t, "#/components/responses/Problem", op.Responses["default"].Ref)
+}
@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ValidationError reports the first part of a request or response that does
+// not match the document. Location names it, such as query.page or
+// body.addresses[0].city.
+type ValidationError struct {
+	Location string
+	Message  string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Location + ": " + e.Message
+}
+
+// ValidateRequest checks the path and query parameters of a request and its
+// JSON body, already read into body. Headers are left to the handlers, which
+// answer a missing If-Match with their own status.
+func (d *Document) ValidateRequest(op *Operation, r *http.Request, pathParams map[string]string, body []byte) error {
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		p = d.parameter(p)
+		if p.Schema == nil {
+			continue
+		}
+
+		switch p.In {
+		case "path":
+			if err := d.validateParam("path."+p.Name, p.Schema, []string{pathParams[p.Name]}); err != nil {
+				return err
+			}
+		case "query":
+			if p.Style == "deepObject" {
+				continue
+			}
+			values, ok := query[p.Name]
+			if !ok {
+				if p.Required {
+					return &ValidationError{Location: "query." + p.Name, Message: "is required"}
+				}
+				continue
+			}
+			if err := d.validateParam("query."+p.Name, p.Schema, values); err != nil {
+				return err
+			}
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok || media.Schema == nil {
+		return nil
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "" && !isJSON(contentType) {
+		return &ValidationError{Location: "body", Message: fmt.Sprintf("content type %s is not accepted", contentType)}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return &ValidationError{Location: "body", Message: "is required"}
+		}
+		return nil
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		// Malformed JSON is reported by the handler, like without the
+		// validator.
+		return nil
+	}
+	return d.Validate("body", media.Schema, value)
+}
+
+// ValidateResponse checks a JSON response body against the response
+// documented for its status, or the default one.
+func (d *Document) ValidateResponse(op *Operation, status int, contentType string, body []byte) error {
+	response, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		if response, ok = op.Responses["default"]; !ok {
+			return &ValidationError{Location: "response", Message: fmt.Sprintf("status %d is not documented", status)}
+		}
+	}
+	response = d.response(response)
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := response.Content[mediaType]
+	if !ok {
+		return &ValidationError{Location: "response", Message: fmt.Sprintf("content type %s is not documented for status %d", mediaType, status)}
+	}
+	if media.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	value, err := decode(body)
+	if err != nil {
+		return &ValidationError{Location: "response", Message: "body is not valid JSON"}
+	}
+	return d.Validate("response", media.Schema, value)
+}
+
+// Validate checks a decoded JSON value against a schema. Numbers must be
+// decoded as json.Number.
+func (d *Document) Validate(location string, schema *Schema, value any) error {
+	schema, err := d.resolve(location, schema)
+	if err != nil {
+		return err
+	}
+
+	if len(schema.AnyOf) > 0 {
+		for _, option := range schema.AnyOf {
+			if d.Validate(location, option, value) == nil {
+				return nil
+			}
+		}
+		return &ValidationError{Location: location, Message: "matches none of the allowed schemas"}
+	}
+
+	if len(schema.Type) > 0 && !slices.ContainsFunc(schema.Type, func(t string) bool { return hasType(value, t) }) {
+		return &ValidationError{Location: location, Message: fmt.Sprintf("must be of type %s", strings.Join(schema.Type, " or "))}
+	}
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(allowed any) bool { return fmt.Sprint(allowed) == fmt.Sprint(value) }) {
+		return &ValidationError{Location: location, Message: fmt.Sprintf("must be one of %v", schema.Enum)}
+	}
+	if number, ok := value.(json.Number); ok && schema.Minimum != nil {
+		if f, err := number.Float64(); err == nil && f < *schema.Minimum {
+			return &ValidationError{Location: location, Message: fmt.Sprintf("must be at least %v", *schema.Minimum)}
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, ok := value[name]; !ok {
+				return &ValidationError{Location: location + "." + name, Message: "is required"}
+			}
+		}
+		for name, field := range value {
+			property, ok := schema.Properties[name]
+			if !ok {
+				property = schema.AdditionalProperties
+			}
+			if property == nil {
+				continue
+			}
+			if err := d.Validate(location+"."+name, property, field); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if schema.Items == nil {
+			return nil
+		}
+		for i, item := range value {
+			if err := d.Validate(fmt.Sprintf("%s[%d]", location, i), schema.Items, item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Document) resolve(location string, schema *Schema) (*Schema, error) {
+	for schema.Ref != "" {
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		resolved := d.Components.Schemas[name]
+		if !ok || resolved == nil {
+			return nil, &ValidationError{Location: location, Message: fmt.Sprintf("schema %s is not defined", schema.Ref)}
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+// validateParam checks the raw values of a path or query parameter. Only
+// scalar types and arrays of them are supported.
+func (d *Document) validateParam(location string, schema *Schema, values []string) error {
+	schema, err := d.resolve(location, schema)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(schema.Type, "array") {
+		if schema.Items == nil {
+			return nil
+		}
+		for _, value := range values {
+			if err := d.validateParam(location, schema.Items, []string{value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	raw := values[0]
+	var value any = raw
+	switch {
+	case slices.Contains(schema.Type, "integer"), slices.Contains(schema.Type, "number"):
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return &ValidationError{Location: location, Message: "must be a number"}
+		}
+		value = json.Number(raw)
+	case slices.Contains(schema.Type, "boolean"):
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return &ValidationError{Location: location, Message: "must be true or false"}
+		}
+		value = parsed
+	}
+	return d.Validate(location, schema, value)
+}
+
+func hasType(value any, t string) bool {
+	switch value := value.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		_, err := value.Int64()
+		return t == "integer" && err == nil
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	}
+	return false
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func load(t *testing.T) *Document {
+	t.Helper()
+	doc, err := Load()
+	require.NoError(t, err)
+	return doc
+}
+
+func operation(t *testing.T, doc *Document, method string, route string) *Operation {
+	t.Helper()
+	op, ok := doc.Operation(method, route)
+	require.True(t, ok, "%s %s is not documented", method, route)
+	return op
+}
+
+func TestPathTemplate(t *testing.T) {
+	assert.Equal(t, "/api/v1/patient/{id}/addresses/{addressId}", PathTemplate("/api/v1/patient/:id/addresses/:addressId"))
+	assert.Equal(t, "/files/{path}", PathTemplate("/files/*path"))
+}
+
+func TestValidateRequest(t *testing.T) {
+	doc := load(t)
+	login := operation(t, doc, http.MethodPost, "/api/v1/staff/login")
+	listHospitals := operation(t, doc, http.MethodGet, "/api/v1/hospitals/")
+	findHospital := operation(t, doc, http.MethodGet, "/api/v1/hospitals/:id")
+
+	tests := []struct {
+		name     string
+		op       *Operation
+		target   string
+		params   map[string]string
+		body     string
+		location string
+	}{
+		{"Valid Body", login, "/api/v1/staff/login", nil, `{"username":"admin","password":"secret","hospital":"Siriraj"}`, ""},
+		{"Missing Body", login, "/api/v1/staff/login", nil, ``, "body"},
+		{"Missing Required Field", login, "/api/v1/staff/login", nil, `{"username":"admin"}`, "body.password"},
+		{"Wrong Field Type", login, "/api/v1/staff/login", nil, `{"username":1,"password":"secret"}`, "body.username"},
+		{"Malformed Body Is Left To The Handler", login, "/api/v1/staff/login", nil, `{"username":`, ""},
+		{"Valid Query", listHospitals, "/api/v1/hospitals/?page=2&filter[province][eq]=Bangkok", nil, ``, ""},
+		{"Bad Query Number", listHospitals, "/api/v1/hospitals/?page=two", nil, ``, "query.page"},
+		{"Bad Path Parameter", findHospital, "/api/v1/hospitals/abc", map[string]string{"id": "abc"}, ``, "path.id"},
+		{"Negative Path Parameter", findHospital, "/api/v1/hospitals/-1", map[string]string{"id": "-1"}, ``, "path.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			err := doc.ValidateRequest(tt.op, req, tt.params, []byte(tt.body))
+
+			if tt.location == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.location, validationErr.Location)
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc := load(t)
+	op := operation(t, doc, http.MethodGet, "/api/v1/hospitals/:id")
+
+	t.Run("Envelope", func(t *testing.T) {
+		err := doc.ValidateResponse(op, http.StatusOK, "application/json; charset=utf-8",
+			[]byte(`{"data":{"id":1,"hospital_name":"Siriraj","deleted_at":null},"message":"success","status":"ok"}`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Missing Envelope", func(t *testing.T) {
+		err := doc.ValidateResponse(op, http.StatusOK, "application/json", []byte(`{"id":1}`))
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "response.data", validationErr.Location)
+	})
+
+	t.Run("Wrong Nested Type", func(t *testing.T) {
+		err := doc.ValidateResponse(op, http.StatusOK, "application/json",
+			[]byte(`{"data":{"id":"1"},"message":"success","status":"ok"}`))
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "response.data.id", validationErr.Location)
+	})
+
+	t.Run("Problem", func(t *testing.T) {
+		err := doc.ValidateResponse(op, http.StatusNotFound, "application/problem+json",
+			[]byte(`{"type":"about:blank","title":"Not Found","status":404,"code":"hospital_not_found"}`))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Undocumented Content Type", func(t *testing.T) {
+		err := doc.ValidateResponse(op, http.StatusOK, "text/plain", []byte(`ok`))
+
+		assert.Error(t, err)
+	})
+}
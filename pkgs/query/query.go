@@ -0,0 +1,434 @@
+// Package query reads the parameters shared by the listing endpoints and
+// turns them into gorm scopes:
+//
+//	filter[province][eq]=Bangkok&filter[created_at][gte]=2024-01-01
+//	sort=-created_at,hospital_name
+//	fields=id,hospital_name
+//	include=hospital
+//	page=2&limit=20
+//
+// Every name is looked up in the Resource of the listing, so the columns in
+// the generated SQL never come from the request itself.
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+)
+
+// Op compares a field with the filter value.
+type Op string
+
+const (
+	OpEq   Op = "eq"
+	OpNe   Op = "ne"
+	OpGt   Op = "gt"
+	OpGte  Op = "gte"
+	OpLt   Op = "lt"
+	OpLte  Op = "lte"
+	OpLike Op = "like"
+	OpIn   Op = "in"
+	OpNull Op = "null"
+)
+
+// Kind is the type filter values of a field are parsed as.
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Bool
+	Date
+	Time
+)
+
+// MaxInValues limits the number of values of an in filter.
+const MaxInValues = 100
+
+type (
+	// Field is a filterable field. A field without a Column is applied by
+	// the repository with a FilterFunc.
+	Field struct {
+		Column string
+		Kind   Kind
+		Ops    []Op
+		// Valid, when set, must accept every value of the filter.
+		Valid func(value string) bool
+	}
+
+	// Alias is a shorthand parameter kept for older clients, such as
+	// ?name= for filter[hospital_name][like]=.
+	Alias struct {
+		Field string
+		Op    Op
+	}
+
+	// Resource lists what a listing may be filtered, sorted, trimmed and
+	// expanded by.
+	Resource struct {
+		Filters map[string]Field
+		Aliases map[string]Alias
+		// Sorts maps the names accepted in sort= to columns.
+		Sorts map[string]string
+		// Fields maps the names accepted in fields= to columns. The names
+		// are the JSON names of the listed items.
+		Fields map[string]string
+		// Required lists the columns selected whatever fields= asks for,
+		// such as the keys relations are loaded by.
+		Required []string
+		// Includes maps the names accepted in include= to the association
+		// preloaded. The names are the JSON names of the relations.
+		Includes map[string]string
+		// DefaultIncludes are loaded when the request has no include=.
+		DefaultIncludes []string
+		DefaultLimit    int
+		MaxLimit        int
+	}
+
+	// Filter is one parsed filter. Value is a slice for OpIn and a bool for
+	// OpNull.
+	Filter struct {
+		Field  string
+		Column string
+		Op     Op
+		Value  any
+	}
+
+	// SortField orders a listing by one column taken from a Resource.
+	SortField struct {
+		Column string
+		Desc   bool
+	}
+
+	// Spec is a parsed and validated listing request.
+	Spec struct {
+		Filters []Filter
+		Sort    []SortField
+		// Fields are the names asked for with fields=, and Columns the
+		// columns they need. Both are empty when every field is wanted.
+		Fields   []string
+		Columns  []string
+		Includes []string
+		Preloads []string
+		Page     int
+		Limit    int
+	}
+
+	// Error reports an invalid parameter, named as written in the request.
+	Error struct {
+		Param   string
+		Message string
+	}
+)
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Parse validates the request parameters against the resource.
+func (r *Resource) Parse(values url.Values) (*Spec, error) {
+	page, limit, err := ParsePage(values, r.DefaultLimit, r.MaxLimit)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{Page: page, Limit: limit}
+
+	if spec.Filters, err = r.parseFilters(values); err != nil {
+		return nil, err
+	}
+	if spec.Sort, err = ParseSort(values.Get("sort"), r.Sorts); err != nil {
+		return nil, err
+	}
+	if err := r.parseFields(spec, values.Get("fields")); err != nil {
+		return nil, err
+	}
+
+	includes := r.DefaultIncludes
+	if _, ok := values["include"]; ok {
+		includes = splitList(values.Get("include"))
+	}
+	for _, name := range includes {
+		association, ok := r.Includes[name]
+		if !ok {
+			return nil, &Error{Param: "include", Message: fmt.Sprintf("cannot include %q", name)}
+		}
+		if !slices.Contains(spec.Includes, name) {
+			spec.Includes = append(spec.Includes, name)
+			spec.Preloads = append(spec.Preloads, association)
+		}
+	}
+
+	return spec, nil
+}
+
+// ParsePage reads page= and limit=. A page below 1 becomes 1, a limit below
+// 1 becomes defaultLimit and one above maxLimit becomes maxLimit.
+func ParsePage(values url.Values, defaultLimit int, maxLimit int) (int, int, error) {
+	page, limit := 1, defaultLimit
+
+	if value := values.Get("page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, &Error{Param: "page", Message: "page must be an integer"}
+		}
+		page = max(parsed, 1)
+	}
+
+	if value := values.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, &Error{Param: "limit", Message: "limit must be an integer"}
+		}
+		if parsed >= 1 {
+			limit = parsed
+		}
+	}
+	if maxLimit > 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+
+	return page, limit, nil
+}
+
+// ParseSort reads a sort= value such as "province,-created_at" or
+// "province:asc,created_at:desc". Each name must be a key of columns.
+func ParseSort(value string, columns map[string]string) ([]SortField, error) {
+	var fields []SortField
+	seen := make(map[string]bool)
+
+	for _, item := range splitList(value) {
+		desc := false
+		if strings.HasPrefix(item, "-") {
+			desc, item = true, item[1:]
+		} else if name, direction, ok := strings.Cut(item, ":"); ok {
+			switch strings.ToLower(direction) {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return nil, &Error{Param: "sort", Message: fmt.Sprintf("sort direction of %s must be asc or desc", name)}
+			}
+			item = name
+		}
+
+		column, ok := columns[item]
+		if !ok {
+			return nil, &Error{Param: "sort", Message: fmt.Sprintf("cannot sort by %q", item)}
+		}
+		if seen[item] {
+			return nil, &Error{Param: "sort", Message: fmt.Sprintf("%s is listed twice in sort", item)}
+		}
+		seen[item] = true
+
+		fields = append(fields, SortField{Column: column, Desc: desc})
+	}
+
+	return fields, nil
+}
+
+// TotalPages is the number of pages count rows fill at the spec's limit.
+func (s *Spec) TotalPages(count int64) int {
+	return int((count + int64(s.Limit) - 1) / int64(s.Limit))
+}
+
+// Offset is the number of rows before the spec's page.
+func (s *Spec) Offset() int {
+	return (s.Page - 1) * s.Limit
+}
+
+// Pick trims each of the listed items to the fields and includes of the
+// spec. Items are returned as they are when the request had no fields=.
+func (s *Spec) Pick(items any) (any, error) {
+	if len(s.Fields) == 0 {
+		return items, nil
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for key := range row {
+			if !slices.Contains(s.Fields, key) && !slices.Contains(s.Includes, key) {
+				delete(row, key)
+			}
+		}
+	}
+	return rows, nil
+}
+
+func (r *Resource) parseFilters(values url.Values) ([]Filter, error) {
+	// Go maps have no order; sort the parameters so that errors and the
+	// generated SQL do not change from one request to the next.
+	params := make([]string, 0, len(values))
+	for param := range values {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	var filters []Filter
+	for _, param := range params {
+		var name string
+		var op Op
+		if alias, ok := r.Aliases[param]; ok {
+			name, op = alias.Field, alias.Op
+		} else if strings.HasPrefix(param, "filter[") {
+			var ok bool
+			if name, op, ok = splitFilterParam(param); !ok {
+				return nil, &Error{Param: param, Message: fmt.Sprintf("%s must be written as filter[field][op]", param)}
+			}
+		} else {
+			continue
+		}
+
+		field, ok := r.Filters[name]
+		if !ok {
+			return nil, &Error{Param: param, Message: fmt.Sprintf("cannot filter by %q", name)}
+		}
+		if !slices.Contains(field.Ops, op) {
+			return nil, &Error{Param: param, Message: fmt.Sprintf("%s does not support the %s operator", name, op)}
+		}
+
+		for _, raw := range values[param] {
+			raw = strings.TrimSpace(raw)
+			if raw == "" && r.Aliases[param].Field != "" {
+				// An empty shorthand such as ?name= means no filter.
+				continue
+			}
+			value, err := field.parse(op, raw)
+			if err != nil {
+				return nil, &Error{Param: param, Message: fmt.Sprintf("%s %s", param, err.Error())}
+			}
+			filters = append(filters, Filter{Field: name, Column: field.Column, Op: op, Value: value})
+		}
+	}
+
+	return filters, nil
+}
+
+func (r *Resource) parseFields(spec *Spec, value string) error {
+	names := splitList(value)
+	if len(names) == 0 {
+		return nil
+	}
+
+	columns := append([]string(nil), r.Required...)
+	for _, name := range names {
+		column, ok := r.Fields[name]
+		if !ok {
+			return &Error{Param: "fields", Message: fmt.Sprintf("unknown field %q", name)}
+		}
+		if slices.Contains(spec.Fields, name) {
+			continue
+		}
+		spec.Fields = append(spec.Fields, name)
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	spec.Columns = columns
+
+	return nil
+}
+
+// splitFilterParam splits filter[name][op], or filter[name] meaning eq.
+func splitFilterParam(param string) (string, Op, bool) {
+	rest, ok := strings.CutPrefix(param, "filter[")
+	if !ok || !strings.HasSuffix(rest, "]") {
+		return "", "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(rest, "]"), "][")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return parts[0], OpEq, true
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], Op(parts[1]), true
+	}
+	return "", "", false
+}
+
+func (f Field) parse(op Op, raw string) (any, error) {
+	switch op {
+	case OpNull:
+		null, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return null, nil
+	case OpIn:
+		items := splitList(raw)
+		if len(items) == 0 || len(items) > MaxInValues {
+			return nil, fmt.Errorf("must list between 1 and %d values", MaxInValues)
+		}
+		values := make([]any, len(items))
+		for i, item := range items {
+			value, err := f.parseValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+	return f.parseValue(raw)
+}
+
+func (f Field) parseValue(raw string) (any, error) {
+	if f.Valid != nil && !f.Valid(raw) {
+		return nil, fmt.Errorf("has an invalid value %q", raw)
+	}
+
+	switch f.Kind {
+	case Int:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return value, nil
+	case Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return value, nil
+	case Date:
+		value, err := civil.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a date in YYYY-MM-DD form")
+		}
+		return value, nil
+	case Time:
+		if value, err := time.Parse(time.RFC3339, raw); err == nil {
+			return value, nil
+		}
+		value, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be an RFC 3339 time or a YYYY-MM-DD date")
+		}
+		return value, nil
+	}
+	return raw, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
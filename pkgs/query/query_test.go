@@ -0,0 +1,232 @@
+package query
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/civil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var testResource = &Resource{
+	Filters: map[string]Field{
+		"name":       {Column: "name", Ops: []Op{OpEq, OpLike, OpIn}},
+		"age":        {Column: "age", Kind: Int, Ops: []Op{OpGt, OpLte, OpIn}},
+		"born":       {Column: "born", Kind: Date, Ops: []Op{OpGte}},
+		"created_at": {Column: "created_at", Kind: Time, Ops: []Op{OpLt}},
+		"archived":   {Column: "archived_at", Ops: []Op{OpNull}},
+		"color":      {Column: "color", Ops: []Op{OpEq}, Valid: func(v string) bool { return v == "red" || v == "blue" }},
+		"team":       {Kind: Int, Ops: []Op{OpEq}},
+	},
+	Aliases: map[string]Alias{
+		"q": {Field: "name", Op: OpLike},
+	},
+	Sorts: map[string]string{
+		"name":       "name",
+		"created_at": "created_at",
+	},
+	Fields: map[string]string{
+		"id":   "id",
+		"name": "name",
+		"age":  "age",
+	},
+	Required:        []string{"id", "owner_id"},
+	Includes:        map[string]string{"owner": "Owner", "tags": "Tags"},
+	DefaultIncludes: []string{"owner"},
+	DefaultLimit:    10,
+	MaxLimit:        50,
+}
+
+func parse(t *testing.T, raw string) (*Spec, error) {
+	t.Helper()
+	values, err := url.ParseQuery(raw)
+	require.NoError(t, err)
+	return testResource.Parse(values)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		spec, err := parse(t, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, &Spec{Includes: []string{"owner"}, Preloads: []string{"Owner"}, Page: 1, Limit: 10}, spec)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		spec, err := parse(t, "filter[age][in]=30,40&filter[name]=Ann&filter[born][gte]=1990-01-31&filter[created_at][lt]=2024-05-01T10:00:00Z&filter[archived][null]=true&q=an")
+
+		require.NoError(t, err)
+		assert.Equal(t, []Filter{
+			{Field: "age", Column: "age", Op: OpIn, Value: []any{int64(30), int64(40)}},
+			{Field: "archived", Column: "archived_at", Op: OpNull, Value: true},
+			{Field: "born", Column: "born", Op: OpGte, Value: civil.Date{Year: 1990, Month: time.January, Day: 31}},
+			{Field: "created_at", Column: "created_at", Op: OpLt, Value: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+			{Field: "name", Column: "name", Op: OpEq, Value: "Ann"},
+			{Field: "name", Column: "name", Op: OpLike, Value: "an"},
+		}, spec.Filters)
+	})
+
+	t.Run("Empty Shorthand Is Ignored", func(t *testing.T) {
+		spec, err := parse(t, "q=")
+
+		require.NoError(t, err)
+		assert.Empty(t, spec.Filters)
+	})
+
+	t.Run("Sort Fields And Includes", func(t *testing.T) {
+		spec, err := parse(t, "sort=-created_at,name&fields=name,age,name&include=tags")
+
+		require.NoError(t, err)
+		assert.Equal(t, []SortField{{Column: "created_at", Desc: true}, {Column: "name"}}, spec.Sort)
+		assert.Equal(t, []string{"name", "age"}, spec.Fields)
+		assert.Equal(t, []string{"id", "owner_id", "name", "age"}, spec.Columns)
+		assert.Equal(t, []string{"tags"}, spec.Includes)
+		assert.Equal(t, []string{"Tags"}, spec.Preloads)
+	})
+
+	t.Run("Empty Include Drops The Defaults", func(t *testing.T) {
+		spec, err := parse(t, "include=")
+
+		require.NoError(t, err)
+		assert.Empty(t, spec.Includes)
+	})
+
+	t.Run("Page And Limit Are Clamped", func(t *testing.T) {
+		spec, err := parse(t, "page=0&limit=500")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, spec.Page)
+		assert.Equal(t, 50, spec.Limit)
+	})
+
+	errorTests := []struct {
+		name  string
+		query string
+		param string
+	}{
+		{"Unknown Filter Field", "filter[password][eq]=x", "filter[password][eq]"},
+		{"Unsupported Operator", "filter[name][gt]=x", "filter[name][gt]"},
+		{"Malformed Filter", "filter[name][eq][x]=1", "filter[name][eq][x]"},
+		{"Bad Integer", "filter[age][gt]=old", "filter[age][gt]"},
+		{"Bad Date", "filter[born][gte]=31/01/1990", "filter[born][gte]"},
+		{"Bad Time", "filter[created_at][lt]=yesterday", "filter[created_at][lt]"},
+		{"Bad Null", "filter[archived][null]=maybe", "filter[archived][null]"},
+		{"Value Not Allowed", "filter[color][eq]=green", "filter[color][eq]"},
+		{"Empty In", "filter[age][in]=,", "filter[age][in]"},
+		{"Unknown Sort", "sort=password", "sort"},
+		{"Sort Listed Twice", "sort=name,-name", "sort"},
+		{"Unknown Field", "fields=password", "fields"},
+		{"Unknown Include", "include=secrets", "include"},
+		{"Bad Page", "page=first", "page"},
+		{"Bad Limit", "limit=all", "limit"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(t, tt.query)
+
+			var queryErr *Error
+			require.ErrorAs(t, err, &queryErr)
+			assert.Equal(t, tt.param, queryErr.Param)
+		})
+	}
+}
+
+func TestScopes(t *testing.T) {
+	type row struct {
+		ID   uint
+		Name string
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	t.Run("Where List And Custom Filters", func(t *testing.T) {
+		spec, err := parse(t, "filter[age][gt]=30&filter[team]=4&q=a_b&sort=-name&fields=name&include=&page=3")
+		require.NoError(t, err)
+		custom := map[string]FilterFunc{
+			"team": func(db *gorm.DB, filter Filter) *gorm.DB {
+				return db.Where("team_id IN (SELECT id FROM teams WHERE parent_id = ?)", filter.Value)
+			},
+		}
+
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var rows []row
+			return tx.Table("rows").Scopes(spec.Where(custom), spec.List).Find(&rows)
+		})
+
+		assert.Equal(t, `SELECT "id",owner_id,"name" FROM "rows" WHERE "age" > 30 AND team_id IN (SELECT id FROM teams WHERE parent_id = 4) AND "name" ILIKE '%a\_b%' ORDER BY "name" DESC,id LIMIT 10 OFFSET 20`, sql)
+	})
+
+	t.Run("Missing Custom Filter Fails", func(t *testing.T) {
+		spec, err := parse(t, "filter[team]=4")
+		require.NoError(t, err)
+
+		var rows []row
+		result := db.Table("rows").Scopes(spec.Where(nil)).Find(&rows)
+
+		assert.Error(t, result.Error)
+	})
+
+	t.Run("Null And In", func(t *testing.T) {
+		spec, err := parse(t, "filter[archived][null]=false&filter[name][in]=a,b")
+		require.NoError(t, err)
+
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var rows []row
+			return tx.Table("rows").Scopes(spec.Where(nil)).Find(&rows)
+		})
+
+		assert.Equal(t, `SELECT * FROM "rows" WHERE "archived_at" IS NOT NULL AND "name" IN ('a','b')`, sql)
+	})
+}
+
+func toJSON(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestPick(t *testing.T) {
+	type item struct {
+		ID    uint     `json:"id"`
+		Name  string   `json:"name"`
+		Age   int      `json:"age"`
+		Owner string   `json:"owner"`
+		Tags  []string `json:"tags"`
+	}
+	items := []item{{ID: 1, Name: "Ann", Age: 30, Owner: "Bob", Tags: []string{"x"}}}
+
+	t.Run("Keeps Fields And Includes", func(t *testing.T) {
+		spec, err := parse(t, "fields=name&include=tags")
+		require.NoError(t, err)
+
+		picked, err := spec.Pick(items)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name":"Ann","tags":["x"]}]`, toJSON(t, picked))
+	})
+
+	t.Run("Returns Items Without Fields", func(t *testing.T) {
+		spec, err := parse(t, "")
+		require.NoError(t, err)
+
+		picked, err := spec.Pick(items)
+
+		require.NoError(t, err)
+		assert.Equal(t, items, picked)
+	})
+}
+
+func TestTotalPages(t *testing.T) {
+	spec := &Spec{Page: 1, Limit: 10}
+
+	assert.Equal(t, 0, spec.TotalPages(0))
+	assert.Equal(t, 1, spec.TotalPages(10))
+	assert.Equal(t, 2, spec.TotalPages(11))
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FilterFunc applies a filter on a field the repository resolves itself,
+// such as a name matched across several columns.
+type FilterFunc func(db *gorm.DB, filter Filter) *gorm.DB
+
+// Where returns a scope applying the spec's filters. Filters on fields
+// without a Column are passed to the custom func of the same name.
+func (s *Spec) Where(custom map[string]FilterFunc) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, filter := range s.Filters {
+			if filter.Column != "" {
+				db = db.Where(Compare(filter.Column, filter.Op, filter.Value))
+				continue
+			}
+			apply, ok := custom[filter.Field]
+			if !ok {
+				db.AddError(fmt.Errorf("query: no filter func for %s", filter.Field))
+				return db
+			}
+			db = apply(db, filter)
+		}
+		return db
+	}
+}
+
+// List returns a scope applying the spec's sort, fields, includes and page.
+// It is meant for the query that loads the rows, not the one counting them.
+func (s *Spec) List(db *gorm.DB) *gorm.DB {
+	db = OrderBy(db, s.Sort)
+	if len(s.Columns) > 0 {
+		db = db.Select(s.Columns)
+	}
+	for _, association := range s.Preloads {
+		db = db.Preload(association)
+	}
+	return db.Offset(s.Offset()).Limit(s.Limit)
+}
+
+// Compare builds the condition of one filter.
+func Compare(column string, op Op, value any) clause.Expression {
+	col := clause.Column{Name: column}
+	switch op {
+	case OpNe:
+		return clause.Neq{Column: col, Value: value}
+	case OpGt:
+		return clause.Gt{Column: col, Value: value}
+	case OpGte:
+		return clause.Gte{Column: col, Value: value}
+	case OpLt:
+		return clause.Lt{Column: col, Value: value}
+	case OpLte:
+		return clause.Lte{Column: col, Value: value}
+	case OpLike:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []any{col, ContainsPattern(fmt.Sprint(value))}}
+	case OpIn:
+		values, _ := value.([]any)
+		return clause.IN{Column: col, Values: values}
+	case OpNull:
+		if null, _ := value.(bool); null {
+			return clause.Eq{Column: col, Value: nil}
+		}
+		return clause.Neq{Column: col, Value: nil}
+	}
+	return clause.Eq{Column: col, Value: value}
+}
+
+// OrderBy applies the sort fields to the query, then orders by id so that
+// rows with equal sort values keep the same order from page to page.
+func OrderBy(query *gorm.DB, fields []SortField) *gorm.DB {
+	for _, field := range fields {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: field.Desc})
+	}
+	return query.Order("id")
+}
+
+// ContainsPattern returns an ILIKE pattern matching value anywhere, with
+// the LIKE wildcards in value escaped.
+func ContainsPattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(value) + "%"
+}
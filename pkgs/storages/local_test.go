@@ -0,0 +1,36 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+	ctx := context.Background()
+
+	assert.NoError(t, storage.Put(ctx, "patients/1/id-card.png", strings.NewReader("png"), 3, "image/png"))
+
+	body, err := storage.Get(ctx, "patients/1/id-card.png")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "png", string(data))
+
+	assert.NoError(t, storage.Delete(ctx, "patients/1/id-card.png"))
+
+	_, err = storage.Get(ctx, "patients/1/id-card.png")
+	assert.True(t, errors.Is(err, ErrObjectNotFound))
+}
+
+func TestLocalStorageRejectsTraversal(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir())
+
+	err := storage.Put(context.Background(), "../escape.txt", strings.NewReader("x"), 1, "text/plain")
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package storages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/configs"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage keeps binary objects such as patient attachments outside the
+// database. Keys are slash separated relative paths.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func NewStorage(cfg configs.Storage) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		root := cfg.LocalPath
+		if root == "" {
+			root = "uploads"
+		}
+		return NewLocalStorage(root), nil
+	case "s3":
+		return NewS3Storage(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,129 @@
+package tabular
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("file format must be csv or xlsx")
+
+func (f Format) IsValid() bool {
+	return f == FormatCSV || f == FormatXLSX
+}
+
+// FormatFromFilename picks the format from the file extension.
+func FormatFromFilename(name string) (Format, error) {
+	format := Format(strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), "."))
+	if !format.IsValid() {
+		return "", ErrUnsupportedFormat
+	}
+	return format, nil
+}
+
+// Reader yields spreadsheet rows one at a time and returns io.EOF after the
+// last one. Rows may be shorter than the header when trailing cells are
+// empty.
+type Reader interface {
+	Read() ([]string, error)
+	Close() error
+}
+
+func NewReader(format Format, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r), nil
+	case FormatXLSX:
+		return newXLSXReader(r)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	first  bool
+}
+
+func newCSVReader(r io.Reader) *csvReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = false
+	return &csvReader{reader: reader, first: true}
+}
+
+func (r *csvReader) Read() ([]string, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	// Excel writes a UTF-8 byte order mark when saving CSV files.
+	if r.first && len(record) > 0 {
+		record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		r.first = false
+	}
+	return record, nil
+}
+
+func (r *csvReader) Close() error {
+	return nil
+}
+
+// xlsxReader streams the first worksheet. The workbook itself is a zip
+// archive, so excelize buffers the file while the rows are decoded lazily.
+type xlsxReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+}
+
+func newXLSXReader(r io.Reader) (*xlsxReader, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		file.Close()
+		return nil, errors.New("xlsx file has no worksheets")
+	}
+
+	rows, err := file.Rows(sheets[0])
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &xlsxReader{file: file, rows: rows}, nil
+}
+
+func (r *xlsxReader) Read() ([]string, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return r.rows.Columns()
+}
+
+func (r *xlsxReader) Close() error {
+	rowsErr := r.rows.Close()
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	return rowsErr
+}
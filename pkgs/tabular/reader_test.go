@@ -0,0 +1,70 @@
+package tabular
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func readAll(t *testing.T, reader Reader) [][]string {
+	t.Helper()
+	defer reader.Close()
+
+	var rows [][]string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	format, err := FormatFromFilename("Patients.XLSX")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatXLSX, format)
+
+	_, err = FormatFromFilename("patients.xls")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "\ufeffnational_id,first_name_th\n1234567890123,\"สมชาย, ใจดี\"\n1234567890124\n"
+
+	reader, err := NewReader(FormatCSV, bytes.NewBufferString(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"national_id", "first_name_th"},
+		{"1234567890123", "สมชาย, ใจดี"},
+		{"1234567890124"},
+	}, readAll(t, reader))
+}
+
+func TestXLSXReader(t *testing.T) {
+	book := excelize.NewFile()
+	sheet := book.GetSheetName(0)
+	require.NoError(t, book.SetSheetRow(sheet, "A1", &[]interface{}{"national_id", "first_name_th"}))
+	require.NoError(t, book.SetSheetRow(sheet, "A2", &[]interface{}{"1234567890123", "สมชาย"}))
+	var buf bytes.Buffer
+	require.NoError(t, book.Write(&buf))
+
+	reader, err := NewReader(FormatXLSX, &buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"national_id", "first_name_th"},
+		{"1234567890123", "สมชาย"},
+	}, readAll(t, reader))
+}
+
+func TestXLSXReaderRejectsGarbage(t *testing.T) {
+	_, err := NewReader(FormatXLSX, bytes.NewBufferString("not a workbook"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package tabular
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer appends spreadsheet rows one at a time. Close must be called to
+// flush the output; nothing is guaranteed to reach the underlying writer
+// before it returns.
+type Writer interface {
+	Write(record []string) error
+	Close() error
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+type csvWriter struct {
+	buffer *bufio.Writer
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	buffer := bufio.NewWriter(w)
+
+	// Without the byte order mark Excel opens UTF-8 files as the local code
+	// page and garbles Thai names. NewReader strips it again.
+	if _, err := buffer.WriteString("\ufeff"); err != nil {
+		return nil, err
+	}
+
+	return &csvWriter{buffer: buffer, writer: csv.NewWriter(buffer)}, nil
+}
+
+func (w *csvWriter) Write(record []string) error {
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return err
+	}
+	return w.buffer.Flush()
+}
+
+// xlsxWriter streams rows into the first worksheet. excelize spills large
+// sheets to a temporary file, and the zip archive is only written to the
+// output on Close. Cells are stored as text so identifiers keep their
+// leading zeros.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &xlsxWriter{out: w, file: file, stream: stream}, nil
+}
+
+func (w *xlsxWriter) Write(record []string) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(record))
+	for i, value := range record {
+		values[i] = value
+	}
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := w.file.WriteTo(w.out)
+	return err
+}
@@ -0,0 +1,52 @@
+package tabular
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"national_id", "first_name_th", "email"},
+		{"0123456789012", "สมชาย, ใจดี", ""},
+		{"0123456789013", "สมหญิง", "a@example.com"},
+	}
+
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			var out bytes.Buffer
+			writer, err := NewWriter(format, &out)
+			require.NoError(t, err)
+			for _, row := range rows {
+				require.NoError(t, writer.Write(row))
+			}
+			require.NoError(t, writer.Close())
+
+			reader, err := NewReader(format, &out)
+			require.NoError(t, err)
+			got := readAll(t, reader)
+
+			assert.Equal(t, rows[0], got[0])
+			assert.Equal(t, rows[1][:2], got[1][:2])
+			assert.Equal(t, rows[2], got[2])
+		})
+	}
+}
+
+func TestCSVWriterWritesByteOrderMark(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &out)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write([]string{"a", "b"}))
+	require.NoError(t, writer.Close())
+
+	assert.Equal(t, "\ufeffa,b\n", out.String())
+}
+
+func TestNewWriterRejectsUnknownFormat(t *testing.T) {
+	_, err := NewWriter(Format("xls"), &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
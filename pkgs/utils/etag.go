@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetETag(c *gin.Context, version uint) {
+	c.Header("ETag", fmt.Sprintf("\"%d\"", version))
+}
+
+// IfMatchVersion reads the version the client expects to update from the
+// If-Match header. When the header is missing or unusable it writes the
+// matching error response and returns false.
+func IfMatchVersion(c *gin.Context) (uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		PreconditionRequiredResponse(c, "If-Match header is required")
+		return 0, false
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	tag = strings.Trim(tag, "\"")
+
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil {
+		PreconditionFailedResponse(c, "If-Match does not match the current version")
+		return 0, false
+	}
+
+	return uint(version), true
+}
@@ -17,6 +17,8 @@ func GenerateAccessToken(cfg *configs.Config, req *entities.Jwtpassport) (string
 		Username:   req.Username,
 		Hospital:   req.Hospital,
 		HospitalID: req.HospitalID,
+		Role:       req.Role,
+		Scopes:     req.Scopes,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(cfg.JWT.Expire))),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLocale picks the locale display labels are written in from the
+// Accept-Language header. The supported language with the highest weight
+// wins, and requests without one get Thai.
+func RequestLocale(c *gin.Context) consts.Locale {
+	locale, best := consts.LocaleTH, 0.0
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(tag, "-")
+
+		candidate := consts.Locale(strings.ToLower(primary))
+		if !candidate.IsValid() {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+
+		if weight > best {
+			locale, best = candidate, weight
+		}
+	}
+	return locale
+}
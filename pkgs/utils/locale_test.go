@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/pkgs/consts"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLocale(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		locale consts.Locale
+	}{
+		{"Missing", "", consts.LocaleTH},
+		{"English", "en", consts.LocaleEN},
+		{"Region Subtag", "en-US,en;q=0.9", consts.LocaleEN},
+		{"Highest Weight Wins", "th;q=0.5, en;q=0.8", consts.LocaleEN},
+		{"Unsupported Skipped", "fr-FR, en;q=0.7, th;q=0.3", consts.LocaleEN},
+		{"Unsupported Only", "fr, de", consts.LocaleTH},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("Accept-Language", tt.header)
+			}
+
+			assert.Equal(t, tt.locale, RequestLocale(c))
+		})
+	}
+}
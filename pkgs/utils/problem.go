@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+type (
+	// Problem is the body of every error response. Code is stable and is
+	// what clients should branch on; Detail is meant for people.
+	Problem struct {
+		Type     string         `json:"type"`
+		Title    string         `json:"title"`
+		Status   int            `json:"status"`
+		Detail   string         `json:"detail,omitempty"`
+		Instance string         `json:"instance,omitempty"`
+		Code     string         `json:"code"`
+		Errors   []ProblemField `json:"errors,omitempty"`
+		Data     interface{}    `json:"data,omitempty"`
+	}
+
+	// ProblemField explains why a single request field was rejected.
+	ProblemField struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+)
+
+func init() {
+	// Report body fields by their JSON names rather than the Go ones.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+func NewProblem(status int, code string, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// WriteProblem writes the problem as the response and aborts the handler
+// chain.
+func WriteProblem(c *gin.Context, problem *Problem) {
+	if problem.Instance == "" {
+		problem.Instance = c.Request.URL.Path
+	}
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// ProblemFromError decides how an error returned by a use case is reported.
+// Errors it does not know become a 500 that does not reveal the message.
+func ProblemFromError(err error) *Problem {
+	var (
+		domainErr *entities.DomainError
+		fieldErr  *entities.FieldError
+		invalid   validator.ValidationErrors
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+		queryErr  *query.Error
+	)
+
+	switch {
+	case errors.Is(err, entities.ErrVersionConflict):
+		return NewProblem(http.StatusPreconditionFailed, "version_conflict", err.Error())
+	case errors.As(err, &fieldErr):
+		problem := NewProblem(http.StatusBadRequest, "validation_failed", fieldErr.Message)
+		problem.Errors = []ProblemField{{Field: fieldErr.Field, Message: fieldErr.Message}}
+		return problem
+	case errors.As(err, &domainErr):
+		return NewProblem(kindStatus(domainErr.Kind), domainErr.Code, domainErr.Message)
+	case errors.As(err, &queryErr):
+		problem := NewProblem(http.StatusBadRequest, "validation_failed", queryErr.Message)
+		problem.Errors = []ProblemField{{Field: queryErr.Param, Message: queryErr.Message}}
+		return problem
+	case errors.As(err, &invalid):
+		problem := NewProblem(http.StatusBadRequest, "validation_failed", "request body is not valid")
+		for _, fe := range invalid {
+			problem.Errors = append(problem.Errors, ProblemField{Field: fieldPath(fe.Namespace()), Message: validationMessage(fe)})
+		}
+		return problem
+	case errors.As(err, &typeErr):
+		problem := NewProblem(http.StatusBadRequest, "validation_failed", "request body is not valid")
+		problem.Errors = []ProblemField{{Field: typeErr.Field, Message: typeErr.Field + " must be a " + typeErr.Type.String()}}
+		return problem
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return NewProblem(http.StatusBadRequest, "malformed_body", "request body is not valid JSON")
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return NewProblem(http.StatusNotFound, "not_found", entities.ErrNotFound.Error())
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return NewProblem(http.StatusConflict, "duplicate", "resource already exists")
+	}
+
+	for _, kind := range []error{entities.ErrNotFound, entities.ErrConflict, entities.ErrValidation, entities.ErrForbidden} {
+		if errors.Is(err, kind) {
+			return NewProblem(kindStatus(kind), kindCode(kind), err.Error())
+		}
+	}
+
+	return NewProblem(http.StatusInternalServerError, "internal_error", "internal server error")
+}
+
+func kindStatus(kind error) int {
+	switch kind {
+	case entities.ErrNotFound:
+		return http.StatusNotFound
+	case entities.ErrConflict:
+		return http.StatusConflict
+	case entities.ErrValidation:
+		return http.StatusBadRequest
+	case entities.ErrForbidden:
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+func kindCode(kind error) string {
+	switch kind {
+	case entities.ErrNotFound:
+		return "not_found"
+	case entities.ErrConflict:
+		return "conflict"
+	case entities.ErrValidation:
+		return "invalid_request"
+	case entities.ErrForbidden:
+		return "forbidden"
+	}
+	return "internal_error"
+}
+
+// fieldPath drops the request type from a validator namespace such as
+// "StaffLoginRequest.hospital".
+func fieldPath(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
+	}
+	return path
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without", "required_with", "required_if":
+		return fe.Field() + " is required"
+	case "email":
+		return fe.Field() + " must be an email address"
+	case "oneof":
+		return fe.Field() + " must be one of " + fe.Param()
+	case "min", "gte":
+		return fe.Field() + " must be at least " + fe.Param()
+	case "max", "lte":
+		return fe.Field() + " must be at most " + fe.Param()
+	case "len":
+		return fe.Field() + " must have length " + fe.Param()
+	}
+	return fe.Field() + " is not valid"
+}
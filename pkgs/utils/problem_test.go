@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Teemo4621/Hospital-Api/modules/entities"
+	"github.com/Teemo4621/Hospital-Api/pkgs/query"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestProblemFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{"Not Found", entities.NotFound("parent department"), http.StatusNotFound, "parent_department_not_found", "parent department not found"},
+		{"Wrapped Not Found", fmt.Errorf("load: %w", entities.NotFound("patient")), http.StatusNotFound, "patient_not_found", "patient not found"},
+		{"Conflict", entities.ErrBedNotAvailable, http.StatusConflict, "bed_not_available", "bed is not available"},
+		{"Invalid", entities.Invalid("name is required"), http.StatusBadRequest, "invalid_request", "name is required"},
+		{"Forbidden", entities.Forbidden("not_transfer_source", "only the source hospital can cancel a transfer"), http.StatusForbidden, "not_transfer_source", "only the source hospital can cancel a transfer"},
+		{"Version Conflict", entities.ErrVersionConflict, http.StatusPreconditionFailed, "version_conflict", entities.ErrVersionConflict.Error()},
+		{"Record Not Found", gorm.ErrRecordNotFound, http.StatusNotFound, "not_found", "resource not found"},
+		{"Duplicated Key", gorm.ErrDuplicatedKey, http.StatusConflict, "duplicate", "resource already exists"},
+		{"Unknown Error Is Hidden", errors.New("pq: connection refused"), http.StatusInternalServerError, "internal_error", "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := ProblemFromError(tt.err)
+
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, http.StatusText(tt.status), problem.Title)
+			assert.Equal(t, tt.code, problem.Code)
+			assert.Equal(t, tt.detail, problem.Detail)
+			assert.Equal(t, "about:blank", problem.Type)
+		})
+	}
+
+	t.Run("Field Error Lists The Field", func(t *testing.T) {
+		problem := ProblemFromError(&entities.FieldError{Field: "timezone", Message: "timezone is not valid"})
+
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, []ProblemField{{Field: "timezone", Message: "timezone is not valid"}}, problem.Errors)
+	})
+
+	t.Run("Query Error Names The Parameter", func(t *testing.T) {
+		problem := ProblemFromError(&query.Error{Param: "filter[province][gt]", Message: "province does not support the gt operator"})
+
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, []ProblemField{{Field: "filter[province][gt]", Message: "province does not support the gt operator"}}, problem.Errors)
+	})
+
+	t.Run("Binding Errors Use JSON Field Names", func(t *testing.T) {
+		var req struct {
+			HospitalName string `json:"hospital_name" binding:"required"`
+			Email        string `json:"email" binding:"omitempty,email"`
+		}
+		err := binding.JSON.BindBody([]byte(`{"email":"not-an-email"}`), &req)
+
+		problem := ProblemFromError(err)
+
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Equal(t, "validation_failed", problem.Code)
+		assert.Equal(t, []ProblemField{
+			{Field: "hospital_name", Message: "hospital_name is required"},
+			{Field: "email", Message: "email must be an email address"},
+		}, problem.Errors)
+	})
+
+	t.Run("Malformed JSON", func(t *testing.T) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		err := binding.JSON.BindBody([]byte(`{"name":`), &req)
+
+		problem := ProblemFromError(err)
+
+		assert.Equal(t, http.StatusBadRequest, problem.Status)
+		assert.Equal(t, "malformed_body", problem.Code)
+	})
+}
@@ -14,30 +14,49 @@ func OkResponse(c *gin.Context, data interface{}) {
 	})
 }
 
+// The error helpers below write a problem with the generic code of their
+// status. Handlers that only pass on a use case error should call c.Error
+// instead and leave the response to the error middleware.
+
 func NotFoundResponse(c *gin.Context, message string) {
-	c.JSON(http.StatusNotFound, gin.H{
-		"message": message,
-		"status":  "error",
-	})
+	WriteProblem(c, NewProblem(http.StatusNotFound, "not_found", message))
 }
 
 func ErrorResponse(c *gin.Context, message string) {
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"message": message,
-		"status":  "error",
-	})
+	WriteProblem(c, NewProblem(http.StatusInternalServerError, "internal_error", message))
 }
 
 func BadRequestResponse(c *gin.Context, message string) {
-	c.JSON(http.StatusBadRequest, gin.H{
-		"message": message,
-		"status":  "error",
-	})
+	WriteProblem(c, NewProblem(http.StatusBadRequest, "bad_request", message))
+}
+
+// BindErrorResponse reports a request body or query that could not be
+// bound, with the fields that failed validation. Errors ProblemFromError
+// does not know are still the client's fault here, so they become a 400.
+func BindErrorResponse(c *gin.Context, err error) {
+	problem := ProblemFromError(err)
+	if problem.Status == http.StatusInternalServerError {
+		problem = NewProblem(http.StatusBadRequest, "bad_request", err.Error())
+	}
+	WriteProblem(c, problem)
 }
 
 func UnauthorizedResponse(c *gin.Context, message string) {
-	c.JSON(http.StatusUnauthorized, gin.H{
-		"message": message,
-		"status":  "error",
-	})
+	WriteProblem(c, NewProblem(http.StatusUnauthorized, "unauthorized", message))
+}
+
+func PreconditionFailedResponse(c *gin.Context, message string) {
+	WriteProblem(c, NewProblem(http.StatusPreconditionFailed, "precondition_failed", message))
+}
+
+func PreconditionRequiredResponse(c *gin.Context, message string) {
+	WriteProblem(c, NewProblem(http.StatusPreconditionRequired, "precondition_required", message))
+}
+
+func ConflictResponse(c *gin.Context, message string) {
+	WriteProblem(c, NewProblem(http.StatusConflict, "conflict", message))
+}
+
+func ForbiddenResponse(c *gin.Context, message string) {
+	WriteProblem(c, NewProblem(http.StatusForbidden, "forbidden", message))
 }